@@ -0,0 +1,68 @@
+// Command spacetimedb-gen is a go:generate-compatible code generator for
+// Go SpacetimeDB modules. Pointed at a struct tagged with
+// `spacetimedb:"primary_key"` and `spacetimedb:"index"`/`"unique"` field
+// tags, it emits a spacetimedb.TableCache constructor plus typed lookup
+// and BSATN (de)serialization helpers (see internal/codegen.GenerateTable),
+// mirroring the ergonomics of the Rust `#[table]` macro without requiring
+// a module author to hand-roll row encoding and index bookkeeping.
+//
+// Typical usage, placed above the struct it describes:
+//
+//	//go:generate go run github.com/clockworklabs/SpacetimeDB/crates/bindings-go/cmd/spacetimedb-gen -type=Player
+//	type Player struct {
+//		ID   uint64 `spacetimedb:"primary_key"`
+//		Name string `spacetimedb:"unique"`
+//	}
+//
+// spacetimedb-gen parses the package's source directly (like `stringer`)
+// rather than importing it, so it works before the package itself is
+// guaranteed to compile.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/codegen"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "spacetimedb-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("spacetimedb-gen", flag.ContinueOnError)
+	typeName := fs.String("type", "", "name of the struct to generate table accessors for (required)")
+	tableName := fs.String("table", "", "module-side table name (default: the struct name, snake_cased)")
+	dir := fs.String("dir", ".", "directory containing the package to parse")
+	output := fs.String("output", "", "output file path (default: <table>_table.go in -dir)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *typeName == "" {
+		return fmt.Errorf("usage: spacetimedb-gen -type <StructName> [-table <name>] [-dir <path>] [-output <file>]")
+	}
+
+	pkg, def, err := parseTableStruct(*dir, *typeName)
+	if err != nil {
+		return err
+	}
+	if *tableName != "" {
+		def.Name = *tableName
+	}
+
+	src, err := codegen.GenerateTable(pkg, def)
+	if err != nil {
+		return err
+	}
+
+	outPath := *output
+	if outPath == "" {
+		outPath = *dir + "/" + def.Name + "_table.go"
+	}
+	return os.WriteFile(outPath, src, 0o644)
+}
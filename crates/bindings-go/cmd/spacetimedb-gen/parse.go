@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/codegen"
+)
+
+// parseTableStruct finds typeName's struct declaration among the Go
+// source files in dir (skipping _test.go files) and returns the
+// package it was declared in, plus a codegen.TableDef built from its
+// exported fields. It parses source directly, the same way `stringer`
+// does, so it works on a type whose package does not yet compile.
+func parseTableStruct(dir, typeName string) (pkg string, def codegen.TableDef, err error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", codegen.TableDef{}, fmt.Errorf("spacetimedb-gen: glob %s: %w", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return "", codegen.TableDef{}, fmt.Errorf("spacetimedb-gen: parse %s: %w", path, err)
+		}
+		if pkg == "" {
+			pkg = file.Name.Name
+		}
+
+		st, ok := findStruct(file, typeName)
+		if !ok {
+			continue
+		}
+		fields, err := structFields(fset, st)
+		if err != nil {
+			return "", codegen.TableDef{}, fmt.Errorf("spacetimedb-gen: type %s: %w", typeName, err)
+		}
+		return pkg, codegen.TableDef{
+			Name:   snakeCase(typeName),
+			GoType: typeName,
+			Fields: fields,
+		}, nil
+	}
+
+	return "", codegen.TableDef{}, fmt.Errorf("spacetimedb-gen: no struct type %q found in %s", typeName, dir)
+}
+
+// findStruct looks for a top-level "type <typeName> struct{...}"
+// declaration in file.
+func findStruct(file *ast.File, typeName string) (*ast.StructType, bool) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != typeName {
+				continue
+			}
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				return st, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// structFields flattens st's field list into TableFieldDef entries,
+// skipping unexported and embedded fields: codegen has no receiver
+// expression to address either through a row value.
+func structFields(fset *token.FileSet, st *ast.StructType) ([]codegen.TableFieldDef, error) {
+	var fields []codegen.TableFieldDef
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			continue // embedded field
+		}
+		goType, err := exprString(fset, f.Type)
+		if err != nil {
+			return nil, err
+		}
+		tag := ""
+		if f.Tag != nil {
+			unquoted, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid struct tag %s: %w", f.Tag.Value, err)
+			}
+			tag = unquoted
+		}
+		for _, name := range f.Names {
+			if !name.IsExported() {
+				continue
+			}
+			fields = append(fields, codegen.TableFieldDef{Name: name.Name, GoType: goType, Tag: tag})
+		}
+	}
+	return fields, nil
+}
+
+// exprString renders a type expression back to the source text it came
+// from, e.g. "uint64" or "[]string", so generated code spells a field's
+// type exactly as the struct declared it.
+func exprString(fset *token.FileSet, expr ast.Expr) (string, error) {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return "", fmt.Errorf("render type: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// snakeCase converts a PascalCase or camelCase Go identifier into the
+// lower_snake_case table-naming convention SpacetimeDB modules use,
+// e.g. "PlayerScore" -> "player_score".
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
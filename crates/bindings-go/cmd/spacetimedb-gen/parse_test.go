@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTableStruct(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+type Player struct {
+	ID    uint64 ` + "`spacetimedb:\"primary_key\"`" + `
+	Name  string ` + "`spacetimedb:\"unique\"`" + `
+	Score int32
+	unexportedField bool
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "player.go"), []byte(src), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	pkg, def, err := parseTableStruct(dir, "Player")
+	if err != nil {
+		t.Fatalf("parseTableStruct: %v", err)
+	}
+	if pkg != "model" {
+		t.Fatalf("pkg = %q, want %q", pkg, "model")
+	}
+	if def.Name != "player" || def.GoType != "Player" {
+		t.Fatalf("def = %+v, want Name=player GoType=Player", def)
+	}
+	if len(def.Fields) != 3 {
+		t.Fatalf("len(def.Fields) = %d, want 3 (unexportedField skipped): %+v", len(def.Fields), def.Fields)
+	}
+	if def.Fields[0].Name != "ID" || def.Fields[0].GoType != "uint64" || def.Fields[0].Tag != `spacetimedb:"primary_key"` {
+		t.Fatalf("def.Fields[0] = %+v", def.Fields[0])
+	}
+}
+
+func TestParseTableStructMissingType(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "player.go"), []byte("package model\n"), 0o644)
+
+	if _, _, err := parseTableStruct(dir, "Ghost"); err == nil {
+		t.Fatal("parseTableStruct for missing type: want error, got nil")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"Player":      "player",
+		"PlayerScore": "player_score",
+		"ID":          "i_d",
+	}
+	for in, want := range cases {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
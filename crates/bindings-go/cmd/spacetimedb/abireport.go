@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/wasm"
+)
+
+// runABIReport prints Runtime.ABIReport() as a table, so an operator can
+// see exactly which spacetime_10.0 host functions will not behave
+// correctly under this Go host yet without reading the source.
+func runABIReport(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: abi-report")
+	}
+
+	rt := &wasm.Runtime{}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FUNCTION\tSTATUS\tTESTED\tNOTES")
+	for _, fn := range rt.ABIReport() {
+		fmt.Fprintf(tw, "%s\t%s\t%v\t%s\n", fn.Name, fn.Status, fn.Tested, fn.Notes)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/wirebench"
+)
+
+// runBenchWire loads a schema and sample rows from JSON files and prints
+// a wirebench.Report comparing BSATN against SATS-JSON for them.
+func runBenchWire(args []string) error {
+	fs := flag.NewFlagSet("bench-wire", flag.ContinueOnError)
+	schemaPath := fs.String("schema", "", "path to a schema JSON file (see pkg/wirebench.ParseSchema)")
+	rowsPath := fs.String("rows", "", "path to a JSON array of sample rows matching the schema")
+	iterations := fs.Int("iterations", 1000, "how many times to repeat each row's encode/decode")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *schemaPath == "" || *rowsPath == "" {
+		return fmt.Errorf("usage: bench-wire -schema <file> -rows <file> [-iterations N]")
+	}
+
+	schemaData, err := os.ReadFile(*schemaPath)
+	if err != nil {
+		return fmt.Errorf("read schema: %w", err)
+	}
+	schema, err := wirebench.ParseSchema(schemaData)
+	if err != nil {
+		return err
+	}
+
+	rowsData, err := os.ReadFile(*rowsPath)
+	if err != nil {
+		return fmt.Errorf("read rows: %w", err)
+	}
+	var rows []any
+	if err := json.Unmarshal(rowsData, &rows); err != nil {
+		return fmt.Errorf("parse rows: %w", err)
+	}
+
+	report, err := wirebench.Compare(schema, rows, *iterations)
+	if err != nil {
+		return err
+	}
+	fmt.Println(report)
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+// runCheck opens the database at db-dir and reports its table row
+// counts.
+//
+// A bare WAL directory carries no index manifest (indexes are defined
+// programmatically via Database.RegisterIndex, with a Go IndexKeyFunc
+// that a CLI has no way to reconstruct), so this cannot run
+// Database.VerifyIndexes on its own; a host process with a real
+// ModuleDef-derived Database should call VerifyIndexes/RebuildIndex
+// directly instead of going through this CLI, same as backup.Write
+// (see runBackup).
+func runCheck(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: check <db-dir>")
+	}
+	dbDir := args[0]
+
+	engine, err := db.OpenWAL(filepath.Join(dbDir, "wal.log"), db.WALConfig{Policy: db.FsyncAlways})
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer engine.Close()
+
+	for _, name := range engine.ListTables() {
+		cur, err := engine.Scan(name)
+		if err != nil {
+			return fmt.Errorf("scan %q: %w", name, err)
+		}
+		n := 0
+		for cur.Next() {
+			n++
+		}
+		if err := cur.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d rows\n", name, n)
+	}
+	fmt.Println("no indexes registered: run Database.VerifyIndexes/RebuildIndex from a host process with a schema-derived Database to check indexes")
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/backup"
+)
+
+// runCompactDeltas collapses base-archive plus a chain of delta files
+// into a single delta file with the same net effect, so a fixture's
+// delta chain can be periodically flattened instead of growing forever.
+func runCompactDeltas(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("usage: compact-deltas <base-archive> <compacted-delta-file> <delta-file>...")
+	}
+	basePath, outPath, deltaPaths := args[0], args[1], args[2:]
+
+	base, err := os.ReadFile(basePath)
+	if err != nil {
+		return fmt.Errorf("read base archive: %w", err)
+	}
+	deltas := make([][]byte, len(deltaPaths))
+	for i, p := range deltaPaths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("read delta %q: %w", p, err)
+		}
+		deltas[i] = data
+	}
+
+	compacted, stats, err := backup.CompactChain(base, deltas)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, compacted, 0o644); err != nil {
+		return fmt.Errorf("write compacted delta: %w", err)
+	}
+
+	total := 0
+	for _, n := range stats.Rows {
+		total += n
+	}
+	fmt.Printf("compacted %d delta(s) into %s (%d tables, %d changed rows)\n", len(deltaPaths), outPath, stats.Tables, total)
+	return nil
+}
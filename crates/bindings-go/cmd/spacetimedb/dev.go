@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/devloop"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/spacetimedb"
+)
+
+// runDev embeds a module in-process for local iteration: it loads the
+// wasm file once, replays any configured seed reducers, and — with
+// -watch — polls the same path for a changed modification time,
+// hot-swapping the rebuilt module into the running host and replaying
+// the seeds again every time the build output actually changes. See
+// internal/devloop for the polling/reload loop this wraps.
+func runDev(args []string) error {
+	fs := flag.NewFlagSet("dev", flag.ContinueOnError)
+	watch := fs.Bool("watch", false, "poll the wasm file and hot-swap it into the running host on change")
+	var seedFlags stringSliceFlag
+	fs.Var(&seedFlags, "seed", "reducer to call after load/reload, as name:id[:hex-args] (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: dev [flags] <wasm-file>")
+	}
+	wasmPath := fs.Arg(0)
+
+	seeds, err := parseSeeds(seedFlags)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	handle, err := spacetimedb.Embed(ctx, wasmPath)
+	if err != nil {
+		return err
+	}
+	defer handle.Shutdown()
+
+	return devloop.Watch(ctx, wasmPath, *watch, handle, devloop.Options{Seeds: seeds})
+}
+
+// parseSeeds turns "name:id[:hex-args]" flag values into devloop.SeedCall.
+func parseSeeds(flags []string) ([]devloop.SeedCall, error) {
+	seeds := make([]devloop.SeedCall, 0, len(flags))
+	for _, raw := range flags {
+		parts := strings.SplitN(raw, ":", 3)
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("dev: -seed %q: want name:id[:hex-args]", raw)
+		}
+		id, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("dev: -seed %q: invalid reducer id: %w", raw, err)
+		}
+		var reducerArgs []byte
+		if len(parts) == 3 && parts[2] != "" {
+			reducerArgs, err = hex.DecodeString(parts[2])
+			if err != nil {
+				return nil, fmt.Errorf("dev: -seed %q: invalid hex args: %w", raw, err)
+			}
+		}
+		seeds = append(seeds, devloop.SeedCall{Name: parts[0], ID: uint32(id), Args: reducerArgs})
+	}
+	return seeds, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/logs"
+)
+
+// runLogs streams a database's structured logs from the server's log
+// endpoint, filters them, and pretty-prints survivors to stdout as they
+// arrive.
+func runLogs(args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	server := fs.String("server", "http://localhost:3000", "SpacetimeDB server base URL")
+	tokenPath := fs.String("token-file", "", "path to a stored login token (default: identity.TokenPath())")
+	follow := fs.Bool("follow", true, "keep the connection open and stream new entries")
+	levelName := fs.String("level", "info", "minimum level to show (error, warn, info, debug, trace)")
+	reducer := fs.String("reducer", "", "only show entries logged from this reducer")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: logs [flags] <module-name>")
+	}
+	moduleName := fs.Arg(0)
+
+	minLevel, err := logs.ParseLevel(*levelName)
+	if err != nil {
+		return err
+	}
+
+	path := *tokenPath
+	if path == "" {
+		path, err = identity.TokenPath()
+		if err != nil {
+			return err
+		}
+	}
+	token, err := identity.LoadToken(path)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/database/%s/logs?follow=%t", *server, moduleName, *follow)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("logs: request %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("logs: server returned %s", resp.Status)
+	}
+
+	entries, errc := logs.Follow(context.Background(), resp.Body, logs.Filter{MinLevel: minLevel, Reducer: *reducer})
+	for entry := range entries {
+		fmt.Fprintln(os.Stdout, entry.Format())
+	}
+	return <-errc
+}
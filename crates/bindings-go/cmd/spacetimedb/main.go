@@ -0,0 +1,57 @@
+// Command spacetimedb is a CLI for operating a locally hosted SpacetimeDB
+// Go host: backing up and restoring databases, and (see later
+// subcommands) publishing modules and inspecting schemas.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"backup":         {usage: "backup <db-dir> <archive-file>", run: runBackup},
+	"restore":        {usage: "restore <archive-file> <db-dir>", run: runRestore},
+	"publish":        {usage: "publish [flags] <wasm-file> <module-name>", run: runPublish},
+	"logs":           {usage: "logs [flags] <module-name>", run: runLogs},
+	"check":          {usage: "check <db-dir>", run: runCheck},
+	"abi-report":     {usage: "abi-report", run: runABIReport},
+	"bench-wire":     {usage: "bench-wire -schema <file> -rows <file> [-iterations N]", run: runBenchWire},
+	"openapi":        {usage: "openapi -module <file>", run: runOpenAPI},
+	"backup-delta":   {usage: "backup-delta <db-dir> <base-archive> <delta-file>", run: runBackupDelta},
+	"restore-chain":  {usage: "restore-chain <db-dir> <base-archive> [delta-file...]", run: runRestoreChain},
+	"compact-deltas": {usage: "compact-deltas <base-archive> <compacted-delta-file> <delta-file>...", run: runCompactDeltas},
+	"promote":        {usage: "promote [flags] <manifest-file>", run: runPromote},
+	"dev":            {usage: "dev [flags] <wasm-file>", run: runDev},
+	"replay":         {usage: "replay [flags] <wasm-file> <log-file>", run: runReplay},
+	"schema-diff":    {usage: "schema-diff -local <file> [flags] <database-name>", run: runSchemaDiff},
+	"sql":            {usage: "sql [flags] <database-name> [query]", run: runSQL},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "spacetimedb: unknown subcommand %q\n", os.Args[1])
+		printUsage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "spacetimedb %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: spacetimedb <command> [args...]")
+	for name, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %s %s\n", name, cmd.usage[len(name)+1:])
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/codegen"
+)
+
+// moduleDefJSON is the on-disk shape runOpenAPI and runSchemaDiff read: a
+// module name and its reducers, each with an argument schema in the same
+// format wirebench.ParseSchema accepts (see parseModuleDefJSON).
+type moduleDefJSON struct {
+	Module   string `json:"module"`
+	Reducers []struct {
+		Name string          `json:"name"`
+		Args json.RawMessage `json:"args"`
+	} `json:"reducers"`
+}
+
+// runOpenAPI loads a module definition from a JSON file and prints the
+// OpenAPI document codegen.GenerateOpenAPI derives from it.
+func runOpenAPI(args []string) error {
+	fs := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	modulePath := fs.String("module", "", "path to a module definition JSON file (see moduleDefJSON)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *modulePath == "" {
+		return fmt.Errorf("usage: openapi -module <file>")
+	}
+
+	def, err := loadModuleDefJSON(*modulePath)
+	if err != nil {
+		return fmt.Errorf("read module definition: %w", err)
+	}
+
+	doc, err := codegen.GenerateOpenAPI(def)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(append(doc, '\n'))
+	return err
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/httpclient"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/promote"
+)
+
+// runPromote publishes a module through every environment named in a
+// manifest's order, in sequence, refreshing generated client code once
+// it lands everywhere. See internal/promote for the manifest format.
+func runPromote(args []string) error {
+	fs := flag.NewFlagSet("promote", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "approve every environment without prompting")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: promote [flags] <manifest-file>")
+	}
+
+	m, err := promote.LoadManifest(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	wasmBytes, err := os.ReadFile(m.Wasm)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", m.Wasm, err)
+	}
+
+	p := &promote.Promoter{HTTP: httpclient.New(httpclient.NewConfig())}
+	if !*yes {
+		p.Approve = confirmPromotion
+	}
+
+	results, err := p.Promote(context.Background(), m, wasmBytes)
+	for _, r := range results {
+		if r.Published {
+			fmt.Printf("published to %s as database %s (schema %s)\n", r.Environment.Name, r.Environment.Database, r.Hash[:12])
+		}
+	}
+	return err
+}
+
+// confirmPromotion asks on stdin/stdout before publishing to env, so a
+// promotion doesn't silently push a schema change to a later
+// environment (e.g. prod) without a human in the loop.
+func confirmPromotion(env promote.Environment, hash string) (bool, error) {
+	fmt.Printf("promote to %s (%s) at schema %s? [y/N] ", env.Name, env.Database, hash[:12])
+	reader := bufio.NewReader(os.Stdin)
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return false, nil
+	}
+	return line == "y\n" || line == "Y\n" || line == "yes\n", nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/codegen"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/httpclient"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/publish"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/wasm"
+)
+
+// runPublish validates a compiled module and publishes it directly to a
+// SpacetimeDB server's HTTP endpoint, without going through the Rust
+// `spacetime` CLI.
+//
+// Schema extraction is limited today: the host does not yet parse
+// __describe_module__ (that lands with the reducer lifecycle work), so
+// -codegen-out cannot discover event tables on its own. Callers that
+// want generated subscriber code must name the tables with repeated
+// -event-table flags; this restriction goes away once module
+// description parsing exists.
+func runPublish(args []string) error {
+	fs := flag.NewFlagSet("publish", flag.ContinueOnError)
+	server := fs.String("server", "http://localhost:3000", "SpacetimeDB server base URL")
+	tokenPath := fs.String("token-file", "", "path to a stored login token (default: identity.TokenPath())")
+	codegenOut := fs.String("codegen-out", "", "if set, write generated Go event subscriber code here")
+	codegenPkg := fs.String("codegen-package", "module", "package name for -codegen-out")
+	schemaVersionOut := fs.String("schema-version-out", "", "if set, write a generated SchemaVersion pinned to this module's hash here")
+	var eventTables stringSliceFlag
+	fs.Var(&eventTables, "event-table", "event table name to generate a subscriber for (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: publish [flags] <wasm-file> <module-name>")
+	}
+	wasmPath, moduleName := fs.Arg(0), fs.Arg(1)
+
+	wasmBytes, err := os.ReadFile(wasmPath)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", wasmPath, err)
+	}
+
+	if err := wasm.ValidateABI(context.Background(), wasmBytes); err != nil {
+		return err
+	}
+
+	path := *tokenPath
+	if path == "" {
+		path, err = identity.TokenPath()
+		if err != nil {
+			return err
+		}
+	}
+	token, err := identity.LoadToken(path)
+	if err != nil {
+		return err
+	}
+
+	client := &publish.Client{BaseURL: *server, Token: token, HTTP: httpclient.New(httpclient.NewConfig())}
+	id, err := client.Publish(moduleName, wasmBytes)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("published %s as database %s\n", moduleName, id)
+
+	if *codegenOut != "" {
+		tables := make([]codegen.EventTable, 0, len(eventTables))
+		for _, name := range eventTables {
+			tables = append(tables, codegen.AutoEventTables([]string{name})...)
+		}
+		src, err := codegen.GenerateEventEmitters(*codegenPkg, tables)
+		if err != nil {
+			return fmt.Errorf("codegen: %w", err)
+		}
+		if err := os.WriteFile(*codegenOut, src, 0o644); err != nil {
+			return fmt.Errorf("codegen: write %s: %w", *codegenOut, err)
+		}
+		fmt.Printf("wrote generated event subscribers to %s\n", *codegenOut)
+	}
+
+	if *schemaVersionOut != "" {
+		src, err := codegen.GenerateSchemaVersion(*codegenPkg, codegen.HashModule(wasmBytes))
+		if err != nil {
+			return fmt.Errorf("codegen: %w", err)
+		}
+		if err := os.WriteFile(*schemaVersionOut, src, 0o644); err != nil {
+			return fmt.Errorf("codegen: write %s: %w", *schemaVersionOut, err)
+		}
+		fmt.Printf("wrote schema version to %s\n", *schemaVersionOut)
+	}
+	return nil
+}
+
+// stringSliceFlag collects repeated -flag=value occurrences into a slice.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string { return fmt.Sprint([]string(*s)) }
+
+func (s *stringSliceFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
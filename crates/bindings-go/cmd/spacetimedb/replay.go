@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/reducerlog"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/spacetimedb"
+)
+
+// runReplay drives an embedded module through a reducerlog recording,
+// for deterministic performance regression testing against a
+// production-like workload. See internal/reducerlog.
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	speed := fs.Float64("speed", 1.0, "replay speed relative to the recording's original pacing (0 = as fast as possible)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: replay [flags] <wasm-file> <log-file>")
+	}
+	wasmPath, logPath := fs.Arg(0), fs.Arg(1)
+
+	logFile, err := os.Open(logPath)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", logPath, err)
+	}
+	defer logFile.Close()
+	calls, err := reducerlog.Read(logFile)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", logPath, err)
+	}
+
+	ctx := context.Background()
+	handle, err := spacetimedb.Embed(ctx, wasmPath)
+	if err != nil {
+		return err
+	}
+	defer handle.Shutdown()
+
+	stats, err := reducerlog.Replay(ctx, calls, handle, reducerlog.ReplayOptions{Speed: *speed})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("replayed %d calls (%d failures) in %s\n", stats.Calls, stats.Failures, stats.Duration)
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/codegen"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/httpclient"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/wirebench"
+)
+
+// runSchemaDiff compares a local module's reducer schema against a
+// deployed database's, reporting every difference and exiting non-zero
+// if any is breaking — suitable for a CI gate that blocks a publish
+// which would strand already-generated clients.
+//
+// Schema extraction is limited the same way runPublish's is: the host
+// does not yet parse __describe_module__, so "local module" here means
+// the same hand-assembled JSON manifest openapi's -module flag reads
+// (see moduleDefJSON), not the wasm file itself. Once module description
+// parsing lands, -local can take a wasm file directly.
+func runSchemaDiff(args []string) error {
+	fs := flag.NewFlagSet("schema-diff", flag.ContinueOnError)
+	local := fs.String("local", "", "path to a module definition JSON file describing the local build (see moduleDefJSON)")
+	server := fs.String("server", "http://localhost:3000", "SpacetimeDB server base URL")
+	tokenPath := fs.String("token-file", "", "path to a stored login token (default: identity.TokenPath())")
+	jsonOut := fs.Bool("json", false, "print the machine-readable JSON change report instead of the human-readable one")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *local == "" || fs.NArg() != 1 {
+		return fmt.Errorf("usage: schema-diff -local <file> [flags] <database-name>")
+	}
+	database := fs.Arg(0)
+
+	localDef, err := loadModuleDefJSON(*local)
+	if err != nil {
+		return fmt.Errorf("read local schema: %w", err)
+	}
+
+	path := *tokenPath
+	if path == "" {
+		path, err = identity.TokenPath()
+		if err != nil {
+			return err
+		}
+	}
+	token, err := identity.LoadToken(path)
+	if err != nil {
+		return err
+	}
+
+	deployedDef, err := fetchDeployedSchema(httpclient.New(httpclient.NewConfig()), *server, database, token)
+	if err != nil {
+		return fmt.Errorf("fetch deployed schema: %w", err)
+	}
+
+	changes := codegen.DiffModuleDef(deployedDef, localDef)
+
+	if *jsonOut {
+		out, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	} else {
+		printSchemaDiff(changes)
+	}
+
+	if codegen.AnyBreaking(changes) {
+		return fmt.Errorf("%d breaking change(s) found", countBreaking(changes))
+	}
+	return nil
+}
+
+func printSchemaDiff(changes []codegen.SchemaChange) {
+	if len(changes) == 0 {
+		fmt.Println("no schema changes")
+		return
+	}
+	for _, c := range changes {
+		marker := "  "
+		if c.Breaking {
+			marker = "! "
+		}
+		fmt.Printf("%s%s: %s (%s)\n", marker, c.Kind, c.Reducer, c.Detail)
+	}
+}
+
+func countBreaking(changes []codegen.SchemaChange) int {
+	n := 0
+	for _, c := range changes {
+		if c.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+// fetchDeployedSchema GETs database's reducer schema from server's HTTP
+// API, in the same moduleDefJSON shape a local module definition file
+// uses, so both sides of the diff go through one parsing path.
+func fetchDeployedSchema(doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}, server, database, token string) (codegen.ModuleDef, error) {
+	url := fmt.Sprintf("%s/v1/database/%s/schema", server, database)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return codegen.ModuleDef{}, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		return codegen.ModuleDef{}, fmt.Errorf("request %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return codegen.ModuleDef{}, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return codegen.ModuleDef{}, fmt.Errorf("server returned %s: %s", resp.Status, body)
+	}
+
+	return parseModuleDefJSON(body)
+}
+
+// loadModuleDefJSON reads and parses a moduleDefJSON file from path.
+func loadModuleDefJSON(path string) (codegen.ModuleDef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return codegen.ModuleDef{}, err
+	}
+	return parseModuleDefJSON(data)
+}
+
+// parseModuleDefJSON parses data in the moduleDefJSON shape (see
+// openapi.go) into a codegen.ModuleDef.
+func parseModuleDefJSON(data []byte) (codegen.ModuleDef, error) {
+	var raw moduleDefJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return codegen.ModuleDef{}, fmt.Errorf("parse module definition: %w", err)
+	}
+	def := codegen.ModuleDef{Module: raw.Module}
+	for _, r := range raw.Reducers {
+		argsType, err := wirebench.ParseSchema(r.Args)
+		if err != nil {
+			return codegen.ModuleDef{}, fmt.Errorf("reducer %q: %w", r.Name, err)
+		}
+		def.Reducers = append(def.Reducers, codegen.ReducerDef{Name: r.Name, Args: argsType})
+	}
+	return def, nil
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/httpclient"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+)
+
+// runSQL runs one or more SQL queries against either a remote server's
+// SQL API or a local WAL directory, printing results as a table, CSV, or
+// JSON.
+//
+// This is a read-only client, not a query engine: against a remote
+// server it POSTs the query text verbatim to the SQL endpoint and
+// prints whatever rows come back; against -local it can only run
+// `SELECT * FROM <table> [LIMIT n]`, since a bare WAL directory has no
+// schema to plan a real query against (see runCheck's doc comment for
+// the same limitation applied to indexes) — rows print as key/value hex
+// pairs rather than decoded columns for the same reason. -interactive
+// gives a line-oriented REPL (read a query, run it, print it) rather
+// than a readline-backed one with history and tab completion: this tree
+// has no readline dependency (its only external module is wazero), so
+// adding one for this alone would be a bigger call than one CLI command
+// justifies.
+func runSQL(args []string) error {
+	fs := flag.NewFlagSet("sql", flag.ContinueOnError)
+	server := fs.String("server", "http://localhost:3000", "SpacetimeDB server base URL (ignored with -local)")
+	tokenPath := fs.String("token-file", "", "path to a stored login token (default: identity.TokenPath())")
+	local := fs.String("local", "", "path to a local db-dir to query directly instead of a remote server")
+	interactive := fs.Bool("interactive", false, "read queries from stdin in a loop until EOF or \"exit\"")
+	csvOut := fs.Bool("csv", false, "print results as CSV instead of an aligned table")
+	jsonOut := fs.Bool("json", false, "print results as JSON instead of an aligned table")
+	timing := fs.Bool("timing", false, "print how long each query took")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *csvOut && *jsonOut {
+		return fmt.Errorf("sql: -csv and -json are mutually exclusive")
+	}
+
+	var runner sqlRunner
+	var database string
+	var queryArgs []string
+	if *local != "" {
+		r, err := newLocalSQLRunner(*local)
+		if err != nil {
+			return err
+		}
+		runner, queryArgs = r, fs.Args()
+	} else {
+		if fs.NArg() < 1 {
+			return fmt.Errorf("usage: sql [flags] <database-name> [query]\n   or: sql -local <db-dir> [flags] [query]")
+		}
+		database, queryArgs = fs.Arg(0), fs.Args()[1:]
+
+		path := *tokenPath
+		var err error
+		if path == "" {
+			path, err = identity.TokenPath()
+			if err != nil {
+				return err
+			}
+		}
+		token, err := identity.LoadToken(path)
+		if err != nil {
+			return err
+		}
+		runner = &remoteSQLRunner{doer: httpclient.New(httpclient.NewConfig()), server: *server, token: token}
+	}
+	defer runner.Close()
+
+	format := tableFormat
+	switch {
+	case *csvOut:
+		format = csvFormat
+	case *jsonOut:
+		format = jsonFormatOut
+	}
+
+	if *interactive {
+		return runSQLRepl(runner, database, format, *timing)
+	}
+
+	query := strings.Join(queryArgs, " ")
+	if query == "" {
+		return fmt.Errorf("usage: sql [flags] <database-name> <query>")
+	}
+	return runOneQuery(runner, database, query, format, *timing)
+}
+
+// sqlResultFormat selects how runSQL prints a sqlResult.
+type sqlResultFormat int
+
+const (
+	tableFormat sqlResultFormat = iota
+	csvFormat
+	jsonFormatOut
+)
+
+// sqlResult is one query's output: a column header and the row values
+// beneath it, both already stringified for printing.
+type sqlResult struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// sqlRunner executes SQL text against either a remote server or a local
+// db-dir. database is ignored by a local runner, which has no notion of
+// a database name.
+type sqlRunner interface {
+	Run(database, query string) (sqlResult, error)
+	Close() error
+}
+
+func runOneQuery(runner sqlRunner, database, query string, format sqlResultFormat, timing bool) error {
+	start := time.Now()
+	result, err := runner.Run(database, query)
+	elapsed := time.Since(start)
+	if err != nil {
+		return err
+	}
+	printSQLResult(os.Stdout, result, format)
+	if timing {
+		fmt.Fprintf(os.Stdout, "(%d row(s) in %s)\n", len(result.Rows), elapsed)
+	}
+	return nil
+}
+
+func runSQLRepl(runner sqlRunner, database string, format sqlResultFormat, timing bool) error {
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Fprint(os.Stdout, "sql> ")
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "exit" || line == "quit" {
+			return nil
+		}
+		if err := runOneQuery(runner, database, line, format, timing); err != nil {
+			fmt.Fprintf(os.Stderr, "sql: %v\n", err)
+		}
+	}
+}
+
+func printSQLResult(w io.Writer, result sqlResult, format sqlResultFormat) {
+	switch format {
+	case csvFormat:
+		writer := csv.NewWriter(w)
+		writer.Write(result.Columns)
+		writer.WriteAll(result.Rows)
+		writer.Flush()
+	case jsonFormatOut:
+		rows := make([]map[string]string, len(result.Rows))
+		for i, row := range result.Rows {
+			m := make(map[string]string, len(result.Columns))
+			for j, col := range result.Columns {
+				m[col] = row[j]
+			}
+			rows[i] = m
+		}
+		out, _ := json.MarshalIndent(rows, "", "  ")
+		fmt.Fprintln(w, string(out))
+	default:
+		printAlignedTable(w, result.Columns, result.Rows)
+	}
+}
+
+// printAlignedTable prints columns and rows padded to each column's
+// widest value, in the spirit of a psql/sqlite3 CLI's default output.
+func printAlignedTable(w io.Writer, columns []string, rows [][]string) {
+	widths := make([]int, len(columns))
+	for i, c := range columns {
+		widths[i] = len(c)
+	}
+	for _, row := range rows {
+		for i, v := range row {
+			if len(v) > widths[i] {
+				widths[i] = len(v)
+			}
+		}
+	}
+	printRow := func(values []string) {
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = v + strings.Repeat(" ", widths[i]-len(v))
+		}
+		fmt.Fprintln(w, strings.Join(parts, " | "))
+	}
+	printRow(columns)
+	for _, row := range rows {
+		printRow(row)
+	}
+}
+
+// remoteSQLRunner sends query text to a server's SQL API, in the same
+// %s/v1/database/%s/... shape runSchemaDiff's fetchDeployedSchema uses.
+// The response is a JSON array of row objects; column order is derived
+// by sorting the union of every row's keys, since JSON object key order
+// is not preserved.
+type remoteSQLRunner struct {
+	doer interface {
+		Do(req *http.Request) (*http.Response, error)
+	}
+	server string
+	token  string
+}
+
+func (r *remoteSQLRunner) Run(database, query string) (sqlResult, error) {
+	url := fmt.Sprintf("%s/v1/database/%s/sql", r.server, database)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(query))
+	if err != nil {
+		return sqlResult{}, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	req.Header.Set("Content-Type", "text/plain")
+
+	resp, err := r.doer.Do(req)
+	if err != nil {
+		return sqlResult{}, fmt.Errorf("request %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return sqlResult{}, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return sqlResult{}, fmt.Errorf("server returned %s: %s", resp.Status, body)
+	}
+
+	var rows []map[string]any
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return sqlResult{}, fmt.Errorf("parse response: %w", err)
+	}
+	return rowsToResult(rows), nil
+}
+
+func (r *remoteSQLRunner) Close() error { return nil }
+
+func rowsToResult(rows []map[string]any) sqlResult {
+	colSet := make(map[string]bool)
+	for _, row := range rows {
+		for k := range row {
+			colSet[k] = true
+		}
+	}
+	columns := make([]string, 0, len(colSet))
+	for c := range colSet {
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+
+	result := sqlResult{Columns: columns, Rows: make([][]string, len(rows))}
+	for i, row := range rows {
+		values := make([]string, len(columns))
+		for j, c := range columns {
+			if v, ok := row[c]; ok {
+				values[j] = fmt.Sprint(v)
+			}
+		}
+		result.Rows[i] = values
+	}
+	return result
+}
+
+// localSQLRunner runs the SELECT-star-with-optional-LIMIT subset
+// described by runSQL's doc comment directly against a WAL directory's
+// tables.
+type localSQLRunner struct {
+	engine *db.WALEngine
+}
+
+var localSelectPattern = regexp.MustCompile(`(?i)^\s*select\s+\*\s+from\s+(\S+?)\s*(?:limit\s+(\d+)\s*)?;?\s*$`)
+
+func newLocalSQLRunner(dbDir string) (*localSQLRunner, error) {
+	engine, err := db.OpenWAL(filepath.Join(dbDir, "wal.log"), db.WALConfig{Policy: db.FsyncAlways})
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	return &localSQLRunner{engine: engine}, nil
+}
+
+func (r *localSQLRunner) Run(_, query string) (sqlResult, error) {
+	match := localSelectPattern.FindStringSubmatch(query)
+	if match == nil {
+		return sqlResult{}, fmt.Errorf("sql: -local only supports \"SELECT * FROM <table> [LIMIT n]\"; run against a remote server for the full SQL API")
+	}
+	table, limitStr := match[1], match[2]
+	limit := -1
+	if limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return sqlResult{}, fmt.Errorf("sql: invalid LIMIT %q", limitStr)
+		}
+		limit = n
+	}
+
+	cur, err := r.engine.Scan(table)
+	if err != nil {
+		return sqlResult{}, fmt.Errorf("scan %q: %w", table, err)
+	}
+	defer cur.Close()
+
+	result := sqlResult{Columns: []string{"key", "value"}}
+	for cur.Next() {
+		if limit >= 0 && len(result.Rows) >= limit {
+			break
+		}
+		result.Rows = append(result.Rows, []string{hex.EncodeToString(cur.Key()), hex.EncodeToString(cur.Value())})
+	}
+	return result, nil
+}
+
+func (r *localSQLRunner) Close() error { return r.engine.Close() }
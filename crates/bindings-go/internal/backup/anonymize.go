@@ -0,0 +1,191 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+// HashString returns a ColumnTransform that replaces a string column
+// with the hex-encoded SHA-256 of salt+value, so equal source values
+// still hash equal (useful for joining anonymized exports back on a
+// pseudonymized key) without exposing the original value.
+func HashString(salt string) ColumnTransform {
+	return func(value any) any {
+		s, ok := value.(string)
+		if !ok {
+			return value
+		}
+		sum := sha256.Sum256([]byte(salt + s))
+		return hex.EncodeToString(sum[:])
+	}
+}
+
+// Zero returns a ColumnTransform that discards a column's value
+// entirely, replacing it with the zero value for its JSON-ish type
+// (empty string, false, or 0), for columns that must not leave the
+// production environment at all.
+func Zero() ColumnTransform {
+	return func(value any) any {
+		switch value.(type) {
+		case string:
+			return ""
+		case bool:
+			return false
+		case float64:
+			return float64(0)
+		default:
+			return value
+		}
+	}
+}
+
+// ColumnTransform replaces a single decoded column value (in the
+// map[string]any shape bsatn.UnmarshalJSON/MarshalJSON use) with a safe
+// substitute, e.g. hashing an email or zeroing out a PII field.
+type ColumnTransform func(value any) any
+
+// TableTransform is the anonymization applied to one table's rows while
+// streaming them out with WriteAnonymized.
+type TableTransform struct {
+	// Columns maps a column's index in TableInfo.Columns (as produced
+	// by strconv.Itoa) to the transform applied to that column's
+	// decoded value. A column with no entry is copied through
+	// unchanged.
+	Columns map[int]ColumnTransform
+	// SampleRate, if in (0, 1), keeps roughly this fraction of rows and
+	// drops the rest. Which rows survive is decided by hashing each
+	// row's key, so the same source row is always kept or dropped for a
+	// given SampleRate rather than varying between runs. A zero (or
+	// >=1) SampleRate keeps every row.
+	SampleRate float64
+}
+
+// keep reports whether the row with the given key survives t's
+// SampleRate, using an FNV hash of key so sampling is deterministic
+// across repeated exports of the same database.
+func (t TableTransform) keep(key []byte) bool {
+	if t.SampleRate <= 0 || t.SampleRate >= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write(key)
+	// h.Sum32() is uniform over uint32, so comparing against a scaled
+	// threshold approximates keeping SampleRate of rows.
+	threshold := uint32(t.SampleRate * float64(^uint32(0)))
+	return h.Sum32() <= threshold
+}
+
+// Transform maps a table name to the anonymization applied to its rows.
+// A table absent from Transform is copied through unchanged.
+type Transform map[string]TableTransform
+
+// WriteAnonymized is Write with per-column value transforms and
+// optional row sampling applied while streaming rows out of database,
+// for producing safe-to-share development fixtures from a production
+// snapshot without ever materializing an untransformed second copy.
+//
+// A table's rows can only be transformed if its TableInfo.Columns was
+// populated at registration (see db.Database.RegisterTable); a table
+// with no column schema is copied through unchanged even if transform
+// names it, since there is no schema to decode its rows against.
+func WriteAnonymized(w io.Writer, database *db.Database, transform Transform) (Stats, error) {
+	out := db.NewMemEngine()
+	shadow := db.NewDatabase(out)
+
+	for _, info := range database.Tables() {
+		shadow.RegisterTable(info)
+		shadow.SetSequence(info.Name, database.SequenceValue(info.Name))
+
+		t, hasTransform := transform[info.Name]
+		if !hasTransform || len(info.Columns) == 0 {
+			if err := copyTable(database.Engine, out, info.Name); err != nil {
+				return Stats{}, fmt.Errorf("backup: copy %q: %w", info.Name, err)
+			}
+			continue
+		}
+		if err := anonymizeTable(database.Engine, out, info, t); err != nil {
+			return Stats{}, fmt.Errorf("backup: anonymize %q: %w", info.Name, err)
+		}
+	}
+	return Write(w, shadow)
+}
+
+// copyTable copies every row of table from src to dst unchanged, for a
+// table WriteAnonymized has no Transform for.
+func copyTable(src db.Engine, dst db.Engine, table string) error {
+	cur, err := src.Scan(table)
+	if err != nil {
+		return fmt.Errorf("scan: %w", err)
+	}
+	defer cur.Close()
+	for cur.Next() {
+		if err := dst.Put(table, cur.Key(), cur.Value()); err != nil {
+			return fmt.Errorf("write row: %w", err)
+		}
+	}
+	return nil
+}
+
+// rowSchema builds a synthetic product schema for a table's columns,
+// naming each field by its index, so bsatn.UnmarshalJSON/MarshalJSON
+// (which require named product elements) can round-trip a row that
+// TableInfo only describes as a flat list of column types.
+func rowSchema(columns []bsatn.AlgebraicType) bsatn.AlgebraicType {
+	elems := make([]bsatn.ProductElement, len(columns))
+	for i, col := range columns {
+		name := strconv.Itoa(i)
+		elems[i] = bsatn.ProductElement{Name: &name, Type: col}
+	}
+	return bsatn.ProductOf(elems...)
+}
+
+// anonymizeTable scans info's rows out of src, applies t's column
+// transforms and sampling, and writes the surviving, transformed rows
+// into dst under the same keys.
+func anonymizeTable(src db.Engine, dst db.Engine, info db.TableInfo, t TableTransform) error {
+	schema := rowSchema(info.Columns)
+
+	cur, err := src.Scan(info.Name)
+	if err != nil {
+		return fmt.Errorf("scan: %w", err)
+	}
+	defer cur.Close()
+
+	for cur.Next() {
+		key, value := cur.Key(), cur.Value()
+		if !t.keep(key) {
+			continue
+		}
+
+		decoded, err := bsatn.UnmarshalJSON(value, schema)
+		if err != nil {
+			return fmt.Errorf("decode row: %w", err)
+		}
+		row, ok := decoded.(map[string]any)
+		if !ok {
+			return fmt.Errorf("decode row: expected product, got %T", decoded)
+		}
+		for i, transform := range t.Columns {
+			name := strconv.Itoa(i)
+			if _, present := row[name]; present {
+				row[name] = transform(row[name])
+			}
+		}
+
+		encoded, err := bsatn.MarshalJSON(row, schema)
+		if err != nil {
+			return fmt.Errorf("encode row: %w", err)
+		}
+		if err := dst.Put(info.Name, key, encoded); err != nil {
+			return fmt.Errorf("write row: %w", err)
+		}
+	}
+	return nil
+}
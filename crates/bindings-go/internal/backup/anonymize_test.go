@@ -0,0 +1,144 @@
+package backup
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func newUsersDatabase(t *testing.T) (*db.Database, bsatn.AlgebraicType) {
+	t.Helper()
+	engine := db.NewMemEngine()
+	database := db.NewDatabase(engine)
+	schema := rowSchema([]bsatn.AlgebraicType{bsatn.String(), bsatn.String()})
+	database.RegisterTable(db.TableInfo{Name: "users", Columns: []bsatn.AlgebraicType{bsatn.String(), bsatn.String()}})
+
+	for i, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		row := map[string]any{"0": "user" + string(rune('a'+i)), "1": email}
+		encoded, err := bsatn.MarshalJSON(row, schema)
+		if err != nil {
+			t.Fatalf("MarshalJSON: %v", err)
+		}
+		if err := engine.Put("users", []byte{byte(i)}, encoded); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+	return database, schema
+}
+
+func decodeRow(t *testing.T, value []byte, schema bsatn.AlgebraicType) map[string]any {
+	t.Helper()
+	decoded, err := bsatn.UnmarshalJSON(value, schema)
+	if err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	row, ok := decoded.(map[string]any)
+	if !ok {
+		t.Fatalf("decoded row is %T, want map[string]any", decoded)
+	}
+	return row
+}
+
+func TestWriteAnonymizedHashesNamedColumn(t *testing.T) {
+	database, schema := newUsersDatabase(t)
+
+	var buf bytes.Buffer
+	if _, err := WriteAnonymized(&buf, database, Transform{
+		"users": {Columns: map[int]ColumnTransform{1: HashString("pepper")}},
+	}); err != nil {
+		t.Fatalf("WriteAnonymized: %v", err)
+	}
+
+	restored, _, err := Read(&buf, db.NewMemEngine())
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	cur, err := restored.Engine.Scan("users")
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	defer cur.Close()
+	count := 0
+	for cur.Next() {
+		row := decodeRow(t, cur.Value(), schema)
+		if row["1"] == "a@example.com" || row["1"] == "b@example.com" || row["1"] == "c@example.com" {
+			t.Fatalf("row %v: email column was not transformed", row)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Fatalf("got %d rows, want 3", count)
+	}
+}
+
+func TestWriteAnonymizedHashIsDeterministic(t *testing.T) {
+	transform := HashString("pepper")
+	if transform("a@example.com") != transform("a@example.com") {
+		t.Fatal("HashString: same input produced different output")
+	}
+	if transform("a@example.com") == transform("b@example.com") {
+		t.Fatal("HashString: different input produced the same output")
+	}
+}
+
+func TestWriteAnonymizedZeroesColumn(t *testing.T) {
+	database, schema := newUsersDatabase(t)
+
+	var buf bytes.Buffer
+	if _, err := WriteAnonymized(&buf, database, Transform{
+		"users": {Columns: map[int]ColumnTransform{1: Zero()}},
+	}); err != nil {
+		t.Fatalf("WriteAnonymized: %v", err)
+	}
+
+	restored, _, _ := Read(&buf, db.NewMemEngine())
+	cur, _ := restored.Engine.Scan("users")
+	defer cur.Close()
+	for cur.Next() {
+		row := decodeRow(t, cur.Value(), schema)
+		if row["1"] != "" {
+			t.Fatalf("row %v: column 1 was not zeroed", row)
+		}
+	}
+}
+
+func TestWriteAnonymizedSampleRateDropsRows(t *testing.T) {
+	database, _ := newUsersDatabase(t)
+
+	var buf bytes.Buffer
+	stats, err := WriteAnonymized(&buf, database, Transform{
+		"users": {SampleRate: 0.0000001},
+	})
+	if err != nil {
+		t.Fatalf("WriteAnonymized: %v", err)
+	}
+	if stats.Rows["users"] >= 3 {
+		t.Fatalf("Rows[users] = %d, want fewer than the source's 3 with a near-zero sample rate", stats.Rows["users"])
+	}
+}
+
+func TestWriteAnonymizedCopiesTableWithoutTransformUnchanged(t *testing.T) {
+	database, schema := newUsersDatabase(t)
+
+	var buf bytes.Buffer
+	if _, err := WriteAnonymized(&buf, database, Transform{}); err != nil {
+		t.Fatalf("WriteAnonymized: %v", err)
+	}
+
+	restored, _, _ := Read(&buf, db.NewMemEngine())
+	cur, _ := restored.Engine.Scan("users")
+	defer cur.Close()
+	seen := 0
+	for cur.Next() {
+		row := decodeRow(t, cur.Value(), schema)
+		if row["1"] != "a@example.com" && row["1"] != "b@example.com" && row["1"] != "c@example.com" {
+			t.Fatalf("row %v: email column changed despite no transform", row)
+		}
+		seen++
+	}
+	if seen != 3 {
+		t.Fatalf("got %d rows, want 3", seen)
+	}
+}
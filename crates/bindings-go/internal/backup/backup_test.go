@@ -0,0 +1,113 @@
+package backup
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	engine := db.NewMemEngine()
+	database := db.NewDatabase(engine)
+	database.RegisterTable(db.TableInfo{Name: "players"})
+	engine.Put("players", []byte("p1"), []byte("alice"))
+	engine.Put("players", []byte("p2"), []byte("bob"))
+	database.SetSequence("players", 2)
+
+	var buf bytes.Buffer
+	wantStats, err := Write(&buf, database)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	restoredEngine := db.NewMemEngine()
+	restoredDB, gotStats, err := Read(&buf, restoredEngine)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if gotStats.SHA256 != wantStats.SHA256 {
+		t.Fatalf("hash mismatch: got %x, want %x", gotStats.SHA256, wantStats.SHA256)
+	}
+	if gotStats.Rows["players"] != 2 {
+		t.Fatalf("Rows[players] = %d, want 2", gotStats.Rows["players"])
+	}
+	if restoredDB.SequenceValue("players") != 2 {
+		t.Fatalf("sequence not restored")
+	}
+	v, ok, _ := restoredEngine.Get("players", []byte("p1"))
+	if !ok || string(v) != "alice" {
+		t.Fatalf("Get(p1) = %q, %v", v, ok)
+	}
+}
+
+// corruptedArchive builds an archive with two tables, then flips a byte
+// inside "players"'s row frames (but outside "scores", which stays
+// intact) so its single chunk (both rows fit in one chunkSize group)
+// fails checksum verification.
+func corruptedArchive(t *testing.T) []byte {
+	t.Helper()
+	engine := db.NewMemEngine()
+	database := db.NewDatabase(engine)
+	database.RegisterTable(db.TableInfo{Name: "players"})
+	database.RegisterTable(db.TableInfo{Name: "scores"})
+	engine.Put("players", []byte("p1"), []byte("alice"))
+	engine.Put("players", []byte("p2"), []byte("bob"))
+	engine.Put("scores", []byte("s1"), []byte("100"))
+
+	var buf bytes.Buffer
+	if _, err := Write(&buf, database); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	archive := buf.Bytes()
+
+	idx := bytes.Index(archive, []byte("alice"))
+	if idx < 0 {
+		t.Fatalf("archive missing expected row content")
+	}
+	archive[idx] ^= 0xFF
+	return archive
+}
+
+func TestReadRejectsCorruptedArchiveByDefault(t *testing.T) {
+	archive := corruptedArchive(t)
+
+	_, _, err := Read(bytes.NewReader(archive), db.NewMemEngine())
+	if err == nil {
+		t.Fatal("expected an error for a corrupted archive")
+	}
+	if !bytes.Contains([]byte(err.Error()), []byte("players: rows 0-1")) {
+		t.Fatalf("error %q does not name the corrupted table/row range", err)
+	}
+}
+
+func TestReadWithOptionsQuarantinesCorruptedChunk(t *testing.T) {
+	archive := corruptedArchive(t)
+
+	restoredEngine := db.NewMemEngine()
+	_, stats, err := ReadWithOptions(bytes.NewReader(archive), restoredEngine, ReadOptions{Quarantine: true})
+	if err != nil {
+		t.Fatalf("ReadWithOptions: %v", err)
+	}
+
+	if len(stats.Corruptions) != 1 {
+		t.Fatalf("Corruptions = %v, want exactly one", stats.Corruptions)
+	}
+	want := Corruption{Table: "players", FirstRow: 0, LastRow: 1}
+	if stats.Corruptions[0] != want {
+		t.Fatalf("Corruptions[0] = %+v, want %+v", stats.Corruptions[0], want)
+	}
+	if stats.Rows["players"] != 0 {
+		t.Fatalf("Rows[players] = %d, want 0 (chunk was quarantined)", stats.Rows["players"])
+	}
+	if stats.Rows["scores"] != 1 {
+		t.Fatalf("Rows[scores] = %d, want 1 (unaffected table)", stats.Rows["scores"])
+	}
+	if _, ok, _ := restoredEngine.Get("players", []byte("p1")); ok {
+		t.Fatal("quarantined row should not have been restored")
+	}
+	v, ok, _ := restoredEngine.Get("scores", []byte("s1"))
+	if !ok || string(v) != "100" {
+		t.Fatalf("Get(s1) = %q, %v", v, ok)
+	}
+}
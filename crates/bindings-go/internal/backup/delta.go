@@ -0,0 +1,241 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+// deltaMagic identifies a delta-snapshot archive: the rows a table
+// gained or changed and the keys it lost since some base archive, not a
+// standalone Write archive (see magic). A delta only makes sense
+// applied on top of the exact base it was computed against; nothing in
+// the format itself checks that, so callers must track which base a
+// chain of deltas belongs to (e.g. by filename convention).
+const deltaMagic = "STDBDLTA\x01"
+
+// WriteDelta writes the rows database's tables gained or changed and
+// the keys they lost since base into w. Both must share the same table
+// set for a table to be diffed; a table present in database but not in
+// base is treated as entirely new (every row is an upsert), and a table
+// present in base but not database is ignored (WriteDelta only tracks
+// per-row deletions, not whole-table drops).
+//
+// Diffing works by content comparison of the two databases' current
+// engine contents, not by tracking writes as they happen, so it costs a
+// full scan of both — the same cost Write already pays, which is the
+// point: this exists to make repeated CI fixture restores cheap, not
+// repeated diffing cheap.
+func WriteDelta(w io.Writer, database, base *db.Database) (Stats, error) {
+	h := sha256.New()
+	mw := io.MultiWriter(w, h)
+
+	if _, err := mw.Write([]byte(deltaMagic)); err != nil {
+		return Stats{}, err
+	}
+	tables := database.Tables()
+	if err := writeU32(mw, uint32(len(tables))); err != nil {
+		return Stats{}, err
+	}
+
+	stats := Stats{Tables: len(tables), Rows: make(map[string]int, len(tables))}
+	for _, info := range tables {
+		baseRows, err := scanRows(base.Engine, info.Name)
+		if err != nil {
+			return Stats{}, fmt.Errorf("backup: scan base %q: %w", info.Name, err)
+		}
+		curRows, err := scanRows(database.Engine, info.Name)
+		if err != nil {
+			return Stats{}, fmt.Errorf("backup: scan %q: %w", info.Name, err)
+		}
+
+		var deletes [][]byte
+		for key := range baseRows {
+			if _, ok := curRows[key]; !ok {
+				deletes = append(deletes, []byte(key))
+			}
+		}
+		var upserts []frame
+		for key, value := range curRows {
+			if old, ok := baseRows[key]; !ok || !bytes.Equal(old, value) {
+				upserts = append(upserts, frame{key: []byte(key), value: value})
+			}
+		}
+
+		encInfo, err := bsatn.Marshal(info)
+		if err != nil {
+			return Stats{}, fmt.Errorf("backup: encode schema for %q: %w", info.Name, err)
+		}
+		if err := writeFrame(mw, encInfo); err != nil {
+			return Stats{}, err
+		}
+		if err := writeU32(mw, uint32(len(deletes))); err != nil {
+			return Stats{}, err
+		}
+		for _, key := range deletes {
+			if err := writeFrame(mw, key); err != nil {
+				return Stats{}, err
+			}
+		}
+		if err := writeU32(mw, uint32(len(upserts))); err != nil {
+			return Stats{}, err
+		}
+		if err := writeChunkChecksums(mw, upserts); err != nil {
+			return Stats{}, err
+		}
+		for _, r := range upserts {
+			if err := writeFrame(mw, r.key); err != nil {
+				return Stats{}, err
+			}
+			if err := writeFrame(mw, r.value); err != nil {
+				return Stats{}, err
+			}
+		}
+		stats.Rows[info.Name] = len(upserts) + len(deletes)
+	}
+
+	copy(stats.SHA256[:], h.Sum(nil))
+	return stats, nil
+}
+
+// ApplyDelta applies a delta written by WriteDelta to database in
+// place, registering any table the delta introduces, deleting the keys
+// it dropped and upserting the rows it changed, subject to the same
+// chunk-checksum verification and ReadOptions.Quarantine as
+// ReadWithOptions.
+func ApplyDelta(r io.Reader, database *db.Database, opts ReadOptions) (Stats, error) {
+	h := sha256.New()
+	tr := io.TeeReader(r, h)
+
+	var gotMagic [len(deltaMagic)]byte
+	if _, err := io.ReadFull(tr, gotMagic[:]); err != nil {
+		return Stats{}, fmt.Errorf("restore: read delta magic: %w", err)
+	}
+	if string(gotMagic[:]) != deltaMagic {
+		return Stats{}, fmt.Errorf("restore: not a spacetimedb delta archive (or an incompatible version)")
+	}
+
+	numTables, err := readU32(tr)
+	if err != nil {
+		return Stats{}, err
+	}
+	stats := Stats{Tables: int(numTables), Rows: make(map[string]int, numTables)}
+
+	for i := uint32(0); i < numTables; i++ {
+		encInfo, err := readFrame(tr)
+		if err != nil {
+			return Stats{}, err
+		}
+		var info db.TableInfo
+		if err := bsatn.Unmarshal(encInfo, &info); err != nil {
+			return Stats{}, fmt.Errorf("restore: decode schema: %w", err)
+		}
+		database.RegisterTable(info)
+
+		numDeletes, err := readU32(tr)
+		if err != nil {
+			return Stats{}, err
+		}
+		for j := uint32(0); j < numDeletes; j++ {
+			key, err := readFrame(tr)
+			if err != nil {
+				return Stats{}, err
+			}
+			if err := database.Engine.Delete(info.Name, key); err != nil {
+				return Stats{}, err
+			}
+		}
+
+		numUpserts, err := readU32(tr)
+		if err != nil {
+			return Stats{}, err
+		}
+		checksums, err := readChunkChecksums(tr, int(numUpserts))
+		if err != nil {
+			return Stats{}, err
+		}
+		restored, corruptions, err := restoreRows(tr, database.Engine, info.Name, int(numUpserts), checksums, opts)
+		if err != nil {
+			return Stats{}, err
+		}
+		stats.Corruptions = append(stats.Corruptions, corruptions...)
+		stats.Rows[info.Name] = restored + int(numDeletes)
+	}
+
+	copy(stats.SHA256[:], h.Sum(nil))
+	return stats, nil
+}
+
+// ApplyChain restores base into engine, then applies deltas in order
+// (each one produced by WriteDelta against the state left by the
+// previous archive in the chain), returning the resulting database and
+// the accumulated Stats of every archive applied.
+func ApplyChain(base io.Reader, deltas []io.Reader, engine db.Engine, opts ReadOptions) (*db.Database, Stats, error) {
+	database, stats, err := ReadWithOptions(base, engine, opts)
+	if err != nil {
+		return nil, Stats{}, fmt.Errorf("restore: base: %w", err)
+	}
+	for i, delta := range deltas {
+		deltaStats, err := ApplyDelta(delta, database, opts)
+		if err != nil {
+			return nil, Stats{}, fmt.Errorf("restore: delta %d: %w", i, err)
+		}
+		stats.Corruptions = append(stats.Corruptions, deltaStats.Corruptions...)
+		for table, n := range deltaStats.Rows {
+			stats.Rows[table] += n
+		}
+	}
+	return database, stats, nil
+}
+
+// CompactChain collapses base plus a chain of deltas into a single
+// delta with the same net effect, so a long-running fixture's delta
+// chain doesn't have to be replayed link by link on every restore. It
+// works by actually applying the whole chain to a scratch engine and
+// then re-diffing the result against base, rather than merging the
+// deltas' upsert/delete lists directly, so it automatically gets
+// later-wins-on-conflict and delete-then-recreate semantics right for
+// free.
+func CompactChain(base []byte, deltas [][]byte) ([]byte, Stats, error) {
+	baseDB, _, err := Read(bytes.NewReader(base), db.NewMemEngine())
+	if err != nil {
+		return nil, Stats{}, fmt.Errorf("compact: base: %w", err)
+	}
+
+	deltaReaders := make([]io.Reader, len(deltas))
+	for i, d := range deltas {
+		deltaReaders[i] = bytes.NewReader(d)
+	}
+	finalDB, _, err := ApplyChain(bytes.NewReader(base), deltaReaders, db.NewMemEngine(), ReadOptions{})
+	if err != nil {
+		return nil, Stats{}, fmt.Errorf("compact: chain: %w", err)
+	}
+
+	var buf bytes.Buffer
+	stats, err := WriteDelta(&buf, finalDB, baseDB)
+	if err != nil {
+		return nil, Stats{}, fmt.Errorf("compact: write: %w", err)
+	}
+	return buf.Bytes(), stats, nil
+}
+
+// scanRows reads every row of table in engine into a map keyed by its
+// raw key bytes (as a string, so it can key a Go map); it exists for
+// WriteDelta's before/after comparison.
+func scanRows(engine db.Engine, table string) (map[string][]byte, error) {
+	cur, err := engine.Scan(table)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close()
+
+	rows := make(map[string][]byte)
+	for cur.Next() {
+		rows[string(cur.Key())] = append([]byte(nil), cur.Value()...)
+	}
+	return rows, nil
+}
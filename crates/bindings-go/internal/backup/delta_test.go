@@ -0,0 +1,131 @@
+package backup
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func seedPlayers(engine db.Engine) *db.Database {
+	database := db.NewDatabase(engine)
+	database.RegisterTable(db.TableInfo{Name: "players"})
+	engine.Put("players", []byte("p1"), []byte("alice"))
+	engine.Put("players", []byte("p2"), []byte("bob"))
+	return database
+}
+
+func TestWriteDeltaCapturesUpsertsAndDeletes(t *testing.T) {
+	baseEngine := db.NewMemEngine()
+	base := seedPlayers(baseEngine)
+
+	curEngine := db.NewMemEngine()
+	current := seedPlayers(curEngine)
+	curEngine.Put("players", []byte("p1"), []byte("alice2")) // changed
+	curEngine.Delete("players", []byte("p2"))                // deleted
+	curEngine.Put("players", []byte("p3"), []byte("carol"))  // new
+
+	var buf bytes.Buffer
+	stats, err := WriteDelta(&buf, current, base)
+	if err != nil {
+		t.Fatalf("WriteDelta: %v", err)
+	}
+	if stats.Rows["players"] != 3 {
+		t.Fatalf("Rows[players] = %d, want 3 (2 upserts + 1 delete)", stats.Rows["players"])
+	}
+
+	target := seedPlayers(db.NewMemEngine())
+	if _, err := ApplyDelta(&buf, target, ReadOptions{}); err != nil {
+		t.Fatalf("ApplyDelta: %v", err)
+	}
+	if v, ok, _ := target.Engine.Get("players", []byte("p1")); !ok || string(v) != "alice2" {
+		t.Fatalf("Get(p1) = %q, %v", v, ok)
+	}
+	if _, ok, _ := target.Engine.Get("players", []byte("p2")); ok {
+		t.Fatal("p2 should have been deleted")
+	}
+	if v, ok, _ := target.Engine.Get("players", []byte("p3")); !ok || string(v) != "carol" {
+		t.Fatalf("Get(p3) = %q, %v", v, ok)
+	}
+}
+
+func TestApplyChainRestoresBasePlusDeltas(t *testing.T) {
+	var baseArchive bytes.Buffer
+	base := seedPlayers(db.NewMemEngine())
+	if _, err := Write(&baseArchive, base); err != nil {
+		t.Fatalf("Write base: %v", err)
+	}
+
+	afterEdit := seedPlayers(db.NewMemEngine())
+	afterEdit.Engine.Put("players", []byte("p1"), []byte("alice2"))
+	var delta1 bytes.Buffer
+	if _, err := WriteDelta(&delta1, afterEdit, base); err != nil {
+		t.Fatalf("WriteDelta 1: %v", err)
+	}
+
+	afterAdd := seedPlayers(db.NewMemEngine())
+	afterAdd.Engine.Put("players", []byte("p1"), []byte("alice2"))
+	afterAdd.Engine.Put("players", []byte("p3"), []byte("carol"))
+	var delta2 bytes.Buffer
+	if _, err := WriteDelta(&delta2, afterAdd, afterEdit); err != nil {
+		t.Fatalf("WriteDelta 2: %v", err)
+	}
+
+	restored, stats, err := ApplyChain(&baseArchive, []io.Reader{&delta1, &delta2}, db.NewMemEngine(), ReadOptions{})
+	if err != nil {
+		t.Fatalf("ApplyChain: %v", err)
+	}
+	if v, ok, _ := restored.Engine.Get("players", []byte("p1")); !ok || string(v) != "alice2" {
+		t.Fatalf("Get(p1) = %q, %v", v, ok)
+	}
+	if v, ok, _ := restored.Engine.Get("players", []byte("p3")); !ok || string(v) != "carol" {
+		t.Fatalf("Get(p3) = %q, %v", v, ok)
+	}
+	if stats.Tables != 1 {
+		t.Fatalf("Tables = %d, want 1", stats.Tables)
+	}
+}
+
+func TestCompactChainProducesEquivalentSingleDelta(t *testing.T) {
+	var baseArchive bytes.Buffer
+	base := seedPlayers(db.NewMemEngine())
+	if _, err := Write(&baseArchive, base); err != nil {
+		t.Fatalf("Write base: %v", err)
+	}
+	baseBytes := baseArchive.Bytes()
+
+	afterEdit := seedPlayers(db.NewMemEngine())
+	afterEdit.Engine.Delete("players", []byte("p2"))
+	var delta1 bytes.Buffer
+	if _, err := WriteDelta(&delta1, afterEdit, base); err != nil {
+		t.Fatalf("WriteDelta 1: %v", err)
+	}
+
+	afterAdd := seedPlayers(db.NewMemEngine())
+	afterAdd.Engine.Delete("players", []byte("p2"))
+	afterAdd.Engine.Put("players", []byte("p3"), []byte("carol"))
+	var delta2 bytes.Buffer
+	if _, err := WriteDelta(&delta2, afterAdd, afterEdit); err != nil {
+		t.Fatalf("WriteDelta 2: %v", err)
+	}
+
+	compacted, _, err := CompactChain(baseBytes, [][]byte{delta1.Bytes(), delta2.Bytes()})
+	if err != nil {
+		t.Fatalf("CompactChain: %v", err)
+	}
+
+	restored, _, err := ApplyChain(bytes.NewReader(baseBytes), []io.Reader{bytes.NewReader(compacted)}, db.NewMemEngine(), ReadOptions{})
+	if err != nil {
+		t.Fatalf("ApplyChain with compacted delta: %v", err)
+	}
+	if _, ok, _ := restored.Engine.Get("players", []byte("p2")); ok {
+		t.Fatal("p2 should still be deleted after compaction")
+	}
+	if v, ok, _ := restored.Engine.Get("players", []byte("p3")); !ok || string(v) != "carol" {
+		t.Fatalf("Get(p3) = %q, %v", v, ok)
+	}
+	if v, ok, _ := restored.Engine.Get("players", []byte("p1")); !ok || string(v) != "alice" {
+		t.Fatalf("Get(p1) = %q, %v", v, ok)
+	}
+}
@@ -0,0 +1,227 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// EnumDef describes one SATS sum type to generate a sealed Go interface
+// and Match helper for.
+type EnumDef struct {
+	// Name is the Go interface name generated for the sum type, e.g.
+	// "PlayerStatus". Each variant's struct type is named Name plus the
+	// variant's title-cased name, e.g. "PlayerStatusBanned".
+	Name string
+	// Type is the sum's AlgebraicType; Type.Kind must be bsatn.KindSum.
+	Type bsatn.AlgebraicType
+}
+
+const enumTemplate = `// Code generated by spacetimedb-gen from a sum type; DO NOT EDIT.
+
+package {{.Package}}
+
+import "fmt"
+
+// {{.Name}} is the sealed interface generated for the {{.Name}} sum
+// type. Only the variant types below implement it, so the compiler
+// (via Match{{.Name}}) is the only way code outside this package
+// constructs or inspects one.
+type {{.Name}} interface {
+	is{{.Name}}()
+}
+{{range .Variants}}
+// {{.StructName}} is the "{{.VariantName}}" variant of {{$.Name}}.
+type {{.StructName}} struct {
+{{range .Fields}}	{{.Name}} {{.Type}}
+{{end}}}
+
+func ({{.StructName}}) is{{$.Name}}() {}
+{{end}}
+// Match{{.Name}} dispatches v to whichever callback matches its
+// variant. Every variant requires a callback, so adding a new variant
+// to {{.Name}} breaks every existing Match{{.Name}} call site at
+// compile time instead of silently falling through.
+func Match{{.Name}}(v {{.Name}}, {{.CallbackParams}}) {
+	switch v := v.(type) {
+{{range .Variants}}	case {{.StructName}}:
+		{{.CallbackName}}(v)
+{{end}}	default:
+		panic(fmt.Sprintf("codegen: unhandled {{.Name}} variant %T", v))
+	}
+}
+`
+
+type enumField struct {
+	Name string
+	Type string
+}
+
+type enumVariant struct {
+	VariantName  string
+	StructName   string
+	CallbackName string
+	Fields       []enumField
+}
+
+type enumTemplateData struct {
+	Package        string
+	Name           string
+	Variants       []enumVariant
+	CallbackParams string
+}
+
+// GenerateEnum renders a Go source file, in package pkg, declaring a
+// sealed interface and Match helper for def, as described by
+// EnumDef.Name's doc comment. def.Type.Kind must be bsatn.KindSum.
+//
+// A KindProduct variant becomes a struct with one field per product
+// element (named by elemName, so unnamed elements fall back to
+// "_<index>"); any other variant kind becomes a struct with a single
+// Value field of the corresponding Go type. A variant whose type is
+// itself bsatn.KindSum is rejected: nested sum types have no natural
+// single Go field to hold them without collapsing the nested tag, so
+// callers should flatten or name the nested sum as its own EnumDef
+// first.
+func GenerateEnum(pkg string, def EnumDef) ([]byte, error) {
+	if pkg == "" {
+		return nil, fmt.Errorf("codegen: package name is empty")
+	}
+	if def.Name == "" {
+		return nil, fmt.Errorf("codegen: enum name is empty")
+	}
+	if def.Type.Kind != bsatn.KindSum {
+		return nil, fmt.Errorf("codegen: enum %q: Type must be a sum, got %s", def.Name, def.Type.Kind)
+	}
+
+	data := enumTemplateData{Package: pkg, Name: def.Name}
+	callbackParams := make([]string, 0, len(def.Type.Sum))
+	for i, variant := range def.Type.Sum {
+		name := elemName(variant.Name, i)
+		v := enumVariant{
+			VariantName:  name,
+			StructName:   def.Name + pascalCase(name),
+			CallbackName: "on" + pascalCase(name),
+		}
+		fields, err := enumVariantFields(v.StructName, variant.Type)
+		if err != nil {
+			return nil, fmt.Errorf("codegen: enum %q: variant %q: %w", def.Name, name, err)
+		}
+		v.Fields = fields
+		data.Variants = append(data.Variants, v)
+		callbackParams = append(callbackParams, fmt.Sprintf("%s func(%s)", v.CallbackName, v.StructName))
+	}
+	data.CallbackParams = strings.Join(callbackParams, ", ")
+
+	tmpl, err := template.New("enum").Parse(enumTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("codegen: parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("codegen: execute template: %w", err)
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: format generated source: %w", err)
+	}
+	return out, nil
+}
+
+// enumVariantFields derives a variant's struct fields from its
+// AlgebraicType: a product becomes one field per element, an empty
+// product becomes a fieldless (unit) struct, and anything else becomes
+// a single Value field.
+func enumVariantFields(structName string, t bsatn.AlgebraicType) ([]enumField, error) {
+	if t.Kind == bsatn.KindProduct {
+		fields := make([]enumField, 0, len(t.Product))
+		for i, elem := range t.Product {
+			goType, err := algebraicTypeToGoType(elem.Type)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, enumField{Name: pascalCase(elemName(elem.Name, i)), Type: goType})
+		}
+		return fields, nil
+	}
+	goType, err := algebraicTypeToGoType(t)
+	if err != nil {
+		return nil, err
+	}
+	return []enumField{{Name: "Value", Type: goType}}, nil
+}
+
+// algebraicTypeToGoType maps t to the Go type codegen uses to hold it,
+// mirroring algebraicTypeToJSONSchema's switch but producing a Go type
+// literal instead of a JSON Schema fragment. bsatn.KindI128/KindU128
+// map to [16]byte: no wider native Go integer exists, and nothing else
+// in this tree (see internal/db/typed.go's wire-level skip helper)
+// uses a richer representation either.
+func algebraicTypeToGoType(t bsatn.AlgebraicType) (string, error) {
+	switch t.Kind {
+	case bsatn.KindBool:
+		return "bool", nil
+	case bsatn.KindI8:
+		return "int8", nil
+	case bsatn.KindU8:
+		return "uint8", nil
+	case bsatn.KindI16:
+		return "int16", nil
+	case bsatn.KindU16:
+		return "uint16", nil
+	case bsatn.KindI32:
+		return "int32", nil
+	case bsatn.KindU32:
+		return "uint32", nil
+	case bsatn.KindI64:
+		return "int64", nil
+	case bsatn.KindU64:
+		return "uint64", nil
+	case bsatn.KindI128, bsatn.KindU128:
+		return "[16]byte", nil
+	case bsatn.KindF32:
+		return "float32", nil
+	case bsatn.KindF64:
+		return "float64", nil
+	case bsatn.KindString:
+		return "string", nil
+	case bsatn.KindArray:
+		if t.Array.Kind == bsatn.KindU8 {
+			return "[]byte", nil
+		}
+		elem, err := algebraicTypeToGoType(*t.Array)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case bsatn.KindMap:
+		key, err := algebraicTypeToGoType(*t.MapKey)
+		if err != nil {
+			return "", err
+		}
+		value, err := algebraicTypeToGoType(*t.MapValue)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("map[%s]%s", key, value), nil
+	case bsatn.KindProduct:
+		fields := make([]string, 0, len(t.Product))
+		for i, elem := range t.Product {
+			fieldType, err := algebraicTypeToGoType(elem.Type)
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, fmt.Sprintf("%s %s", pascalCase(elemName(elem.Name, i)), fieldType))
+		}
+		return fmt.Sprintf("struct{ %s }", strings.Join(fields, "; ")), nil
+	case bsatn.KindSum:
+		return "", fmt.Errorf("nested sum types are not supported; give it its own EnumDef")
+	default:
+		return "", fmt.Errorf("unsupported AlgebraicTypeKind %s", t.Kind)
+	}
+}
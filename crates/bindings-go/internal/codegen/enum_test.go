@@ -0,0 +1,97 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func TestGenerateEnumTwoVariants(t *testing.T) {
+	name := "name"
+	banned := "banned"
+	active := "active"
+	def := EnumDef{
+		Name: "PlayerStatus",
+		Type: bsatn.SumOf(
+			bsatn.SumVariant{Name: &banned, Type: bsatn.ProductOf(bsatn.ProductElement{Name: &name, Type: bsatn.String()})},
+			bsatn.SumVariant{Name: &active, Type: bsatn.ProductOf()},
+		),
+	}
+	out, err := GenerateEnum("model", def)
+	if err != nil {
+		t.Fatalf("GenerateEnum: %v", err)
+	}
+	src := string(out)
+	for _, want := range []string{
+		"type PlayerStatus interface",
+		"type PlayerStatusBanned struct",
+		"Name string",
+		"func (PlayerStatusBanned) isPlayerStatus() {}",
+		"type PlayerStatusActive struct",
+		"func MatchPlayerStatus(",
+		"case PlayerStatusBanned:",
+		"case PlayerStatusActive:",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated source missing %q\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateEnumUnitVariantHasNoFields(t *testing.T) {
+	active := "active"
+	def := EnumDef{Name: "Status", Type: bsatn.SumOf(bsatn.SumVariant{Name: &active, Type: bsatn.ProductOf()})}
+	out, err := GenerateEnum("model", def)
+	if err != nil {
+		t.Fatalf("GenerateEnum: %v", err)
+	}
+	if !strings.Contains(string(out), "type StatusActive struct {\n}") {
+		t.Errorf("expected a fieldless StatusActive struct, got:\n%s", out)
+	}
+}
+
+func TestGenerateEnumNonProductVariantGetsValueField(t *testing.T) {
+	count := "count"
+	def := EnumDef{Name: "Metric", Type: bsatn.SumOf(bsatn.SumVariant{Name: &count, Type: bsatn.U32()})}
+	out, err := GenerateEnum("model", def)
+	if err != nil {
+		t.Fatalf("GenerateEnum: %v", err)
+	}
+	if !strings.Contains(string(out), "Value uint32") {
+		t.Errorf("expected a Value uint32 field, got:\n%s", out)
+	}
+}
+
+func TestGenerateEnumRejectsNestedSum(t *testing.T) {
+	inner := "inner"
+	outer := "outer"
+	def := EnumDef{Name: "Outer", Type: bsatn.SumOf(bsatn.SumVariant{
+		Name: &outer,
+		Type: bsatn.SumOf(bsatn.SumVariant{Name: &inner, Type: bsatn.Bool()}),
+	})}
+	if _, err := GenerateEnum("model", def); err == nil {
+		t.Fatal("expected an error for a nested sum variant")
+	}
+}
+
+func TestGenerateEnumRejectsNonSumType(t *testing.T) {
+	def := EnumDef{Name: "NotAnEnum", Type: bsatn.String()}
+	if _, err := GenerateEnum("model", def); err == nil {
+		t.Fatal("expected an error for a non-sum Type")
+	}
+}
+
+func TestGenerateEnumRejectsEmptyPackage(t *testing.T) {
+	def := EnumDef{Name: "Status", Type: bsatn.SumOf()}
+	if _, err := GenerateEnum("", def); err == nil {
+		t.Fatal("expected an error for an empty package name")
+	}
+}
+
+func TestGenerateEnumRejectsEmptyName(t *testing.T) {
+	def := EnumDef{Type: bsatn.SumOf()}
+	if _, err := GenerateEnum("model", def); err == nil {
+		t.Fatal("expected an error for an empty enum name")
+	}
+}
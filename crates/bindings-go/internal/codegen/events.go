@@ -0,0 +1,144 @@
+// Package codegen generates Go source from a module's table schema. It
+// currently covers one case: typed subscriber wrappers for append-only
+// "event" tables, so callers write OnPlayerScored(func(evt PlayerScored))
+// instead of registering a raw Client.OnInsert callback and decoding
+// BSATN by hand.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// EventTableSuffix is the naming convention that marks a table as an
+// event stream: a table named "player_scored_event" is exposed as a
+// typed PlayerScored subscriber.
+const EventTableSuffix = "_event"
+
+// IsEventTable reports whether name follows the event-table naming
+// convention.
+func IsEventTable(name string) bool {
+	return strings.HasSuffix(name, EventTableSuffix)
+}
+
+// EventTable describes one event table to generate a subscriber for.
+type EventTable struct {
+	// TableName is the module-side table name, e.g. "player_scored_event".
+	TableName string
+	// GoType is the already-generated row struct type for TableName,
+	// e.g. "PlayerScored", produced by the row-type codegen this
+	// package assumes runs separately.
+	GoType string
+}
+
+// eventMethodName derives the "On<GoType>" subscriber method name.
+func eventMethodName(t EventTable) string {
+	return "On" + t.GoType
+}
+
+const eventTemplate = `// Code generated by spacetimedb-gen from event tables; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/spacetimedb"
+)
+{{range .Tables}}
+// {{.MethodName}} subscribes fn to be called every time a row is
+// inserted into the "{{.TableName}}" table, decoded into a {{.GoType}}.
+func {{.MethodName}}(c spacetimedb.Client, fn func(evt {{.GoType}})) {
+	c.OnInsert("{{.TableName}}", func(row []byte) {
+		var evt {{.GoType}}
+		if err := bsatn.Unmarshal(row, &evt); err != nil {
+			return
+		}
+		fn(evt)
+	})
+}
+{{end}}`
+
+type templateTable struct {
+	TableName  string
+	GoType     string
+	MethodName string
+}
+
+type templateData struct {
+	Package string
+	Tables  []templateTable
+}
+
+// GenerateEventEmitters renders a Go source file, in package pkg,
+// declaring one On<GoType> subscriber function per table in tables. The
+// output imports pkg/bsatn and pkg/spacetimedb, so it must live in a
+// module that depends on both. Tables not matching IsEventTable are
+// still emitted verbatim: the naming convention decides discovery, not
+// what codegen is willing to handle.
+func GenerateEventEmitters(pkg string, tables []EventTable) ([]byte, error) {
+	if pkg == "" {
+		return nil, fmt.Errorf("codegen: package name is empty")
+	}
+	data := templateData{Package: pkg}
+	for _, t := range tables {
+		if t.GoType == "" {
+			return nil, fmt.Errorf("codegen: event table %q has no GoType", t.TableName)
+		}
+		data.Tables = append(data.Tables, templateTable{
+			TableName:  t.TableName,
+			GoType:     t.GoType,
+			MethodName: eventMethodName(t),
+		})
+	}
+
+	tmpl, err := template.New("events").Parse(eventTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("codegen: parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("codegen: execute template: %w", err)
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: format generated source: %w", err)
+	}
+	return out, nil
+}
+
+// AutoEventTables filters tableNames down to those matching the event
+// naming convention and derives a GoType for each by stripping the
+// "_event" suffix and title-casing the remaining underscore-separated
+// words, e.g. "player_scored_event" -> "PlayerScored".
+func AutoEventTables(tableNames []string) []EventTable {
+	var out []EventTable
+	for _, name := range tableNames {
+		if !IsEventTable(name) {
+			continue
+		}
+		base := strings.TrimSuffix(name, EventTableSuffix)
+		out = append(out, EventTable{TableName: name, GoType: pascalCase(base)})
+	}
+	return out
+}
+
+// pascalCase title-cases each underscore-separated word in s, for
+// deriving a GoType from a table name when the caller has not already
+// generated one, e.g. "player_scored_event" -> "PlayerScoredEvent".
+func pascalCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i, p := range parts {
+		if p == "" {
+			continue
+		}
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		parts[i] = string(r)
+	}
+	return strings.Join(parts, "")
+}
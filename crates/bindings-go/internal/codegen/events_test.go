@@ -0,0 +1,35 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAutoEventTablesDerivesGoType(t *testing.T) {
+	tables := AutoEventTables([]string{"player_scored_event", "accounts"})
+	if len(tables) != 1 {
+		t.Fatalf("AutoEventTables = %v, want 1 entry", tables)
+	}
+	if tables[0].GoType != "PlayerScored" {
+		t.Fatalf("GoType = %q, want PlayerScored", tables[0].GoType)
+	}
+}
+
+func TestGenerateEventEmittersProducesSubscriber(t *testing.T) {
+	src, err := GenerateEventEmitters("module", []EventTable{
+		{TableName: "player_scored_event", GoType: "PlayerScored"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateEventEmitters: %v", err)
+	}
+	if !strings.Contains(string(src), "func OnPlayerScored(c spacetimedb.Client, fn func(evt PlayerScored))") {
+		t.Fatalf("generated source missing subscriber func: %s", src)
+	}
+}
+
+func TestGenerateEventEmittersRejectsMissingGoType(t *testing.T) {
+	_, err := GenerateEventEmitters("module", []EventTable{{TableName: "foo_event"}})
+	if err == nil {
+		t.Fatal("expected error for missing GoType")
+	}
+}
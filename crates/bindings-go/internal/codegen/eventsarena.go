@@ -0,0 +1,73 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"text/template"
+)
+
+const eventArenaTemplate = `// Code generated by spacetimedb-gen from event tables; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/spacetimedb"
+)
+{{range .Tables}}
+// {{.MethodName}} subscribes fn to be called every time a row is
+// inserted into the "{{.TableName}}" table, decoded into a {{.GoType}}
+// through dec. Passing the same *spacetimedb.BatchDecoder to every
+// On<Type> registration for a batch of rows (e.g. one db.ChangeSet) and
+// calling dec.Release once every row's fn has returned lets a
+// high-churn subscription reuse one buffer for the batch's decoded
+// strings and []byte fields instead of allocating one per field per
+// row (see BatchDecoder's doc comment).
+func {{.MethodName}}(c spacetimedb.Client, dec *spacetimedb.BatchDecoder, fn func(evt {{.GoType}})) {
+	c.OnInsert("{{.TableName}}", func(row []byte) {
+		var evt {{.GoType}}
+		if err := dec.Decode(row, &evt); err != nil {
+			return
+		}
+		fn(evt)
+	})
+}
+{{end}}`
+
+// GenerateEventEmittersArena renders a Go source file like
+// GenerateEventEmitters, except each generated On<GoType> subscriber
+// takes a *spacetimedb.BatchDecoder and decodes through it instead of
+// calling bsatn.Unmarshal directly — the arena-reuse mode described on
+// BatchDecoder, for callers whose subscriptions see enough row churn
+// that per-row allocation shows up in GC pauses.
+func GenerateEventEmittersArena(pkg string, tables []EventTable) ([]byte, error) {
+	if pkg == "" {
+		return nil, fmt.Errorf("codegen: package name is empty")
+	}
+	data := templateData{Package: pkg}
+	for _, t := range tables {
+		if t.GoType == "" {
+			return nil, fmt.Errorf("codegen: event table %q has no GoType", t.TableName)
+		}
+		data.Tables = append(data.Tables, templateTable{
+			TableName:  t.TableName,
+			GoType:     t.GoType,
+			MethodName: eventMethodName(t),
+		})
+	}
+
+	tmpl, err := template.New("eventsarena").Parse(eventArenaTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("codegen: parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("codegen: execute template: %w", err)
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: format generated source: %w", err)
+	}
+	return out, nil
+}
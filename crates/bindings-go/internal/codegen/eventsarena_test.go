@@ -0,0 +1,36 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateEventEmittersArenaProducesSubscriber(t *testing.T) {
+	src, err := GenerateEventEmittersArena("module", []EventTable{
+		{TableName: "player_scored_event", GoType: "PlayerScored"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateEventEmittersArena: %v", err)
+	}
+	got := string(src)
+	if !strings.Contains(got, "func OnPlayerScored(c spacetimedb.Client, dec *spacetimedb.BatchDecoder, fn func(evt PlayerScored))") {
+		t.Fatalf("generated source missing arena subscriber func: %s", got)
+	}
+	if !strings.Contains(got, "dec.Decode(row, &evt)") {
+		t.Fatalf("generated source does not decode through the batch decoder: %s", got)
+	}
+}
+
+func TestGenerateEventEmittersArenaRejectsMissingGoType(t *testing.T) {
+	_, err := GenerateEventEmittersArena("module", []EventTable{{TableName: "foo_event"}})
+	if err == nil {
+		t.Fatal("expected error for missing GoType")
+	}
+}
+
+func TestGenerateEventEmittersArenaRejectsEmptyPackage(t *testing.T) {
+	_, err := GenerateEventEmittersArena("", []EventTable{{TableName: "foo_event", GoType: "Foo"}})
+	if err == nil {
+		t.Fatal("expected error for empty package name")
+	}
+}
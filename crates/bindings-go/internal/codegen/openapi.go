@@ -0,0 +1,155 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// ReducerDef describes one reducer's callable HTTP shape: its name and
+// argument schema, enough to generate an OpenAPI operation for it. Args
+// must be a KindProduct AlgebraicType, since a reducer's HTTP call body
+// is a JSON object keyed by argument name.
+type ReducerDef struct {
+	Name string
+	Args bsatn.AlgebraicType
+}
+
+// ModuleDef is the minimal reducer manifest GenerateOpenAPI needs. This
+// tree has no wasm __describe_module__ parser yet (see internal/wasm's
+// planned reducer-lifecycle work), so nothing produces a ModuleDef from
+// a running module automatically; a caller assembles one by hand from
+// its own module's reducer signatures until that lands.
+type ModuleDef struct {
+	// Module is the database name reducers are called against, used to
+	// build each operation's path.
+	Module   string
+	Reducers []ReducerDef
+}
+
+// GenerateOpenAPI renders def as an OpenAPI 3.0 document (JSON, not
+// YAML) describing one POST operation per reducer at
+// /database/{module}/call/{reducer name}, with a request body schema
+// derived from the reducer's argument AlgebraicType and a bearer-token
+// security requirement matching how a SpacetimeDB client authenticates
+// (see internal/protocol.IdentityToken).
+func GenerateOpenAPI(def ModuleDef) ([]byte, error) {
+	paths := make(map[string]any, len(def.Reducers))
+	for _, r := range def.Reducers {
+		if r.Args.Kind != bsatn.KindProduct {
+			return nil, fmt.Errorf("codegen: reducer %q: Args must be a product, got %s", r.Name, r.Args.Kind)
+		}
+		path := fmt.Sprintf("/database/%s/call/%s", def.Module, r.Name)
+		paths[path] = map[string]any{
+			"post": map[string]any{
+				"operationId": r.Name,
+				"summary":     fmt.Sprintf("Call reducer %s", r.Name),
+				"security":    []any{map[string]any{"bearerAuth": []string{}}},
+				"requestBody": map[string]any{
+					"required": true,
+					"content": map[string]any{
+						"application/json": map[string]any{
+							"schema": algebraicTypeToJSONSchema(r.Args),
+						},
+					},
+				},
+				"responses": map[string]any{
+					"200": map[string]any{"description": "the reducer ran successfully"},
+					"400": map[string]any{"description": "the reducer's arguments failed to validate"},
+					"401": map[string]any{"description": "the caller's identity token was missing or invalid"},
+				},
+			},
+		}
+	}
+
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   def.Module,
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{
+					"type":   "http",
+					"scheme": "bearer",
+				},
+			},
+		},
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// algebraicTypeToJSONSchema renders t as a JSON Schema (draft-07-ish,
+// the subset OpenAPI 3.0 accepts inline) fragment describing the JSON
+// value bsatn.MarshalJSON/UnmarshalJSON would encode/decode for t — the
+// same `any` shape a reducer's HTTP call body must match.
+func algebraicTypeToJSONSchema(t bsatn.AlgebraicType) map[string]any {
+	switch t.Kind {
+	case bsatn.KindBool:
+		return map[string]any{"type": "boolean"}
+	case bsatn.KindI8, bsatn.KindU8, bsatn.KindI16, bsatn.KindU16,
+		bsatn.KindI32, bsatn.KindU32, bsatn.KindI64, bsatn.KindU64,
+		bsatn.KindI128, bsatn.KindU128:
+		return map[string]any{"type": "integer"}
+	case bsatn.KindF32, bsatn.KindF64:
+		return map[string]any{"type": "number"}
+	case bsatn.KindString:
+		return map[string]any{"type": "string"}
+	case bsatn.KindArray:
+		return map[string]any{
+			"type":  "array",
+			"items": algebraicTypeToJSONSchema(*t.Array),
+		}
+	case bsatn.KindMap:
+		// JSON objects only take string keys; a non-string MapKey has no
+		// faithful JSON Schema representation, so this documents the
+		// value shape and leaves keys unconstrained.
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": algebraicTypeToJSONSchema(*t.MapValue),
+		}
+	case bsatn.KindProduct:
+		properties := make(map[string]any, len(t.Product))
+		required := make([]string, 0, len(t.Product))
+		for i, elem := range t.Product {
+			name := elemName(elem.Name, i)
+			properties[name] = algebraicTypeToJSONSchema(elem.Type)
+			required = append(required, name)
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	case bsatn.KindSum:
+		// Mirrors the {"tag": "<variant>", "value": <payload>} shape
+		// bsatn.MarshalJSON/UnmarshalJSON use for sums (see
+		// pkg/bsatn/fromjson.go).
+		variants := make([]any, len(t.Sum))
+		for i, v := range t.Sum {
+			variants[i] = elemName(v.Name, i)
+		}
+		return map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"tag":   map[string]any{"type": "string", "enum": variants},
+				"value": map[string]any{},
+			},
+			"required":             []string{"tag", "value"},
+			"additionalProperties": false,
+		}
+	default:
+		return map[string]any{}
+	}
+}
+
+func elemName(name *string, index int) string {
+	if name != nil {
+		return *name
+	}
+	return fmt.Sprintf("_%d", index)
+}
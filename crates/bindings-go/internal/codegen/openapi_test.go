@@ -0,0 +1,102 @@
+package codegen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func TestGenerateOpenAPIProducesOneOperationPerReducer(t *testing.T) {
+	todo, done := "todo", "done"
+	def := ModuleDef{
+		Module: "mydb",
+		Reducers: []ReducerDef{
+			{Name: "add_todo", Args: bsatn.ProductOf(
+				bsatn.ProductElement{Name: &todo, Type: bsatn.String()},
+				bsatn.ProductElement{Name: &done, Type: bsatn.Bool()},
+			)},
+		},
+	}
+
+	doc, err := GenerateOpenAPI(def)
+	if err != nil {
+		t.Fatalf("GenerateOpenAPI: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(doc, &decoded); err != nil {
+		t.Fatalf("generated document is not valid JSON: %v", err)
+	}
+
+	paths, ok := decoded["paths"].(map[string]any)
+	if !ok {
+		t.Fatalf("paths missing or wrong type: %v", decoded["paths"])
+	}
+	op, ok := paths["/database/mydb/call/add_todo"]
+	if !ok {
+		t.Fatalf("expected a path for add_todo, got %v", paths)
+	}
+	post := op.(map[string]any)["post"].(map[string]any)
+	if post["operationId"] != "add_todo" {
+		t.Fatalf("operationId = %v, want add_todo", post["operationId"])
+	}
+
+	schema := post["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	props := schema["properties"].(map[string]any)
+	if _, ok := props["todo"]; !ok {
+		t.Fatalf("schema missing todo property: %v", schema)
+	}
+	if _, ok := props["done"]; !ok {
+		t.Fatalf("schema missing done property: %v", schema)
+	}
+}
+
+func TestGenerateOpenAPIRejectsNonProductArgs(t *testing.T) {
+	def := ModuleDef{
+		Module:   "mydb",
+		Reducers: []ReducerDef{{Name: "bad", Args: bsatn.U32()}},
+	}
+	if _, err := GenerateOpenAPI(def); err == nil {
+		t.Fatal("expected an error for non-product reducer args")
+	}
+}
+
+func TestAlgebraicTypeToJSONSchemaCoversPrimitivesAndContainers(t *testing.T) {
+	cases := []struct {
+		name string
+		t    bsatn.AlgebraicType
+		want string
+	}{
+		{"bool", bsatn.Bool(), "boolean"},
+		{"u32", bsatn.U32(), "integer"},
+		{"f64", bsatn.F64(), "number"},
+		{"string", bsatn.String(), "string"},
+		{"array", bsatn.ArrayOf(bsatn.String()), "array"},
+		{"map", bsatn.MapOf(bsatn.String(), bsatn.U32()), "object"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := algebraicTypeToJSONSchema(c.t)
+			if got["type"] != c.want {
+				t.Fatalf("type = %v, want %v", got["type"], c.want)
+			}
+		})
+	}
+}
+
+func TestAlgebraicTypeToJSONSchemaSumMatchesBSATNJSONShape(t *testing.T) {
+	yes, no := "Yes", "No"
+	sum := bsatn.SumOf(
+		bsatn.SumVariant{Name: &yes, Type: bsatn.ProductOf()},
+		bsatn.SumVariant{Name: &no, Type: bsatn.ProductOf()},
+	)
+	schema := algebraicTypeToJSONSchema(sum)
+	props := schema["properties"].(map[string]any)
+	if _, ok := props["tag"]; !ok {
+		t.Fatalf("sum schema missing tag property: %v", schema)
+	}
+	if _, ok := props["value"]; !ok {
+		t.Fatalf("sum schema missing value property: %v", schema)
+	}
+}
@@ -0,0 +1,54 @@
+package codegen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/format"
+	"text/template"
+)
+
+// HashModule returns a stable hex-encoded hash of a compiled module's
+// wasm bytes, used to pin generated bindings to the schema they were
+// generated from (see GenerateSchemaVersion) so a client can detect a
+// server-side module update before decoding rows against a stale
+// layout.
+func HashModule(wasmBytes []byte) string {
+	sum := sha256.Sum256(wasmBytes)
+	return hex.EncodeToString(sum[:])
+}
+
+const schemaVersionTemplate = `// Code generated by spacetimedb-gen from the published module; DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/spacetimedb"
+
+// SchemaVersion pins the module schema hash these generated bindings
+// were produced from. Pass it, with the hash the server reports on
+// connect, to spacetimedb.CheckSchema (or Conn.VerifySchema) to catch a
+// stale client before it decodes rows against the wrong layout.
+var SchemaVersion = spacetimedb.SchemaVersion{Hash: {{printf "%q" .Hash}}}
+`
+
+// GenerateSchemaVersion renders a Go source file, in package pkg,
+// declaring a SchemaVersion pinned to hash.
+func GenerateSchemaVersion(pkg, hash string) ([]byte, error) {
+	if pkg == "" {
+		return nil, fmt.Errorf("codegen: package name is empty")
+	}
+	tmpl, err := template.New("schemaVersion").Parse(schemaVersionTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("codegen: parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ Package, Hash string }{pkg, hash}); err != nil {
+		return nil, fmt.Errorf("codegen: execute template: %w", err)
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: format generated source: %w", err)
+	}
+	return out, nil
+}
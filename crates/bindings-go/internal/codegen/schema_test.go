@@ -0,0 +1,36 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashModuleIsDeterministicAndDistinguishesInput(t *testing.T) {
+	a := HashModule([]byte("module a"))
+	b := HashModule([]byte("module a"))
+	if a != b {
+		t.Fatalf("HashModule not deterministic: %q != %q", a, b)
+	}
+	if c := HashModule([]byte("module b")); c == a {
+		t.Fatalf("HashModule collided for different input: %q", c)
+	}
+}
+
+func TestGenerateSchemaVersionProducesPinnedHash(t *testing.T) {
+	src, err := GenerateSchemaVersion("module", "deadbeef")
+	if err != nil {
+		t.Fatalf("GenerateSchemaVersion: %v", err)
+	}
+	if !strings.Contains(string(src), `spacetimedb.SchemaVersion{Hash: "deadbeef"}`) {
+		t.Fatalf("generated source missing pinned hash: %s", src)
+	}
+	if !strings.Contains(string(src), "package module") {
+		t.Fatalf("generated source missing package clause: %s", src)
+	}
+}
+
+func TestGenerateSchemaVersionRejectsEmptyPackage(t *testing.T) {
+	if _, err := GenerateSchemaVersion("", "deadbeef"); err == nil {
+		t.Fatal("expected error for empty package name")
+	}
+}
@@ -0,0 +1,112 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// ChangeKind classifies one difference DiffModuleDef found between two
+// ModuleDefs.
+type ChangeKind int
+
+const (
+	// ReducerAdded is a reducer present in new but not old.
+	ReducerAdded ChangeKind = iota
+	// ReducerRemoved is a reducer present in old but not new — breaking,
+	// since a client generated against old can no longer call it.
+	ReducerRemoved
+	// ReducerArgsChanged is a reducer present in both whose Args type
+	// changed; see SchemaChange.Breaking for whether the change is
+	// backward compatible.
+	ReducerArgsChanged
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ReducerAdded:
+		return "reducer added"
+	case ReducerRemoved:
+		return "reducer removed"
+	case ReducerArgsChanged:
+		return "reducer args changed"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+}
+
+// SchemaChange is one difference between two ModuleDefs, as reported by
+// DiffModuleDef.
+type SchemaChange struct {
+	Kind    ChangeKind
+	Reducer string
+	// Detail explains the change in prose, e.g. bsatn.TypeCompatible's
+	// error message for a breaking ReducerArgsChanged.
+	Detail string
+	// Breaking is true if a client built against old cannot call
+	// Reducer against new without being regenerated: ReducerRemoved
+	// always, ReducerArgsChanged only when the new Args type is not
+	// bsatn.TypeCompatible with the old one. ReducerAdded is never
+	// breaking — an existing client simply never calls the new reducer.
+	Breaking bool
+}
+
+// DiffModuleDef compares old against new, returning every SchemaChange
+// found, sorted by reducer name (ties broken by Kind) for a stable
+// report. ModuleDef has no table manifest yet (see ModuleDef's doc
+// comment), so this only ever reports reducer differences; extending it
+// to tables is natural follow-up once __describe_module__ parsing
+// produces one.
+//
+// A ReducerArgsChanged entry's Breaking flag is computed with
+// bsatn.TypeCompatible(old args, new args) — the same compatibility
+// rules a migration or hot-swap check uses — rather than a strict
+// equality check, so widening a reducer's argument type (e.g. adding an
+// optional field) is reported but not flagged as breaking.
+func DiffModuleDef(old, new ModuleDef) []SchemaChange {
+	oldByName := make(map[string]ReducerDef, len(old.Reducers))
+	for _, r := range old.Reducers {
+		oldByName[r.Name] = r
+	}
+	newByName := make(map[string]ReducerDef, len(new.Reducers))
+	for _, r := range new.Reducers {
+		newByName[r.Name] = r
+	}
+
+	var changes []SchemaChange
+	for name, oldReducer := range oldByName {
+		newReducer, ok := newByName[name]
+		if !ok {
+			changes = append(changes, SchemaChange{Kind: ReducerRemoved, Reducer: name, Breaking: true, Detail: "reducer no longer exists"})
+			continue
+		}
+		if err := bsatn.TypeCompatible(oldReducer.Args, newReducer.Args); err != nil {
+			changes = append(changes, SchemaChange{Kind: ReducerArgsChanged, Reducer: name, Breaking: true, Detail: err.Error()})
+		}
+	}
+	for name := range newByName {
+		if _, ok := oldByName[name]; !ok {
+			changes = append(changes, SchemaChange{Kind: ReducerAdded, Reducer: name, Breaking: false, Detail: "new reducer"})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Reducer != changes[j].Reducer {
+			return changes[i].Reducer < changes[j].Reducer
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+	return changes
+}
+
+// AnyBreaking reports whether any change in changes is breaking, for a
+// caller (e.g. a CI gate) that only cares about pass/fail.
+func AnyBreaking(changes []SchemaChange) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
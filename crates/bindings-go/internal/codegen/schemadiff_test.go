@@ -0,0 +1,84 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func TestDiffModuleDefNoChanges(t *testing.T) {
+	def := ModuleDef{Module: "app", Reducers: []ReducerDef{{Name: "greet", Args: bsatn.ProductOf()}}}
+	if changes := DiffModuleDef(def, def); len(changes) != 0 {
+		t.Fatalf("changes = %+v, want none", changes)
+	}
+}
+
+func TestDiffModuleDefReportsAddedReducerAsNonBreaking(t *testing.T) {
+	old := ModuleDef{Reducers: []ReducerDef{{Name: "greet", Args: bsatn.ProductOf()}}}
+	new := ModuleDef{Reducers: []ReducerDef{
+		{Name: "greet", Args: bsatn.ProductOf()},
+		{Name: "wave", Args: bsatn.ProductOf()},
+	}}
+	changes := DiffModuleDef(old, new)
+	if len(changes) != 1 || changes[0].Kind != ReducerAdded || changes[0].Breaking {
+		t.Fatalf("changes = %+v, want one non-breaking ReducerAdded", changes)
+	}
+}
+
+func TestDiffModuleDefReportsRemovedReducerAsBreaking(t *testing.T) {
+	old := ModuleDef{Reducers: []ReducerDef{{Name: "greet", Args: bsatn.ProductOf()}}}
+	new := ModuleDef{}
+	changes := DiffModuleDef(old, new)
+	if len(changes) != 1 || changes[0].Kind != ReducerRemoved || !changes[0].Breaking {
+		t.Fatalf("changes = %+v, want one breaking ReducerRemoved", changes)
+	}
+	if !AnyBreaking(changes) {
+		t.Fatal("AnyBreaking: want true")
+	}
+}
+
+func TestDiffModuleDefAllowsCompatibleArgWidening(t *testing.T) {
+	name := "text"
+	old := ModuleDef{Reducers: []ReducerDef{{Name: "greet", Args: bsatn.ProductOf(bsatn.ProductElement{Name: &name, Type: bsatn.String()})}}}
+	added := "shout"
+	new := ModuleDef{Reducers: []ReducerDef{{Name: "greet", Args: bsatn.ProductOf(
+		bsatn.ProductElement{Name: &name, Type: bsatn.String()},
+		bsatn.ProductElement{Name: &added, Type: bsatn.Bool()},
+	)}}}
+	changes := DiffModuleDef(old, new)
+	if len(changes) != 0 {
+		t.Fatalf("changes = %+v, want none (appending a field is compatible)", changes)
+	}
+}
+
+func TestDiffModuleDefFlagsIncompatibleArgChangeAsBreaking(t *testing.T) {
+	name := "count"
+	old := ModuleDef{Reducers: []ReducerDef{{Name: "increment", Args: bsatn.ProductOf(bsatn.ProductElement{Name: &name, Type: bsatn.U32()})}}}
+	new := ModuleDef{Reducers: []ReducerDef{{Name: "increment", Args: bsatn.ProductOf(bsatn.ProductElement{Name: &name, Type: bsatn.String()})}}}
+	changes := DiffModuleDef(old, new)
+	if len(changes) != 1 || changes[0].Kind != ReducerArgsChanged || !changes[0].Breaking {
+		t.Fatalf("changes = %+v, want one breaking ReducerArgsChanged", changes)
+	}
+	if changes[0].Detail == "" {
+		t.Fatal("Detail should explain the incompatibility")
+	}
+}
+
+func TestDiffModuleDefSortsByReducerName(t *testing.T) {
+	old := ModuleDef{}
+	new := ModuleDef{Reducers: []ReducerDef{
+		{Name: "zeta", Args: bsatn.ProductOf()},
+		{Name: "alpha", Args: bsatn.ProductOf()},
+	}}
+	changes := DiffModuleDef(old, new)
+	if len(changes) != 2 || changes[0].Reducer != "alpha" || changes[1].Reducer != "zeta" {
+		t.Fatalf("changes = %+v, want alpha before zeta", changes)
+	}
+}
+
+func TestAnyBreakingFalseForNonBreakingChangesOnly(t *testing.T) {
+	changes := []SchemaChange{{Kind: ReducerAdded, Reducer: "wave", Breaking: false}}
+	if AnyBreaking(changes) {
+		t.Fatal("AnyBreaking: want false")
+	}
+}
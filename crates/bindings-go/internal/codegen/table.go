@@ -0,0 +1,154 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"text/template"
+)
+
+// TableFieldDef describes one field of a Go struct discovered by the
+// spacetimedb-gen command (see cmd/spacetimedb-gen): its Go name, its
+// Go type exactly as written in source (so codegen never has to guess
+// how to spell e.g. a package-qualified type), and its raw struct tag.
+type TableFieldDef struct {
+	Name   string
+	GoType string
+	Tag    string
+}
+
+// tag reads the field's "spacetimedb" struct tag, the same way a real
+// reflect.StructField would, without requiring the field to belong to
+// a compiled type: TableFieldDef.Tag is the raw backtick-delimited tag
+// text captured from source by the AST-based parser in cmd/spacetimedb-gen.
+func (f TableFieldDef) tag() string {
+	return reflect.StructTag(f.Tag).Get("spacetimedb")
+}
+
+// TableDef describes one Go struct to generate table and index
+// accessors for, as annotated with `spacetimedb:"..."` struct tags.
+type TableDef struct {
+	// Name is the module-side table name, e.g. "player".
+	Name string
+	// GoType is the row struct's Go type name, e.g. "Player".
+	GoType string
+	// Fields are GoType's exported fields, in declaration order.
+	Fields []TableFieldDef
+}
+
+const tableTemplate = `// Code generated by spacetimedb-gen from struct tags; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/spacetimedb"
+)
+
+// New{{.GoType}}Table returns a TableCache for the "{{.Name}}" table,
+// keyed by {{.GoType}}.{{.PrimaryKey.Name}}{{if .Indexes}}, with a secondary index declared
+// for every other spacetimedb-tagged field{{end}}.
+func New{{.GoType}}Table() *spacetimedb.TableCache {
+	t := spacetimedb.NewTableCache(func(row any) any { return row.({{.GoType}}).{{.PrimaryKey.Name}} })
+{{range .Indexes}}	t.Index("{{.Name}}", func(row any) any { return row.({{$.GoType}}).{{.Name}} })
+{{end}}	return t
+}
+
+// Get{{.GoType}}By{{.PrimaryKey.Name}} returns the cached {{.GoType}} under the given
+// {{.PrimaryKey.Name}}, if any.
+func Get{{.GoType}}By{{.PrimaryKey.Name}}(t *spacetimedb.TableCache, key {{.PrimaryKey.GoType}}) ({{.GoType}}, bool) {
+	row, ok := t.Get(key)
+	if !ok {
+		return {{.GoType}}{}, false
+	}
+	return row.({{.GoType}}), true
+}
+{{range .Indexes}}
+// Find{{$.GoType}}By{{.Name}} returns every cached {{$.GoType}} whose {{.Name}} equals key.
+func Find{{$.GoType}}By{{.Name}}(t *spacetimedb.TableCache, key {{.GoType}}) []{{$.GoType}} {
+	rows := t.Lookup("{{.Name}}", key)
+	out := make([]{{$.GoType}}, len(rows))
+	for i, row := range rows {
+		out[i] = row.({{$.GoType}})
+	}
+	return out
+}
+{{end}}
+// Marshal{{.GoType}} BSATN-encodes row.
+func Marshal{{.GoType}}(row {{.GoType}}) ([]byte, error) {
+	return bsatn.Marshal(row)
+}
+
+// Unmarshal{{.GoType}} BSATN-decodes data into a {{.GoType}}.
+func Unmarshal{{.GoType}}(data []byte) ({{.GoType}}, error) {
+	var row {{.GoType}}
+	if err := bsatn.Unmarshal(data, &row); err != nil {
+		return {{.GoType}}{}, err
+	}
+	return row, nil
+}
+`
+
+type tableTemplateData struct {
+	Package    string
+	Name       string
+	GoType     string
+	PrimaryKey TableFieldDef
+	Indexes    []TableFieldDef
+}
+
+// GenerateTable renders a Go source file, in package pkg, declaring a
+// spacetimedb.TableCache constructor and typed lookup/serialization
+// helpers for def, mirroring the Rust `#[table]` macro's generated
+// accessors closely enough to spare a module author hand-rolling them.
+//
+// Exactly one field of def.Fields must carry a `spacetimedb:"primary_key"`
+// tag; it becomes the TableCache's primary key and New<GoType>Table's
+// keyOf. Every other field tagged `spacetimedb:"index"` or
+// `spacetimedb:"unique"` becomes a secondary index with a generated
+// Find<GoType>By<Field> accessor. Untagged fields are encoded (via
+// bsatn.Marshal/Unmarshal, same as any other struct) but get no
+// generated accessor of their own.
+func GenerateTable(pkg string, def TableDef) ([]byte, error) {
+	if pkg == "" {
+		return nil, fmt.Errorf("codegen: package name is empty")
+	}
+	if def.Name == "" {
+		return nil, fmt.Errorf("codegen: table name is empty")
+	}
+	if def.GoType == "" {
+		return nil, fmt.Errorf("codegen: table %q has no GoType", def.Name)
+	}
+
+	data := tableTemplateData{Package: pkg, Name: def.Name, GoType: def.GoType}
+	for _, f := range def.Fields {
+		switch f.tag() {
+		case "primary_key":
+			if data.PrimaryKey.Name != "" {
+				return nil, fmt.Errorf("codegen: table %q: multiple primary_key fields (%s and %s)", def.Name, data.PrimaryKey.Name, f.Name)
+			}
+			data.PrimaryKey = f
+		case "index", "unique":
+			data.Indexes = append(data.Indexes, f)
+		}
+	}
+	if data.PrimaryKey.Name == "" {
+		return nil, fmt.Errorf("codegen: table %q: no field tagged spacetimedb:\"primary_key\"", def.Name)
+	}
+
+	tmpl, err := template.New("table").Parse(tableTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("codegen: parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("codegen: execute template: %w", err)
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: format generated source: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,64 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateTablePrimaryKeyAndIndex(t *testing.T) {
+	def := TableDef{
+		Name:   "player",
+		GoType: "Player",
+		Fields: []TableFieldDef{
+			{Name: "ID", GoType: "uint64", Tag: `spacetimedb:"primary_key"`},
+			{Name: "Name", GoType: "string", Tag: `spacetimedb:"unique"`},
+			{Name: "Score", GoType: "int32"},
+		},
+	}
+	out, err := GenerateTable("model", def)
+	if err != nil {
+		t.Fatalf("GenerateTable: %v", err)
+	}
+	src := string(out)
+	for _, want := range []string{
+		"func NewPlayerTable() *spacetimedb.TableCache",
+		`return row.(Player).ID`,
+		`t.Index("Name", func(row any) any { return row.(Player).Name })`,
+		"func GetPlayerByID(t *spacetimedb.TableCache, key uint64) (Player, bool)",
+		"func FindPlayerByName(t *spacetimedb.TableCache, key string) []Player",
+		"func MarshalPlayer(row Player) ([]byte, error)",
+		"func UnmarshalPlayer(data []byte) (Player, error)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated source missing %q\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "Score") {
+		t.Errorf("generated source should not reference untagged field Score\n%s", src)
+	}
+}
+
+func TestGenerateTableRequiresPrimaryKey(t *testing.T) {
+	def := TableDef{
+		Name:   "player",
+		GoType: "Player",
+		Fields: []TableFieldDef{{Name: "Name", GoType: "string"}},
+	}
+	if _, err := GenerateTable("model", def); err == nil {
+		t.Fatal("GenerateTable with no primary_key field: want error, got nil")
+	}
+}
+
+func TestGenerateTableRejectsMultiplePrimaryKeys(t *testing.T) {
+	def := TableDef{
+		Name:   "player",
+		GoType: "Player",
+		Fields: []TableFieldDef{
+			{Name: "ID", GoType: "uint64", Tag: `spacetimedb:"primary_key"`},
+			{Name: "Slug", GoType: "string", Tag: `spacetimedb:"primary_key"`},
+		},
+	}
+	if _, err := GenerateTable("model", def); err == nil {
+		t.Fatal("GenerateTable with two primary_key fields: want error, got nil")
+	}
+}
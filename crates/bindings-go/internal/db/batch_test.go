@@ -0,0 +1,84 @@
+package db
+
+import "testing"
+
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+	return NewDatabase(NewKVEngine(NewMemKVStore(), KVConfig{}))
+}
+
+func TestDeleteRangeRemovesOnlyKeysInBounds(t *testing.T) {
+	d := newTestDatabase(t)
+	for _, n := range []uint32{1, 2, 3, 4, 5} {
+		key := EncodeOrderedKey(n)
+		if err := d.Engine.Put("scores", key, []byte{byte(n)}); err != nil {
+			t.Fatalf("Put(%d): %v", n, err)
+		}
+	}
+
+	n, err := d.DeleteRange("scores", EncodeOrderedKey(uint32(2)), EncodeOrderedKey(uint32(4)))
+	if err != nil {
+		t.Fatalf("DeleteRange: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("DeleteRange count = %d, want 2", n)
+	}
+
+	cur, err := d.Engine.Scan("scores")
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	defer cur.Close()
+	var remaining []byte
+	for cur.Next() {
+		remaining = append(remaining, cur.Value()[0])
+	}
+	want := []byte{1, 4, 5}
+	if len(remaining) != len(want) {
+		t.Fatalf("remaining = %v, want %v", remaining, want)
+	}
+	for i := range want {
+		if remaining[i] != want[i] {
+			t.Fatalf("remaining = %v, want %v", remaining, want)
+		}
+	}
+}
+
+func TestDeleteRangeUnboundedDeletesEverything(t *testing.T) {
+	d := newTestDatabase(t)
+	for _, n := range []uint32{1, 2, 3} {
+		d.Engine.Put("scores", EncodeOrderedKey(n), []byte{byte(n)})
+	}
+
+	n, err := d.DeleteRange("scores", nil, nil)
+	if err != nil {
+		t.Fatalf("DeleteRange: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("DeleteRange count = %d, want 3", n)
+	}
+}
+
+func TestTruncateDeletesRowsAndResetsSequences(t *testing.T) {
+	d := newTestDatabase(t)
+	d.Engine.Put("players", EncodeOrderedKey(uint32(1)), []byte("alice"))
+	d.Engine.Put("players", EncodeOrderedKey(uint32(2)), []byte("bob"))
+	d.NextSequence("players.id")
+	d.NextSequence("players.id")
+
+	if err := d.Truncate("players", "players.id"); err != nil {
+		t.Fatalf("Truncate: %v", err)
+	}
+
+	cur, err := d.Engine.Scan("players")
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	defer cur.Close()
+	if cur.Next() {
+		t.Fatal("expected no rows after Truncate")
+	}
+	if got := d.SequenceValue("players.id"); got != 0 {
+		t.Fatalf("SequenceValue after Truncate = %d, want 0", got)
+	}
+}
@@ -0,0 +1,76 @@
+package db
+
+// ChangeOp distinguishes the kind of row mutation a RowChange records.
+type ChangeOp uint8
+
+const (
+	ChangeInsert ChangeOp = iota
+	ChangeUpdate
+	ChangeDelete
+)
+
+func (op ChangeOp) String() string {
+	switch op {
+	case ChangeInsert:
+		return "insert"
+	case ChangeUpdate:
+		return "update"
+	case ChangeDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// RowChange records one row mutation: table, key, and the row's encoded
+// value — the new value for ChangeInsert/ChangeUpdate, or the value it
+// held just before removal for ChangeDelete, so a subscriber's cache can
+// still look up whatever it indexed the deleted row by.
+type RowChange struct {
+	Table string
+	Key   []byte
+	Value []byte
+	Op    ChangeOp
+}
+
+// ChangeSet is every RowChange produced by one atomic unit of work — a
+// single Txn, or a single Database.DeleteRange/Database.ExpireRows call —
+// delivered to ChangeListeners together so a subscriber applies them as
+// one batch instead of observing a table half-updated partway through.
+type ChangeSet []RowChange
+
+// ChangeListener is called with every ChangeSet a Database produces. It
+// runs synchronously on the goroutine that produced the ChangeSet (the
+// caller of Txn.Commit/Rollback, DeleteRange, Truncate, or ExpireRows),
+// so a slow or blocking listener slows that caller down; a listener that
+// needs to fan out asynchronously should buffer internally.
+type ChangeListener func(ChangeSet)
+
+// OnChange registers listener to be called with every ChangeSet d
+// produces from then on, so a subscription broadcaster (or, in tests, a
+// pkg/spacetimedb.Conn bridged via a caller-provided adapter) sees every
+// row mutation — explicit per-row writes and deletes, DeleteRange,
+// Truncate, and ExpireRows alike — instead of only whichever paths it
+// happens to call directly. Registering does not replace previously
+// registered listeners; all of them are called, in registration order.
+func (d *Database) OnChange(listener ChangeListener) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.changeListeners = append(d.changeListeners, listener)
+}
+
+// notify delivers changes to every registered listener, in registration
+// order. It is a no-op for an empty ChangeSet, so a Commit/Rollback with
+// no writes, or a DeleteRange that matched no rows, does not produce a
+// spurious empty notification.
+func (d *Database) notify(changes ChangeSet) {
+	if len(changes) == 0 {
+		return
+	}
+	d.mu.Lock()
+	listeners := append([]ChangeListener(nil), d.changeListeners...)
+	d.mu.Unlock()
+	for _, listener := range listeners {
+		listener(changes)
+	}
+}
@@ -0,0 +1,171 @@
+package db
+
+import "testing"
+
+func TestTxnPutFiresInsertThenUpdateOnCommit(t *testing.T) {
+	database := NewDatabase(NewMemEngine())
+
+	var got []ChangeSet
+	database.OnChange(func(cs ChangeSet) { got = append(got, cs) })
+
+	txn := database.Begin()
+	if err := txn.Put("widgets", []byte("w1"), []byte("v1")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if len(got) != 1 || len(got[0]) != 1 || got[0][0].Op != ChangeInsert {
+		t.Fatalf("got = %+v, want one ChangeSet with one ChangeInsert", got)
+	}
+
+	txn = database.Begin()
+	if err := txn.Put("widgets", []byte("w1"), []byte("v2")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if len(got) != 2 || len(got[1]) != 1 || got[1][0].Op != ChangeUpdate {
+		t.Fatalf("got[1] = %+v, want one ChangeUpdate", got)
+	}
+}
+
+func TestTxnGroupsMultipleWritesIntoOneChangeSet(t *testing.T) {
+	database := NewDatabase(NewMemEngine())
+
+	var got []ChangeSet
+	database.OnChange(func(cs ChangeSet) { got = append(got, cs) })
+
+	txn := database.Begin()
+	txn.Put("widgets", []byte("w1"), []byte("v1"))
+	txn.Put("widgets", []byte("w2"), []byte("v2"))
+	txn.Delete("widgets", []byte("w1"))
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("got %d ChangeSets, want 1", len(got))
+	}
+	if len(got[0]) != 3 {
+		t.Fatalf("ChangeSet has %d entries, want 3", len(got[0]))
+	}
+}
+
+func TestTxnDeleteOfNonexistentKeyRecordsNoChange(t *testing.T) {
+	database := NewDatabase(NewMemEngine())
+	var got []ChangeSet
+	database.OnChange(func(cs ChangeSet) { got = append(got, cs) })
+
+	txn := database.Begin()
+	if err := txn.Delete("widgets", []byte("missing")); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("got = %+v, want no ChangeSets for a no-op delete", got)
+	}
+}
+
+func TestTxnRollbackUndoesChangesAndDeliversNone(t *testing.T) {
+	database := NewDatabase(NewMemEngine())
+	var got []ChangeSet
+	database.OnChange(func(cs ChangeSet) { got = append(got, cs) })
+
+	txn := database.Begin()
+	txn.Put("widgets", []byte("w1"), []byte("v1"))
+	if err := txn.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("got = %+v, want no ChangeSets once Rollback undid the write", got)
+	}
+	if _, ok, err := database.Engine.Get("widgets", []byte("w1")); err != nil || ok {
+		t.Fatalf("Engine.Get after Rollback = (ok=%v, err=%v), want (false, nil)", ok, err)
+	}
+}
+
+func TestDeleteRangeDeliversOneChangeSetForWholeRange(t *testing.T) {
+	database := NewDatabase(NewMemEngine())
+	database.Engine.Put("widgets", []byte("w1"), []byte("v1"))
+	database.Engine.Put("widgets", []byte("w2"), []byte("v2"))
+	database.Engine.Put("widgets", []byte("w3"), []byte("v3"))
+
+	var got []ChangeSet
+	database.OnChange(func(cs ChangeSet) { got = append(got, cs) })
+
+	n, err := database.DeleteRange("widgets", nil, nil)
+	if err != nil {
+		t.Fatalf("DeleteRange: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("DeleteRange removed %d rows, want 3", n)
+	}
+	if len(got) != 1 || len(got[0]) != 3 {
+		t.Fatalf("got = %+v, want one ChangeSet with 3 deletes", got)
+	}
+	for _, c := range got[0] {
+		if c.Op != ChangeDelete {
+			t.Fatalf("op = %v, want ChangeDelete", c.Op)
+		}
+	}
+}
+
+func TestTruncateDeliversOneChangeSet(t *testing.T) {
+	database := NewDatabase(NewMemEngine())
+	database.Engine.Put("widgets", []byte("w1"), []byte("v1"))
+	database.Engine.Put("widgets", []byte("w2"), []byte("v2"))
+
+	var got []ChangeSet
+	database.OnChange(func(cs ChangeSet) { got = append(got, cs) })
+
+	if err := database.Truncate("widgets"); err != nil {
+		t.Fatalf("Truncate: %v", err)
+	}
+	if len(got) != 1 || len(got[0]) != 2 {
+		t.Fatalf("got = %+v, want one ChangeSet with 2 deletes", got)
+	}
+}
+
+func TestExpireRowsDeliversOneChangeSetForExpiredRowsOnly(t *testing.T) {
+	database := NewDatabase(NewMemEngine())
+	database.Engine.Put("sessions", []byte("s1"), []byte("expired"))
+	database.Engine.Put("sessions", []byte("s2"), []byte("fresh"))
+
+	var got []ChangeSet
+	database.OnChange(func(cs ChangeSet) { got = append(got, cs) })
+
+	n, err := database.ExpireRows("sessions", func(key, value []byte) bool {
+		return string(value) == "expired"
+	})
+	if err != nil {
+		t.Fatalf("ExpireRows: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("ExpireRows removed %d rows, want 1", n)
+	}
+	if len(got) != 1 || len(got[0]) != 1 || string(got[0][0].Key) != "s1" {
+		t.Fatalf("got = %+v, want one ChangeSet deleting s1", got)
+	}
+
+	if _, ok, _ := database.Engine.Get("sessions", []byte("s2")); !ok {
+		t.Fatal("fresh row s2 should not have been expired")
+	}
+}
+
+func TestOnChangeIgnoresEmptyChangeSets(t *testing.T) {
+	database := NewDatabase(NewMemEngine())
+	called := false
+	database.OnChange(func(cs ChangeSet) { called = true })
+
+	txn := database.Begin()
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if called {
+		t.Fatal("OnChange should not fire for a txn with no writes")
+	}
+}
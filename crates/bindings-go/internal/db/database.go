@@ -0,0 +1,229 @@
+package db
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// TableInfo describes a table's row schema, enough to round-trip it
+// through a backup archive or an ad-hoc SQL projection.
+type TableInfo struct {
+	Name    string
+	Columns []bsatn.AlgebraicType
+}
+
+// Database is a thin manifest layered on top of an Engine: it remembers
+// which tables exist, their schemas, and any auto-increment sequence
+// counters, none of which the storage SPI itself is responsible for.
+type Database struct {
+	Engine Engine
+
+	mu        sync.Mutex
+	tables    map[string]TableInfo
+	tableOrd  []string // registration order, for stable backup output
+	sequences map[string]uint64
+
+	indexes  map[string]IndexInfo
+	indexOrd []string // registration order
+
+	locks *LockManager
+	level IsolationLevel
+
+	quotas        map[string]QuotaConfig
+	dbQuota       DatabaseQuota
+	quotaMetrics  quotaMetrics
+	quotaedTables map[string]struct{} // every table name ever checked by checkQuota, for totalBytes
+
+	changeListeners []ChangeListener
+}
+
+// NewDatabase wraps engine with an empty table manifest, defaulting to
+// Serializable isolation for transactions started with Begin.
+func NewDatabase(engine Engine) *Database {
+	return &Database{
+		Engine:    engine,
+		tables:    make(map[string]TableInfo),
+		sequences: make(map[string]uint64),
+		indexes:   make(map[string]IndexInfo),
+		locks:     NewLockManager(),
+		level:     Serializable,
+	}
+}
+
+// SetIsolationLevel changes the isolation level Begin gives to
+// subsequently started transactions; transactions already in progress
+// keep the level they started with.
+func (d *Database) SetIsolationLevel(level IsolationLevel) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.level = level
+}
+
+// IsolationLevel reports the level Begin currently gives new
+// transactions.
+func (d *Database) IsolationLevel() IsolationLevel {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.level
+}
+
+// RegisterTable adds (or replaces) a table's schema in the manifest.
+func (d *Database) RegisterTable(info TableInfo) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, exists := d.tables[info.Name]; !exists {
+		d.tableOrd = append(d.tableOrd, info.Name)
+	}
+	d.tables[info.Name] = info
+}
+
+// Tables returns table manifests in registration order.
+func (d *Database) Tables() []TableInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]TableInfo, len(d.tableOrd))
+	for i, name := range d.tableOrd {
+		out[i] = d.tables[name]
+	}
+	return out
+}
+
+// NextSequence returns the next value of the named auto-increment sequence,
+// starting at 1.
+func (d *Database) NextSequence(name string) uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sequences[name]++
+	return d.sequences[name]
+}
+
+// SequenceValue returns the current value of the named sequence without
+// advancing it.
+func (d *Database) SequenceValue(name string) uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.sequences[name]
+}
+
+// SetSequence forces the named sequence to value, used when restoring a
+// backup.
+func (d *Database) SetSequence(name string, value uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sequences[name] = value
+}
+
+// DeleteRange deletes every row in table whose key falls in [lower,
+// upper) under the engine's native byte ordering (see EncodeOrderedKey
+// for constructing lower/upper from a BTree index's bounds), returning
+// the number of rows removed. A nil lower or upper bound is unbounded in
+// that direction, so DeleteRange(table, nil, nil) deletes every row.
+//
+// It exists so cleanup reducers and test teardown can drop a whole key
+// range in one call instead of scanning table and issuing a per-row
+// Delete from the caller's side; Engine has no native range-delete, so
+// this still costs a full Scan, but saves the round trips.
+//
+// Every row DeleteRange removes is delivered to registered
+// ChangeListeners (see OnChange) as a single ChangeSet, so a subscriber
+// sees the whole range disappear atomically instead of one delete
+// notification per row with no indication they belong together.
+func (d *Database) DeleteRange(table string, lower, upper []byte) (int, error) {
+	cur, err := d.Engine.Scan(table)
+	if err != nil {
+		return 0, err
+	}
+	var rows []RowChange
+	for cur.Next() {
+		key := cur.Key()
+		if lower != nil && bytes.Compare(key, lower) < 0 {
+			continue
+		}
+		if upper != nil && bytes.Compare(key, upper) >= 0 {
+			continue
+		}
+		rows = append(rows, RowChange{
+			Table: table,
+			Key:   append([]byte(nil), key...),
+			Value: append([]byte(nil), cur.Value()...),
+			Op:    ChangeDelete,
+		})
+	}
+	if err := cur.Close(); err != nil {
+		return 0, err
+	}
+
+	var changes ChangeSet
+	for _, row := range rows {
+		if err := d.Engine.Delete(table, row.Key); err != nil {
+			d.notify(changes)
+			return len(changes), err
+		}
+		changes = append(changes, row)
+	}
+	d.notify(changes)
+	return len(rows), nil
+}
+
+// ExpireRows scans table and deletes every row for which isExpired
+// returns true, given that row's key and encoded value — the host's TTL
+// primitive: a caller (a scheduled reducer, or a background sweep
+// alongside CallReducer) invokes it periodically with an isExpired that
+// decodes whatever timestamp field the row's schema defines and compares
+// it against the current time. As with DeleteRange, every row removed is
+// delivered to registered ChangeListeners as a single ChangeSet.
+func (d *Database) ExpireRows(table string, isExpired func(key, value []byte) bool) (int, error) {
+	cur, err := d.Engine.Scan(table)
+	if err != nil {
+		return 0, err
+	}
+	var expired []RowChange
+	for cur.Next() {
+		if isExpired(cur.Key(), cur.Value()) {
+			expired = append(expired, RowChange{
+				Table: table,
+				Key:   append([]byte(nil), cur.Key()...),
+				Value: append([]byte(nil), cur.Value()...),
+				Op:    ChangeDelete,
+			})
+		}
+	}
+	if err := cur.Close(); err != nil {
+		return 0, err
+	}
+
+	var changes ChangeSet
+	for _, row := range expired {
+		if err := d.Engine.Delete(table, row.Key); err != nil {
+			d.notify(changes)
+			return len(changes), err
+		}
+		changes = append(changes, row)
+	}
+	d.notify(changes)
+	return len(expired), nil
+}
+
+// Truncate deletes every row in table, then resets sequences (as
+// SetSequence(name, 0) would) — typically the auto-increment sequences
+// backing table's primary key, which the caller must name explicitly
+// since Database's sequences are named independently of any table (see
+// NextSequence). Truncate is not transactional: if Engine.Delete fails
+// partway through, table is left with only some rows removed and the
+// sequences untouched, matching Engine's own lack of an all-or-nothing
+// guarantee around Put/Delete. It delegates to DeleteRange, so ChangeListeners
+// see the whole truncation as one ChangeSet the same way a bounded
+// DeleteRange call does.
+func (d *Database) Truncate(table string, sequences ...string) error {
+	if _, err := d.DeleteRange(table, nil, nil); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, seq := range sequences {
+		d.sequences[seq] = 0
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Package db implements the Go host's datastore: a storage
+// service-provider interface (SPI) plus one or more Engine implementations
+// that back it, following the same "pluggable storage" shape as
+// SpacetimeDB's Rust `Locking`/durable datastores.
+package db
+
+// Engine is the storage SPI that a Database delegates physical row storage
+// to. Rows are addressed by an opaque, engine-defined key within a table;
+// Database (and its callers) are responsible for constructing keys that
+// encode index semantics (see the `sortkey` helpers used by BTree indexes).
+//
+// Implementations must be safe for concurrent use.
+type Engine interface {
+	// Put writes value under key in table, overwriting any existing value.
+	Put(table string, key, value []byte) error
+	// Get returns the value stored under key in table, or ok == false if
+	// no such key exists.
+	Get(table string, key []byte) (value []byte, ok bool, err error)
+	// Delete removes key from table. It is not an error to delete a
+	// key that does not exist.
+	Delete(table string, key []byte) error
+	// Scan returns a Cursor over every key/value pair in table, ordered by
+	// key according to the engine's native byte ordering.
+	Scan(table string) (Cursor, error)
+	// Close releases any resources (file handles, connections) held by
+	// the engine. Subsequent calls to the engine are undefined.
+	Close() error
+}
+
+// Cursor iterates over the key/value pairs produced by Engine.Scan.
+type Cursor interface {
+	// Next advances the cursor and reports whether a value is available.
+	Next() bool
+	// Key returns the key at the cursor's current position.
+	Key() []byte
+	// Value returns the value at the cursor's current position.
+	Value() []byte
+	// Close releases resources held by the cursor.
+	Close() error
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+)
+
+// historyOp records enough of a single Put/Delete to undo and redo it.
+type historyOp struct {
+	table          string
+	key            []byte
+	hadOld, hadNew bool
+	old, new       []byte
+}
+
+// Transaction is one recorded reducer execution's worth of writes, in the
+// order they were applied.
+type Transaction struct {
+	ops []historyOp
+}
+
+// HistoryEngine wraps an Engine with an undo log, grouped per transaction,
+// so a test harness can step backward and forward through a sequence of
+// reducer executions and inspect table state at each point. It is meant
+// for debugging/test use; production hosting should not pay for the
+// bookkeeping this adds to every write.
+type HistoryEngine struct {
+	Engine
+
+	mu        sync.Mutex
+	txns      []Transaction
+	cursor    int // number of transactions currently applied, from the start
+	recording *Transaction
+}
+
+// NewHistoryEngine wraps inner with history tracking. inner is assumed to
+// start empty; recording writes made before wrapping would desync the log
+// from the underlying engine's actual state.
+func NewHistoryEngine(inner Engine) *HistoryEngine {
+	return &HistoryEngine{Engine: inner}
+}
+
+// Begin starts recording a new transaction. It is an error to call Begin
+// while another transaction is already being recorded, or after stepping
+// back (StepForward or discarding the undone transactions is required
+// first, mirroring how a real history can't branch).
+func (h *HistoryEngine) Begin() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.recording != nil {
+		return fmt.Errorf("db: history: transaction already in progress")
+	}
+	if h.cursor != len(h.txns) {
+		// Stepped back and now writing: truncate the redo log, matching
+		// normal undo-history semantics (a new edit discards old redos).
+		h.txns = h.txns[:h.cursor]
+	}
+	h.recording = &Transaction{}
+	return nil
+}
+
+// Commit finishes recording the current transaction and appends it to the
+// history, advancing the cursor.
+func (h *HistoryEngine) Commit() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.recording == nil {
+		return fmt.Errorf("db: history: no transaction in progress")
+	}
+	h.txns = append(h.txns, *h.recording)
+	h.cursor = len(h.txns)
+	h.recording = nil
+	return nil
+}
+
+func (h *HistoryEngine) recordOp(table string, key []byte) {
+	old, hadOld, _ := h.Engine.Get(table, key)
+	h.recording.ops = append(h.recording.ops, historyOp{table: table, key: key, old: old, hadOld: hadOld})
+}
+
+func (h *HistoryEngine) Put(table string, key, value []byte) error {
+	h.mu.Lock()
+	if h.recording != nil {
+		h.recordOp(table, key)
+		last := &h.recording.ops[len(h.recording.ops)-1]
+		last.new, last.hadNew = append([]byte(nil), value...), true
+	}
+	h.mu.Unlock()
+	return h.Engine.Put(table, key, value)
+}
+
+func (h *HistoryEngine) Delete(table string, key []byte) error {
+	h.mu.Lock()
+	if h.recording != nil {
+		h.recordOp(table, key)
+	}
+	h.mu.Unlock()
+	return h.Engine.Delete(table, key)
+}
+
+// StepBack undoes the most recently applied transaction, moving the
+// cursor back one position. It returns an error if there is nothing to
+// undo.
+func (h *HistoryEngine) StepBack() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.cursor == 0 {
+		return fmt.Errorf("db: history: already at the beginning")
+	}
+	txn := h.txns[h.cursor-1]
+	for i := len(txn.ops) - 1; i >= 0; i-- {
+		op := txn.ops[i]
+		if op.hadOld {
+			if err := h.Engine.Put(op.table, op.key, op.old); err != nil {
+				return err
+			}
+		} else {
+			if err := h.Engine.Delete(op.table, op.key); err != nil {
+				return err
+			}
+		}
+	}
+	h.cursor--
+	return nil
+}
+
+// StepForward re-applies the transaction that was last undone, moving the
+// cursor forward one position. It returns an error if there is nothing to
+// redo.
+func (h *HistoryEngine) StepForward() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.cursor == len(h.txns) {
+		return fmt.Errorf("db: history: already at the latest transaction")
+	}
+	txn := h.txns[h.cursor]
+	for _, op := range txn.ops {
+		if op.hadNew {
+			if err := h.Engine.Put(op.table, op.key, op.new); err != nil {
+				return err
+			}
+		} else {
+			if err := h.Engine.Delete(op.table, op.key); err != nil {
+				return err
+			}
+		}
+	}
+	h.cursor++
+	return nil
+}
+
+// TransactionCount reports how many transactions have been recorded.
+func (h *HistoryEngine) TransactionCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.txns)
+}
+
+// Cursor reports the index of the current position in history: the number
+// of transactions currently applied.
+func (h *HistoryEngine) Cursor() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.cursor
+}
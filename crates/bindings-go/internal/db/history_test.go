@@ -0,0 +1,62 @@
+package db
+
+import "testing"
+
+func TestHistoryEngineStepBackAndForward(t *testing.T) {
+	h := NewHistoryEngine(NewMemEngine())
+
+	h.Begin()
+	h.Put("players", []byte("p1"), []byte("alice"))
+	h.Commit()
+
+	h.Begin()
+	h.Put("players", []byte("p1"), []byte("alice-v2"))
+	h.Commit()
+
+	v, _, _ := h.Get("players", []byte("p1"))
+	if string(v) != "alice-v2" {
+		t.Fatalf("Get = %q, want alice-v2", v)
+	}
+
+	if err := h.StepBack(); err != nil {
+		t.Fatalf("StepBack: %v", err)
+	}
+	v, _, _ = h.Get("players", []byte("p1"))
+	if string(v) != "alice" {
+		t.Fatalf("after StepBack, Get = %q, want alice", v)
+	}
+
+	if err := h.StepForward(); err != nil {
+		t.Fatalf("StepForward: %v", err)
+	}
+	v, _, _ = h.Get("players", []byte("p1"))
+	if string(v) != "alice-v2" {
+		t.Fatalf("after StepForward, Get = %q, want alice-v2", v)
+	}
+
+	if err := h.StepForward(); err == nil {
+		t.Fatalf("expected error stepping forward past the latest transaction")
+	}
+}
+
+func TestHistoryEngineStepBackToDelete(t *testing.T) {
+	h := NewHistoryEngine(NewMemEngine())
+	h.Begin()
+	h.Put("t", []byte("k"), []byte("v"))
+	h.Commit()
+
+	h.Begin()
+	h.Delete("t", []byte("k"))
+	h.Commit()
+
+	if _, ok, _ := h.Get("t", []byte("k")); ok {
+		t.Fatalf("expected key deleted")
+	}
+	if err := h.StepBack(); err != nil {
+		t.Fatalf("StepBack: %v", err)
+	}
+	v, ok, _ := h.Get("t", []byte("k"))
+	if !ok || string(v) != "v" {
+		t.Fatalf("Get = %q, %v, want v, true", v, ok)
+	}
+}
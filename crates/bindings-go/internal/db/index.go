@@ -0,0 +1,172 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// IndexKeyFunc derives a secondary index's ordered key for a row (see
+// EncodeOrderedKey for building one from the row's decoded column
+// values). It is supplied by the caller registering the index: Engine
+// and Database treat row bytes as opaque and have no schema-driven
+// column extraction of their own.
+type IndexKeyFunc func(row []byte) ([]byte, error)
+
+// IndexInfo describes a secondary index over a table: RebuildIndex and
+// VerifyIndexes use Key to re-derive, for every row in Table, the key it
+// should be found under in the index's own storage.
+type IndexInfo struct {
+	Name  string
+	Table string
+	Key   IndexKeyFunc
+	// Unique makes RebuildIndex fail if two rows in Table derive the same
+	// key, instead of silently letting the second overwrite the first.
+	Unique bool
+}
+
+// indexTable returns the Engine table name an index's entries (derived
+// key -> primary key) are stored under, kept out of the "__"-prefixed
+// namespace so it can't collide with a real table registered via
+// RegisterTable.
+func indexTable(indexName string) string {
+	return "__index__" + indexName
+}
+
+// RegisterIndex adds (or replaces) a secondary index's definition in the
+// manifest. It does not itself populate the index's storage; call
+// RebuildIndex to do that.
+func (d *Database) RegisterIndex(info IndexInfo) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, exists := d.indexes[info.Name]; !exists {
+		d.indexOrd = append(d.indexOrd, info.Name)
+	}
+	d.indexes[info.Name] = info
+}
+
+func (d *Database) indexInfo(name string) (IndexInfo, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	info, ok := d.indexes[name]
+	return info, ok
+}
+
+func (d *Database) indexesForTable(table string) []IndexInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var out []IndexInfo
+	for _, name := range d.indexOrd {
+		if info := d.indexes[name]; info.Table == table {
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// RebuildIndex re-derives every entry of the named index from its
+// table's current row storage, replacing whatever the index's storage
+// held before. It is meant to be run after a bulk import or snapshot
+// restore populates row storage without going through the index
+// maintenance a normal Put would perform.
+func (d *Database) RebuildIndex(name string) error {
+	info, ok := d.indexInfo(name)
+	if !ok {
+		return fmt.Errorf("db: no index registered as %q", name)
+	}
+
+	if _, err := d.DeleteRange(indexTable(info.Name), nil, nil); err != nil {
+		return fmt.Errorf("db: rebuild index %q: clear old entries: %w", name, err)
+	}
+
+	cur, err := d.Engine.Scan(info.Table)
+	if err != nil {
+		return fmt.Errorf("db: rebuild index %q: scan %s: %w", name, info.Table, err)
+	}
+	defer cur.Close()
+
+	seen := map[string]bool{}
+	for cur.Next() {
+		pk := append([]byte(nil), cur.Key()...)
+		key, err := info.Key(cur.Value())
+		if err != nil {
+			return fmt.Errorf("db: rebuild index %q: derive key for row: %w", name, err)
+		}
+		if info.Unique {
+			if seen[string(key)] {
+				return fmt.Errorf("db: rebuild index %q: duplicate key for unique index", name)
+			}
+			seen[string(key)] = true
+		}
+		if err := d.Engine.Put(indexTable(info.Name), key, pk); err != nil {
+			return fmt.Errorf("db: rebuild index %q: write entry: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// IndexInconsistency describes one mismatch VerifyIndexes found between
+// a table's row storage and one of its indexes.
+type IndexInconsistency struct {
+	Index  string
+	Reason string
+	// Key is the primary key of the row the inconsistency was found
+	// against.
+	Key []byte
+}
+
+// VerifyIndexes re-derives, for every index registered on table, the key
+// every row in table should be found under, and reports every place
+// that disagrees with what the index's storage actually holds: a row
+// with no corresponding index entry, an index entry pointing at the
+// wrong row, and an index entry whose row no longer exists (orphaned by
+// a delete that didn't go through index maintenance). It does not
+// modify anything; RebuildIndex is the fix once a caller has decided a
+// reported inconsistency needs correcting.
+func (d *Database) VerifyIndexes(table string) ([]IndexInconsistency, error) {
+	var problems []IndexInconsistency
+
+	for _, info := range d.indexesForTable(table) {
+		rows, err := d.Engine.Scan(info.Table)
+		if err != nil {
+			return nil, fmt.Errorf("db: verify index %q: scan %s: %w", info.Name, info.Table, err)
+		}
+		for rows.Next() {
+			pk := append([]byte(nil), rows.Key()...)
+			key, err := info.Key(rows.Value())
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("db: verify index %q: derive key for row: %w", info.Name, err)
+			}
+			stored, ok, err := d.Engine.Get(indexTable(info.Name), key)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("db: verify index %q: read entry: %w", info.Name, err)
+			}
+			switch {
+			case !ok:
+				problems = append(problems, IndexInconsistency{Index: info.Name, Key: pk, Reason: "missing index entry"})
+			case !bytes.Equal(stored, pk):
+				problems = append(problems, IndexInconsistency{Index: info.Name, Key: pk, Reason: "index entry points to wrong row"})
+			}
+		}
+		if err := rows.Close(); err != nil {
+			return nil, err
+		}
+
+		entries, err := d.Engine.Scan(indexTable(info.Name))
+		if err != nil {
+			return nil, fmt.Errorf("db: verify index %q: scan entries: %w", info.Name, err)
+		}
+		for entries.Next() {
+			pk := entries.Value()
+			if _, ok, err := d.Engine.Get(info.Table, pk); err == nil && !ok {
+				problems = append(problems, IndexInconsistency{Index: info.Name, Key: append([]byte(nil), pk...), Reason: "orphaned index entry"})
+			}
+		}
+		if err := entries.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	return problems, nil
+}
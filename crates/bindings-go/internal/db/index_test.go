@@ -0,0 +1,93 @@
+package db
+
+import "testing"
+
+func byNameKey(row []byte) ([]byte, error) {
+	return EncodeOrderedKey(string(row)), nil
+}
+
+func TestRebuildIndexPopulatesFromRowStorage(t *testing.T) {
+	d := newTestDatabase(t)
+	d.Engine.Put("players", EncodeOrderedKey(uint32(1)), []byte("alice"))
+	d.Engine.Put("players", EncodeOrderedKey(uint32(2)), []byte("bob"))
+	d.RegisterIndex(IndexInfo{Name: "players_by_name", Table: "players", Key: byNameKey, Unique: true})
+
+	if err := d.RebuildIndex("players_by_name"); err != nil {
+		t.Fatalf("RebuildIndex: %v", err)
+	}
+
+	pk, ok, err := d.Engine.Get(indexTable("players_by_name"), EncodeOrderedKey("alice"))
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected index entry for alice")
+	}
+	if string(pk) != string(EncodeOrderedKey(uint32(1))) {
+		t.Fatalf("indexed pk = %v, want key for row 1", pk)
+	}
+}
+
+func TestRebuildIndexRejectsDuplicateUniqueKey(t *testing.T) {
+	d := newTestDatabase(t)
+	d.Engine.Put("players", EncodeOrderedKey(uint32(1)), []byte("alice"))
+	d.Engine.Put("players", EncodeOrderedKey(uint32(2)), []byte("alice"))
+	d.RegisterIndex(IndexInfo{Name: "players_by_name", Table: "players", Key: byNameKey, Unique: true})
+
+	if err := d.RebuildIndex("players_by_name"); err == nil {
+		t.Fatal("expected error for duplicate unique key")
+	}
+}
+
+func TestVerifyIndexesReportsMissingEntry(t *testing.T) {
+	d := newTestDatabase(t)
+	d.Engine.Put("players", EncodeOrderedKey(uint32(1)), []byte("alice"))
+	d.RegisterIndex(IndexInfo{Name: "players_by_name", Table: "players", Key: byNameKey})
+
+	problems, err := d.VerifyIndexes("players")
+	if err != nil {
+		t.Fatalf("VerifyIndexes: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Reason != "missing index entry" {
+		t.Fatalf("problems = %+v, want one missing index entry", problems)
+	}
+}
+
+func TestVerifyIndexesReportsOrphanedEntry(t *testing.T) {
+	d := newTestDatabase(t)
+	d.Engine.Put("players", EncodeOrderedKey(uint32(1)), []byte("alice"))
+	d.RegisterIndex(IndexInfo{Name: "players_by_name", Table: "players", Key: byNameKey})
+	if err := d.RebuildIndex("players_by_name"); err != nil {
+		t.Fatalf("RebuildIndex: %v", err)
+	}
+
+	if _, err := d.DeleteRange("players", nil, nil); err != nil {
+		t.Fatalf("DeleteRange: %v", err)
+	}
+
+	problems, err := d.VerifyIndexes("players")
+	if err != nil {
+		t.Fatalf("VerifyIndexes: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Reason != "orphaned index entry" {
+		t.Fatalf("problems = %+v, want one orphaned index entry", problems)
+	}
+}
+
+func TestVerifyIndexesCleanAfterRebuild(t *testing.T) {
+	d := newTestDatabase(t)
+	d.Engine.Put("players", EncodeOrderedKey(uint32(1)), []byte("alice"))
+	d.Engine.Put("players", EncodeOrderedKey(uint32(2)), []byte("bob"))
+	d.RegisterIndex(IndexInfo{Name: "players_by_name", Table: "players", Key: byNameKey, Unique: true})
+	if err := d.RebuildIndex("players_by_name"); err != nil {
+		t.Fatalf("RebuildIndex: %v", err)
+	}
+
+	problems, err := d.VerifyIndexes("players")
+	if err != nil {
+		t.Fatalf("VerifyIndexes: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("problems = %+v, want none", problems)
+	}
+}
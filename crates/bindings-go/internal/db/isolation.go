@@ -0,0 +1,32 @@
+package db
+
+import "fmt"
+
+// IsolationLevel selects how a Txn's reads and writes interact with
+// concurrently running transactions against the same Database.
+type IsolationLevel int
+
+const (
+	// ReadCommitted takes no locks: every Get returns the Engine's
+	// current value and every Put writes straight through. Two
+	// transactions may interleave read-modify-write cycles on the same
+	// key, so it does not prevent lost updates or write skew.
+	ReadCommitted IsolationLevel = iota
+
+	// Serializable acquires (via the Database's shared LockManager) and
+	// holds a lock on every key a transaction touches, releasing all of
+	// them on Commit or Rollback, so concurrent transactions touching the
+	// same key are serialized and lost updates cannot occur.
+	Serializable
+)
+
+func (l IsolationLevel) String() string {
+	switch l {
+	case ReadCommitted:
+		return "read-committed"
+	case Serializable:
+		return "serializable"
+	default:
+		return fmt.Sprintf("IsolationLevel(%d)", int(l))
+	}
+}
@@ -0,0 +1,46 @@
+package db
+
+import "testing"
+
+func TestKVEngineRangeScanOrder(t *testing.T) {
+	e := NewKVEngine(NewMemKVStore(), KVConfig{})
+	for _, n := range []uint32{3, 1, 2} {
+		key := EncodeOrderedKey(n)
+		if err := e.Put("scores", key, []byte{byte(n)}); err != nil {
+			t.Fatalf("Put(%d): %v", n, err)
+		}
+	}
+	cur, err := e.Scan("scores")
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	defer cur.Close()
+
+	var got []byte
+	for cur.Next() {
+		got = append(got, cur.Value()[0])
+	}
+	want := []byte{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestKVEngineCompactionTrigger(t *testing.T) {
+	e := NewKVEngine(NewMemKVStore(), KVConfig{CompactionThreshold: 2})
+	e.Put("t", []byte("a"), []byte("1"))
+	e.Put("t", []byte("b"), []byte("2"))
+	e.Delete("t", []byte("a"))
+	if e.tombst != 1 {
+		t.Fatalf("tombst = %d, want 1", e.tombst)
+	}
+	e.Delete("t", []byte("b"))
+	if e.tombst != 0 {
+		t.Fatalf("expected compaction to reset tombst, got %d", e.tombst)
+	}
+}
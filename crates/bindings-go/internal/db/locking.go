@@ -0,0 +1,105 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ReducerConflictError is returned when a reducer's transaction conflicts
+// with another transaction under first-committer-wins semantics: someone
+// else committed a write to the same resource first.
+type ReducerConflictError struct {
+	Table  string
+	Key    []byte
+	Holder string
+}
+
+func (e *ReducerConflictError) Error() string {
+	return fmt.Sprintf("db: conflict on %s/%x: held by transaction %s", e.Table, e.Key, e.Holder)
+}
+
+// DeadlockError is returned when acquiring a lock would complete a cycle
+// in the wait-for graph. Cycle lists the transaction IDs in the cycle,
+// starting and ending at the caller.
+type DeadlockError struct {
+	Cycle []string
+}
+
+func (e *DeadlockError) Error() string {
+	return fmt.Sprintf("db: deadlock detected: %v", e.Cycle)
+}
+
+// LockManager implements row-level locking for the transaction layer:
+// each resource (identified by an opaque string key, typically
+// "table/key") is held by at most one transaction at a time. Acquire
+// fails fast with a DeadlockError instead of blocking forever when
+// granting the lock would create a cycle in the wait-for graph.
+type LockManager struct {
+	mu      sync.Mutex
+	holders map[string]string // resource -> holding transaction ID
+	waitFor map[string]string // transaction ID -> transaction ID it is blocked on
+}
+
+// NewLockManager returns an empty LockManager.
+func NewLockManager() *LockManager {
+	return &LockManager{holders: map[string]string{}, waitFor: map[string]string{}}
+}
+
+// Acquire locks resource on behalf of txnID, blocking the caller's logical
+// wait (recorded, not slept on) if it is already held. If granting the
+// lock would create a cycle, Acquire returns a DeadlockError and grants
+// nothing.
+func (lm *LockManager) Acquire(txnID, resource string) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	holder, held := lm.holders[resource]
+	if !held || holder == txnID {
+		lm.holders[resource] = txnID
+		delete(lm.waitFor, txnID)
+		return nil
+	}
+
+	if cycle := lm.wouldDeadlock(txnID, holder); cycle != nil {
+		return &DeadlockError{Cycle: cycle}
+	}
+	lm.waitFor[txnID] = holder
+	return &ReducerConflictError{Holder: holder}
+}
+
+// wouldDeadlock walks the wait-for graph starting at target; if it leads
+// back to start, that path (plus start) is the cycle.
+func (lm *LockManager) wouldDeadlock(start, target string) []string {
+	path := []string{start}
+	cur := target
+	for {
+		path = append(path, cur)
+		if cur == start {
+			return path
+		}
+		next, ok := lm.waitFor[cur]
+		if !ok {
+			return nil
+		}
+		cur = next
+	}
+}
+
+// Release drops every lock held by txnID and clears any recorded wait
+// edge for it, e.g. after the transaction commits, rolls back, or aborts
+// on conflict.
+func (lm *LockManager) Release(txnID string) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	for resource, holder := range lm.holders {
+		if holder == txnID {
+			delete(lm.holders, resource)
+		}
+	}
+	delete(lm.waitFor, txnID)
+	for waiter, blocker := range lm.waitFor {
+		if blocker == txnID {
+			delete(lm.waitFor, waiter)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package db
+
+import "testing"
+
+func TestLockManagerConflict(t *testing.T) {
+	lm := NewLockManager()
+	if err := lm.Acquire("txn1", "accounts/p1"); err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+	err := lm.Acquire("txn2", "accounts/p1")
+	if _, ok := err.(*ReducerConflictError); !ok {
+		t.Fatalf("err = %v, want *ReducerConflictError", err)
+	}
+}
+
+func TestLockManagerDetectsDeadlock(t *testing.T) {
+	lm := NewLockManager()
+	if err := lm.Acquire("txn1", "a"); err != nil {
+		t.Fatalf("Acquire a: %v", err)
+	}
+	if err := lm.Acquire("txn2", "b"); err != nil {
+		t.Fatalf("Acquire b: %v", err)
+	}
+	if _, ok := lm.Acquire("txn1", "b").(*ReducerConflictError); !ok {
+		t.Fatalf("expected txn1 to block on b")
+	}
+	err := lm.Acquire("txn2", "a")
+	dl, ok := err.(*DeadlockError)
+	if !ok {
+		t.Fatalf("err = %v, want *DeadlockError", err)
+	}
+	if len(dl.Cycle) < 2 {
+		t.Fatalf("Cycle = %v, want at least 2 entries", dl.Cycle)
+	}
+}
@@ -0,0 +1,70 @@
+package db
+
+import "sort"
+
+// MemEngine is a simple, non-durable Engine that keeps every table as an
+// in-memory map. It is the default engine used by embedded hosts and by
+// tests that don't need data to survive a restart; see WALEngine for a
+// durable variant built on top of it.
+type MemEngine struct {
+	tables map[string]map[string][]byte
+}
+
+// NewMemEngine returns an empty MemEngine.
+func NewMemEngine() *MemEngine {
+	return &MemEngine{tables: make(map[string]map[string][]byte)}
+}
+
+func (e *MemEngine) table(name string) map[string][]byte {
+	t, ok := e.tables[name]
+	if !ok {
+		t = make(map[string][]byte)
+		e.tables[name] = t
+	}
+	return t
+}
+
+func (e *MemEngine) Put(table string, key, value []byte) error {
+	e.table(table)[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+
+func (e *MemEngine) Get(table string, key []byte) ([]byte, bool, error) {
+	v, ok := e.table(table)[string(key)]
+	return v, ok, nil
+}
+
+func (e *MemEngine) Delete(table string, key []byte) error {
+	delete(e.table(table), string(key))
+	return nil
+}
+
+func (e *MemEngine) Scan(table string) (Cursor, error) {
+	t := e.table(table)
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([][]byte, len(keys))
+	byteKeys := make([][]byte, len(keys))
+	for i, k := range keys {
+		byteKeys[i] = []byte(k)
+		values[i] = t[k]
+	}
+	return &sliceCursor{keys: byteKeys, values: values, pos: -1}, nil
+}
+
+func (e *MemEngine) Close() error { return nil }
+
+// ListTables returns the names of every table with at least one row ever
+// written to it, in no particular order. It lets callers without a schema
+// manifest (e.g. the backup CLI) discover what to snapshot.
+func (e *MemEngine) ListTables() []string {
+	names := make([]string, 0, len(e.tables))
+	for name := range e.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
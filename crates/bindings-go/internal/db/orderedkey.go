@@ -0,0 +1,112 @@
+package db
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// EncodeOrderedKey concatenates the BSATN-typed values in parts into a byte
+// string whose lexicographic (unsigned byte-wise) ordering matches the
+// natural ordering of the tuple, matching the ordering BTree indexes must
+// provide for range scans. This differs from the BSATN wire format (which
+// is little-endian and not order-preserving) and is only used for
+// constructing Engine keys.
+//
+// Supported element types: bool, int8/16/32/64, uint8/16/32/64,
+// float32/64, string, []byte.
+func EncodeOrderedKey(parts ...any) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = appendOrdered(out, p)
+	}
+	return out
+}
+
+func appendOrdered(out []byte, p any) []byte {
+	switch v := p.(type) {
+	case bool:
+		if v {
+			return append(out, 1)
+		}
+		return append(out, 0)
+	case int8:
+		return append(out, byte(uint8(v)^0x80))
+	case int16:
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], uint16(v)^0x8000)
+		return append(out, b[:]...)
+	case int32:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(v)^0x80000000)
+		return append(out, b[:]...)
+	case int64:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(v)^0x8000000000000000)
+		return append(out, b[:]...)
+	case uint8:
+		return append(out, v)
+	case uint16:
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], v)
+		return append(out, b[:]...)
+	case uint32:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], v)
+		return append(out, b[:]...)
+	case uint64:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], v)
+		return append(out, b[:]...)
+	case float32:
+		return append(out, orderedFloatBytes32(v)...)
+	case float64:
+		return append(out, orderedFloatBytes64(v)...)
+	case string:
+		return appendOrderedBytes(out, []byte(v))
+	case []byte:
+		return appendOrderedBytes(out, v)
+	default:
+		panic("db: EncodeOrderedKey: unsupported type")
+	}
+}
+
+// appendOrderedBytes escapes 0x00 as 0x00 0xFF and terminates the field
+// with 0x00 0x00, so concatenated variable-length fields still compare
+// correctly against each other.
+func appendOrderedBytes(out, b []byte) []byte {
+	for _, c := range b {
+		if c == 0x00 {
+			out = append(out, 0x00, 0xFF)
+		} else {
+			out = append(out, c)
+		}
+	}
+	return append(out, 0x00, 0x00)
+}
+
+// orderedFloatBytes32/64 flip the sign bit of positive floats and invert
+// all bits of negative floats, giving a byte order that matches IEEE-754's
+// total order for the (finite, non-NaN) common case.
+func orderedFloatBytes32(f float32) []byte {
+	bits := math.Float32bits(f)
+	if bits&0x80000000 != 0 {
+		bits = ^bits
+	} else {
+		bits |= 0x80000000
+	}
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], bits)
+	return b[:]
+}
+
+func orderedFloatBytes64(f float64) []byte {
+	bits := math.Float64bits(f)
+	if bits&0x8000000000000000 != 0 {
+		bits = ^bits
+	} else {
+		bits |= 0x8000000000000000
+	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], bits)
+	return b[:]
+}
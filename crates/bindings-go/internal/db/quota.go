@@ -0,0 +1,231 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+)
+
+// QuotaConfig bounds a single table's row storage. Any field left at
+// zero is unbounded, so the zero QuotaConfig{} (the default for a table
+// that never calls SetTableQuota) imposes no limits at all.
+type QuotaConfig struct {
+	// MaxRowBytes bounds the encoded size of any single row's value.
+	MaxRowBytes int
+	// MaxRows bounds how many rows the table may hold at once.
+	MaxRows int
+	// MaxTableBytes bounds the sum of every row's value size in the table.
+	MaxTableBytes int
+}
+
+// DatabaseQuota bounds a Database as a whole, independent of any
+// per-table QuotaConfig.
+type DatabaseQuota struct {
+	// MaxTotalBytes bounds the sum of every row's value size across
+	// every table in the database.
+	MaxTotalBytes int
+}
+
+// Quota error codes, distinct per limit so a caller (or a metrics
+// dashboard reading Database.QuotaRejections) can tell which one was hit
+// without string-matching Error() — the same "distinct status per cause"
+// shape as internal/wasm's statusRowTooLarge/statusUnauthorized.
+const (
+	QuotaErrRowBytes = iota + 1
+	QuotaErrTableRows
+	QuotaErrTableBytes
+	QuotaErrDatabaseBytes
+)
+
+// QuotaExceededError is returned by Txn.Put when a write would violate a
+// table's QuotaConfig or the Database's DatabaseQuota. Code identifies
+// which limit was hit (see the QuotaErr* constants).
+type QuotaExceededError struct {
+	Code  int
+	Table string
+	Value int
+	Max   int
+}
+
+func (e *QuotaExceededError) Error() string {
+	switch e.Code {
+	case QuotaErrRowBytes:
+		return fmt.Sprintf("db: row of %d bytes in table %q exceeds its %d byte row quota", e.Value, e.Table, e.Max)
+	case QuotaErrTableRows:
+		return fmt.Sprintf("db: table %q would hold %d rows, exceeding its %d row quota", e.Table, e.Value, e.Max)
+	case QuotaErrTableBytes:
+		return fmt.Sprintf("db: table %q would hold %d bytes, exceeding its %d byte quota", e.Table, e.Value, e.Max)
+	case QuotaErrDatabaseBytes:
+		return fmt.Sprintf("db: database would hold %d bytes, exceeding its %d byte quota", e.Value, e.Max)
+	default:
+		return fmt.Sprintf("db: table %q write rejected by quota (code %d)", e.Table, e.Code)
+	}
+}
+
+// quotaMetrics counts how many writes each quota code has rejected,
+// mirroring the mutex-protected-counters shape already used by
+// pkg/spacetimedb's healthCounters.
+type quotaMetrics struct {
+	mu     sync.Mutex
+	counts map[int]uint64
+}
+
+func (m *quotaMetrics) record(code int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[int]uint64)
+	}
+	m.counts[code]++
+}
+
+func (m *quotaMetrics) snapshot() map[int]uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[int]uint64, len(m.counts))
+	for code, n := range m.counts {
+		out[code] = n
+	}
+	return out
+}
+
+// SetTableQuota sets (or clears, with the zero QuotaConfig{}) the row
+// size, row count, and table byte quotas enforced on table by
+// subsequent Txn.Put calls. It does not retroactively check table's
+// existing rows.
+func (d *Database) SetTableQuota(table string, quota QuotaConfig) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.quotas == nil {
+		d.quotas = make(map[string]QuotaConfig)
+	}
+	d.quotas[table] = quota
+}
+
+// TableQuota returns the quota currently configured for table, or the
+// zero QuotaConfig{} (unbounded) if SetTableQuota was never called for it.
+func (d *Database) TableQuota(table string) QuotaConfig {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.quotas[table]
+}
+
+// SetDatabaseQuota sets (or clears, with the zero DatabaseQuota{}) the
+// total byte quota enforced across every table by subsequent Txn.Put
+// calls. It does not retroactively check the database's existing rows.
+func (d *Database) SetDatabaseQuota(quota DatabaseQuota) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dbQuota = quota
+}
+
+// QuotaRejections returns how many writes have been rejected by each
+// quota error code (see the QuotaErr* constants) since the Database was
+// created, for a caller wiring quota simulation into its own metrics.
+func (d *Database) QuotaRejections() map[int]uint64 {
+	return d.quotaMetrics.snapshot()
+}
+
+// checkQuota returns a *QuotaExceededError if writing value under key in
+// table would violate table's QuotaConfig or the Database's
+// DatabaseQuota, and records the rejection in QuotaRejections. It has
+// nothing to enforce against (and does not touch the Engine at all) for
+// a table with no quota configured and no DatabaseQuota set, which is
+// the common case.
+func (d *Database) checkQuota(table string, key, value []byte) error {
+	d.mu.Lock()
+	quota := d.quotas[table]
+	dbQuota := d.dbQuota
+	if d.quotaedTables == nil {
+		d.quotaedTables = make(map[string]struct{})
+	}
+	d.quotaedTables[table] = struct{}{}
+	d.mu.Unlock()
+
+	if quota.MaxRowBytes > 0 && len(value) > quota.MaxRowBytes {
+		return d.rejectQuota(&QuotaExceededError{Code: QuotaErrRowBytes, Table: table, Value: len(value), Max: quota.MaxRowBytes})
+	}
+	if quota.MaxRows == 0 && quota.MaxTableBytes == 0 && dbQuota.MaxTotalBytes == 0 {
+		return nil
+	}
+
+	existing, exists, err := d.Engine.Get(table, key)
+	if err != nil {
+		return err
+	}
+
+	if quota.MaxRows > 0 && !exists {
+		rows, _, err := d.tableStats(table)
+		if err != nil {
+			return err
+		}
+		if rows+1 > quota.MaxRows {
+			return d.rejectQuota(&QuotaExceededError{Code: QuotaErrTableRows, Table: table, Value: rows + 1, Max: quota.MaxRows})
+		}
+	}
+	if quota.MaxTableBytes > 0 {
+		_, tableBytes, err := d.tableStats(table)
+		if err != nil {
+			return err
+		}
+		newBytes := tableBytes - len(existing) + len(value)
+		if newBytes > quota.MaxTableBytes {
+			return d.rejectQuota(&QuotaExceededError{Code: QuotaErrTableBytes, Table: table, Value: newBytes, Max: quota.MaxTableBytes})
+		}
+	}
+	if dbQuota.MaxTotalBytes > 0 {
+		total, err := d.totalBytes()
+		if err != nil {
+			return err
+		}
+		newTotal := total - len(existing) + len(value)
+		if newTotal > dbQuota.MaxTotalBytes {
+			return d.rejectQuota(&QuotaExceededError{Code: QuotaErrDatabaseBytes, Table: table, Value: newTotal, Max: dbQuota.MaxTotalBytes})
+		}
+	}
+	return nil
+}
+
+func (d *Database) rejectQuota(err *QuotaExceededError) error {
+	d.quotaMetrics.record(err.Code)
+	return err
+}
+
+// tableStats scans table to compute its current row count and total
+// value bytes, the same full-scan approach Handle.Health already uses
+// for DatastoreRows; quota checks are a simulation/testing aid, not a
+// hot path, so an O(rows) scan per write is an acceptable trade for not
+// having to keep a separate running total in sync with the Engine.
+func (d *Database) tableStats(table string) (rows, bytes int, err error) {
+	cur, err := d.Engine.Scan(table)
+	if err != nil {
+		return 0, 0, err
+	}
+	for cur.Next() {
+		rows++
+		bytes += len(cur.Value())
+	}
+	return rows, bytes, cur.Close()
+}
+
+// totalBytes sums tableStats' byte count across every table checkQuota
+// has ever seen. It deliberately doesn't use Tables(), since a caller
+// exercising quotas has no obligation to have called RegisterTable for
+// every table it writes to.
+func (d *Database) totalBytes() (int, error) {
+	d.mu.Lock()
+	names := make([]string, 0, len(d.quotaedTables))
+	for name := range d.quotaedTables {
+		names = append(names, name)
+	}
+	d.mu.Unlock()
+
+	total := 0
+	for _, name := range names {
+		_, bytes, err := d.tableStats(name)
+		if err != nil {
+			return 0, err
+		}
+		total += bytes
+	}
+	return total, nil
+}
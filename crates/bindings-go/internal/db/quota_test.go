@@ -0,0 +1,109 @@
+package db
+
+import "testing"
+
+func TestTxnPutRejectsRowOverMaxRowBytes(t *testing.T) {
+	d := NewDatabase(NewMemEngine())
+	d.SetTableQuota("widgets", QuotaConfig{MaxRowBytes: 4})
+
+	txn := d.Begin()
+	err := txn.Put("widgets", []byte("k"), []byte("toolong"))
+	txn.Rollback()
+
+	var qerr *QuotaExceededError
+	if !asQuotaError(err, &qerr) {
+		t.Fatalf("Put error = %v, want *QuotaExceededError", err)
+	}
+	if qerr.Code != QuotaErrRowBytes {
+		t.Fatalf("Code = %d, want QuotaErrRowBytes", qerr.Code)
+	}
+	if got := d.QuotaRejections()[QuotaErrRowBytes]; got != 1 {
+		t.Fatalf("QuotaRejections[RowBytes] = %d, want 1", got)
+	}
+}
+
+func TestTxnPutRejectsTableOverMaxRows(t *testing.T) {
+	d := NewDatabase(NewMemEngine())
+	d.SetTableQuota("widgets", QuotaConfig{MaxRows: 1})
+
+	txn := d.Begin()
+	if err := txn.Put("widgets", []byte("a"), []byte("v")); err != nil {
+		t.Fatalf("first Put: %v", err)
+	}
+	err := txn.Put("widgets", []byte("b"), []byte("v"))
+	txn.Rollback()
+
+	var qerr *QuotaExceededError
+	if !asQuotaError(err, &qerr) || qerr.Code != QuotaErrTableRows {
+		t.Fatalf("second Put error = %v, want QuotaErrTableRows", err)
+	}
+}
+
+func TestTxnPutAllowsOverwriteAtMaxRows(t *testing.T) {
+	d := NewDatabase(NewMemEngine())
+	d.SetTableQuota("widgets", QuotaConfig{MaxRows: 1})
+
+	txn := d.Begin()
+	if err := txn.Put("widgets", []byte("a"), []byte("v1")); err != nil {
+		t.Fatalf("first Put: %v", err)
+	}
+	if err := txn.Put("widgets", []byte("a"), []byte("v2")); err != nil {
+		t.Fatalf("overwrite Put: %v", err)
+	}
+	txn.Rollback()
+}
+
+func TestTxnPutRejectsTableOverMaxTableBytes(t *testing.T) {
+	d := NewDatabase(NewMemEngine())
+	d.SetTableQuota("widgets", QuotaConfig{MaxTableBytes: 3})
+
+	txn := d.Begin()
+	if err := txn.Put("widgets", []byte("a"), []byte("ab")); err != nil {
+		t.Fatalf("first Put: %v", err)
+	}
+	err := txn.Put("widgets", []byte("b"), []byte("ab"))
+	txn.Rollback()
+
+	var qerr *QuotaExceededError
+	if !asQuotaError(err, &qerr) || qerr.Code != QuotaErrTableBytes {
+		t.Fatalf("second Put error = %v, want QuotaErrTableBytes", err)
+	}
+}
+
+func TestTxnPutRejectsDatabaseOverMaxTotalBytes(t *testing.T) {
+	d := NewDatabase(NewMemEngine())
+	d.SetDatabaseQuota(DatabaseQuota{MaxTotalBytes: 3})
+
+	txn := d.Begin()
+	if err := txn.Put("widgets", []byte("a"), []byte("ab")); err != nil {
+		t.Fatalf("first Put: %v", err)
+	}
+	err := txn.Put("gadgets", []byte("b"), []byte("ab"))
+	txn.Rollback()
+
+	var qerr *QuotaExceededError
+	if !asQuotaError(err, &qerr) || qerr.Code != QuotaErrDatabaseBytes {
+		t.Fatalf("second Put error = %v, want QuotaErrDatabaseBytes", err)
+	}
+	if got := d.QuotaRejections()[QuotaErrDatabaseBytes]; got != 1 {
+		t.Fatalf("QuotaRejections[DatabaseBytes] = %d, want 1", got)
+	}
+}
+
+func TestTxnPutUnaffectedByQuotaWhenUnconfigured(t *testing.T) {
+	d := NewDatabase(NewMemEngine())
+
+	txn := d.Begin()
+	if err := txn.Put("widgets", []byte("a"), []byte("anything at all")); err != nil {
+		t.Fatalf("Put with no quota configured: %v", err)
+	}
+	txn.Rollback()
+}
+
+func asQuotaError(err error, out **QuotaExceededError) bool {
+	qerr, ok := err.(*QuotaExceededError)
+	if ok {
+		*out = qerr
+	}
+	return ok
+}
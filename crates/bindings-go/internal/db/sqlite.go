@@ -0,0 +1,135 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// SQLiteEngine is an Engine backed by a SQL database reachable through
+// database/sql. It is written against the standard library's driver
+// interface rather than a concrete SQLite package so that callers can wire
+// up whichever driver suits their build (e.g. modernc.org/sqlite for a
+// pure-Go, CGO-free binary, or mattn/go-sqlite3 where CGO is acceptable):
+//
+//	db, err := sql.Open("sqlite", "spacetime.db") // driver registered by caller's import
+//	engine, err := db.NewSQLiteEngine(db)
+//
+// Every table is stored in its own SQL table named "row_<table>" with a
+// `key BLOB PRIMARY KEY` and `value BLOB` column; row bytes are always the
+// row's BSATN encoding, so the engine imposes no schema migration burden
+// beyond creating the wrapper table on first use.
+type SQLiteEngine struct {
+	sqlDB *sql.DB
+}
+
+// NewSQLiteEngine wraps an already-opened *sql.DB. The caller owns the
+// underlying connection's lifetime beyond what Close releases.
+func NewSQLiteEngine(sqlDB *sql.DB) (*SQLiteEngine, error) {
+	if err := sqlDB.Ping(); err != nil {
+		return nil, fmt.Errorf("db: sqlite engine: %w", err)
+	}
+	return &SQLiteEngine{sqlDB: sqlDB}, nil
+}
+
+func (e *SQLiteEngine) ensureTable(table string) error {
+	_, err := e.sqlDB.Exec(fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s (key BLOB PRIMARY KEY, value BLOB NOT NULL)`,
+		quoteTable(table),
+	))
+	return err
+}
+
+func (e *SQLiteEngine) Put(table string, key, value []byte) error {
+	if err := e.ensureTable(table); err != nil {
+		return err
+	}
+	_, err := e.sqlDB.Exec(
+		fmt.Sprintf(`INSERT INTO %s (key, value) VALUES (?, ?)
+			ON CONFLICT(key) DO UPDATE SET value = excluded.value`, quoteTable(table)),
+		key, value,
+	)
+	return err
+}
+
+func (e *SQLiteEngine) Get(table string, key []byte) ([]byte, bool, error) {
+	if err := e.ensureTable(table); err != nil {
+		return nil, false, err
+	}
+	var value []byte
+	err := e.sqlDB.QueryRow(
+		fmt.Sprintf(`SELECT value FROM %s WHERE key = ?`, quoteTable(table)), key,
+	).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (e *SQLiteEngine) Delete(table string, key []byte) error {
+	if err := e.ensureTable(table); err != nil {
+		return err
+	}
+	_, err := e.sqlDB.Exec(fmt.Sprintf(`DELETE FROM %s WHERE key = ?`, quoteTable(table)), key)
+	return err
+}
+
+func (e *SQLiteEngine) Scan(table string) (Cursor, error) {
+	if err := e.ensureTable(table); err != nil {
+		return nil, err
+	}
+	rows, err := e.sqlDB.Query(fmt.Sprintf(`SELECT key, value FROM %s ORDER BY key`, quoteTable(table)))
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteCursor{rows: rows}, nil
+}
+
+func (e *SQLiteEngine) Close() error {
+	return e.sqlDB.Close()
+}
+
+type sqliteCursor struct {
+	rows       *sql.Rows
+	key, value []byte
+}
+
+func (c *sqliteCursor) Next() bool {
+	if !c.rows.Next() {
+		return false
+	}
+	return c.rows.Scan(&c.key, &c.value) == nil
+}
+
+func (c *sqliteCursor) Key() []byte   { return c.key }
+func (c *sqliteCursor) Value() []byte { return c.value }
+func (c *sqliteCursor) Close() error  { return c.rows.Close() }
+
+func quoteTable(table string) string {
+	return `"row_` + table + `"`
+}
+
+// ColumnType returns the SQLite column affinity that best represents t,
+// used when generating human-inspectable projection tables/views over the
+// BSATN blob store (`row_<table>.value`) for ad-hoc SQL access. Types
+// without a natural SQLite affinity (products, sums, arrays, maps) fall
+// back to BLOB, holding the field's raw BSATN encoding.
+func ColumnType(t bsatn.AlgebraicType) string {
+	switch t.Kind {
+	case bsatn.KindBool, bsatn.KindI8, bsatn.KindU8, bsatn.KindI16, bsatn.KindU16,
+		bsatn.KindI32, bsatn.KindU32, bsatn.KindI64, bsatn.KindU64:
+		return "INTEGER"
+	case bsatn.KindF32, bsatn.KindF64:
+		return "REAL"
+	case bsatn.KindString:
+		return "TEXT"
+	default:
+		// I128/U128, Product, Sum, Array, Map: no lossless native affinity,
+		// so store the BSATN encoding verbatim.
+		return "BLOB"
+	}
+}
@@ -0,0 +1,195 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+var txnSeq int64
+
+// Txn is a unit of work against a Database, whose Get/Put semantics
+// depend on the isolation level it was started with (see
+// Database.Begin and Database.SetIsolationLevel).
+type Txn struct {
+	id      string
+	db      *Database
+	level   IsolationLevel
+	touched []string // resources locked under Serializable, for release on end
+	pending ChangeSet
+	undo    []undoOp // prior state for each write, oldest first, for Rollback
+	done    bool
+}
+
+// undoOp records what a table/key held before one of Txn's Put/Delete
+// calls wrote through to the Engine, so Rollback can restore it.
+type undoOp struct {
+	table  string
+	key    []byte
+	hadOld bool
+	old    []byte
+}
+
+// Begin starts a new Txn against d, using d's currently configured
+// isolation level.
+func (d *Database) Begin() *Txn {
+	id := fmt.Sprintf("txn-%d", atomic.AddInt64(&txnSeq, 1))
+	return &Txn{id: id, db: d, level: d.IsolationLevel()}
+}
+
+// ID returns the identifier Txn uses with the Database's LockManager,
+// useful for a test asserting on DeadlockError.Cycle or
+// ReducerConflictError.Holder.
+func (t *Txn) ID() string { return t.id }
+
+// Get reads key from table. Under Serializable it first acquires (and
+// holds until Commit/Rollback) a lock on the key, so a concurrent
+// transaction touching the same key blocks or conflicts; under
+// ReadCommitted it takes no lock and simply returns the Engine's
+// current value.
+func (t *Txn) Get(table string, key []byte) ([]byte, bool, error) {
+	if t.level == Serializable {
+		if err := t.lock(table, key); err != nil {
+			return nil, false, err
+		}
+	}
+	return t.db.Engine.Get(table, key)
+}
+
+// Put writes key/value in table, taking the same lock Get does under
+// Serializable before writing through to the Engine. It fails with a
+// *QuotaExceededError, before taking any lock or touching the Engine,
+// if the write would violate table's QuotaConfig or the Database's
+// DatabaseQuota (see Database.SetTableQuota/SetDatabaseQuota).
+//
+// Put records a RowChange (ChangeInsert if key didn't already exist in
+// table, ChangeUpdate otherwise) that Commit/Rollback delivers to the
+// Database's ChangeListeners together with every other change t made,
+// once t ends (see OnChange).
+func (t *Txn) Put(table string, key, value []byte) error {
+	if err := t.db.checkQuota(table, key, value); err != nil {
+		return err
+	}
+	if t.level == Serializable {
+		if err := t.lock(table, key); err != nil {
+			return err
+		}
+	}
+	old, existed, err := t.db.Engine.Get(table, key)
+	if err != nil {
+		return err
+	}
+	if err := t.db.Engine.Put(table, key, value); err != nil {
+		return err
+	}
+	t.undo = append(t.undo, undoOp{table: table, key: key, hadOld: existed, old: old})
+	op := ChangeInsert
+	if existed {
+		op = ChangeUpdate
+	}
+	t.pending = append(t.pending, RowChange{Table: table, Key: key, Value: value, Op: op})
+	return nil
+}
+
+// Delete removes key from table, taking the same lock Put does under
+// Serializable before writing through to the Engine. It is not an error
+// to delete a key that does not exist, matching Engine.Delete, but no
+// RowChange is recorded for it since nothing observable changed.
+func (t *Txn) Delete(table string, key []byte) error {
+	if t.level == Serializable {
+		if err := t.lock(table, key); err != nil {
+			return err
+		}
+	}
+	prev, existed, err := t.db.Engine.Get(table, key)
+	if err != nil {
+		return err
+	}
+	if err := t.db.Engine.Delete(table, key); err != nil {
+		return err
+	}
+	if existed {
+		t.undo = append(t.undo, undoOp{table: table, key: key, hadOld: true, old: prev})
+		t.pending = append(t.pending, RowChange{Table: table, Key: key, Value: prev, Op: ChangeDelete})
+	}
+	return nil
+}
+
+func (t *Txn) lock(table string, key []byte) error {
+	resource := table + "/" + string(key)
+	if err := t.db.locks.Acquire(t.id, resource); err != nil {
+		return err
+	}
+	t.touched = append(t.touched, resource)
+	return nil
+}
+
+// Commit ends the transaction, releasing any locks it holds, and
+// delivers every RowChange t made to the Database's ChangeListeners as a
+// single ChangeSet (see OnChange), so a subscriber applies t's writes
+// atomically instead of one at a time.
+func (t *Txn) Commit() error {
+	return t.end()
+}
+
+// Rollback undoes every write t made, restoring each touched key to
+// whatever it held (or removing it, if it didn't exist) before t's first
+// Put or Delete of it, then ends the transaction the same way Commit
+// does — except no RowChanges reach ChangeListeners, since nothing t did
+// is observable once this returns. Writes are undone in reverse order, so
+// a key t wrote more than once is restored to its value from before t's
+// very first write to it, not its most recent one.
+//
+// Under Serializable, t holds every touched key's lock until this
+// returns, so nothing else could have written the key in between and
+// this restoration is exact. Under ReadCommitted, Put/Delete takes no
+// lock, so a concurrent transaction could have written the same key
+// between t's write and this Rollback; restoring t's recorded prior
+// value in that case clobbers the concurrent write, the same lost-update
+// hazard ReadCommitted already accepts on every other write.
+//
+// If the Engine itself fails partway through undoing t's writes (e.g. a
+// disk error from a persistent Engine), Rollback still ends t — releasing
+// its locks and clearing its pending changes — instead of leaving a
+// Serializable t's locked keys wedged in the LockManager for the rest of
+// the Database's life; the Engine error is still returned, wrapped
+// together with any end error, so the caller knows the undo was
+// incomplete.
+func (t *Txn) Rollback() error {
+	if t.done {
+		return fmt.Errorf("db: txn %s already ended", t.id)
+	}
+	var errs []error
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		op := t.undo[i]
+		var err error
+		if op.hadOld {
+			err = t.db.Engine.Put(op.table, op.key, op.old)
+		} else {
+			err = t.db.Engine.Delete(op.table, op.key)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.pending = nil
+	if err := t.end(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("db: txn %s: rollback: %w", t.id, errors.Join(errs...))
+	}
+	return nil
+}
+
+func (t *Txn) end() error {
+	if t.done {
+		return fmt.Errorf("db: txn %s already ended", t.id)
+	}
+	t.done = true
+	if t.level == Serializable {
+		t.db.locks.Release(t.id)
+	}
+	t.db.notify(t.pending)
+	return nil
+}
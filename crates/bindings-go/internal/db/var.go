@@ -0,0 +1,31 @@
+package db
+
+// stVarTable is the reserved Engine table name Database uses to back
+// module variable storage, named after SpacetimeDB's own st_var system
+// table, which stores a module's persisted configuration the same way.
+const stVarTable = "st_var"
+
+// GetVar returns the module-level variable last stored under name by
+// SetVar, or ok=false if nothing has been stored under that name in d
+// yet — the Go host's emulation of a module reading its own st_var row.
+func (d *Database) GetVar(name string) (value string, ok bool, err error) {
+	raw, ok, err := d.Engine.Get(stVarTable, []byte(name))
+	if err != nil || !ok {
+		return "", ok, err
+	}
+	return string(raw), true, nil
+}
+
+// SetVar stores value under name, creating or overwriting whatever was
+// stored there before — the Go host's emulation of a module writing to
+// its own st_var row, so a test can preseed configuration a module reads
+// at startup without first driving a reducer call that writes it.
+func (d *Database) SetVar(name, value string) error {
+	return d.Engine.Put(stVarTable, []byte(name), []byte(value))
+}
+
+// DeleteVar removes name from module variable storage. It is not an
+// error to delete a name that was never set, matching Engine.Delete.
+func (d *Database) DeleteVar(name string) error {
+	return d.Engine.Delete(stVarTable, []byte(name))
+}
@@ -0,0 +1,50 @@
+package db
+
+import "testing"
+
+func TestSetVarGetVarRoundTrip(t *testing.T) {
+	database := NewDatabase(NewMemEngine())
+
+	if err := database.SetVar("max_players", "64"); err != nil {
+		t.Fatalf("SetVar: %v", err)
+	}
+
+	got, ok, err := database.GetVar("max_players")
+	if err != nil {
+		t.Fatalf("GetVar: %v", err)
+	}
+	if !ok || got != "64" {
+		t.Fatalf("GetVar = (%q, %v), want (64, true)", got, ok)
+	}
+}
+
+func TestGetVarMissingReturnsFalse(t *testing.T) {
+	database := NewDatabase(NewMemEngine())
+
+	_, ok, err := database.GetVar("nope")
+	if err != nil {
+		t.Fatalf("GetVar: %v", err)
+	}
+	if ok {
+		t.Fatal("GetVar: ok = true, want false for a name never set")
+	}
+}
+
+func TestDeleteVarRemovesValue(t *testing.T) {
+	database := NewDatabase(NewMemEngine())
+	if err := database.SetVar("feature_x", "on"); err != nil {
+		t.Fatalf("SetVar: %v", err)
+	}
+
+	if err := database.DeleteVar("feature_x"); err != nil {
+		t.Fatalf("DeleteVar: %v", err)
+	}
+
+	_, ok, err := database.GetVar("feature_x")
+	if err != nil {
+		t.Fatalf("GetVar: %v", err)
+	}
+	if ok {
+		t.Fatal("GetVar: ok = true after DeleteVar, want false")
+	}
+}
@@ -0,0 +1,395 @@
+package db
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// FsyncPolicy controls how aggressively WALEngine flushes its log to disk.
+type FsyncPolicy int
+
+const (
+	// FsyncAlways calls fsync after every appended record. Safest, slowest.
+	FsyncAlways FsyncPolicy = iota
+	// FsyncNever relies on the OS to eventually flush dirty pages. Fastest,
+	// loses at most the OS's buffering window of writes on a crash.
+	FsyncNever
+	// FsyncBatch fsyncs once every WALConfig.BatchSize records.
+	FsyncBatch
+)
+
+// WALConfig configures WALEngine's durability/throughput trade-off.
+type WALConfig struct {
+	Policy FsyncPolicy
+	// BatchSize is the number of records between fsyncs when Policy is
+	// FsyncBatch. Ignored otherwise.
+	BatchSize int
+
+	// CompactAfterRecords, if positive, makes append call Compact once
+	// this many records have been appended since the file was last
+	// opened or compacted — the case a large delete burst (or any
+	// workload that repeatedly overwrites the same keys) leaves behind:
+	// every Put and Delete is append-only, so the file keeps growing
+	// even though most of those records are superseded and Scan never
+	// looks at them again. Zero disables automatic compaction; a caller
+	// that wants full control over when the rewrite happens (e.g. off
+	// of peak hours) should leave this at zero and call Compact
+	// directly instead.
+	CompactAfterRecords int
+}
+
+// walRecord is the unit appended to the log. Op 0 is Put, Op 1 is Delete.
+type walRecord struct {
+	Op    uint8
+	Table string
+	Key   []byte
+	Value []byte
+}
+
+// WALEngine wraps an in-memory Engine (normally a *MemEngine) with a
+// write-ahead log: every mutation is appended to the log before being
+// applied in memory, and the log is replayed on Open to reconstruct state
+// after a restart or crash. Records are individually checksummed so a
+// torn write at the tail of the file (the case a crash produces) is
+// detected and truncated rather than corrupting later, unrelated data.
+type WALEngine struct {
+	mu       sync.Mutex
+	mem      *MemEngine
+	file     *os.File
+	path     string
+	cfg      WALConfig
+	nsince   int // records appended since last fsync, for FsyncBatch
+	nrecords int // records appended since open or last Compact, for CompactAfterRecords
+}
+
+// OpenWAL opens (creating if necessary) the WAL file at path, replays any
+// existing records into a fresh MemEngine, and returns a WALEngine ready
+// to accept further writes.
+func OpenWAL(path string, cfg WALConfig) (*WALEngine, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("db: open WAL: %w", err)
+	}
+	mem := NewMemEngine()
+	nrecords, err := replayWAL(f, mem)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &WALEngine{mem: mem, file: f, path: path, cfg: cfg, nrecords: nrecords}, nil
+}
+
+// replayWAL reads every well-formed record from f into mem, stopping (and
+// truncating the underlying file to the last good offset) at the first
+// truncated or checksum-mismatched record, which is what a crash mid-write
+// leaves behind. It returns the number of records successfully replayed,
+// so OpenWAL can seed nrecords for CompactAfterRecords without rescanning
+// the file a second time.
+func replayWAL(f *os.File, mem *MemEngine) (int, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	var offset int64
+	var count int
+	for {
+		rec, n, err := readWALRecord(f)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Torn write at the tail: discard it and stop replaying.
+			return count, f.Truncate(offset)
+		}
+		offset += int64(n)
+		count++
+		switch rec.Op {
+		case 0:
+			mem.Put(rec.Table, rec.Key, rec.Value)
+		case 1:
+			mem.Delete(rec.Table, rec.Key)
+		}
+	}
+	return count, nil
+}
+
+// readWALRecord reads one length-prefixed, checksummed record: u32 length,
+// u32 CRC32(payload), then the BSATN-encoded walRecord payload.
+func readWALRecord(f *os.File) (walRecord, int, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(f, header[:]); err != nil {
+		return walRecord{}, 0, err
+	}
+	length := binary.LittleEndian.Uint32(header[0:4])
+	wantCRC := binary.LittleEndian.Uint32(header[4:8])
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(f, payload); err != nil {
+		return walRecord{}, 0, fmt.Errorf("db: truncated WAL record: %w", io.ErrUnexpectedEOF)
+	}
+	if crc32.ChecksumIEEE(payload) != wantCRC {
+		return walRecord{}, 0, fmt.Errorf("db: WAL record checksum mismatch")
+	}
+	var rec walRecord
+	if err := bsatn.Unmarshal(payload, &rec); err != nil {
+		return walRecord{}, 0, err
+	}
+	return rec, 8 + len(payload), nil
+}
+
+// writeRecord encodes rec in the same length-prefixed, checksummed
+// format readWALRecord reads, and writes it to f; shared by append
+// (the live log) and compactLocked (the rewritten log).
+func writeRecord(f *os.File, rec walRecord) error {
+	payload, err := bsatn.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	var header [8]byte
+	binary.LittleEndian.PutUint32(header[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(header[4:8], crc32.ChecksumIEEE(payload))
+	if _, err := f.Write(header[:]); err != nil {
+		return err
+	}
+	if _, err := f.Write(payload); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (e *WALEngine) append(rec walRecord) error {
+	if err := writeRecord(e.file, rec); err != nil {
+		return err
+	}
+
+	e.nsince++
+	e.nrecords++
+	switch e.cfg.Policy {
+	case FsyncAlways:
+		return e.file.Sync()
+	case FsyncBatch:
+		if e.cfg.BatchSize > 0 && e.nsince >= e.cfg.BatchSize {
+			e.nsince = 0
+			return e.file.Sync()
+		}
+	}
+	return nil
+}
+
+func (e *WALEngine) Put(table string, key, value []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.append(walRecord{Op: 0, Table: table, Key: key, Value: value}); err != nil {
+		return err
+	}
+	if err := e.mem.Put(table, key, value); err != nil {
+		return err
+	}
+	return e.maybeAutoCompact()
+}
+
+func (e *WALEngine) Delete(table string, key []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.append(walRecord{Op: 1, Table: table, Key: key}); err != nil {
+		return err
+	}
+	if err := e.mem.Delete(table, key); err != nil {
+		return err
+	}
+	return e.maybeAutoCompact()
+}
+
+// maybeAutoCompact runs Compact if CompactAfterRecords is configured and
+// has been reached. Callers must already hold e.mu.
+func (e *WALEngine) maybeAutoCompact() error {
+	if e.cfg.CompactAfterRecords <= 0 || e.nrecords < e.cfg.CompactAfterRecords {
+		return nil
+	}
+	_, err := e.compactLocked()
+	return err
+}
+
+func (e *WALEngine) Get(table string, key []byte) ([]byte, bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.mem.Get(table, key)
+}
+
+func (e *WALEngine) Scan(table string) (Cursor, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.mem.Scan(table)
+}
+
+// ListTables returns the names of every table currently known to the WAL's
+// backing MemEngine. See MemEngine.ListTables.
+func (e *WALEngine) ListTables() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.mem.ListTables()
+}
+
+func (e *WALEngine) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.file.Close()
+}
+
+// CompactStats reports the outcome of a Compact call.
+type CompactStats struct {
+	// RecordsBefore is the number of append-only records the log held
+	// before compaction (every Put and Delete appended since the file
+	// was opened or last compacted).
+	RecordsBefore int
+	// RecordsAfter is the number of records needed to describe the
+	// engine's current state: one Put per live key, across every table.
+	RecordsAfter int
+	// BytesReclaimed is how many bytes shorter the log file is after
+	// compaction than before. It can be negative for a log that was
+	// already near-minimal, since the rewritten records carry their own
+	// length/checksum header too.
+	BytesReclaimed int64
+}
+
+// Compact rewrites e's log file to hold exactly one Put record per
+// currently live key, discarding every Delete record and every Put
+// record a later write to the same key superseded. This is the
+// reclamation half of the log's append-only design: Put and Delete
+// never rewrite or remove earlier records in place (see append), so a
+// workload with a large delete burst, or one that repeatedly overwrites
+// the same keys, leaves the log far larger on disk than the state it
+// actually describes until Compact runs. See WALConfig.CompactAfterRecords
+// to trigger this automatically instead of calling it directly.
+func (e *WALEngine) Compact() (CompactStats, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.compactLocked()
+}
+
+func (e *WALEngine) compactLocked() (CompactStats, error) {
+	before, err := e.file.Stat()
+	if err != nil {
+		return CompactStats{}, fmt.Errorf("db: compact: stat log file: %w", err)
+	}
+	recordsBefore := e.nrecords
+
+	tmpPath := e.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return CompactStats{}, fmt.Errorf("db: compact: open temp file: %w", err)
+	}
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	recordsAfter, err := writeLiveRecords(tmp, e.mem)
+	if err != nil {
+		tmp.Close()
+		return CompactStats{}, fmt.Errorf("db: compact: write live records: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return CompactStats{}, fmt.Errorf("db: compact: sync temp file: %w", err)
+	}
+	after, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		return CompactStats{}, fmt.Errorf("db: compact: stat temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return CompactStats{}, fmt.Errorf("db: compact: close temp file: %w", err)
+	}
+	if err := e.file.Close(); err != nil {
+		return CompactStats{}, fmt.Errorf("db: compact: close log file: %w", err)
+	}
+	if err := os.Rename(tmpPath, e.path); err != nil {
+		return CompactStats{}, fmt.Errorf("db: compact: replace log file: %w", err)
+	}
+
+	f, err := os.OpenFile(e.path, os.O_RDWR, 0o644)
+	if err != nil {
+		return CompactStats{}, fmt.Errorf("db: compact: reopen log file: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return CompactStats{}, err
+	}
+	e.file = f
+	e.nrecords = recordsAfter
+	e.nsince = 0
+
+	return CompactStats{
+		RecordsBefore:  recordsBefore,
+		RecordsAfter:   recordsAfter,
+		BytesReclaimed: before.Size() - after.Size(),
+	}, nil
+}
+
+// writeLiveRecords writes one Put record per key currently in mem,
+// across every table, to f, and returns how many it wrote.
+func writeLiveRecords(f *os.File, mem *MemEngine) (int, error) {
+	var n int
+	for _, table := range mem.ListTables() {
+		cur, err := mem.Scan(table)
+		if err != nil {
+			return n, err
+		}
+		for cur.Next() {
+			rec := walRecord{Op: 0, Table: table, Key: cur.Key(), Value: cur.Value()}
+			if err := writeRecord(f, rec); err != nil {
+				cur.Close()
+				return n, err
+			}
+			n++
+		}
+		if err := cur.Close(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// WALStats reports e's current size, for deciding whether a manual
+// Compact call is worthwhile.
+type WALStats struct {
+	// Records is the number of append-only records currently in the log
+	// file (RecordsBefore, if Compact were called right now).
+	Records int
+	// LiveKeys is the number of keys Compact would condense the log
+	// down to, across every table.
+	LiveKeys int
+	// FileBytes is the log file's current size on disk.
+	FileBytes int64
+}
+
+// Stats reports e's current log size and live key count.
+func (e *WALEngine) Stats() (WALStats, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	info, err := e.file.Stat()
+	if err != nil {
+		return WALStats{}, fmt.Errorf("db: stats: stat log file: %w", err)
+	}
+	var liveKeys int
+	for _, table := range e.mem.ListTables() {
+		cur, err := e.mem.Scan(table)
+		if err != nil {
+			return WALStats{}, err
+		}
+		for cur.Next() {
+			liveKeys++
+		}
+		if err := cur.Close(); err != nil {
+			return WALStats{}, err
+		}
+	}
+	return WALStats{Records: e.nrecords, LiveKeys: liveKeys, FileBytes: info.Size()}, nil
+}
@@ -0,0 +1,142 @@
+// Package devloop implements the polling rebuild-watch-reload loop
+// behind `spacetimedb dev --watch`: notice a module's wasm build output
+// changed, hot-swap it into a running embedded host, replay a
+// configured set of seed reducer calls, and report the schema hash
+// change — without pulling in a filesystem-notification dependency this
+// tree doesn't otherwise need.
+package devloop
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+)
+
+// Reloader is the subset of *spacetimedb.Handle Watch needs to hot-swap
+// a module. Satisfied by *spacetimedb.Handle; a fake in tests.
+type Reloader interface {
+	Reload(ctx context.Context, wasmPath string) (changed bool, err error)
+	SchemaHash() string
+}
+
+// Seeder is the subset of *spacetimedb.Handle Watch needs to replay
+// SeedCalls after a reload.
+type Seeder interface {
+	CallReducer(ctx context.Context, reducerName string, reducerID uint32, args []byte, flags protocol.CallFlags) error
+}
+
+// Target is what Watch drives: a hot-swappable, seedable embedded
+// module handle.
+type Target interface {
+	Reloader
+	Seeder
+}
+
+// SeedCall names one reducer call to replay after every reload that
+// actually changed the module, so local state (an admin user, a handful
+// of fixture rows) is back in a known-good shape for manual testing
+// without the module author re-typing the same calls after every save.
+type SeedCall struct {
+	Name string
+	ID   uint32
+	Args []byte
+}
+
+// Options controls Watch's polling and reporting.
+type Options struct {
+	// PollInterval is how often Watch stats wasmPath for a changed
+	// modification time. Zero uses a 500ms default.
+	PollInterval time.Duration
+	// Seeds are replayed, in order, once after the initial load and
+	// again after every reload that changed the module.
+	Seeds []SeedCall
+	// Output receives progress lines (schema hash on load, on each
+	// detected change, and seed replay status). Nil uses os.Stdout.
+	Output io.Writer
+}
+
+// Watch replays opts.Seeds once against target, then — if watch is true
+// — polls wasmPath for a changed modification time until ctx is done,
+// calling target.Reload and, when it reports a real change, printing
+// the schema hash transition and replaying opts.Seeds again. With
+// watch false, Watch returns immediately after the initial seed replay.
+func Watch(ctx context.Context, wasmPath string, watch bool, target Target, opts Options) error {
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+
+	fmt.Fprintf(out, "loaded %s (schema %s)\n", wasmPath, target.SchemaHash())
+	if err := replaySeeds(ctx, target, opts.Seeds, out); err != nil {
+		return err
+	}
+	if !watch {
+		return nil
+	}
+
+	lastMod, err := modTime(wasmPath)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			mod, err := modTime(wasmPath)
+			if err != nil {
+				fmt.Fprintf(out, "watch: stat %s: %v\n", wasmPath, err)
+				continue
+			}
+			if !mod.After(lastMod) {
+				continue
+			}
+			lastMod = mod
+
+			before := target.SchemaHash()
+			changed, err := target.Reload(ctx, wasmPath)
+			if err != nil {
+				fmt.Fprintf(out, "reload %s: %v\n", wasmPath, err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+			fmt.Fprintf(out, "reloaded %s: schema %s -> %s\n", wasmPath, before, target.SchemaHash())
+			if err := replaySeeds(ctx, target, opts.Seeds, out); err != nil {
+				fmt.Fprintf(out, "seed replay: %v\n", err)
+			}
+		}
+	}
+}
+
+// replaySeeds calls each seed reducer in order, stopping at the first
+// failure so a broken seed doesn't mask which one failed.
+func replaySeeds(ctx context.Context, target Seeder, seeds []SeedCall, out io.Writer) error {
+	for _, seed := range seeds {
+		if err := target.CallReducer(ctx, seed.Name, seed.ID, seed.Args, 0); err != nil {
+			return fmt.Errorf("seed %s: %w", seed.Name, err)
+		}
+		fmt.Fprintf(out, "seeded %s\n", seed.Name)
+	}
+	return nil
+}
+
+func modTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("devloop: stat %s: %w", path, err)
+	}
+	return info.ModTime(), nil
+}
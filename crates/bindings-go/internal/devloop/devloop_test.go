@@ -0,0 +1,117 @@
+package devloop
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+)
+
+type fakeTarget struct {
+	hash       string
+	reloadErr  error
+	reloadHits int32
+	seedCalls  []string
+}
+
+func (f *fakeTarget) SchemaHash() string { return f.hash }
+
+func (f *fakeTarget) Reload(ctx context.Context, wasmPath string) (bool, error) {
+	atomic.AddInt32(&f.reloadHits, 1)
+	if f.reloadErr != nil {
+		return false, f.reloadErr
+	}
+	f.hash = f.hash + "'"
+	return true, nil
+}
+
+func (f *fakeTarget) CallReducer(ctx context.Context, name string, id uint32, args []byte, flags protocol.CallFlags) error {
+	f.seedCalls = append(f.seedCalls, name)
+	return nil
+}
+
+func TestWatchReplaysSeedsOnceWithoutWatching(t *testing.T) {
+	wasmPath := filepath.Join(t.TempDir(), "module.wasm")
+	if err := os.WriteFile(wasmPath, []byte("v1"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	target := &fakeTarget{hash: "h1"}
+
+	var buf bytes.Buffer
+	err := Watch(context.Background(), wasmPath, false, target, Options{
+		Seeds:  []SeedCall{{Name: "seed_admin", ID: 1}},
+		Output: &buf,
+	})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if len(target.seedCalls) != 1 || target.seedCalls[0] != "seed_admin" {
+		t.Fatalf("seedCalls = %v, want [seed_admin]", target.seedCalls)
+	}
+	if atomic.LoadInt32(&target.reloadHits) != 0 {
+		t.Fatal("Watch: reloaded without -watch")
+	}
+}
+
+func TestWatchReloadsAndReseedsOnFileChange(t *testing.T) {
+	wasmPath := filepath.Join(t.TempDir(), "module.wasm")
+	if err := os.WriteFile(wasmPath, []byte("v1"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	target := &fakeTarget{hash: "h1"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		future := time.Now().Add(time.Second)
+		os.Chtimes(wasmPath, future, future)
+	}()
+
+	var buf bytes.Buffer
+	err := Watch(ctx, wasmPath, true, target, Options{
+		PollInterval: 10 * time.Millisecond,
+		Seeds:        []SeedCall{{Name: "seed_admin", ID: 1}},
+		Output:       &buf,
+	})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if atomic.LoadInt32(&target.reloadHits) == 0 {
+		t.Fatal("Watch: expected at least one reload after the file changed")
+	}
+	if len(target.seedCalls) < 2 {
+		t.Fatalf("seedCalls = %v, want at least 2 (initial load + one reload)", target.seedCalls)
+	}
+}
+
+func TestWatchStopsWhenContextIsCancelled(t *testing.T) {
+	wasmPath := filepath.Join(t.TempDir(), "module.wasm")
+	if err := os.WriteFile(wasmPath, []byte("v1"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	target := &fakeTarget{hash: "h1"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, wasmPath, true, target, Options{PollInterval: 5 * time.Millisecond, Output: &bytes.Buffer{}})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Watch: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Watch did not return after context cancellation")
+	}
+}
@@ -0,0 +1,73 @@
+// Package health defines the status snapshot and HTTP handler an
+// embedding program uses to wire a Go-hosted module into Kubernetes
+// liveness/readiness probes (or any other health-checking system) without
+// depending on pkg/spacetimedb directly.
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Status is a point-in-time snapshot of an embedded module's health.
+type Status struct {
+	// ModuleLoaded is false only before the first module load completes
+	// or after Shutdown; Ready reports false whenever this does.
+	ModuleLoaded bool `json:"module_loaded"`
+	// LastReducerLatency is how long the most recently completed
+	// CallReducer call took, zero if none have completed yet.
+	LastReducerLatency time.Duration `json:"last_reducer_latency_ns"`
+	// SchedulerBacklog is how many scheduled reducer invocations are
+	// waiting to run. It is always zero for an embedded Handle today,
+	// since this host does not yet run a background scheduler pump
+	// (see internal/scheduler, which only implements the ScheduleAt
+	// value type) — kept as a field so a Source that does have a real
+	// backlog (a future host scheduler, or a client-side ReducerQueue)
+	// can report it without changing Status's shape.
+	SchedulerBacklog int `json:"scheduler_backlog"`
+	// DatastoreRows is the total row count across every registered
+	// table in the module's local database.
+	DatastoreRows int `json:"datastore_rows"`
+	// ReducerCalls and ReducerErrors count every CallReducer attempt
+	// and how many of them returned an error, since the module loaded.
+	ReducerCalls  uint64 `json:"reducer_calls"`
+	ReducerErrors uint64 `json:"reducer_errors"`
+}
+
+// ErrorRate returns ReducerErrors/ReducerCalls, or 0 if no calls have
+// been made yet.
+func (s Status) ErrorRate() float64 {
+	if s.ReducerCalls == 0 {
+		return 0
+	}
+	return float64(s.ReducerErrors) / float64(s.ReducerCalls)
+}
+
+// Ready reports whether s represents a module fit to serve traffic: it
+// must be loaded. Callers with stricter requirements (e.g. an error
+// rate ceiling) should check Status's other fields themselves; Ready
+// only covers the one condition every embedder needs.
+func (s Status) Ready() bool {
+	return s.ModuleLoaded
+}
+
+// Source reports the current Status of whatever it's checking, e.g.
+// *spacetimedb.Handle.
+type Source interface {
+	Health() Status
+}
+
+// Handler returns an http.Handler suitable for a Kubernetes liveness or
+// readiness probe: it writes source's current Status as JSON, with a
+// 200 status code if Status.Ready() and 503 otherwise.
+func Handler(source Source) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := source.Health()
+		w.Header().Set("Content-Type", "application/json")
+		if !status.Ready() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+}
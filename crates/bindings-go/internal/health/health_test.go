@@ -0,0 +1,50 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeSource struct{ status Status }
+
+func (f fakeSource) Health() Status { return f.status }
+
+func TestStatusErrorRate(t *testing.T) {
+	s := Status{ReducerCalls: 4, ReducerErrors: 1}
+	if got := s.ErrorRate(); got != 0.25 {
+		t.Fatalf("ErrorRate() = %v, want 0.25", got)
+	}
+	if got := (Status{}).ErrorRate(); got != 0 {
+		t.Fatalf("ErrorRate() with no calls = %v, want 0", got)
+	}
+}
+
+func TestHandlerReturns200WhenReady(t *testing.T) {
+	src := fakeSource{status: Status{ModuleLoaded: true, LastReducerLatency: 5 * time.Millisecond, DatastoreRows: 3}}
+	rec := httptest.NewRecorder()
+	Handler(src).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var got Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if got.DatastoreRows != 3 {
+		t.Fatalf("DatastoreRows = %d, want 3", got.DatastoreRows)
+	}
+}
+
+func TestHandlerReturns503WhenModuleNotLoaded(t *testing.T) {
+	src := fakeSource{status: Status{ModuleLoaded: false}}
+	rec := httptest.NewRecorder()
+	Handler(src).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,119 @@
+package host
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+// Federation lets several modules share one underlying db.Database while
+// keeping their tables namespaced apart, so multi-module hosting and its
+// cross-module data visibility rules can be prototyped and exercised in
+// Go ahead of server-side support. Each bound module's tables live in
+// the shared Database under a "<prefix>.<table>" name, so two modules
+// can each register a table called "players" without colliding.
+//
+// This is a test-mode facility, not a security boundary: a module with
+// direct access to the shared db.Database can always bypass Resolve's
+// visibility rules by using the qualified name outright.
+type Federation struct {
+	shared *db.Database
+
+	mu      sync.RWMutex
+	modules map[string]string   // module name -> table prefix
+	visible map[string][]string // module name -> other module names it may also read from
+}
+
+// NewFederation returns a Federation over shared, with no modules bound.
+func NewFederation(shared *db.Database) *Federation {
+	return &Federation{
+		shared:  shared,
+		modules: make(map[string]string),
+		visible: make(map[string][]string),
+	}
+}
+
+// Bind registers module as a federation participant whose tables are
+// namespaced under prefix. Binding the same module name again replaces
+// its prefix.
+func (f *Federation) Bind(module, prefix string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.modules[module] = prefix
+}
+
+// Grant lets module additionally resolve bare table names against
+// other's tables. By default a module can only resolve its own tables;
+// Grant is what lets a test exercise the cross-module visibility rules
+// the server will eventually enforce.
+func (f *Federation) Grant(module, other string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.visible[module] = append(f.visible[module], other)
+}
+
+// RegisterTable adds info to the shared Database under module's prefix.
+func (f *Federation) RegisterTable(module string, info db.TableInfo) error {
+	f.mu.RLock()
+	prefix, ok := f.modules[module]
+	f.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("host: module %q is not bound to this federation", module)
+	}
+	info.Name = qualifyTable(prefix, info.Name)
+	f.shared.RegisterTable(info)
+	return nil
+}
+
+// Resolve maps table, as module names it, to its fully-qualified name in
+// the shared Database. module's own tables always resolve; otherwise
+// table is looked up among the tables of every module Grant has made
+// visible to module, and it is an error if none, or more than one,
+// match.
+func (f *Federation) Resolve(module, table string) (string, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	prefix, ok := f.modules[module]
+	if !ok {
+		return "", fmt.Errorf("host: module %q is not bound to this federation", module)
+	}
+	own := qualifyTable(prefix, table)
+	if f.tableExists(own) {
+		return own, nil
+	}
+
+	var match string
+	for _, other := range f.visible[module] {
+		otherPrefix, ok := f.modules[other]
+		if !ok {
+			continue
+		}
+		candidate := qualifyTable(otherPrefix, table)
+		if !f.tableExists(candidate) {
+			continue
+		}
+		if match != "" && match != candidate {
+			return "", fmt.Errorf("host: table %q is ambiguous across modules visible to %q", table, module)
+		}
+		match = candidate
+	}
+	if match == "" {
+		return "", fmt.Errorf("host: module %q cannot see a table named %q", module, table)
+	}
+	return match, nil
+}
+
+func (f *Federation) tableExists(name string) bool {
+	for _, info := range f.shared.Tables() {
+		if info.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func qualifyTable(prefix, table string) string {
+	return prefix + "." + table
+}
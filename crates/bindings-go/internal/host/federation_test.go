@@ -0,0 +1,75 @@
+package host
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func TestFederationNamespacesTablesByPrefix(t *testing.T) {
+	shared := db.NewDatabase(db.NewMemEngine())
+	f := NewFederation(shared)
+	f.Bind("inventory", "inventory")
+	f.Bind("chat", "chat")
+
+	if err := f.RegisterTable("inventory", db.TableInfo{Name: "players"}); err != nil {
+		t.Fatalf("RegisterTable: %v", err)
+	}
+	if err := f.RegisterTable("chat", db.TableInfo{Name: "players"}); err != nil {
+		t.Fatalf("RegisterTable: %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, info := range shared.Tables() {
+		names[info.Name] = true
+	}
+	if !names["inventory.players"] || !names["chat.players"] {
+		t.Fatalf("tables = %v, want both inventory.players and chat.players", names)
+	}
+}
+
+func TestFederationResolveOwnTable(t *testing.T) {
+	shared := db.NewDatabase(db.NewMemEngine())
+	f := NewFederation(shared)
+	f.Bind("inventory", "inventory")
+	f.RegisterTable("inventory", db.TableInfo{Name: "players", Columns: []bsatn.AlgebraicType{}})
+
+	got, err := f.Resolve("inventory", "players")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if got != "inventory.players" {
+		t.Fatalf("Resolve = %q, want inventory.players", got)
+	}
+}
+
+func TestFederationResolveDeniesUngrantedCrossModuleTable(t *testing.T) {
+	shared := db.NewDatabase(db.NewMemEngine())
+	f := NewFederation(shared)
+	f.Bind("inventory", "inventory")
+	f.Bind("chat", "chat")
+	f.RegisterTable("chat", db.TableInfo{Name: "messages"})
+
+	if _, err := f.Resolve("inventory", "messages"); err == nil || !strings.Contains(err.Error(), "cannot see") {
+		t.Fatalf("err = %v, want 'cannot see'", err)
+	}
+}
+
+func TestFederationResolveAllowsGrantedCrossModuleTable(t *testing.T) {
+	shared := db.NewDatabase(db.NewMemEngine())
+	f := NewFederation(shared)
+	f.Bind("inventory", "inventory")
+	f.Bind("chat", "chat")
+	f.RegisterTable("chat", db.TableInfo{Name: "messages"})
+	f.Grant("inventory", "chat")
+
+	got, err := f.Resolve("inventory", "messages")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if got != "chat.messages" {
+		t.Fatalf("Resolve = %q, want chat.messages", got)
+	}
+}
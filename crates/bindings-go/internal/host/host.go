@@ -0,0 +1,101 @@
+// Package host implements a multi-database host: a single Go process
+// hosting several SpacetimeDB modules, each bound to its own database,
+// with a controlled API for one module's reducer to call into another's.
+package host
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/profiling"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/wasm"
+)
+
+// Host manages a set of named modules and routes reducer calls, including
+// calls one module's reducer makes into another module hosted by the same
+// Host.
+type Host struct {
+	mu      sync.RWMutex
+	modules map[string]*wasm.Runtime
+}
+
+// NewHost returns an empty Host.
+func NewHost() *Host {
+	return &Host{modules: make(map[string]*wasm.Runtime)}
+}
+
+// Register binds name to runtime, replacing any previous binding.
+func (h *Host) Register(name string, runtime *wasm.Runtime) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.modules[name] = runtime
+}
+
+// callChainKey is the context key under which Host tracks the sequence of
+// database names already entered by the current inter-module call, so
+// CallReducer can detect a cycle (A calls B calls A) before it recurses
+// into a wasm trap or a stack overflow.
+type callChainKey struct{}
+
+// CallReducer invokes reducerID (named reducerName for profiling and
+// logging; Host itself does not parse __describe_module__ to derive
+// this — a caller can do so via internal/moduledef.Decode, see
+// wasm.Runtime.CallReducer) on the module registered as
+// dbName, propagating caller's identity and flags and threading the
+// inter-module call chain through ctx so a cycle across any number of
+// hops is detected. The call runs under pprof labels {reducer:
+// reducerName, database: dbName} (see internal/profiling) so a CPU or
+// heap profile taken while it runs attributes its cost correctly.
+func (h *Host) CallReducer(ctx context.Context, dbName, reducerName string, reducerID uint32, args []byte, caller identity.Identity, flags protocol.CallFlags) error {
+	chain, _ := ctx.Value(callChainKey{}).([]string)
+	for _, seen := range chain {
+		if seen == dbName {
+			return fmt.Errorf("host: cross-module call cycle detected: %v -> %s", append(chain, dbName), dbName)
+		}
+	}
+
+	h.mu.RLock()
+	runtime, ok := h.modules[dbName]
+	h.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("host: no module registered for database %q", dbName)
+	}
+
+	ctx = context.WithValue(ctx, callChainKey{}, append(append([]string{}, chain...), dbName))
+	// The caller's identity is available to the callee's reducer through
+	// this same context value once host functions expose it (see the
+	// transaction-metadata host call); for now it is threaded but not yet
+	// surfaced to the guest. It is also what Runtime's AuthorizeRead/
+	// AuthorizeWrite hooks see as the acting identity.
+	ctx = callContext(ctx, caller, flags)
+
+	var callErr error
+	profiling.Do(ctx, reducerName, dbName, func(ctx context.Context) {
+		callErr = runtime.CallReducer(ctx, reducerID, args)
+	})
+	return callErr
+}
+
+// CallerIdentity extracts the identity propagated by CallReducer, if any.
+func CallerIdentity(ctx context.Context) (identity.Identity, bool) {
+	return identity.FromContext(ctx)
+}
+
+// CallFlagsOf extracts the flags propagated by CallReducer, if any.
+func CallFlagsOf(ctx context.Context) (protocol.CallFlags, bool) {
+	return protocol.CallFlagsFromContext(ctx)
+}
+
+// callContext returns ctx augmented with the caller's identity and this
+// call's flags, the two pieces of per-call metadata CallReducer threads
+// down to the invoked runtime and, eventually, host functions. Split out
+// so the augmentation itself — as opposed to routing, cycle detection,
+// and profiling — can be tested without a real wasm.Runtime.
+func callContext(ctx context.Context, caller identity.Identity, flags protocol.CallFlags) context.Context {
+	ctx = identity.WithCaller(ctx, caller)
+	ctx = protocol.WithCallFlags(ctx, flags)
+	return ctx
+}
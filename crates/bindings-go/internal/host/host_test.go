@@ -0,0 +1,48 @@
+package host
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+)
+
+func TestCallReducerUnknownDatabase(t *testing.T) {
+	h := NewHost()
+	err := h.CallReducer(context.Background(), "missing", "reduce", 0, nil, identity.Zero, 0)
+	if err == nil || !strings.Contains(err.Error(), "no module registered") {
+		t.Fatalf("err = %v, want 'no module registered'", err)
+	}
+}
+
+func TestCallReducerDetectsCycle(t *testing.T) {
+	h := NewHost()
+	ctx := context.WithValue(context.Background(), callChainKey{}, []string{"a", "b"})
+	err := h.CallReducer(ctx, "a", "reduce", 0, nil, identity.Zero, 0)
+	if err == nil || !strings.Contains(err.Error(), "cycle detected") {
+		t.Fatalf("err = %v, want cycle detected", err)
+	}
+}
+
+func TestCallContextCarriesCallerAndFlags(t *testing.T) {
+	caller := identity.Identity{1, 2, 3, 4}
+	ctx := callContext(context.Background(), caller, protocol.CallFlagNoSuccessNotify)
+
+	gotCaller, ok := CallerIdentity(ctx)
+	if !ok || gotCaller != caller {
+		t.Fatalf("CallerIdentity = %v, %v, want %v, true", gotCaller, ok, caller)
+	}
+
+	gotFlags, ok := CallFlagsOf(ctx)
+	if !ok || !gotFlags.NoSuccessNotify() {
+		t.Fatalf("CallFlagsOf = %v, %v, want NoSuccessNotify set, true", gotFlags, ok)
+	}
+}
+
+func TestCallFlagsOfAbsentByDefault(t *testing.T) {
+	if _, ok := CallFlagsOf(context.Background()); ok {
+		t.Fatal("CallFlagsOf found flags on a context that never carried any")
+	}
+}
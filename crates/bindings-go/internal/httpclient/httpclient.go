@@ -0,0 +1,265 @@
+// Package httpclient provides a tuned, connection-pooled http.Client
+// wrapper shared by every HTTP-speaking client in this tree (the module
+// publish client, log streaming, and any future admin/SQL client): one
+// place to configure connection limits and HTTP/2 keepalive, retry
+// idempotent requests, optionally hedge slow ones, and observe
+// connection reuse — instead of each client hand-rolling its own
+// http.Client and quietly opening far more TCP connections than a bulk
+// workload needs.
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// Config controls Client's connection pooling, retry, and hedging
+// behavior. The zero value is not directly usable; call NewConfig for
+// sensible defaults and override individual fields from there.
+type Config struct {
+	// MaxIdleConns is the transport's total idle connection cap, across
+	// all hosts.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the transport's idle connection cap for a
+	// single host — the limit that actually matters for a bulk SQL
+	// workload hammering one server, since Go's http.DefaultTransport
+	// caps this at 2.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout closes an idle connection that has sat unused
+	// this long.
+	IdleConnTimeout time.Duration
+
+	// MaxRetries is how many additional attempts Do makes for an
+	// idempotent request (GET, HEAD, OPTIONS) that failed with a
+	// transport error or a 5xx status. Zero disables retries.
+	MaxRetries int
+	// RetryBackoff is the base delay before each retry, doubled after
+	// every attempt (so attempt N waits RetryBackoff*2^(N-1)).
+	RetryBackoff time.Duration
+
+	// HedgeAfter, if positive, makes Do fire a second attempt of an
+	// idempotent request if the first has not completed after this
+	// long, and returns whichever response comes back first, canceling
+	// the loser. Zero disables hedging. This trades extra load for
+	// tail latency, so it should stay off for anything that isn't
+	// read-only and idempotent — Do only ever hedges GET/HEAD/OPTIONS,
+	// regardless of this setting.
+	HedgeAfter time.Duration
+}
+
+// NewConfig returns a Config with defaults suited to a bulk SQL/admin
+// workload against one server: enough idle connections per host to
+// avoid the dial-per-request behavior Go's DefaultTransport's default
+// of 2 forces under concurrency, HTTP/2 enabled, and no retry/hedging
+// (both are opt-in, since they change request semantics under load).
+func NewConfig() Config {
+	return Config{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 32,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// Metrics counts Client's connection reuse and reliability behavior
+// since construction. A snapshot, not a live view — see Client.Metrics.
+type Metrics struct {
+	// ReusedConns and NewConns count how many requests reused a pooled
+	// connection versus dialed a new one, per httptrace's
+	// GotConn.WasIdle (a fresh connection reports WasIdle=false the
+	// first time it's used, same as a genuinely new dial).
+	ReusedConns uint64
+	NewConns    uint64
+	// Retries counts every retry attempt Do made (not counting the
+	// original attempt).
+	Retries uint64
+	// HedgedWins counts every request where the hedged (second)
+	// attempt returned before the original.
+	HedgedWins uint64
+}
+
+// Client wraps an *http.Client tuned per Config, tracking Metrics across
+// every call to Do.
+type Client struct {
+	http    *http.Client
+	cfg     Config
+	metrics *atomicMetrics
+}
+
+// New returns a Client configured per cfg, with its own *http.Transport
+// (never http.DefaultTransport, so its connection pool isn't shared with
+// unrelated callers in the same process).
+func New(cfg Config) *Client {
+	transport := &http.Transport{
+		MaxIdleConns:        cfg.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:     cfg.IdleConnTimeout,
+		ForceAttemptHTTP2:   true,
+		TLSClientConfig:     &tls.Config{},
+	}
+	return &Client{
+		http:    &http.Client{Transport: transport},
+		cfg:     cfg,
+		metrics: &atomicMetrics{},
+	}
+}
+
+// Metrics returns a snapshot of c's connection reuse and reliability
+// counters.
+func (c *Client) Metrics() Metrics {
+	return c.metrics.snapshot()
+}
+
+// idempotentMethods are the HTTP methods Do will retry or hedge without
+// any help from req; any other method (POST, PUT, PATCH, DELETE) is sent
+// exactly once unless req itself declares it safe to retry (see
+// IdempotencyKeyHeader), since retrying or duplicating it could
+// otherwise apply a side effect twice.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+}
+
+// IdempotencyKeyHeader is the header name a caller sets on an otherwise
+// non-idempotent request (POST, PUT, PATCH, DELETE) to mark it safe for
+// Do to retry or hedge anyway: the caller is asserting that the server
+// (or, short of that, the caller's own dedup on the response, see
+// pkg/spacetimedb.HTTPReducerClient) treats repeated deliveries carrying
+// the same key as one logical request rather than applying its side
+// effect once per delivery.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// retryable reports whether Do should treat req the way it treats a
+// naturally idempotent method: either req's method already is one, or
+// req carries an IdempotencyKeyHeader vouching that a retry won't
+// double-apply its side effect.
+func retryable(req *http.Request) bool {
+	return idempotentMethods[req.Method] || req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// Do sends req, retrying and/or hedging it per Config if it is retryable
+// (see retryable). For a non-retryable method it behaves exactly like
+// (*http.Client).Do.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	req = withConnTrace(req, c.metrics)
+
+	if !retryable(req) {
+		return c.http.Do(req)
+	}
+	if c.cfg.HedgeAfter > 0 {
+		return c.doHedged(req)
+	}
+	return c.doWithRetry(req)
+}
+
+// doWithRetry sends req, retrying up to c.cfg.MaxRetries times (with
+// exponential backoff from c.cfg.RetryBackoff) on a transport error or a
+// 5xx response. A request with a body (e.g. a retried POST carrying an
+// IdempotencyKeyHeader) must have been built with a GetBody that can
+// replay it — http.NewRequest and http.NewRequestWithContext set this
+// automatically for the common body types (e.g. bytes.Reader,
+// bytes.Buffer, strings.Reader) — since req.Body is already drained
+// after the first attempt.
+func (c *Client) doWithRetry(req *http.Request) (*http.Response, error) {
+	var (
+		resp *http.Response
+		err  error
+	)
+	backoff := c.cfg.RetryBackoff
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			c.metrics.addRetries(1)
+			if backoff > 0 {
+				time.Sleep(backoff)
+				backoff *= 2
+			}
+			if req.GetBody != nil {
+				body, bodyErr := req.GetBody()
+				if bodyErr != nil {
+					return nil, bodyErr
+				}
+				req.Body = body
+			}
+		}
+		resp, err = c.http.Do(req)
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+	}
+	return resp, err
+}
+
+// doHedged sends req, and if it has not completed after c.cfg.HedgeAfter,
+// fires a second, independent attempt and returns whichever response
+// comes back first — canceling the other's context so it doesn't keep a
+// connection or the server's work occupied after losing.
+func (c *Client) doHedged(req *http.Request) (*http.Response, error) {
+	type result struct {
+		resp  *http.Response
+		err   error
+		hedge bool
+	}
+
+	primaryCtx, cancelPrimary := context.WithCancel(req.Context())
+	defer cancelPrimary()
+	hedgeCtx, cancelHedge := context.WithCancel(req.Context())
+	defer cancelHedge()
+
+	results := make(chan result, 2)
+
+	go func() {
+		resp, err := c.doWithRetry(req.Clone(primaryCtx))
+		results <- result{resp: resp, err: err}
+	}()
+
+	timer := time.NewTimer(c.cfg.HedgeAfter)
+	defer timer.Stop()
+
+	go func() {
+		select {
+		case <-timer.C:
+		case <-primaryCtx.Done():
+			return
+		}
+		resp, err := c.doWithRetry(req.Clone(hedgeCtx))
+		results <- result{resp: resp, err: err, hedge: true}
+	}()
+
+	first := <-results
+	if first.hedge {
+		c.metrics.addHedgedWins(1)
+		cancelPrimary()
+	} else {
+		cancelHedge()
+	}
+	if first.err == nil {
+		return first.resp, nil
+	}
+
+	// The winner failed; give the other attempt a chance rather than
+	// failing the whole call outright.
+	second := <-results
+	return second.resp, second.err
+}
+
+// withConnTrace attaches an httptrace.ClientTrace to req's context that
+// records whether the connection Do used was a reused idle connection
+// or freshly dialed, into m.
+func withConnTrace(req *http.Request, m *atomicMetrics) *http.Request {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				m.addReusedConns(1)
+			} else {
+				m.addNewConns(1)
+			}
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
@@ -0,0 +1,179 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoReusesConnectionsAcrossRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := New(NewConfig())
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatalf("NewRequest: %v", err)
+		}
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	m := c.Metrics()
+	if m.NewConns == 0 {
+		t.Fatalf("Metrics.NewConns = 0, want at least 1 (the first dial)")
+	}
+	if m.ReusedConns == 0 {
+		t.Fatalf("Metrics.ReusedConns = 0, want at least 1 (requests 2 and 3 reusing the pool)")
+	}
+}
+
+func TestDoRetriesOnServerErrorForIdempotentRequest(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := NewConfig()
+	cfg.MaxRetries = 3
+	c := New(cfg)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, want 200 after retries", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Fatalf("server saw %d calls, want 3", calls)
+	}
+	if c.Metrics().Retries != 2 {
+		t.Fatalf("Metrics.Retries = %d, want 2", c.Metrics().Retries)
+	}
+}
+
+func TestDoDoesNotRetryNonIdempotentMethod(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	cfg := NewConfig()
+	cfg.MaxRetries = 5
+	c := New(cfg)
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if calls != 1 {
+		t.Fatalf("server saw %d calls, want exactly 1 for a non-idempotent method", calls)
+	}
+}
+
+func TestDoRetriesPostWithIdempotencyKeyAndReplaysBody(t *testing.T) {
+	var calls int32
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if atomic.AddInt32(&calls, 1) < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := NewConfig()
+	cfg.MaxRetries = 3
+	c := New(cfg)
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, bytes.NewBufferString("call-reducer-args"))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, want 200 after retry", resp.StatusCode)
+	}
+	if calls != 2 {
+		t.Fatalf("server saw %d calls, want 2", calls)
+	}
+	for i, body := range bodies {
+		if body != "call-reducer-args" {
+			t.Fatalf("attempt %d body = %q, want the original body replayed", i, body)
+		}
+	}
+}
+
+func TestDoHedgesSlowRequestAndReturnsFasterOne(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := NewConfig()
+	cfg.HedgeAfter = 20 * time.Millisecond
+	c := New(cfg)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	start := time.Now()
+	resp, err := c.Do(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if elapsed >= 200*time.Millisecond {
+		t.Fatalf("Do took %v, want the hedged request to win well under the slow server's 200ms", elapsed)
+	}
+	if c.Metrics().HedgedWins != 1 {
+		t.Fatalf("Metrics.HedgedWins = %d, want 1", c.Metrics().HedgedWins)
+	}
+}
@@ -0,0 +1,26 @@
+package httpclient
+
+import "sync/atomic"
+
+// atomicMetrics backs Client.Metrics with lock-free counters, since Do
+// can run concurrently across many goroutines in a bulk workload.
+type atomicMetrics struct {
+	reusedConns uint64
+	newConns    uint64
+	retries     uint64
+	hedgedWins  uint64
+}
+
+func (m *atomicMetrics) addReusedConns(n uint64) { atomic.AddUint64(&m.reusedConns, n) }
+func (m *atomicMetrics) addNewConns(n uint64)    { atomic.AddUint64(&m.newConns, n) }
+func (m *atomicMetrics) addRetries(n uint64)     { atomic.AddUint64(&m.retries, n) }
+func (m *atomicMetrics) addHedgedWins(n uint64)  { atomic.AddUint64(&m.hedgedWins, n) }
+
+func (m *atomicMetrics) snapshot() Metrics {
+	return Metrics{
+		ReusedConns: atomic.LoadUint64(&m.reusedConns),
+		NewConns:    atomic.LoadUint64(&m.newConns),
+		Retries:     atomic.LoadUint64(&m.retries),
+		HedgedWins:  atomic.LoadUint64(&m.hedgedWins),
+	}
+}
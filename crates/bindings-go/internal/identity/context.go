@@ -0,0 +1,20 @@
+package identity
+
+import "context"
+
+// callerKey is the context key both the multi-database host and the
+// wasm host functions use to thread the calling identity through a
+// reducer invocation, so packages on either side of that call agree on
+// one representation instead of each defining their own key type.
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx carrying id as the caller identity.
+func WithCaller(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, callerKey{}, id)
+}
+
+// FromContext extracts the identity WithCaller attached to ctx, if any.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(callerKey{}).(Identity)
+	return id, ok
+}
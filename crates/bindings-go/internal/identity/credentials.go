@@ -0,0 +1,113 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Credentials pairs the Identity a SpacetimeDB server assigned a client
+// with the bearer token authenticating as that identity — what
+// RequestAnonymousIdentity returns, and what SaveCredentials/
+// LoadCredentials persist across process restarts.
+type Credentials struct {
+	Identity Identity `json:"identity"`
+	Token    string   `json:"token"`
+}
+
+// CredentialsPath returns the default location saved credentials are
+// stored at: $SPACETIMEDB_CONFIG_DIR/identity.json, or
+// ~/.spacetimedb/identity.json if that variable is unset. It mirrors
+// TokenPath's resolution, under a different filename, since a
+// Credentials file carries the Identity alongside the token rather than
+// just the bare token TokenPath's file holds.
+func CredentialsPath() (string, error) {
+	if dir := os.Getenv("SPACETIMEDB_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "identity.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("identity: resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".spacetimedb", "identity.json"), nil
+}
+
+// SaveCredentials writes creds to path as JSON, creating path's parent
+// directory if needed. The file is written with 0600 permissions, since
+// Token is a bearer credential.
+func SaveCredentials(path string, creds Credentials) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("identity: create credentials directory: %w", err)
+	}
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return fmt.Errorf("identity: encode credentials: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("identity: write credentials: %w", err)
+	}
+	return nil
+}
+
+// LoadCredentials reads and decodes the Credentials previously written
+// to path by SaveCredentials.
+func LoadCredentials(path string) (Credentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("identity: read credentials: %w", err)
+	}
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return Credentials{}, fmt.Errorf("identity: decode credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// AuthHeader returns an http.Header carrying creds.Token as a bearer
+// credential, ready to pass as-is to an http.Request's Header field or
+// to pkg/spacetimedb.DialWSClient's header argument, so a client
+// authenticates the same way whether it connects over HTTP or
+// WebSocket.
+func (creds Credentials) AuthHeader() http.Header {
+	h := make(http.Header, 1)
+	h.Set("Authorization", "Bearer "+creds.Token)
+	return h
+}
+
+// Provider returns a Provider that always hands back creds.Token,
+// letting a caller that already has Credentials (e.g. loaded via
+// LoadCredentials) plug them into anything written against the Provider
+// interface instead of a concrete Credentials value.
+func (creds Credentials) Provider() Provider {
+	return StaticProvider(creds.Token)
+}
+
+// RequestAnonymousIdentity asks serverURL's identity endpoint to mint a
+// new anonymous identity and token, the same request the CLI's
+// `spacetime login` performs when the user has no existing credentials.
+// httpClient may be nil, in which case http.DefaultClient is used.
+func RequestAnonymousIdentity(ctx context.Context, serverURL string, httpClient *http.Client) (Credentials, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, serverURL+"/v1/identity", nil)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("identity: build anonymous identity request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("identity: request anonymous identity: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Credentials{}, fmt.Errorf("identity: request anonymous identity: server returned %s", resp.Status)
+	}
+	var creds Credentials
+	if err := json.NewDecoder(resp.Body).Decode(&creds); err != nil {
+		return Credentials{}, fmt.Errorf("identity: decode anonymous identity response: %w", err)
+	}
+	return creds, nil
+}
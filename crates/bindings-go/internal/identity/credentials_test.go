@@ -0,0 +1,79 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveAndLoadCredentialsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "identity.json")
+	want := Credentials{Identity: Identity{1, 2, 3, 4}, Token: "tok-abc"}
+
+	if err := SaveCredentials(path, want); err != nil {
+		t.Fatalf("SaveCredentials: %v", err)
+	}
+	got, err := LoadCredentials(path)
+	if err != nil {
+		t.Fatalf("LoadCredentials: %v", err)
+	}
+	if got != want {
+		t.Fatalf("LoadCredentials = %+v, want %+v", got, want)
+	}
+}
+
+func TestCredentialsAuthHeaderSetsBearerToken(t *testing.T) {
+	creds := Credentials{Token: "tok-abc"}
+	got := creds.AuthHeader().Get("Authorization")
+	if want := "Bearer tok-abc"; got != want {
+		t.Fatalf("Authorization = %q, want %q", got, want)
+	}
+}
+
+func TestCredentialsProviderReturnsToken(t *testing.T) {
+	creds := Credentials{Token: "tok-abc"}
+	tok, err := creds.Provider().Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if tok != "tok-abc" {
+		t.Fatalf("Token = %q, want tok-abc", tok)
+	}
+}
+
+func TestRequestAnonymousIdentityDecodesServerResponse(t *testing.T) {
+	var gotMethod, gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		json.NewEncoder(w).Encode(map[string]any{
+			"identity": Identity{1, 2, 3, 4}.String(),
+			"token":    "tok-new",
+		})
+	}))
+	defer srv.Close()
+
+	creds, err := RequestAnonymousIdentity(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatalf("RequestAnonymousIdentity: %v", err)
+	}
+	if creds.Token != "tok-new" {
+		t.Fatalf("Token = %q, want tok-new", creds.Token)
+	}
+	if gotMethod != http.MethodPost || gotPath != "/v1/identity" {
+		t.Fatalf("request = %s %s, want POST /v1/identity", gotMethod, gotPath)
+	}
+}
+
+func TestRequestAnonymousIdentityReturnsErrorOnNonOKStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	if _, err := RequestAnonymousIdentity(context.Background(), srv.URL, nil); err == nil {
+		t.Fatal("expected an error for a non-200 response")
+	}
+}
@@ -0,0 +1,64 @@
+// Package identity defines the wire representation SpacetimeDB uses to
+// name a caller, shared by the Go host and the Go client SDK so both
+// sides agree on how an identity round-trips through BSATN.
+package identity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Identity is a 256-bit public identity, mirroring
+// `spacetimedb_lib::Identity`'s four u64 limbs.
+type Identity [4]uint64
+
+// Zero is the identity with every limb set to zero, used as a sentinel
+// for "no identity" in tests and default configuration.
+var Zero Identity
+
+// IsZero reports whether id is the Zero identity.
+func (id Identity) IsZero() bool {
+	return id == Zero
+}
+
+// String renders id as a hex string, most-significant limb first.
+func (id Identity) String() string {
+	return fmt.Sprintf("%016x%016x%016x%016x", id[0], id[1], id[2], id[3])
+}
+
+// MarshalJSON renders id as its hex String form, so a Credentials file
+// or a server response carries an identity the same human-readable way
+// every other SpacetimeDB SDK and CLI does, rather than as a JSON array
+// of four large numbers.
+func (id Identity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(id.String())
+}
+
+// UnmarshalJSON parses id from the hex string MarshalJSON produces.
+func (id *Identity) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("identity: decode identity: %w", err)
+	}
+	parsed, err := ParseIdentity(s)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// ParseIdentity parses the hex form produced by String back into an
+// Identity.
+func ParseIdentity(s string) (Identity, error) {
+	var id Identity
+	if len(s) != 64 {
+		return id, fmt.Errorf("identity: %q is not a 64-character hex identity", s)
+	}
+	for i := range id {
+		if _, err := fmt.Sscanf(s[i*16:i*16+16], "%016x", &id[i]); err != nil {
+			return Identity{}, fmt.Errorf("identity: parse %q: %w", s, err)
+		}
+	}
+	return id, nil
+}
@@ -0,0 +1,20 @@
+package identity
+
+import "testing"
+
+func TestParseIdentityRoundTrip(t *testing.T) {
+	want := Identity{0x1, 0x2, 0x3, 0x4}
+	got, err := ParseIdentity(want.String())
+	if err != nil {
+		t.Fatalf("ParseIdentity: %v", err)
+	}
+	if got != want {
+		t.Fatalf("ParseIdentity(%q) = %v, want %v", want.String(), got, want)
+	}
+}
+
+func TestParseIdentityRejectsWrongLength(t *testing.T) {
+	if _, err := ParseIdentity("abc"); err == nil {
+		t.Fatal("expected error for short identity string")
+	}
+}
@@ -0,0 +1,102 @@
+package identity
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RefreshFunc mints a new externally issued JWT, e.g. by calling a
+// game's own backend or a third-party identity provider's token
+// endpoint. JWTProvider calls it the first time Token is asked for a
+// token and again whenever the cached one is at or past
+// jwtRefreshSkew of its locally-parsed expiry, so a caller supplies
+// however it talks to its OIDC provider without JWTProvider needing to
+// know the specifics the way OIDCProvider's device-code flow does.
+type RefreshFunc func(ctx context.Context) (string, error)
+
+// jwtRefreshSkew is how much headroom before a cached JWT's parsed exp
+// claim JWTProvider insists on refreshing it, for the same reason
+// OIDCProvider's deviceAuthExpiry exists: a request in flight shouldn't
+// race the token expiring mid-call.
+const jwtRefreshSkew = 30 * time.Second
+
+// JWTProvider is a Provider for an externally issued JWT (e.g. from a
+// game's own backend, or a third-party OIDC identity provider): it
+// caches the token Refresh last returned, checks its exp claim locally
+// against the current time rather than trusting a server-reported TTL,
+// and calls Refresh again once the cached token is within jwtRefreshSkew
+// of expiring. It does not itself verify the JWT's signature; that is
+// the issuer's concern, not this client-side cache's.
+type JWTProvider struct {
+	Refresh RefreshFunc
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewJWTProvider returns a JWTProvider that calls refresh to obtain and
+// renew its token.
+func NewJWTProvider(refresh RefreshFunc) *JWTProvider {
+	return &JWTProvider{Refresh: refresh}
+}
+
+// Token returns the cached JWT if its locally-parsed expiry is still
+// more than jwtRefreshSkew away, otherwise calls Refresh for a new one.
+func (p *JWTProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Add(jwtRefreshSkew).Before(p.expiresAt) {
+		return p.token, nil
+	}
+
+	token, err := p.Refresh(ctx)
+	if err != nil {
+		return "", fmt.Errorf("identity: refresh JWT: %w", err)
+	}
+	expiresAt, err := JWTExpiry(token)
+	if err != nil {
+		return "", fmt.Errorf("identity: parse refreshed JWT: %w", err)
+	}
+	p.token = token
+	p.expiresAt = expiresAt
+	return p.token, nil
+}
+
+// jwtClaims is the subset of a JWT's payload claims this package reads.
+// Every other claim is ignored; validating anything beyond expiry (an
+// issuer, audience, or the signature itself) is the caller's or the
+// SpacetimeDB server's concern, not this client-side cache's.
+type jwtClaims struct {
+	Exp int64 `json:"exp"`
+}
+
+// JWTExpiry decodes token's payload segment (without verifying its
+// signature) and returns its exp claim as a time.Time, so a caller can
+// locally decide a token is stale without a round trip to the issuer.
+// It returns an error if token is not a three-segment JWT, its payload
+// is not valid base64url/JSON, or it carries no exp claim.
+func JWTExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("identity: %q is not a JWT (want 3 dot-separated segments)", token)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("identity: decode JWT payload: %w", err)
+	}
+	var claims jwtClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("identity: decode JWT claims: %w", err)
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, fmt.Errorf("identity: JWT has no exp claim")
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
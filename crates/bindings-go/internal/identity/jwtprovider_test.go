@@ -0,0 +1,83 @@
+package identity
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func makeJWT(t *testing.T, exp time.Time) string {
+	t.Helper()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"exp":` + strconv.FormatInt(exp.Unix(), 10) + `}`))
+	return header + "." + payload + ".sig"
+}
+
+func TestJWTExpiryParsesExpClaim(t *testing.T) {
+	want := time.Now().Add(time.Hour).Truncate(time.Second)
+	token := makeJWT(t, want)
+
+	got, err := JWTExpiry(token)
+	if err != nil {
+		t.Fatalf("JWTExpiry: %v", err)
+	}
+	if !got.Equal(want) {
+		t.Fatalf("JWTExpiry = %v, want %v", got, want)
+	}
+}
+
+func TestJWTExpiryRejectsMalformedToken(t *testing.T) {
+	if _, err := JWTExpiry("not-a-jwt"); err == nil {
+		t.Fatal("expected an error for a non-JWT string")
+	}
+}
+
+func TestJWTProviderCachesUntilNearExpiry(t *testing.T) {
+	calls := 0
+	p := NewJWTProvider(func(ctx context.Context) (string, error) {
+		calls++
+		return makeJWT(t, time.Now().Add(time.Hour)), nil
+	})
+
+	if _, err := p.Token(context.Background()); err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if _, err := p.Token(context.Background()); err != nil {
+		t.Fatalf("Token (cached): %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("Refresh called %d times, want 1 (token still fresh)", calls)
+	}
+}
+
+func TestJWTProviderRefreshesNearExpiry(t *testing.T) {
+	calls := 0
+	p := NewJWTProvider(func(ctx context.Context) (string, error) {
+		calls++
+		return makeJWT(t, time.Now().Add(jwtRefreshSkew/2)), nil
+	})
+
+	if _, err := p.Token(context.Background()); err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if _, err := p.Token(context.Background()); err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("Refresh called %d times, want 2 (token within refresh skew each time)", calls)
+	}
+}
+
+func TestJWTProviderPropagatesRefreshError(t *testing.T) {
+	wantErr := errors.New("refresh failed")
+	p := NewJWTProvider(func(ctx context.Context) (string, error) {
+		return "", wantErr
+	})
+
+	if _, err := p.Token(context.Background()); !errors.Is(err, wantErr) {
+		t.Fatalf("Token error = %v, want to wrap %v", err, wantErr)
+	}
+}
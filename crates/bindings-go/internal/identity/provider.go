@@ -0,0 +1,266 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider supplies the bearer token a client or CLI command
+// authenticates its requests with, abstracting over how that token is
+// obtained: hardcoded (StaticProvider), read from disk (FileProvider),
+// or minted via an identity provider's OAuth device-code flow
+// (OIDCProvider). Every implementation is safe for concurrent use, since
+// a long-lived client may call Token from multiple goroutines' requests
+// at once.
+type Provider interface {
+	// Token returns the current bearer token, refreshing it first if
+	// the implementation considers it stale.
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticProvider is a Provider that always returns the same token,
+// useful for local development or a CI job that already has a
+// short-lived token injected as an environment variable.
+type StaticProvider string
+
+// Token returns p unchanged.
+func (p StaticProvider) Token(ctx context.Context) (string, error) {
+	return string(p), nil
+}
+
+// FileProvider is a Provider backed by a token file on disk (see
+// LoadToken), re-reading it at most once per RefreshInterval so a
+// long-lived process picks up a token another process (e.g. `spacetime
+// login`) rotates without needing to restart, while not re-reading the
+// file on every single request.
+type FileProvider struct {
+	// Path is the token file to read; see TokenPath for the default
+	// location.
+	Path string
+	// RefreshInterval is how long a cached token is trusted before the
+	// next Token call re-reads Path. Zero means always re-read.
+	RefreshInterval time.Duration
+
+	mu       sync.Mutex
+	token    string
+	loadedAt time.Time
+}
+
+// NewFileProvider returns a FileProvider reading path, re-reading it at
+// most once per refreshInterval.
+func NewFileProvider(path string, refreshInterval time.Duration) *FileProvider {
+	return &FileProvider{Path: path, RefreshInterval: refreshInterval}
+}
+
+// Token returns the token last read from p.Path, re-reading it first if
+// it has never been read or p.RefreshInterval has elapsed since.
+func (p *FileProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Since(p.loadedAt) < p.RefreshInterval {
+		return p.token, nil
+	}
+	token, err := LoadToken(p.Path)
+	if err != nil {
+		return "", err
+	}
+	p.token = token
+	p.loadedAt = time.Now()
+	return p.token, nil
+}
+
+// OIDCConfig configures an OIDCProvider's device-code flow against an
+// OAuth 2.0 authorization server (RFC 8628).
+type OIDCConfig struct {
+	// DeviceAuthURL is the server's device authorization endpoint.
+	DeviceAuthURL string
+	// TokenURL is the server's token endpoint, polled after the user
+	// approves the device code.
+	TokenURL string
+	// ClientID identifies this application to the authorization server.
+	ClientID string
+	// Scopes are the OAuth scopes to request.
+	Scopes []string
+	// HTTP is the HTTP client to use. If nil, http.DefaultClient is used.
+	HTTP *http.Client
+	// Prompt is called once the device code is issued, with a message
+	// telling the user which URL to open and which code to enter there.
+	// If nil, the prompt is dropped, which is only reasonable if the
+	// caller surfaces deviceAuthResponse itself some other way.
+	Prompt func(verificationURI, userCode string)
+}
+
+// OIDCProvider is a Provider that authenticates via an OAuth 2.0 device
+// authorization grant: it directs the user to a verification URL and
+// polls the token endpoint until they approve it, then caches the
+// resulting access token until it is close to expiring.
+type OIDCProvider struct {
+	cfg OIDCConfig
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewOIDCProvider returns an OIDCProvider using cfg.
+func NewOIDCProvider(cfg OIDCConfig) *OIDCProvider {
+	return &OIDCProvider{cfg: cfg}
+}
+
+// deviceAuthExpiry is how much headroom before a cached access token's
+// reported expiry Token insists on renewing it, so a request in flight
+// doesn't race the token expiring mid-call.
+const deviceAuthExpiry = 30 * time.Second
+
+// Token returns a cached access token if one is still valid, otherwise
+// runs the device-code flow to obtain a new one: requesting a device
+// code, invoking cfg.Prompt with the URL and code the user must enter,
+// then polling the token endpoint at the interval it reports until the
+// user approves it, denies it, or ctx is cancelled.
+func (p *OIDCProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Add(deviceAuthExpiry).Before(p.expiresAt) {
+		return p.token, nil
+	}
+
+	device, err := p.requestDeviceCode(ctx)
+	if err != nil {
+		return "", fmt.Errorf("identity: request device code: %w", err)
+	}
+	if p.cfg.Prompt != nil {
+		p.cfg.Prompt(device.VerificationURI, device.UserCode)
+	}
+
+	token, expiresIn, err := p.pollForToken(ctx, device)
+	if err != nil {
+		return "", err
+	}
+	p.token = token
+	p.expiresAt = time.Now().Add(expiresIn)
+	return p.token, nil
+}
+
+type deviceAuthResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+func (p *OIDCProvider) httpClient() *http.Client {
+	if p.cfg.HTTP != nil {
+		return p.cfg.HTTP
+	}
+	return http.DefaultClient
+}
+
+func (p *OIDCProvider) requestDeviceCode(ctx context.Context) (*deviceAuthResponse, error) {
+	form := url.Values{
+		"client_id": {p.cfg.ClientID},
+		"scope":     {strings.Join(p.cfg.Scopes, " ")},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.DeviceAuthURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("device authorization endpoint returned %s", resp.Status)
+	}
+
+	var device deviceAuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&device); err != nil {
+		return nil, fmt.Errorf("decode device authorization response: %w", err)
+	}
+	if device.Interval <= 0 {
+		device.Interval = 5
+	}
+	return &device, nil
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	Error       string `json:"error"`
+}
+
+// pollForToken polls cfg.TokenURL at device's reported interval until
+// the user approves the device code (authorization_pending is the
+// expected error until then), the flow is denied or expires, or ctx is
+// cancelled.
+func (p *OIDCProvider) pollForToken(ctx context.Context, device *deviceAuthResponse) (string, time.Duration, error) {
+	ticker := time.NewTicker(time.Duration(device.Interval) * time.Second)
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(time.Duration(device.ExpiresIn) * time.Second)
+	for {
+		select {
+		case <-ctx.Done():
+			return "", 0, ctx.Err()
+		case <-ticker.C:
+		}
+		if device.ExpiresIn > 0 && time.Now().After(deadline) {
+			return "", 0, fmt.Errorf("identity: device code expired before the user approved it")
+		}
+
+		tok, err := p.exchangeDeviceCode(ctx, device.DeviceCode)
+		if err != nil {
+			return "", 0, err
+		}
+		if tok.Error == "authorization_pending" {
+			continue
+		}
+		if tok.Error == "slow_down" {
+			ticker.Reset(time.Duration(device.Interval+5) * time.Second)
+			continue
+		}
+		if tok.Error != "" {
+			return "", 0, fmt.Errorf("identity: device code flow failed: %s", tok.Error)
+		}
+		return tok.AccessToken, time.Duration(tok.ExpiresIn) * time.Second, nil
+	}
+}
+
+func (p *OIDCProvider) exchangeDeviceCode(ctx context.Context, deviceCode string) (*tokenResponse, error) {
+	form := url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"device_code": {deviceCode},
+		"client_id":   {p.cfg.ClientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tok tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return nil, fmt.Errorf("decode token response: %w", err)
+	}
+	return &tok, nil
+}
@@ -0,0 +1,124 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStaticProviderReturnsItsToken(t *testing.T) {
+	p := StaticProvider("tok-abc")
+	tok, err := p.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if tok != "tok-abc" {
+		t.Fatalf("Token = %q, want tok-abc", tok)
+	}
+}
+
+func TestFileProviderReadsAndCachesToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("tok-1\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	p := NewFileProvider(path, time.Hour)
+	tok, err := p.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if tok != "tok-1" {
+		t.Fatalf("Token = %q, want tok-1", tok)
+	}
+
+	if err := os.WriteFile(path, []byte("tok-2\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	tok, err = p.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if tok != "tok-1" {
+		t.Fatalf("Token = %q, want tok-1 (still cached)", tok)
+	}
+}
+
+func TestFileProviderRefreshesAfterInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	os.WriteFile(path, []byte("tok-1\n"), 0o600)
+
+	p := NewFileProvider(path, 0)
+	if tok, err := p.Token(context.Background()); err != nil || tok != "tok-1" {
+		t.Fatalf("Token = %q, %v", tok, err)
+	}
+
+	os.WriteFile(path, []byte("tok-2\n"), 0o600)
+	tok, err := p.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if tok != "tok-2" {
+		t.Fatalf("Token = %q, want tok-2 (zero RefreshInterval always re-reads)", tok)
+	}
+}
+
+func TestOIDCProviderRunsDeviceCodeFlow(t *testing.T) {
+	var prompted string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"device_code":      "dc-1",
+			"user_code":        "ABCD-1234",
+			"verification_uri": "https://example.com/activate",
+			"expires_in":       600,
+			"interval":         1,
+		})
+	})
+
+	pollCount := 0
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		pollCount++
+		if pollCount < 2 {
+			json.NewEncoder(w).Encode(map[string]any{"error": "authorization_pending"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "tok-final", "expires_in": 3600})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p := NewOIDCProvider(OIDCConfig{
+		DeviceAuthURL: srv.URL + "/device",
+		TokenURL:      srv.URL + "/token",
+		ClientID:      "cli",
+		Prompt: func(uri, code string) {
+			prompted = uri + " " + code
+		},
+	})
+
+	tok, err := p.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if tok != "tok-final" {
+		t.Fatalf("Token = %q, want tok-final", tok)
+	}
+	if prompted != "https://example.com/activate ABCD-1234" {
+		t.Fatalf("Prompt not invoked with expected args, got %q", prompted)
+	}
+
+	// A second call should hit the cache, not poll again.
+	before := pollCount
+	if _, err := p.Token(context.Background()); err != nil {
+		t.Fatalf("Token (cached): %v", err)
+	}
+	if pollCount != before {
+		t.Fatalf("expected cached Token call to skip polling, pollCount went from %d to %d", before, pollCount)
+	}
+}
@@ -0,0 +1,38 @@
+package identity
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TokenPath returns the default location a login token is stored at:
+// $SPACETIMEDB_CONFIG_DIR/token, or ~/.spacetimedb/token if that
+// variable is unset. It does not check that the file exists.
+func TokenPath() (string, error) {
+	if dir := os.Getenv("SPACETIMEDB_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "token"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("identity: resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".spacetimedb", "token"), nil
+}
+
+// LoadToken reads and trims the login token from path. It reads a bare
+// token file, the shape `spacetime login` writes; a caller that also
+// needs the Identity the token authenticates as should use
+// LoadCredentials and SaveCredentials instead.
+func LoadToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("identity: read token: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("identity: token file %s is empty", path)
+	}
+	return token, nil
+}
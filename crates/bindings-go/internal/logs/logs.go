@@ -0,0 +1,89 @@
+// Package logs defines the structured log entry format SpacetimeDB
+// databases emit (used by both the module host's console_log filtering
+// and the `spacetimedb logs` client) and a follow-mode reader for the
+// database log endpoint's streamed output.
+package logs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Level orders log severities from most to least severe, matching the
+// levels a module's console_log host call can report.
+type Level int
+
+const (
+	LevelError Level = iota
+	LevelWarn
+	LevelInfo
+	LevelDebug
+	LevelTrace
+)
+
+// String renders the level the way the log endpoint and CLI do: lower
+// case, e.g. "info".
+func (l Level) String() string {
+	switch l {
+	case LevelError:
+		return "error"
+	case LevelWarn:
+		return "warn"
+	case LevelInfo:
+		return "info"
+	case LevelDebug:
+		return "debug"
+	case LevelTrace:
+		return "trace"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+// ParseLevel parses the case-insensitive level names String produces.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "error":
+		return LevelError, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "info":
+		return LevelInfo, nil
+	case "debug":
+		return LevelDebug, nil
+	case "trace":
+		return LevelTrace, nil
+	default:
+		return 0, fmt.Errorf("logs: unknown level %q", s)
+	}
+}
+
+// Entry is one structured log line emitted by a running module.
+type Entry struct {
+	Timestamp time.Time
+	Level     Level
+	File      string
+	Line      int
+	Reducer   string
+	Message   string
+}
+
+// Format renders e the way the CLI's pretty printer displays it:
+// "<rfc3339> <LEVEL> [<reducer>] <file>:<line>: <message>". Reducer,
+// file, and line are omitted when empty/zero, since not every log line
+// is emitted from within a reducer call.
+func (e Entry) Format() string {
+	var b strings.Builder
+	b.WriteString(e.Timestamp.Format(time.RFC3339))
+	b.WriteByte(' ')
+	b.WriteString(strings.ToUpper(e.Level.String()))
+	if e.Reducer != "" {
+		fmt.Fprintf(&b, " [%s]", e.Reducer)
+	}
+	if e.File != "" {
+		fmt.Fprintf(&b, " %s:%d:", e.File, e.Line)
+	}
+	fmt.Fprintf(&b, " %s", e.Message)
+	return b.String()
+}
@@ -0,0 +1,119 @@
+package logs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Filter selects which entries Follow forwards to its output channel.
+type Filter struct {
+	// MinLevel forwards entries at this severity or higher (lower
+	// Level value). Zero value LevelError is the least permissive;
+	// pass LevelTrace to forward everything.
+	MinLevel Level
+	// Reducer, if non-empty, forwards only entries logged from that
+	// reducer.
+	Reducer string
+}
+
+// Allows reports whether e passes f.
+func (f Filter) Allows(e Entry) bool {
+	if e.Level > f.MinLevel {
+		return false
+	}
+	if f.Reducer != "" && e.Reducer != f.Reducer {
+		return false
+	}
+	return true
+}
+
+// wireEntry is the JSON-lines format the database log endpoint streams:
+// one object per line, oldest first, with more lines arriving as the
+// module logs them in follow mode.
+type wireEntry struct {
+	Timestamp string `json:"ts"`
+	Level     string `json:"level"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Reducer   string `json:"reducer"`
+	Message   string `json:"message"`
+}
+
+// Follow reads newline-delimited JSON log entries from r, parses and
+// filters each with filter, and sends the survivors on the returned
+// channel. It runs until r returns io.EOF, ctx is cancelled, or a line
+// fails to parse; either way both channels are closed and at most one
+// value (nil on clean EOF) is sent on the error channel.
+//
+// r is typically the body of a streaming HTTP GET against the log
+// endpoint with follow=true, which keeps the connection open and
+// flushes new lines as the module emits them.
+func Follow(ctx context.Context, r io.Reader, filter Filter) (<-chan Entry, <-chan error) {
+	entries := make(chan Entry)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(entries)
+		defer close(errc)
+
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			default:
+			}
+
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+			entry, err := parseLine(line)
+			if err != nil {
+				errc <- err
+				return
+			}
+			if !filter.Allows(entry) {
+				continue
+			}
+			select {
+			case entries <- entry:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+		errc <- scanner.Err()
+	}()
+
+	return entries, errc
+}
+
+func parseLine(line []byte) (Entry, error) {
+	var w wireEntry
+	if err := json.Unmarshal(line, &w); err != nil {
+		return Entry{}, fmt.Errorf("logs: parse line: %w", err)
+	}
+	level, err := ParseLevel(w.Level)
+	if err != nil {
+		return Entry{}, err
+	}
+	ts, err := time.Parse(time.RFC3339Nano, w.Timestamp)
+	if err != nil {
+		return Entry{}, fmt.Errorf("logs: parse timestamp %q: %w", w.Timestamp, err)
+	}
+	return Entry{
+		Timestamp: ts,
+		Level:     level,
+		File:      w.File,
+		Line:      w.Line,
+		Reducer:   w.Reducer,
+		Message:   w.Message,
+	}, nil
+}
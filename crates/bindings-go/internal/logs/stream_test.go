@@ -0,0 +1,50 @@
+package logs
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const sample = `{"ts":"2024-01-01T00:00:00Z","level":"info","file":"main.rs","line":10,"reducer":"deposit","message":"ok"}
+{"ts":"2024-01-01T00:00:01Z","level":"debug","file":"main.rs","line":11,"reducer":"deposit","message":"noisy"}
+{"ts":"2024-01-01T00:00:02Z","level":"error","file":"main.rs","line":12,"reducer":"withdraw","message":"boom"}
+`
+
+func TestFollowFiltersByLevel(t *testing.T) {
+	entries, errc := Follow(context.Background(), strings.NewReader(sample), Filter{MinLevel: LevelInfo})
+	var got []Entry
+	for e := range entries {
+		got = append(got, e)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("Follow: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want 2 (debug filtered out): %v", len(got), got)
+	}
+	if got[0].Message != "ok" || got[1].Message != "boom" {
+		t.Fatalf("unexpected entries: %v", got)
+	}
+}
+
+func TestFollowFiltersByReducer(t *testing.T) {
+	entries, errc := Follow(context.Background(), strings.NewReader(sample), Filter{MinLevel: LevelTrace, Reducer: "withdraw"})
+	var got []Entry
+	for e := range entries {
+		got = append(got, e)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("Follow: %v", err)
+	}
+	if len(got) != 1 || got[0].Reducer != "withdraw" {
+		t.Fatalf("got %v, want single withdraw entry", got)
+	}
+}
+
+func TestFollowRejectsMalformedLine(t *testing.T) {
+	_, errc := Follow(context.Background(), strings.NewReader("not json\n"), Filter{MinLevel: LevelTrace})
+	if err := <-errc; err == nil {
+		t.Fatal("expected parse error")
+	}
+}
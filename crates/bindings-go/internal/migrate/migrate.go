@@ -0,0 +1,174 @@
+// Package migrate implements a generic runner for one-off data
+// migrations against a Go-hosted module's local database: ordered,
+// named steps that backfill or reshape rows, each applied at most once
+// and recorded in a dedicated table so re-running the module (or the
+// migration CLI) is a no-op for anything already applied.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+// tableName is the system table Runner records applied migrations in,
+// named after the "st_"-prefixed system tables (st_table, st_column,
+// ...) SpacetimeDB itself reserves for host bookkeeping.
+const tableName = "st_migrations"
+
+// Migration is one named, ordered data migration step. Apply runs
+// inside a Txn against the Runner's Database — under Serializable
+// isolation (the Database default) its writes conflict the same way a
+// reducer's would if run concurrently with one.
+type Migration struct {
+	// ID must be unique and, by convention, sortable in application
+	// order (e.g. a zero-padded sequence number or a date prefix); the
+	// Runner applies migrations in the order they're passed to
+	// NewRunner, not by sorting ID, but a sortable ID keeps the log
+	// (see Result) readable.
+	ID string
+	// Description is a short, human-readable summary, surfaced in
+	// Result and Progress callbacks but not otherwise interpreted.
+	Description string
+	// Apply performs the migration's work. It must be idempotent only
+	// in the sense that the Runner never calls it twice for the same
+	// ID under normal operation; Apply itself doesn't need to guard
+	// against re-application.
+	Apply func(ctx context.Context, txn *db.Txn) error
+}
+
+// appliedRecord is the BSATN-encoded row Runner stores per applied
+// migration, keyed by Migration.ID.
+type appliedRecord struct {
+	ID          string
+	Description string
+	AppliedAt   int64 // Unix nanoseconds
+}
+
+// Runner applies a fixed, ordered list of Migrations against a
+// Database, skipping any already recorded as applied in st_migrations.
+type Runner struct {
+	database   *db.Database
+	migrations []Migration
+}
+
+// NewRunner returns a Runner that applies migrations, in the order
+// given, against database.
+func NewRunner(database *db.Database, migrations ...Migration) *Runner {
+	return &Runner{database: database, migrations: migrations}
+}
+
+// Progress is called by Run after each migration it actually applies
+// (never for one Pending skips as already-applied, and never during a
+// dry run's Apply-skipping pass).
+type Progress func(m Migration)
+
+// RunOptions configures Run.
+type RunOptions struct {
+	// DryRun reports which migrations would be applied without calling
+	// their Apply functions or recording them as applied.
+	DryRun bool
+	// OnApplied, if set, is called immediately after each migration
+	// commits, in application order, as a checkpoint a caller can use
+	// to log progress through a long migration list.
+	OnApplied Progress
+}
+
+// Result summarizes one Run call.
+type Result struct {
+	// Applied lists the IDs of migrations Run applied (or, under
+	// DryRun, would have applied), in application order.
+	Applied []string
+	// Skipped lists the IDs of migrations Run found already recorded
+	// in st_migrations and left untouched.
+	Skipped []string
+}
+
+// Pending returns the subset of the Runner's migrations not yet
+// recorded as applied in st_migrations, in application order.
+func (r *Runner) Pending() ([]Migration, error) {
+	applied, err := r.appliedIDs()
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, m := range r.migrations {
+		if !applied[m.ID] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// Run applies every pending migration in order, recording each as
+// applied immediately after its Apply function returns without error,
+// so a Run that fails partway through can be safely re-invoked: the
+// migrations it already committed are skipped the second time. Run
+// stops at the first Apply error and returns it, with Result reflecting
+// only the migrations applied (or, under DryRun, identified as pending)
+// before the failure.
+func (r *Runner) Run(ctx context.Context, opts RunOptions) (Result, error) {
+	applied, err := r.appliedIDs()
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, m := range r.migrations {
+		if applied[m.ID] {
+			result.Skipped = append(result.Skipped, m.ID)
+			continue
+		}
+		if opts.DryRun {
+			result.Applied = append(result.Applied, m.ID)
+			continue
+		}
+
+		txn := r.database.Begin()
+		if err := m.Apply(ctx, txn); err != nil {
+			txn.Rollback()
+			return result, fmt.Errorf("migrate: apply %q: %w", m.ID, err)
+		}
+		if err := r.recordApplied(txn, m); err != nil {
+			txn.Rollback()
+			return result, fmt.Errorf("migrate: record %q applied: %w", m.ID, err)
+		}
+		if err := txn.Commit(); err != nil {
+			return result, fmt.Errorf("migrate: commit %q: %w", m.ID, err)
+		}
+
+		result.Applied = append(result.Applied, m.ID)
+		if opts.OnApplied != nil {
+			opts.OnApplied(m)
+		}
+	}
+	return result, nil
+}
+
+// recordApplied writes m's appliedRecord into st_migrations as part of
+// txn, so it commits or rolls back atomically with m's own writes.
+func (r *Runner) recordApplied(txn *db.Txn, m Migration) error {
+	rec := appliedRecord{ID: m.ID, Description: m.Description, AppliedAt: time.Now().UnixNano()}
+	value, err := bsatn.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return txn.Put(tableName, []byte(m.ID), value)
+}
+
+// appliedIDs returns the set of migration IDs already recorded in
+// st_migrations.
+func (r *Runner) appliedIDs() (map[string]bool, error) {
+	cur, err := r.database.Engine.Scan(tableName)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[string]bool)
+	for cur.Next() {
+		applied[string(cur.Key())] = true
+	}
+	return applied, cur.Close()
+}
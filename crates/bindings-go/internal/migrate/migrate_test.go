@@ -0,0 +1,128 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func seedMigration(id string, applied *[]string) Migration {
+	return Migration{
+		ID:          id,
+		Description: "seed " + id,
+		Apply: func(ctx context.Context, txn *db.Txn) error {
+			*applied = append(*applied, id)
+			return txn.Put("widgets", []byte(id), []byte("v"))
+		},
+	}
+}
+
+func TestRunAppliesInOrderAndRecords(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	var calls []string
+	runner := NewRunner(database, seedMigration("001", &calls), seedMigration("002", &calls))
+
+	result, err := runner.Run(context.Background(), RunOptions{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(result.Applied) != 2 || result.Applied[0] != "001" || result.Applied[1] != "002" {
+		t.Fatalf("Applied = %v, want [001 002]", result.Applied)
+	}
+	if len(calls) != 2 || calls[0] != "001" {
+		t.Fatalf("Apply call order = %v", calls)
+	}
+}
+
+func TestRunSkipsAlreadyApplied(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	var calls []string
+	runner := NewRunner(database, seedMigration("001", &calls))
+
+	if _, err := runner.Run(context.Background(), RunOptions{}); err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	calls = nil
+
+	result, err := runner.Run(context.Background(), RunOptions{})
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "001" {
+		t.Fatalf("Skipped = %v, want [001]", result.Skipped)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("Apply called again on already-applied migration")
+	}
+}
+
+func TestRunDryRunDoesNotApplyOrRecord(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	var calls []string
+	runner := NewRunner(database, seedMigration("001", &calls))
+
+	result, err := runner.Run(context.Background(), RunOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(result.Applied) != 1 || result.Applied[0] != "001" {
+		t.Fatalf("Applied = %v, want [001] reported as pending", result.Applied)
+	}
+	if len(calls) != 0 {
+		t.Fatal("DryRun called Apply")
+	}
+
+	pending, err := runner.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("Pending after dry run = %v, want still pending", pending)
+	}
+}
+
+func TestRunStopsAtFirstFailureAndIsResumable(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	var calls []string
+	failing := Migration{
+		ID: "002",
+		Apply: func(ctx context.Context, txn *db.Txn) error {
+			return context.DeadlineExceeded
+		},
+	}
+	runner := NewRunner(database, seedMigration("001", &calls), failing, seedMigration("003", &calls))
+
+	result, err := runner.Run(context.Background(), RunOptions{})
+	if err == nil {
+		t.Fatal("Run: want error from failing migration")
+	}
+	if len(result.Applied) != 1 || result.Applied[0] != "001" {
+		t.Fatalf("Applied before failure = %v, want [001]", result.Applied)
+	}
+
+	pending, err := runner.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 2 || pending[0].ID != "002" {
+		t.Fatalf("Pending after failed Run = %v, want [002 003]", pending)
+	}
+}
+
+func TestRunCallsOnAppliedCheckpoint(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	var calls []string
+	var progressed []string
+	runner := NewRunner(database, seedMigration("001", &calls), seedMigration("002", &calls))
+
+	_, err := runner.Run(context.Background(), RunOptions{
+		OnApplied: func(m Migration) { progressed = append(progressed, m.ID) },
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(progressed) != 2 || progressed[1] != "002" {
+		t.Fatalf("progressed = %v, want [001 002]", progressed)
+	}
+}
@@ -0,0 +1,121 @@
+// Package moduledef decodes the BSATN-encoded module definition a
+// SpacetimeDB module's __describe_module__ export returns, exposing
+// typed Go structs for its tables, reducers, indexes, constraints,
+// schedules, and type-space — the same introspection Rust's
+// spacetimedb-schema crate does for a Rust-built module.
+//
+// ModuleDef's shape is this package's best-effort mirror of the real
+// RawModuleDefV9 wire format: this host has no authoritative copy of
+// that format's exact field layout to decode against, so ModuleDef is
+// decoded with pkg/bsatn's ordinary reflection-based struct decoding
+// (the same mechanism protocol.CallReducerRequest and friends use),
+// honoring field order and `bsatn` tags the way any other BSATN struct
+// in this module does. A module compiled against a future, incompatible
+// RawModuleDefV9 revision may not decode correctly until this package's
+// structs are updated to match it.
+package moduledef
+
+import (
+	"fmt"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// LifecycleKind mirrors internal/wasm.LifecycleKind's values, so
+// ReducerDef.Lifecycle converts to it with a plain cast (see
+// ReducerDef.ToReducerSchema) without this package importing wasm just
+// for one enum.
+type LifecycleKind uint8
+
+const (
+	LifecycleNone LifecycleKind = iota
+	LifecycleInit
+	LifecycleClientConnected
+	LifecycleClientDisconnected
+)
+
+// ConstraintKind classifies a ConstraintDef. Unique is the only kind a
+// Go-hosted module needs enforced today (see internal/db.IndexInfo's
+// Unique field); more RawModuleDefV9 constraint kinds (e.g. foreign key)
+// can be added here without changing ModuleDef's shape.
+type ConstraintKind uint8
+
+const (
+	ConstraintUnique ConstraintKind = iota
+)
+
+// IndexDef describes one secondary index over a table's columns, by
+// position in its row product type.
+type IndexDef struct {
+	Name    string   `bsatn:"name"`
+	Columns []uint32 `bsatn:"columns"`
+	Unique  bool     `bsatn:"unique"`
+}
+
+// ConstraintDef describes one constraint over a table's columns.
+type ConstraintDef struct {
+	Name    string         `bsatn:"name"`
+	Kind    ConstraintKind `bsatn:"kind"`
+	Columns []uint32       `bsatn:"columns"`
+}
+
+// ScheduleDef describes a scheduled table: ReducerName is called once
+// for every row, at the time its AtColumn column (by position in the
+// table's row product type) names — see internal/scheduler.
+type ScheduleDef struct {
+	ReducerName string `bsatn:"reducer_name"`
+	AtColumn    uint32 `bsatn:"at_column"`
+}
+
+// TableDef describes one table: its row type (by index into the
+// ModuleDef's Typespace), primary key (by column position), and the
+// indexes, constraints, and schedule declared on it, if any.
+type TableDef struct {
+	Name        string          `bsatn:"name"`
+	ProductRef  uint32          `bsatn:"product_ref"`
+	PrimaryKey  []uint32        `bsatn:"primary_key"`
+	Indexes     []IndexDef      `bsatn:"indexes"`
+	Constraints []ConstraintDef `bsatn:"constraints"`
+	// Schedule is nil for a table that is not scheduled.
+	Schedule *ScheduleDef `bsatn:"schedule"`
+}
+
+// ReducerDef describes one reducer: its name, argument product type, and
+// which lifecycle moment it runs for, if any (see LifecycleKind).
+type ReducerDef struct {
+	Name      string              `bsatn:"name"`
+	Args      bsatn.AlgebraicType `bsatn:"args"`
+	Lifecycle LifecycleKind       `bsatn:"lifecycle"`
+}
+
+// ModuleDef is this host's decoded view of a module's RawModuleDefV9.
+// Reducers is in the order __describe_module__ reported them, which this
+// package treats as their reducerID assignment (see ReducerSchemas) —
+// the same ordinal-ID assumption a real host's __describe_module__
+// parser makes. Tables and each table's Indexes are treated the same
+// way for tableID/indexID assignment (see TableSchemas).
+type ModuleDef struct {
+	Typespace []bsatn.AlgebraicType `bsatn:"typespace"`
+	Tables    []TableDef            `bsatn:"tables"`
+	Reducers  []ReducerDef          `bsatn:"reducers"`
+}
+
+// Decode decodes raw — the bytes a module's __describe_module__ export
+// returns — into a ModuleDef.
+func Decode(raw []byte) (ModuleDef, error) {
+	var def ModuleDef
+	if err := bsatn.Unmarshal(raw, &def); err != nil {
+		return ModuleDef{}, fmt.Errorf("moduledef: decode: %w", err)
+	}
+	return def, nil
+}
+
+// Encode is Decode's inverse, for building __describe_module__ fixtures
+// in tests without hand-assembling BSATN bytes.
+func Encode(def ModuleDef) ([]byte, error) {
+	data, err := bsatn.Marshal(def)
+	if err != nil {
+		return nil, fmt.Errorf("moduledef: encode: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,72 @@
+package moduledef
+
+import (
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func exampleDef() ModuleDef {
+	name := "id"
+	return ModuleDef{
+		Typespace: []bsatn.AlgebraicType{bsatn.U32()},
+		Tables: []TableDef{
+			{
+				Name:       "player",
+				ProductRef: 0,
+				PrimaryKey: []uint32{0},
+				Indexes:    []IndexDef{{Name: "player_id_idx", Columns: []uint32{0}, Unique: true}},
+				Constraints: []ConstraintDef{
+					{Name: "player_id_unique", Kind: ConstraintUnique, Columns: []uint32{0}},
+				},
+			},
+		},
+		Reducers: []ReducerDef{
+			{Name: "setup", Args: bsatn.ProductOf(), Lifecycle: LifecycleInit},
+			{Name: "add_player", Args: bsatn.ProductOf(bsatn.ProductElement{Name: &name, Type: bsatn.U32()})},
+		},
+	}
+}
+
+func TestEncodeDecodeRoundTrips(t *testing.T) {
+	def := exampleDef()
+
+	data, err := Encode(def)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	got, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if len(got.Tables) != 1 || got.Tables[0].Name != "player" {
+		t.Fatalf("Tables = %+v, want one table named player", got.Tables)
+	}
+	if !got.Tables[0].Indexes[0].Unique || got.Tables[0].Indexes[0].Name != "player_id_idx" {
+		t.Fatalf("Indexes = %+v", got.Tables[0].Indexes)
+	}
+	if len(got.Reducers) != 2 || got.Reducers[0].Name != "setup" || got.Reducers[0].Lifecycle != LifecycleInit {
+		t.Fatalf("Reducers = %+v", got.Reducers)
+	}
+}
+
+func TestReducerSchemasAssignsOrdinalIDs(t *testing.T) {
+	schemas := exampleDef().ReducerSchemas()
+	if len(schemas) != 2 {
+		t.Fatalf("len(schemas) = %d, want 2", len(schemas))
+	}
+	if schemas[0].ID != 0 || schemas[0].Name != "setup" {
+		t.Fatalf("schemas[0] = %+v", schemas[0])
+	}
+	if schemas[1].ID != 1 || schemas[1].Name != "add_player" {
+		t.Fatalf("schemas[1] = %+v", schemas[1])
+	}
+}
+
+func TestDecodeRejectsGarbage(t *testing.T) {
+	if _, err := Decode([]byte{0xFF, 0xFF}); err == nil {
+		t.Fatal("Decode: want an error for malformed input")
+	}
+}
@@ -0,0 +1,24 @@
+package moduledef
+
+import "github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/wasm"
+
+// ReducerSchemas converts d's Reducers into the []wasm.ReducerSchema
+// wasm.Runtime.SetReducers expects, assigning each reducer its position
+// in Reducers as its reducerID — the ordinal assignment this host
+// assumes __describe_module__ uses until it parses a real per-reducer ID
+// out of RawModuleDefV9 instead. Once internal/wasm's host call dispatch
+// also derives call targets this way, a caller can feed this straight
+// into SetReducers instead of hand-building the manifest (see
+// wasm.ReducerSchema's doc comment).
+func (d ModuleDef) ReducerSchemas() []wasm.ReducerSchema {
+	out := make([]wasm.ReducerSchema, len(d.Reducers))
+	for i, r := range d.Reducers {
+		out[i] = wasm.ReducerSchema{
+			ID:        uint32(i),
+			Name:      r.Name,
+			Args:      r.Args,
+			Lifecycle: wasm.LifecycleKind(r.Lifecycle),
+		}
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package moduledef
+
+import (
+	"fmt"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/wasm"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// TableSchemas converts d's Tables into the []wasm.TableSchema
+// wasm.Runtime.SetTables expects, assigning tableID and indexID the same
+// way ReducerSchemas assigns reducerID: a table's tableID is its
+// position in d.Tables, and an index's indexID is its position in the
+// flattened sequence of every index over every table, in table then
+// index order — matching the single flat indexID namespace
+// RegisterIndex/RegisterIndexKey already key their maps by. This is the
+// same ordinal-ID stand-in ReducerSchemas uses until a real per-table,
+// per-index ID is parsed out of RawModuleDefV9 instead.
+//
+// Each IndexSchema's Key decodes a row per its table's ProductRef type
+// (looked up in d.Typespace) and projects the index's Columns with
+// bsatn.ProjectProductColumns, ordered-key-encoding the result with
+// db.EncodeOrderedKey — so a Runtime fed this via SetTables derives a
+// real, schema-correct key for datastore_update_bsatn instead of falling
+// back to Runtime.rowKey's whole-row placeholder.
+//
+// It returns an error if any table's ProductRef is out of range for
+// d.Typespace; a column type ProjectProductColumns can't turn into an
+// ordered key (e.g. a nested product) only surfaces as an error from the
+// Key func itself, the first time that index is actually used to store a
+// row.
+func (d ModuleDef) TableSchemas() ([]wasm.TableSchema, error) {
+	out := make([]wasm.TableSchema, len(d.Tables))
+	indexID := uint32(0)
+	for i, table := range d.Tables {
+		if int(table.ProductRef) >= len(d.Typespace) {
+			return nil, fmt.Errorf("moduledef: table %q: product_ref %d out of range for a %d-entry typespace", table.Name, table.ProductRef, len(d.Typespace))
+		}
+		rowType := d.Typespace[table.ProductRef]
+
+		indexes := make([]wasm.IndexSchema, len(table.Indexes))
+		for j, idx := range table.Indexes {
+			id := indexID
+			indexID++
+			columns := idx.Columns
+			name := idx.Name
+			indexes[j] = wasm.IndexSchema{
+				ID:   id,
+				Name: name,
+				Key: func(row []byte) ([]byte, error) {
+					values, err := bsatn.ProjectProductColumns(row, rowType, columns)
+					if err != nil {
+						return nil, fmt.Errorf("moduledef: derive key for index %q: %w", name, err)
+					}
+					return db.EncodeOrderedKey(values...), nil
+				},
+			}
+		}
+
+		out[i] = wasm.TableSchema{
+			ID:      uint32(i),
+			Name:    table.Name,
+			Indexes: indexes,
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,124 @@
+package moduledef
+
+import (
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// multiIndexDef describes two tables over a shared {id U32, name String}
+// row type, the second table with two indexes, so TableSchemas' ordinal
+// ID assignment can be checked across tables (tableID) and flattened
+// across every table's indexes (indexID).
+func multiIndexDef() ModuleDef {
+	name := "name"
+	row := bsatn.ProductOf(
+		bsatn.ProductElement{Type: bsatn.U32()},
+		bsatn.ProductElement{Name: &name, Type: bsatn.String()},
+	)
+	return ModuleDef{
+		Typespace: []bsatn.AlgebraicType{row},
+		Tables: []TableDef{
+			{
+				Name:       "player",
+				ProductRef: 0,
+				Indexes:    []IndexDef{{Name: "player_id_idx", Columns: []uint32{0}, Unique: true}},
+			},
+			{
+				Name:       "item",
+				ProductRef: 0,
+				Indexes: []IndexDef{
+					{Name: "item_id_idx", Columns: []uint32{0}, Unique: true},
+					{Name: "item_name_idx", Columns: []uint32{1}},
+				},
+			},
+		},
+	}
+}
+
+func TestTableSchemasAssignsOrdinalIDsAcrossTables(t *testing.T) {
+	schemas, err := multiIndexDef().TableSchemas()
+	if err != nil {
+		t.Fatalf("TableSchemas: %v", err)
+	}
+	if len(schemas) != 2 {
+		t.Fatalf("len(schemas) = %d, want 2", len(schemas))
+	}
+	if schemas[0].ID != 0 || schemas[0].Name != "player" {
+		t.Fatalf("schemas[0] = %+v", schemas[0])
+	}
+	if schemas[1].ID != 1 || schemas[1].Name != "item" {
+		t.Fatalf("schemas[1] = %+v", schemas[1])
+	}
+
+	if len(schemas[0].Indexes) != 1 || schemas[0].Indexes[0].ID != 0 {
+		t.Fatalf("schemas[0].Indexes = %+v, want one index with ID 0", schemas[0].Indexes)
+	}
+	item := schemas[1].Indexes
+	if len(item) != 2 || item[0].ID != 1 || item[1].ID != 2 {
+		t.Fatalf("schemas[1].Indexes = %+v, want IDs [1, 2] continuing on from table 0's index", item)
+	}
+}
+
+func TestTableSchemasIndexKeyProjectsOnlyItsOwnColumns(t *testing.T) {
+	schemas, err := multiIndexDef().TableSchemas()
+	if err != nil {
+		t.Fatalf("TableSchemas: %v", err)
+	}
+
+	row, err := bsatn.Marshal(struct {
+		ID   uint32
+		Name string
+	}{42, "alice"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	item := schemas[1]
+	idKey, err := item.Indexes[0].Key(row)
+	if err != nil {
+		t.Fatalf("id index Key: %v", err)
+	}
+	nameKey, err := item.Indexes[1].Key(row)
+	if err != nil {
+		t.Fatalf("name index Key: %v", err)
+	}
+	if string(idKey) == string(nameKey) {
+		t.Fatalf("id key %x and name key %x should differ: they project different columns", idKey, nameKey)
+	}
+
+	// A row with a different id but the same name must still produce a
+	// different id-index key — the whole-row fallback this is replacing
+	// would instead key by the entire row and treat these as unrelated.
+	row2, err := bsatn.Marshal(struct {
+		ID   uint32
+		Name string
+	}{43, "alice"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	idKey2, err := item.Indexes[0].Key(row2)
+	if err != nil {
+		t.Fatalf("id index Key: %v", err)
+	}
+	if string(idKey) == string(idKey2) {
+		t.Fatal("id keys for id 42 and id 43 should differ")
+	}
+
+	// A row sharing only the name column must collide on the name index
+	// alone, proving it keys by name and not by the rest of the row.
+	nameKey2, err := item.Indexes[1].Key(row2)
+	if err != nil {
+		t.Fatalf("name index Key: %v", err)
+	}
+	if string(nameKey) != string(nameKey2) {
+		t.Fatalf("name keys for two rows both named %q should match: got %x and %x", "alice", nameKey, nameKey2)
+	}
+}
+
+func TestTableSchemasRejectsOutOfRangeProductRef(t *testing.T) {
+	def := ModuleDef{Tables: []TableDef{{Name: "player", ProductRef: 0}}}
+	if _, err := def.TableSchemas(); err == nil {
+		t.Fatal("TableSchemas: want an error for a product_ref with no matching typespace entry")
+	}
+}
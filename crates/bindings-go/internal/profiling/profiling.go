@@ -0,0 +1,38 @@
+// Package profiling attributes CPU and heap profile samples collected
+// under load to the reducer and database that caused them, and exposes
+// the standard net/http/pprof endpoints for a Go host to serve.
+package profiling
+
+import (
+	"context"
+	"net/http"
+	httppprof "net/http/pprof"
+	runtimepprof "runtime/pprof"
+)
+
+// Do runs fn with the calling goroutine (and any it starts that inherit
+// ctx) labeled with reducer and database, so a CPU or heap profile taken
+// while fn runs attributes its cost to that reducer via `go tool pprof
+// -tagfocus=reducer=<name>`. It is meant to wrap Host.CallReducer's
+// invocation of the underlying wasm call.
+func Do(ctx context.Context, reducer, database string, fn func(context.Context)) {
+	labels := runtimepprof.Labels("reducer", reducer, "database", database)
+	runtimepprof.Do(ctx, labels, fn)
+}
+
+// Handler returns an http.Handler serving the standard net/http/pprof
+// endpoints (index, cmdline, profile, symbol, trace, and the named
+// profiles registered with the runtime/pprof package) under the
+// "/debug/pprof/" prefix, for a host process to mount on whatever
+// address it chooses. It does not register anything on
+// http.DefaultServeMux, unlike importing net/http/pprof for its
+// init-time side effect.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", httppprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", httppprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", httppprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", httppprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", httppprof.Trace)
+	return mux
+}
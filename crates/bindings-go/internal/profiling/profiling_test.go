@@ -0,0 +1,39 @@
+package profiling
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestDoAppliesReducerAndDatabaseLabels(t *testing.T) {
+	var gotReducer, gotDatabase string
+	Do(context.Background(), "my_reducer", "my_db", func(ctx context.Context) {
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			switch key {
+			case "reducer":
+				gotReducer = value
+			case "database":
+				gotDatabase = value
+			}
+			return true
+		})
+	})
+	if gotReducer != "my_reducer" {
+		t.Fatalf("reducer label = %q, want my_reducer", gotReducer)
+	}
+	if gotDatabase != "my_db" {
+		t.Fatalf("database label = %q, want my_db", gotDatabase)
+	}
+}
+
+func TestHandlerServesIndex(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,123 @@
+// Package promote drives a module through a sequence of named
+// environments (typically dev, staging, prod) from a single declarative
+// manifest, so a release doesn't depend on someone remembering the right
+// -server and -token-file flags for each stage. It builds entirely on
+// internal/publish and internal/codegen; it does not talk to the
+// network or the filesystem beyond what a Manifest names.
+package promote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Environment is one named promotion target: a server to publish to and
+// the database name to publish as there.
+type Environment struct {
+	// Name identifies the environment within a Manifest's Order, e.g.
+	// "dev", "staging", "prod".
+	Name string `json:"name"`
+	// Server is the SpacetimeDB server's HTTP base URL for this
+	// environment.
+	Server string `json:"server"`
+	// Database is the module/database name to publish as.
+	Database string `json:"database"`
+	// TokenFile is the login token path for this environment. Empty
+	// means identity.TokenPath()'s default.
+	TokenFile string `json:"token_file,omitempty"`
+}
+
+// Manifest describes how to promote one compiled module through a chain
+// of environments, plus the client codegen refresh to run once it has
+// landed.
+type Manifest struct {
+	// Wasm is the path to the compiled module, resolved relative to the
+	// manifest file's directory.
+	Wasm string `json:"wasm"`
+	// Environments are the promotion targets, keyed by Environment.Name.
+	Environments []Environment `json:"environments"`
+	// Order lists environment names in the sequence to promote through,
+	// e.g. ["dev", "staging", "prod"]. Promote stops at the first
+	// failure or declined approval, so an environment never publishes
+	// ahead of one earlier in Order.
+	Order []string `json:"order"`
+	// RequiredSchemaVersion, if set, must match codegen.HashModule(wasm
+	// bytes) or LoadManifest's caller-visible publish step refuses to
+	// run — a guard against promoting a module that was rebuilt after
+	// the manifest was reviewed.
+	RequiredSchemaVersion string `json:"required_schema_version,omitempty"`
+	// PrePublish and PostPublish are shell commands run (via
+	// os/exec, one at a time, in order) before and after publishing to
+	// each environment. A hook failing aborts the promotion.
+	PrePublish  []string `json:"pre_publish,omitempty"`
+	PostPublish []string `json:"post_publish,omitempty"`
+
+	// CodegenOut, CodegenPackage, EventTables, and SchemaVersionOut
+	// mirror the publish command's own flags, run once after the last
+	// environment in Order publishes successfully.
+	CodegenOut       string   `json:"codegen_out,omitempty"`
+	CodegenPackage   string   `json:"codegen_package,omitempty"`
+	EventTables      []string `json:"event_tables,omitempty"`
+	SchemaVersionOut string   `json:"schema_version_out,omitempty"`
+}
+
+// LoadManifest reads and parses a Manifest from path.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("promote: read manifest %s: %w", path, err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("promote: parse manifest %s: %w", path, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("promote: %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Validate checks that m's Order refers only to declared Environments
+// and that every environment has the fields Promote needs.
+func (m *Manifest) Validate() error {
+	if m.Wasm == "" {
+		return fmt.Errorf("wasm path is required")
+	}
+	if len(m.Order) == 0 {
+		return fmt.Errorf("order must list at least one environment")
+	}
+	byName := make(map[string]Environment, len(m.Environments))
+	for _, env := range m.Environments {
+		if env.Name == "" {
+			return fmt.Errorf("environment with empty name")
+		}
+		if _, dup := byName[env.Name]; dup {
+			return fmt.Errorf("duplicate environment %q", env.Name)
+		}
+		byName[env.Name] = env
+	}
+	for _, name := range m.Order {
+		env, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("order references undeclared environment %q", name)
+		}
+		if env.Server == "" {
+			return fmt.Errorf("environment %q: server is required", name)
+		}
+		if env.Database == "" {
+			return fmt.Errorf("environment %q: database is required", name)
+		}
+	}
+	return nil
+}
+
+// Environment looks up a declared environment by name.
+func (m *Manifest) Environment(name string) (Environment, bool) {
+	for _, env := range m.Environments {
+		if env.Name == name {
+			return env, true
+		}
+	}
+	return Environment{}, false
+}
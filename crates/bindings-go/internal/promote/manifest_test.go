@@ -0,0 +1,79 @@
+package promote
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeManifest(t *testing.T, m Manifest) string {
+	t.Helper()
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal manifest: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	return path
+}
+
+func TestLoadManifestParsesValidManifest(t *testing.T) {
+	path := writeManifest(t, Manifest{
+		Wasm: "module.wasm",
+		Environments: []Environment{
+			{Name: "dev", Server: "http://dev", Database: "app"},
+			{Name: "prod", Server: "http://prod", Database: "app"},
+		},
+		Order: []string{"dev", "prod"},
+	})
+
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if len(m.Order) != 2 || m.Order[0] != "dev" || m.Order[1] != "prod" {
+		t.Fatalf("Order = %v, want [dev prod]", m.Order)
+	}
+}
+
+func TestLoadManifestRejectsOrderReferencingUndeclaredEnvironment(t *testing.T) {
+	path := writeManifest(t, Manifest{
+		Wasm:         "module.wasm",
+		Environments: []Environment{{Name: "dev", Server: "http://dev", Database: "app"}},
+		Order:        []string{"dev", "staging"},
+	})
+
+	if _, err := LoadManifest(path); err == nil {
+		t.Fatal("LoadManifest: want error for order referencing undeclared environment")
+	}
+}
+
+func TestLoadManifestRejectsDuplicateEnvironmentNames(t *testing.T) {
+	path := writeManifest(t, Manifest{
+		Wasm: "module.wasm",
+		Environments: []Environment{
+			{Name: "dev", Server: "http://a", Database: "app"},
+			{Name: "dev", Server: "http://b", Database: "app"},
+		},
+		Order: []string{"dev"},
+	})
+
+	if _, err := LoadManifest(path); err == nil {
+		t.Fatal("LoadManifest: want error for duplicate environment name")
+	}
+}
+
+func TestManifestEnvironmentLooksUpByName(t *testing.T) {
+	m := &Manifest{Environments: []Environment{{Name: "staging", Server: "http://s", Database: "app"}}}
+
+	env, ok := m.Environment("staging")
+	if !ok || env.Server != "http://s" {
+		t.Fatalf("Environment(%q) = %+v, %v", "staging", env, ok)
+	}
+	if _, ok := m.Environment("missing"); ok {
+		t.Fatal("Environment(missing): want ok=false")
+	}
+}
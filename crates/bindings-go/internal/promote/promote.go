@@ -0,0 +1,178 @@
+package promote
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/codegen"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/publish"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/wasm"
+)
+
+// ApprovalFunc is asked to approve publishing to env before Promote does
+// so. Returning false (with a nil error) stops the promotion at env
+// without treating it as a failure; returning an error aborts the same
+// way a publish failure would. A nil ApprovalFunc approves everything.
+type ApprovalFunc func(env Environment, hash string) (bool, error)
+
+// Result records the outcome of promoting to a single environment.
+type Result struct {
+	Environment Environment
+	Hash        string
+	Published   bool
+	// Approved is false only when an ApprovalFunc declined this
+	// environment; Published is also false in that case.
+	Approved bool
+}
+
+// Promoter drives a Manifest through its Order of environments.
+type Promoter struct {
+	// HTTP is the client used for each environment's publish.Client. A
+	// nil HTTP lets publish.Client fall back to http.DefaultClient.
+	HTTP publish.HTTPDoer
+	// Approve gates each environment's publish; see ApprovalFunc. Nil
+	// approves unconditionally.
+	Approve ApprovalFunc
+	// RunHook executes one pre/post-publish hook command. Nil uses
+	// runShellHook, which runs it through "sh -c".
+	RunHook func(cmd string) error
+}
+
+// Promote publishes wasmBytes to every environment in m.Order, in
+// order, stopping at the first hook failure, publish error, or declined
+// approval. It returns the per-environment results completed so far
+// even when it returns an error, so a caller can report how far the
+// promotion got.
+func (p *Promoter) Promote(ctx context.Context, m *Manifest, wasmBytes []byte) ([]Result, error) {
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("promote: %w", err)
+	}
+
+	hash := codegen.HashModule(wasmBytes)
+	if m.RequiredSchemaVersion != "" && m.RequiredSchemaVersion != hash {
+		return nil, fmt.Errorf("promote: module hash %s does not match manifest's required_schema_version %s", hash, m.RequiredSchemaVersion)
+	}
+	if err := wasm.ValidateABI(ctx, wasmBytes); err != nil {
+		return nil, fmt.Errorf("promote: %w", err)
+	}
+	info, err := wasm.AnalyzeModule(ctx, wasmBytes)
+	if err != nil {
+		return nil, fmt.Errorf("promote: %w", err)
+	}
+	if err := wasm.VerifySchemaHash(info, hash); err != nil {
+		return nil, fmt.Errorf("promote: %w", err)
+	}
+
+	runHook := p.RunHook
+	if runHook == nil {
+		runHook = runShellHook
+	}
+
+	var results []Result
+	for _, name := range m.Order {
+		env, _ := m.Environment(name)
+
+		if p.Approve != nil {
+			ok, err := p.Approve(env, hash)
+			if err != nil {
+				return results, fmt.Errorf("promote: approve %s: %w", env.Name, err)
+			}
+			if !ok {
+				results = append(results, Result{Environment: env, Hash: hash, Approved: false})
+				return results, fmt.Errorf("promote: promotion to %s was not approved", env.Name)
+			}
+		}
+
+		for _, cmd := range m.PrePublish {
+			if err := runHook(cmd); err != nil {
+				return results, fmt.Errorf("promote: pre-publish hook for %s: %w", env.Name, err)
+			}
+		}
+
+		token, err := loadToken(env)
+		if err != nil {
+			return results, fmt.Errorf("promote: %s: %w", env.Name, err)
+		}
+		client := &publish.Client{BaseURL: env.Server, Token: token, HTTP: p.HTTP}
+		if _, err := client.Publish(env.Database, wasmBytes); err != nil {
+			return results, fmt.Errorf("promote: publish to %s: %w", env.Name, err)
+		}
+
+		for _, cmd := range m.PostPublish {
+			if err := runHook(cmd); err != nil {
+				return results, fmt.Errorf("promote: post-publish hook for %s: %w", env.Name, err)
+			}
+		}
+
+		results = append(results, Result{Environment: env, Hash: hash, Published: true, Approved: true})
+	}
+
+	if err := refreshCodegen(m, hash); err != nil {
+		return results, fmt.Errorf("promote: %w", err)
+	}
+	return results, nil
+}
+
+// loadToken resolves env's login token, falling back to
+// identity.TokenPath() when TokenFile is unset.
+func loadToken(env Environment) (string, error) {
+	path := env.TokenFile
+	if path == "" {
+		var err error
+		path, err = identity.TokenPath()
+		if err != nil {
+			return "", err
+		}
+	}
+	return identity.LoadToken(path)
+}
+
+// refreshCodegen regenerates the client-side event subscriber and
+// schema version files a Manifest names, once the module has finished
+// promoting through every environment.
+func refreshCodegen(m *Manifest, hash string) error {
+	if m.CodegenOut != "" {
+		pkg := m.CodegenPackage
+		if pkg == "" {
+			pkg = "module"
+		}
+		tables := codegen.AutoEventTables(m.EventTables)
+		src, err := codegen.GenerateEventEmitters(pkg, tables)
+		if err != nil {
+			return fmt.Errorf("codegen: %w", err)
+		}
+		if err := os.WriteFile(m.CodegenOut, src, 0o644); err != nil {
+			return fmt.Errorf("codegen: write %s: %w", m.CodegenOut, err)
+		}
+	}
+	if m.SchemaVersionOut != "" {
+		pkg := m.CodegenPackage
+		if pkg == "" {
+			pkg = "module"
+		}
+		src, err := codegen.GenerateSchemaVersion(pkg, hash)
+		if err != nil {
+			return fmt.Errorf("codegen: %w", err)
+		}
+		if err := os.WriteFile(m.SchemaVersionOut, src, 0o644); err != nil {
+			return fmt.Errorf("codegen: write %s: %w", m.SchemaVersionOut, err)
+		}
+	}
+	return nil
+}
+
+// runShellHook runs cmd through "sh -c", with the hook's stdout/stderr
+// wired to the process's own, so a promotion's build/test/notify hooks
+// behave like they would run from a terminal.
+func runShellHook(cmd string) error {
+	c := exec.Command("sh", "-c", cmd)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("hook %q: %w", cmd, err)
+	}
+	return nil
+}
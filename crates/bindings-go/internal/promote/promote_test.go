@@ -0,0 +1,189 @@
+package promote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var errFailingHook = errors.New("hook failed")
+
+// validModule is a minimal wasm binary exporting empty spacetime_alloc
+// and __call_reducer__ functions — just enough to satisfy
+// wasm.ValidateABI without needing a real compiled module.
+var validModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x04, 0x01, 0x60, 0x00, 0x00, 0x03, 0x03,
+	0x02, 0x00, 0x00, 0x07, 0x26, 0x02, 0x0f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x00, 0x00, 0x10, 0x5f, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x5f,
+	0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x5f, 0x5f, 0x00, 0x01, 0x0a, 0x07, 0x02, 0x02, 0x00,
+	0x0b, 0x02, 0x00, 0x0b,
+}
+
+// moduleWithCorruptSchemaHash is validModule plus a spacetimedb_schema_hash
+// custom section whose value does not (and cannot) match
+// codegen.HashModule of these exact bytes, simulating a build whose
+// embedded hash was stamped in before some later corruption or edit.
+var moduleWithCorruptSchemaHash = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x04, 0x01, 0x60, 0x00, 0x00, 0x03, 0x03,
+	0x02, 0x00, 0x00, 0x07, 0x26, 0x02, 0x0f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x00, 0x00, 0x10, 0x5f, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x5f,
+	0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x5f, 0x5f, 0x00, 0x01, 0x0a, 0x07, 0x02, 0x02, 0x00,
+	0x0b, 0x02, 0x00, 0x0b, 0x00, 0x20, 0x17, 0x73, 0x70, 0x61, 0x63, 0x65, 0x74, 0x69, 0x6d, 0x65,
+	0x64, 0x62, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x63, 0x61,
+	0x66, 0x65, 0x66, 0x30, 0x30, 0x64,
+}
+
+func newPublishServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"identity":"c200000000000000000000000000000000000000000000000000000000000000"}`))
+	}))
+}
+
+func tokenFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("test-token\n"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+	return path
+}
+
+func TestPromotePublishesEveryEnvironmentInOrder(t *testing.T) {
+	srv := newPublishServer(t)
+	defer srv.Close()
+	token := tokenFile(t)
+
+	m := &Manifest{
+		Wasm: "module.wasm",
+		Environments: []Environment{
+			{Name: "dev", Server: srv.URL, Database: "app", TokenFile: token},
+			{Name: "prod", Server: srv.URL, Database: "app", TokenFile: token},
+		},
+		Order: []string{"dev", "prod"},
+	}
+
+	p := &Promoter{}
+	results, err := p.Promote(context.Background(), m, validModule)
+	if err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for i, name := range []string{"dev", "prod"} {
+		if results[i].Environment.Name != name || !results[i].Published {
+			t.Fatalf("results[%d] = %+v, want published %s", i, results[i], name)
+		}
+	}
+}
+
+func TestPromoteStopsAtDeclinedApproval(t *testing.T) {
+	srv := newPublishServer(t)
+	defer srv.Close()
+	token := tokenFile(t)
+
+	m := &Manifest{
+		Wasm: "module.wasm",
+		Environments: []Environment{
+			{Name: "dev", Server: srv.URL, Database: "app", TokenFile: token},
+			{Name: "prod", Server: srv.URL, Database: "app", TokenFile: token},
+		},
+		Order: []string{"dev", "prod"},
+	}
+
+	p := &Promoter{Approve: func(env Environment, hash string) (bool, error) {
+		return env.Name != "prod", nil
+	}}
+	results, err := p.Promote(context.Background(), m, validModule)
+	if err == nil {
+		t.Fatal("Promote: want error when an environment's approval is declined")
+	}
+	if len(results) != 2 || results[1].Published {
+		t.Fatalf("results = %+v, want dev published and prod not", results)
+	}
+}
+
+func TestPromoteRejectsSchemaVersionMismatch(t *testing.T) {
+	m := &Manifest{
+		Wasm:                  "module.wasm",
+		Environments:          []Environment{{Name: "dev", Server: "http://unused", Database: "app"}},
+		Order:                 []string{"dev"},
+		RequiredSchemaVersion: "not-the-real-hash",
+	}
+
+	p := &Promoter{}
+	if _, err := p.Promote(context.Background(), m, validModule); err == nil {
+		t.Fatal("Promote: want error for mismatched required_schema_version")
+	}
+}
+
+func TestPromoteRejectsCorruptEmbeddedSchemaHash(t *testing.T) {
+	m := &Manifest{
+		Wasm:         "module.wasm",
+		Environments: []Environment{{Name: "dev", Server: "http://unused", Database: "app"}},
+		Order:        []string{"dev"},
+	}
+
+	p := &Promoter{}
+	if _, err := p.Promote(context.Background(), m, moduleWithCorruptSchemaHash); err == nil {
+		t.Fatal("Promote: want error for a module whose embedded schema hash doesn't match its own bytes")
+	}
+}
+
+func TestPromoteRunsHooksAroundEachEnvironment(t *testing.T) {
+	srv := newPublishServer(t)
+	defer srv.Close()
+	token := tokenFile(t)
+
+	m := &Manifest{
+		Wasm:         "module.wasm",
+		Environments: []Environment{{Name: "dev", Server: srv.URL, Database: "app", TokenFile: token}},
+		Order:        []string{"dev"},
+		PrePublish:   []string{"pre"},
+		PostPublish:  []string{"post"},
+	}
+
+	var ran []string
+	p := &Promoter{RunHook: func(cmd string) error {
+		ran = append(ran, cmd)
+		return nil
+	}}
+	if _, err := p.Promote(context.Background(), m, validModule); err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	if len(ran) != 2 || ran[0] != "pre" || ran[1] != "post" {
+		t.Fatalf("hooks ran = %v, want [pre post]", ran)
+	}
+}
+
+func TestPromoteAbortsOnPrePublishHookFailure(t *testing.T) {
+	srv := newPublishServer(t)
+	defer srv.Close()
+	calledPublish := false
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledPublish = true
+		w.Write([]byte(`{"identity":"c200000000000000000000000000000000000000000000000000000000000000"}`))
+	})
+	token := tokenFile(t)
+
+	m := &Manifest{
+		Wasm:         "module.wasm",
+		Environments: []Environment{{Name: "dev", Server: srv.URL, Database: "app", TokenFile: token}},
+		Order:        []string{"dev"},
+		PrePublish:   []string{"fail"},
+	}
+
+	p := &Promoter{RunHook: func(cmd string) error { return errFailingHook }}
+	if _, err := p.Promote(context.Background(), m, validModule); err == nil {
+		t.Fatal("Promote: want error when a pre-publish hook fails")
+	}
+	if calledPublish {
+		t.Fatal("Promote: publish should not run after a failed pre-publish hook")
+	}
+}
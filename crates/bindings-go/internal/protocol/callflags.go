@@ -0,0 +1,58 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+)
+
+// CallFlags carries the flags byte a real CallReducer message sends
+// alongside a reducer's name and arguments, controlling protocol-level
+// behavior of that one call rather than anything about the reducer
+// itself.
+type CallFlags uint8
+
+const (
+	// CallFlagNoSuccessNotify suppresses the TransactionUpdate a server
+	// would otherwise broadcast for a successful call, for callers (e.g.
+	// fire-and-forget telemetry reducers) that don't need confirmation
+	// and would rather not pay for or process one.
+	CallFlagNoSuccessNotify CallFlags = 1 << iota
+)
+
+// NoSuccessNotify reports whether f has CallFlagNoSuccessNotify set.
+func (f CallFlags) NoSuccessNotify() bool {
+	return f&CallFlagNoSuccessNotify != 0
+}
+
+func (f CallFlags) String() string {
+	if f == 0 {
+		return "none"
+	}
+	s := ""
+	if f.NoSuccessNotify() {
+		s += "|NoSuccessNotify"
+	}
+	if rest := f &^ CallFlagNoSuccessNotify; rest != 0 {
+		s += fmt.Sprintf("|unknown(%#x)", uint8(rest))
+	}
+	return s[1:]
+}
+
+// callFlagsKey is the context key the multi-database host uses to thread
+// a call's flags alongside its caller identity (see internal/identity's
+// callerKey), so both sides of a reducer invocation agree on one
+// representation instead of each defining their own key type.
+type callFlagsKey struct{}
+
+// WithCallFlags returns a copy of ctx carrying flags for the current
+// reducer call.
+func WithCallFlags(ctx context.Context, flags CallFlags) context.Context {
+	return context.WithValue(ctx, callFlagsKey{}, flags)
+}
+
+// CallFlagsFromContext extracts the flags WithCallFlags attached to ctx,
+// if any.
+func CallFlagsFromContext(ctx context.Context) (CallFlags, bool) {
+	flags, ok := ctx.Value(callFlagsKey{}).(CallFlags)
+	return flags, ok
+}
@@ -0,0 +1,202 @@
+// Package protocol decodes versioned SpacetimeDB client/server wire
+// messages, tracking which message kinds each server release is known to
+// send so the Go SDK can degrade gracefully — reporting an unsupported
+// protocol feature explicitly, rather than misdecoding it — when it
+// connects to a server older or newer than the ones its compatibility
+// matrix covers.
+package protocol
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// ServerVersion identifies a SpacetimeDB server release line for the
+// purposes of wire compatibility. It is the value a real connected
+// client would read off the server's initial handshake; today it is
+// supplied by the caller (e.g. a test driving fixtures, or a future
+// handshake reader).
+type ServerVersion string
+
+// Known server versions in the compatibility matrix. A version not
+// listed here is unconditionally unsupported (see DecodeEnvelope).
+const (
+	V1_0 ServerVersion = "1.0"
+	V1_1 ServerVersion = "1.1"
+	V1_2 ServerVersion = "1.2"
+)
+
+// MessageKind identifies the shape of a wire message's payload.
+type MessageKind int
+
+const (
+	KindSubscriptionUpdate MessageKind = iota
+	KindIdentityToken
+	KindTransactionUpdate
+	KindCallReducer
+	KindUnsubscribe
+)
+
+func (k MessageKind) String() string {
+	switch k {
+	case KindSubscriptionUpdate:
+		return "SubscriptionUpdate"
+	case KindIdentityToken:
+		return "IdentityToken"
+	case KindTransactionUpdate:
+		return "TransactionUpdate"
+	case KindCallReducer:
+		return "CallReducer"
+	case KindUnsubscribe:
+		return "Unsubscribe"
+	default:
+		return fmt.Sprintf("MessageKind(%d)", int(k))
+	}
+}
+
+// supportedKinds lists, per server version, which message kinds that
+// version is known to send. TransactionUpdate was introduced in 1.1;
+// a 1.0 server never sends one, so decoding it against V1_0 is reported
+// as an unsupported feature rather than attempted.
+var supportedKinds = map[ServerVersion]map[MessageKind]bool{
+	V1_0: {
+		KindSubscriptionUpdate: true,
+		KindIdentityToken:      true,
+		KindCallReducer:        true,
+		KindUnsubscribe:        true,
+	},
+	V1_1: {
+		KindSubscriptionUpdate: true,
+		KindIdentityToken:      true,
+		KindTransactionUpdate:  true,
+		KindCallReducer:        true,
+		KindUnsubscribe:        true,
+	},
+	V1_2: {
+		KindSubscriptionUpdate: true,
+		KindIdentityToken:      true,
+		KindTransactionUpdate:  true,
+		KindCallReducer:        true,
+		KindUnsubscribe:        true,
+	},
+}
+
+// ErrUnsupportedFeature is returned by DecodeEnvelope when kind is not
+// one that version is known to send, or version itself is outside the
+// compatibility matrix. It carries enough detail for a caller to log or
+// surface to the user why a message was dropped instead of decoded.
+type ErrUnsupportedFeature struct {
+	Version ServerVersion
+	Kind    MessageKind
+}
+
+func (e *ErrUnsupportedFeature) Error() string {
+	return fmt.Sprintf("protocol: server version %q does not support %s", e.Version, e.Kind)
+}
+
+// SubscriptionUpdate is the payload of a subscription update message:
+// the rows a table gained and lost, still BSATN-encoded (see
+// pkg/spacetimedb.Conn.Dispatch, which consumes exactly this shape).
+type SubscriptionUpdate struct {
+	Table   string
+	Inserts [][]byte
+	Deletes [][]byte
+}
+
+// IdentityToken is the payload of the identity/token message a server
+// sends on connect.
+type IdentityToken struct {
+	Identity []byte
+	Token    string
+}
+
+// ReducerStatus is the outcome of a reducer call, as reported in a
+// TransactionUpdate.
+type ReducerStatus uint8
+
+const (
+	ReducerStatusCommitted ReducerStatus = iota
+	ReducerStatusFailed
+	ReducerStatusOutOfEnergy
+)
+
+func (s ReducerStatus) String() string {
+	switch s {
+	case ReducerStatusCommitted:
+		return "Committed"
+	case ReducerStatusFailed:
+		return "Failed"
+	case ReducerStatusOutOfEnergy:
+		return "OutOfEnergy"
+	default:
+		return fmt.Sprintf("ReducerStatus(%d)", uint8(s))
+	}
+}
+
+// EnergyQuanta is an amount of energy, in the same units the server's
+// per-reducer energy budget is denominated in.
+type EnergyQuanta uint64
+
+// TransactionUpdate is the payload reporting a reducer call's outcome,
+// sent to clients subscribed to its effects. It was added in server
+// version 1.1 (see supportedKinds). EnergyUsed and HostExecutionDuration
+// let a client build a cost dashboard (energy spent, wall time in the
+// host) per reducer call without parsing them back out of Status or a
+// log line. RequestID echoes the CallReducerRequest that produced this
+// update, letting a caller that is waiting on a specific call's outcome
+// pick its update out of the stream of ones broadcast for every caller;
+// it is zero for updates not caused by a CallReducerRequest this
+// connection sent (e.g. another client's reducer call broadcast to
+// subscribers).
+type TransactionUpdate struct {
+	RequestID             uint32
+	ReducerName           string
+	Status                ReducerStatus
+	EnergyUsed            EnergyQuanta
+	HostExecutionDuration time.Duration
+}
+
+// CallReducerRequest is the payload of a client's request to invoke a
+// reducer. Args is the BSATN-encoded argument tuple, opaque to this
+// package the same way pkg/spacetimedb.ReducerCall.Args is. RequestID is
+// chosen by the caller and echoed back on the TransactionUpdate
+// reporting this call's outcome, so a client sharing one connection
+// across concurrent callers can tell which update answers which call.
+type CallReducerRequest struct {
+	RequestID   uint32
+	ReducerName string
+	Args        []byte
+}
+
+// UnsubscribeRequest is the payload of a client's request to stop
+// receiving updates for every query in QueryStrings. It must list the
+// exact query strings an earlier subscribe request (see
+// pkg/spacetimedb.SubscribeRequest) asked for, the same way the Rust and
+// C# SDKs identify a subscription by its query text rather than a
+// server-assigned handle.
+type UnsubscribeRequest struct {
+	QueryStrings []string
+}
+
+// DecodeEnvelope decodes payload as kind, as sent by a server on
+// version, into out (a pointer to the matching message type, e.g.
+// *SubscriptionUpdate for KindSubscriptionUpdate). It returns
+// *ErrUnsupportedFeature without attempting to decode if version is
+// outside the compatibility matrix or does not support kind, so a
+// caller can distinguish "this server sent us something newer than we
+// understand" from an actual malformed payload.
+func DecodeEnvelope(version ServerVersion, kind MessageKind, payload []byte, out any) error {
+	kinds, ok := supportedKinds[version]
+	if !ok {
+		return &ErrUnsupportedFeature{Version: version, Kind: kind}
+	}
+	if !kinds[kind] {
+		return &ErrUnsupportedFeature{Version: version, Kind: kind}
+	}
+	if err := bsatn.Unmarshal(payload, out); err != nil {
+		return fmt.Errorf("protocol: decode %s payload for version %q: %w", kind, version, err)
+	}
+	return nil
+}
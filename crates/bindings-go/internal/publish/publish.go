@@ -0,0 +1,76 @@
+// Package publish talks to a SpacetimeDB server's HTTP publish endpoint
+// directly, so Go-centric teams can push a module without shelling out
+// to the Rust `spacetime` CLI.
+package publish
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+)
+
+// HTTPDoer is the subset of *http.Client that Client needs. Both
+// *http.Client and *httpclient.Client (this tree's tuned,
+// connection-pooled wrapper — see internal/httpclient) satisfy it, so a
+// caller publishing in bulk can plug in the latter instead of paying for
+// a fresh connection per request.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client publishes wasm modules to a running SpacetimeDB server.
+type Client struct {
+	// BaseURL is the server's HTTP root, e.g. "https://testnet.spacetimedb.com".
+	BaseURL string
+	// Token authenticates the request; see identity.LoadToken.
+	Token string
+	// HTTP is the HTTP client to use. If nil, http.DefaultClient is used.
+	HTTP HTTPDoer
+}
+
+// publishResponse mirrors the subset of the server's JSON response this
+// client cares about.
+type publishResponse struct {
+	Identity string `json:"identity"`
+}
+
+// Publish uploads wasmBytes as the module named name, returning the
+// identity of the resulting (or updated) database.
+func (c *Client) Publish(name string, wasmBytes []byte) (identity.Identity, error) {
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	url := fmt.Sprintf("%s/v1/database/%s/publish", c.BaseURL, name)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(wasmBytes))
+	if err != nil {
+		return identity.Identity{}, fmt.Errorf("publish: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/wasm")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return identity.Identity{}, fmt.Errorf("publish: request %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return identity.Identity{}, fmt.Errorf("publish: read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return identity.Identity{}, fmt.Errorf("publish: server returned %s: %s", resp.Status, body)
+	}
+
+	var parsed publishResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return identity.Identity{}, fmt.Errorf("publish: parse response: %w", err)
+	}
+	return identity.ParseIdentity(parsed.Identity)
+}
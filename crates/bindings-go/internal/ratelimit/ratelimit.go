@@ -0,0 +1,199 @@
+// Package ratelimit implements a host-side policy engine that limits how
+// often, and how much simulated "energy", each caller identity may spend
+// invoking reducers. It lets a module under test be driven into the same
+// throttled outcomes a production SpacetimeDB deployment would impose —
+// call-rate limiting and energy exhaustion — without standing one up.
+package ratelimit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+)
+
+// Policy configures one caller identity's rate-limit buckets, using the
+// standard token-bucket algorithm: each bucket refills continuously at
+// its Rate, up to its Burst/Budget ceiling, and every call withdraws
+// from it. A zero Rate disables that bucket's enforcement entirely.
+type Policy struct {
+	// CallsPerSecond and CallBurst bound how often a caller may invoke a
+	// reducer at all, independent of which reducer or its cost.
+	CallsPerSecond float64
+	CallBurst      float64
+
+	// EnergyPerSecond and EnergyBudget bound how much simulated energy a
+	// caller may spend across all reducer calls; each Allow call reports
+	// how much energy that particular call costs.
+	EnergyPerSecond float64
+	EnergyBudget    float64
+}
+
+// ThrottledError is returned by Limiter.Allow when a call is rejected.
+// Reason identifies which bucket was exhausted; RetryAfter estimates how
+// long the caller must wait before that bucket has enough tokens for
+// this same call.
+type ThrottledError struct {
+	Identity   identity.Identity
+	Reducer    string
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("ratelimit: identity %s throttled calling %q (%s), retry after %s", e.Identity, e.Reducer, e.Reason, e.RetryAfter)
+}
+
+const (
+	// ReasonCallRate is a ThrottledError.Reason for exhausting the call-rate bucket.
+	ReasonCallRate = "call rate exceeded"
+	// ReasonEnergy is a ThrottledError.Reason for exhausting the energy bucket.
+	ReasonEnergy = "energy exhausted"
+)
+
+// bucket is a token bucket holding at most capacity tokens, refilling at
+// rate tokens/second. A zero rate never refills and never denies —
+// Policy uses that to mean "unenforced".
+type bucket struct {
+	rate     float64
+	capacity float64
+	level    float64
+	updated  time.Time
+}
+
+func newBucket(rate, capacity float64, now time.Time) bucket {
+	return bucket{rate: rate, capacity: capacity, level: capacity, updated: now}
+}
+
+// refill advances b to now, the first half of take split out so Allow
+// can refill both of a caller's buckets before checking either of them
+// (see take's doc comment on why withdrawal itself is a separate step).
+func (b *bucket) refill(now time.Time) {
+	if b.rate <= 0 {
+		return
+	}
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.level += elapsed.Seconds() * b.rate
+		if b.level > b.capacity {
+			b.level = b.capacity
+		}
+		b.updated = now
+	}
+}
+
+// check reports whether b currently holds at least cost tokens, and if
+// not, how long the caller must wait until it would — without
+// withdrawing anything. Call refill first to bring b's level current.
+func (b *bucket) check(cost float64) (bool, time.Duration) {
+	if b.rate <= 0 || b.level >= cost {
+		return true, 0
+	}
+	missing := cost - b.level
+	return false, time.Duration(missing / b.rate * float64(time.Second))
+}
+
+// withdraw removes cost tokens from b. Only call it after check has
+// confirmed b can cover cost.
+func (b *bucket) withdraw(cost float64) {
+	if b.rate > 0 {
+		b.level -= cost
+	}
+}
+
+// take refills b to now, then withdraws cost tokens if it can cover
+// them. It reports whether the withdrawal succeeded, and if not, how
+// long the caller must wait until it would. Allow does not use this
+// directly for its two buckets — see withdraw's doc comment — because
+// withdrawing from one bucket before checking the other would make a
+// rejection on the second bucket partially withdraw from the first.
+func (b *bucket) take(cost float64, now time.Time) (bool, time.Duration) {
+	b.refill(now)
+	if ok, retry := b.check(cost); !ok {
+		return false, retry
+	}
+	b.withdraw(cost)
+	return true, 0
+}
+
+// callerState holds one identity's live buckets, seeded from whichever
+// Policy applied when it was first seen.
+type callerState struct {
+	calls  bucket
+	energy bucket
+}
+
+// Limiter enforces a Policy per caller identity. The zero Limiter is not
+// usable; construct one with NewLimiter.
+type Limiter struct {
+	mu       sync.Mutex
+	def      Policy
+	policies map[identity.Identity]Policy
+	state    map[identity.Identity]*callerState
+}
+
+// NewLimiter returns a Limiter that applies def to any identity without
+// an override installed via SetPolicy.
+func NewLimiter(def Policy) *Limiter {
+	return &Limiter{
+		def:      def,
+		policies: make(map[identity.Identity]Policy),
+		state:    make(map[identity.Identity]*callerState),
+	}
+}
+
+// SetPolicy overrides the policy applied to id, replacing its live
+// buckets so the new limits take effect immediately (starting full).
+func (l *Limiter) SetPolicy(id identity.Identity, p Policy) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.policies[id] = p
+	delete(l.state, id)
+}
+
+// policyFor returns the Policy configured for id, falling back to l.def.
+func (l *Limiter) policyFor(id identity.Identity) Policy {
+	if p, ok := l.policies[id]; ok {
+		return p
+	}
+	return l.def
+}
+
+// Allow withdraws one call and energyCost energy from id's buckets,
+// returning a *ThrottledError if either bucket cannot cover the
+// withdrawal. It never partially withdraws: both buckets are checked
+// before either is withdrawn from, so a rejection on one leaves the
+// other untouched — including a call bucket rejection leaving the
+// energy bucket untouched, and an energy bucket rejection leaving the
+// call bucket's token unspent instead of burning it on a call that did
+// not go through.
+func (l *Limiter) Allow(id identity.Identity, reducer string, energyCost float64) error {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	st, ok := l.state[id]
+	if !ok {
+		p := l.policyFor(id)
+		st = &callerState{
+			calls:  newBucket(p.CallsPerSecond, p.CallBurst, now),
+			energy: newBucket(p.EnergyPerSecond, p.EnergyBudget, now),
+		}
+		l.state[id] = st
+	}
+
+	st.calls.refill(now)
+	st.energy.refill(now)
+
+	if ok, retry := st.calls.check(1); !ok {
+		return &ThrottledError{Identity: id, Reducer: reducer, Reason: ReasonCallRate, RetryAfter: retry}
+	}
+	if ok, retry := st.energy.check(energyCost); !ok {
+		return &ThrottledError{Identity: id, Reducer: reducer, Reason: ReasonEnergy, RetryAfter: retry}
+	}
+
+	st.calls.withdraw(1)
+	st.energy.withdraw(energyCost)
+	return nil
+}
@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+)
+
+func TestAllowWithinBurstSucceeds(t *testing.T) {
+	l := NewLimiter(Policy{CallsPerSecond: 1, CallBurst: 3})
+	id := identity.Identity{1}
+	for i := 0; i < 3; i++ {
+		if err := l.Allow(id, "tick", 0); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+}
+
+func TestAllowRejectsOnceCallBurstExhausted(t *testing.T) {
+	l := NewLimiter(Policy{CallsPerSecond: 1, CallBurst: 1})
+	id := identity.Identity{1}
+	if err := l.Allow(id, "tick", 0); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	err := l.Allow(id, "tick", 0)
+	if err == nil {
+		t.Fatal("second immediate call: want throttled error")
+	}
+	te, ok := err.(*ThrottledError)
+	if !ok {
+		t.Fatalf("err = %T, want *ThrottledError", err)
+	}
+	if te.Reason != ReasonCallRate {
+		t.Fatalf("Reason = %q, want %q", te.Reason, ReasonCallRate)
+	}
+	if te.RetryAfter <= 0 {
+		t.Fatalf("RetryAfter = %v, want > 0", te.RetryAfter)
+	}
+}
+
+func TestAllowRefillsOverTime(t *testing.T) {
+	l := NewLimiter(Policy{CallsPerSecond: 1000, CallBurst: 1})
+	id := identity.Identity{1}
+	if err := l.Allow(id, "tick", 0); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := l.Allow(id, "tick", 0); err != nil {
+		t.Fatalf("call after refill: %v", err)
+	}
+}
+
+func TestAllowRejectsOnceEnergyBudgetExhausted(t *testing.T) {
+	l := NewLimiter(Policy{EnergyPerSecond: 1, EnergyBudget: 10})
+	id := identity.Identity{1}
+	if err := l.Allow(id, "expensive", 8); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	err := l.Allow(id, "expensive", 8)
+	if err == nil {
+		t.Fatal("second call: want throttled error for energy")
+	}
+	te, ok := err.(*ThrottledError)
+	if !ok || te.Reason != ReasonEnergy {
+		t.Fatalf("err = %v, want *ThrottledError with reason %q", err, ReasonEnergy)
+	}
+}
+
+func TestAllowCallRateRejectionLeavesEnergyUntouched(t *testing.T) {
+	l := NewLimiter(Policy{CallsPerSecond: 1, CallBurst: 1, EnergyPerSecond: 1, EnergyBudget: 100})
+	id := identity.Identity{1}
+	if err := l.Allow(id, "a", 1); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	// The call bucket is now empty, so this is rejected for call rate,
+	// not energy, even though the cost is well within the energy budget.
+	err := l.Allow(id, "a", 1)
+	te, ok := err.(*ThrottledError)
+	if !ok || te.Reason != ReasonCallRate {
+		t.Fatalf("err = %v, want *ThrottledError with reason %q", err, ReasonCallRate)
+	}
+}
+
+func TestAllowEnergyRejectionLeavesCallRateUntouched(t *testing.T) {
+	l := NewLimiter(Policy{CallsPerSecond: 1, CallBurst: 1, EnergyPerSecond: 1, EnergyBudget: 1})
+	id := identity.Identity{1}
+
+	// Rejected for energy, not call rate: the energy budget can't cover
+	// a cost of 2, even though the call bucket has its one token.
+	err := l.Allow(id, "a", 2)
+	te, ok := err.(*ThrottledError)
+	if !ok || te.Reason != ReasonEnergy {
+		t.Fatalf("err = %v, want *ThrottledError with reason %q", err, ReasonEnergy)
+	}
+
+	// If the energy rejection had already spent the call bucket's token,
+	// this zero-cost call would now be call-rate throttled instead of
+	// succeeding.
+	if err := l.Allow(id, "a", 0); err != nil {
+		t.Fatalf("call after energy rejection: %v, want nil: the earlier rejection must not have spent the call token", err)
+	}
+}
+
+func TestAllowUnconfiguredPolicyNeverThrottles(t *testing.T) {
+	l := NewLimiter(Policy{})
+	id := identity.Identity{1}
+	for i := 0; i < 100; i++ {
+		if err := l.Allow(id, "tick", 1000); err != nil {
+			t.Fatalf("call %d with zero policy: %v", i, err)
+		}
+	}
+}
+
+func TestSetPolicyIsolatesIdentitiesAndResetsBuckets(t *testing.T) {
+	l := NewLimiter(Policy{CallsPerSecond: 1, CallBurst: 1})
+	strict := identity.Identity{1}
+	lenient := identity.Identity{2}
+	l.SetPolicy(lenient, Policy{CallsPerSecond: 1000, CallBurst: 1000})
+
+	if err := l.Allow(strict, "tick", 0); err != nil {
+		t.Fatalf("strict first call: %v", err)
+	}
+	if err := l.Allow(strict, "tick", 0); err == nil {
+		t.Fatal("strict second call: want throttled")
+	}
+	for i := 0; i < 10; i++ {
+		if err := l.Allow(lenient, "tick", 0); err != nil {
+			t.Fatalf("lenient call %d: %v", i, err)
+		}
+	}
+
+	l.SetPolicy(strict, Policy{CallsPerSecond: 1000, CallBurst: 1000})
+	if err := l.Allow(strict, "tick", 0); err != nil {
+		t.Fatalf("strict call after policy reset: %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+// Package reducerlog records a production-like sequence of reducer
+// invocations into a compact binary log and replays it against the Go
+// host (or a remote server through the client SDK) at original or
+// accelerated speed, so a performance regression test can drive a
+// realistic workload instead of a hand-rolled synthetic one.
+package reducerlog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+)
+
+// magic identifies the log format and its version.
+const magic = "STDBRLOG\x01"
+
+// Call is one recorded reducer invocation.
+type Call struct {
+	// At is how long after the log's first call this one was recorded,
+	// used by Replay to reproduce the original pacing between calls.
+	At time.Duration
+	// Caller is the identity that invoked the reducer.
+	Caller identity.Identity
+	// ReducerName and ReducerID identify the reducer, mirroring the
+	// pair every CallReducer signature in this tree already takes.
+	ReducerName string
+	ReducerID   uint32
+	// Args is the BSATN-encoded argument bytes passed to the reducer.
+	Args []byte
+}
+
+// Recorder accumulates Calls as they happen, timestamping each relative
+// to the first one recorded. It is safe for concurrent use, since a
+// production-like capture may record calls arriving on several
+// connections at once.
+type Recorder struct {
+	mu    sync.Mutex
+	start time.Time
+	calls []Call
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Record appends a Call, timestamped relative to the first call this
+// Recorder has seen.
+func (r *Recorder) Record(caller identity.Identity, reducerName string, reducerID uint32, args []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if r.start.IsZero() {
+		r.start = now
+	}
+	r.calls = append(r.calls, Call{
+		At:          now.Sub(r.start),
+		Caller:      caller,
+		ReducerName: reducerName,
+		ReducerID:   reducerID,
+		Args:        append([]byte(nil), args...),
+	})
+}
+
+// Calls returns a copy of every Call recorded so far, in recording
+// order.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// WriteTo encodes every recorded Call to w as a binary log readable by
+// Read.
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	return Write(w, r.Calls())
+}
+
+// Write encodes calls to w as a binary log: a magic header, a u32 count,
+// then for each call a u64 nanosecond timestamp, the 32-byte identity,
+// a u32 reducer ID, a length-prefixed reducer name, and length-prefixed
+// args.
+func Write(w io.Writer, calls []Call) (int64, error) {
+	cw := &countingWriter{w: w}
+	if _, err := io.WriteString(cw, magic); err != nil {
+		return cw.n, err
+	}
+	if err := writeU32(cw, uint32(len(calls))); err != nil {
+		return cw.n, err
+	}
+	for _, c := range calls {
+		if err := writeU64(cw, uint64(c.At)); err != nil {
+			return cw.n, err
+		}
+		for _, word := range c.Caller {
+			if err := writeU64(cw, word); err != nil {
+				return cw.n, err
+			}
+		}
+		if err := writeU32(cw, c.ReducerID); err != nil {
+			return cw.n, err
+		}
+		if err := writeFrame(cw, []byte(c.ReducerName)); err != nil {
+			return cw.n, err
+		}
+		if err := writeFrame(cw, c.Args); err != nil {
+			return cw.n, err
+		}
+	}
+	return cw.n, nil
+}
+
+// Read decodes a binary log written by Write/Recorder.WriteTo.
+func Read(r io.Reader) ([]Call, error) {
+	var gotMagic [len(magic)]byte
+	if _, err := io.ReadFull(r, gotMagic[:]); err != nil {
+		return nil, fmt.Errorf("reducerlog: read magic: %w", err)
+	}
+	if string(gotMagic[:]) != magic {
+		return nil, fmt.Errorf("reducerlog: not a reducer log (bad magic)")
+	}
+
+	count, err := readU32(r)
+	if err != nil {
+		return nil, fmt.Errorf("reducerlog: read call count: %w", err)
+	}
+
+	calls := make([]Call, count)
+	for i := range calls {
+		at, err := readU64(r)
+		if err != nil {
+			return nil, fmt.Errorf("reducerlog: call %d: read timestamp: %w", i, err)
+		}
+		var caller identity.Identity
+		for j := range caller {
+			word, err := readU64(r)
+			if err != nil {
+				return nil, fmt.Errorf("reducerlog: call %d: read identity: %w", i, err)
+			}
+			caller[j] = word
+		}
+		reducerID, err := readU32(r)
+		if err != nil {
+			return nil, fmt.Errorf("reducerlog: call %d: read reducer id: %w", i, err)
+		}
+		name, err := readFrame(r)
+		if err != nil {
+			return nil, fmt.Errorf("reducerlog: call %d: read reducer name: %w", i, err)
+		}
+		reducerArgs, err := readFrame(r)
+		if err != nil {
+			return nil, fmt.Errorf("reducerlog: call %d: read args: %w", i, err)
+		}
+		calls[i] = Call{
+			At:          time.Duration(at),
+			Caller:      caller,
+			ReducerName: string(name),
+			ReducerID:   reducerID,
+			Args:        reducerArgs,
+		}
+	}
+	return calls, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func writeU32(w io.Writer, v uint32) error {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+func writeU64(w io.Writer, v uint64) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+func writeFrame(w io.Writer, b []byte) error {
+	if err := writeU32(w, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func readU32(r io.Reader) (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf[:]), nil
+}
+
+func readU64(r io.Reader) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	n, err := readU32(r)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
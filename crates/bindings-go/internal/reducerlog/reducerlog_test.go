@@ -0,0 +1,76 @@
+package reducerlog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+)
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	calls := []Call{
+		{At: 0, Caller: identity.Identity{1, 2, 3, 4}, ReducerName: "add_player", ReducerID: 1, Args: []byte("abc")},
+		{At: 50 * time.Millisecond, Caller: identity.Identity{5, 6, 7, 8}, ReducerName: "score", ReducerID: 2, Args: nil},
+	}
+
+	var buf bytes.Buffer
+	if _, err := Write(&buf, calls); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d calls, want 2", len(got))
+	}
+	if got[0].ReducerName != "add_player" || got[0].ReducerID != 1 || string(got[0].Args) != "abc" {
+		t.Fatalf("got[0] = %+v", got[0])
+	}
+	if got[1].At != 50*time.Millisecond || got[1].Caller != (identity.Identity{5, 6, 7, 8}) {
+		t.Fatalf("got[1] = %+v", got[1])
+	}
+}
+
+func TestReadRejectsBadMagic(t *testing.T) {
+	if _, err := Read(bytes.NewReader([]byte("not a log"))); err == nil {
+		t.Fatal("Read: want error for bad magic")
+	}
+}
+
+func TestRecorderTimestampsRelativeToFirstCall(t *testing.T) {
+	r := NewRecorder()
+	r.Record(identity.Identity{}, "a", 1, nil)
+	time.Sleep(5 * time.Millisecond)
+	r.Record(identity.Identity{}, "b", 2, nil)
+
+	calls := r.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("got %d calls, want 2", len(calls))
+	}
+	if calls[0].At != 0 {
+		t.Fatalf("calls[0].At = %v, want 0", calls[0].At)
+	}
+	if calls[1].At < 5*time.Millisecond {
+		t.Fatalf("calls[1].At = %v, want >= 5ms", calls[1].At)
+	}
+}
+
+func TestRecorderWriteToRoundTripsThroughRead(t *testing.T) {
+	r := NewRecorder()
+	r.Record(identity.Identity{9}, "seed", 3, []byte{0x01})
+
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(got) != 1 || got[0].ReducerName != "seed" {
+		t.Fatalf("got = %+v", got)
+	}
+}
@@ -0,0 +1,83 @@
+package reducerlog
+
+import (
+	"context"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+)
+
+// Target is what Replay drives: anything that can call a reducer by
+// name and ID, satisfied today by *spacetimedb.Handle (an embedded
+// module) and, once the real client SDK's CallReducer lands, a
+// connection to a remote server — Replay itself has no opinion about
+// which.
+//
+// Replay always calls as the identity Target itself calls as; it has no
+// way to impersonate a Call's original Caller, since nothing in this
+// tree yet lets a caller supply an arbitrary identity to CallReducer
+// (see internal/host.Host.CallReducer, which does take one, but is not
+// exposed through this narrower interface). Caller is kept on Call
+// anyway so a log at least records who really made each call.
+type Target interface {
+	CallReducer(ctx context.Context, reducerName string, reducerID uint32, args []byte, flags protocol.CallFlags) error
+}
+
+// ReplayOptions controls Replay's pacing.
+type ReplayOptions struct {
+	// Speed scales the delay between calls relative to their recorded
+	// Call.At timestamps: 1.0 reproduces the original pacing, 2.0 runs
+	// twice as fast, and 0 (or negative) disables pacing entirely and
+	// replays every call back-to-back as fast as Target allows.
+	Speed float64
+}
+
+// Stats reports what Replay did.
+type Stats struct {
+	// Calls is how many calls were replayed, successful or not.
+	Calls int
+	// Failures counts calls whose Target.CallReducer returned an error.
+	// Replay keeps going past a failure, since a benchmark run wants a
+	// full pass over the workload even if a handful of calls errored
+	// (e.g. because the target's local state has drifted).
+	Failures int
+	// Duration is the wall-clock time the replay took.
+	Duration time.Duration
+}
+
+// Replay drives target through calls in order, pacing them per
+// opts.Speed, and returns once every call has been attempted or ctx is
+// done (whichever comes first).
+func Replay(ctx context.Context, calls []Call, target Target, opts ReplayOptions) (Stats, error) {
+	stats := Stats{}
+	replayStart := time.Now()
+
+	for _, call := range calls {
+		if opts.Speed > 0 {
+			scheduled := time.Duration(float64(call.At) / opts.Speed)
+			if wait := scheduled - time.Since(replayStart); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					stats.Duration = time.Since(replayStart)
+					return stats, ctx.Err()
+				}
+			}
+		}
+
+		stats.Calls++
+		if err := target.CallReducer(ctx, call.ReducerName, call.ReducerID, call.Args, 0); err != nil {
+			stats.Failures++
+		}
+
+		if err := ctx.Err(); err != nil {
+			stats.Duration = time.Since(replayStart)
+			return stats, err
+		}
+	}
+
+	stats.Duration = time.Since(replayStart)
+	return stats, nil
+}
@@ -0,0 +1,109 @@
+package reducerlog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+)
+
+type fakeTarget struct {
+	calls []string
+	fail  map[string]bool
+}
+
+func (f *fakeTarget) CallReducer(ctx context.Context, name string, id uint32, args []byte, flags protocol.CallFlags) error {
+	f.calls = append(f.calls, name)
+	if f.fail[name] {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func TestReplayCallsEveryReducerInOrder(t *testing.T) {
+	calls := []Call{
+		{ReducerName: "a", ReducerID: 1},
+		{ReducerName: "b", ReducerID: 2},
+	}
+	target := &fakeTarget{}
+
+	stats, err := Replay(context.Background(), calls, target, ReplayOptions{})
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if stats.Calls != 2 || stats.Failures != 0 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if len(target.calls) != 2 || target.calls[0] != "a" || target.calls[1] != "b" {
+		t.Fatalf("calls = %v", target.calls)
+	}
+}
+
+func TestReplayCountsFailuresWithoutStopping(t *testing.T) {
+	calls := []Call{
+		{ReducerName: "a", ReducerID: 1},
+		{ReducerName: "b", ReducerID: 2},
+	}
+	target := &fakeTarget{fail: map[string]bool{"a": true}}
+
+	stats, err := Replay(context.Background(), calls, target, ReplayOptions{})
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if stats.Calls != 2 || stats.Failures != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestReplayPacesCallsBySpeed(t *testing.T) {
+	calls := []Call{
+		{ReducerName: "a", ReducerID: 1, At: 0},
+		{ReducerName: "b", ReducerID: 2, At: 40 * time.Millisecond},
+	}
+	target := &fakeTarget{}
+
+	start := time.Now()
+	if _, err := Replay(context.Background(), calls, target, ReplayOptions{Speed: 1.0}); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("Replay took %v, want at least 40ms at 1.0x speed", elapsed)
+	}
+}
+
+func TestReplayIgnoresPacingWithZeroSpeed(t *testing.T) {
+	calls := []Call{
+		{ReducerName: "a", ReducerID: 1, At: 0},
+		{ReducerName: "b", ReducerID: 2, At: time.Second},
+	}
+	target := &fakeTarget{}
+
+	start := time.Now()
+	if _, err := Replay(context.Background(), calls, target, ReplayOptions{Speed: 0}); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("Replay took %v, want well under 1s with pacing disabled", elapsed)
+	}
+}
+
+func TestReplayStopsOnContextCancellation(t *testing.T) {
+	calls := []Call{
+		{ReducerName: "a", ReducerID: 1, At: 0},
+		{ReducerName: "b", ReducerID: 2, At: time.Hour},
+	}
+	target := &fakeTarget{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	stats, err := Replay(ctx, calls, target, ReplayOptions{Speed: 1.0})
+	if err == nil {
+		t.Fatal("Replay: want error from context deadline")
+	}
+	if stats.Calls != 1 {
+		t.Fatalf("stats.Calls = %d, want 1 (only the immediate call before the long wait)", stats.Calls)
+	}
+}
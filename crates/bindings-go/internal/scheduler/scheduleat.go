@@ -0,0 +1,180 @@
+// Package scheduler provides the Go-native representation of
+// spacetimedb_lib's ScheduleAt sum type, used by scheduled table rows to
+// say when a reducer should next run.
+//
+// This tree has no scheduler runtime yet (nothing reads a scheduled
+// table and fires reducers on ScheduleAt.Next), and no codegen-produced
+// typed table wrapper embeds a ScheduleAt field yet either; both are
+// natural follow-ups once a module's schema can be described end to end
+// (see internal/wasm's __describe_module__ work). ScheduleAt is added
+// now as the value type and codecs that work will need, matching the
+// wire representation a real SpacetimeDB module already produces.
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// ScheduleAtKind distinguishes ScheduleAt's two variants.
+type ScheduleAtKind uint8
+
+const (
+	// KindInterval fires repeatedly, every Interval.
+	KindInterval ScheduleAtKind = iota
+	// KindTime fires once, at Time.
+	KindTime
+)
+
+func (k ScheduleAtKind) String() string {
+	switch k {
+	case KindInterval:
+		return "Interval"
+	case KindTime:
+		return "Time"
+	default:
+		return fmt.Sprintf("ScheduleAtKind(%d)", uint8(k))
+	}
+}
+
+// ScheduleAt mirrors spacetimedb_lib's ScheduleAt: a scheduled table
+// row's reducer fires either repeatedly on a fixed Interval, or once at
+// an absolute Time. Only the field matching Kind is meaningful; build
+// one with Interval or At rather than a struct literal.
+type ScheduleAt struct {
+	Kind     ScheduleAtKind
+	Interval time.Duration
+	Time     time.Time
+}
+
+// Interval returns a ScheduleAt that fires every d.
+func Interval(d time.Duration) ScheduleAt {
+	return ScheduleAt{Kind: KindInterval, Interval: d}
+}
+
+// At returns a ScheduleAt that fires once, at t.
+func At(t time.Time) ScheduleAt {
+	return ScheduleAt{Kind: KindTime, Time: t}
+}
+
+// IsInterval reports whether s fires repeatedly.
+func (s ScheduleAt) IsInterval() bool { return s.Kind == KindInterval }
+
+// IsTime reports whether s fires once, at a fixed time.
+func (s ScheduleAt) IsTime() bool { return s.Kind == KindTime }
+
+// Next returns the absolute time s should next fire, given prev (the
+// row's previous fire time, or its creation time before it has ever
+// fired). A KindTime schedule ignores prev, since it always fires at the
+// same fixed point.
+func (s ScheduleAt) Next(prev time.Time) time.Time {
+	if s.Kind == KindTime {
+		return s.Time
+	}
+	return prev.Add(s.Interval)
+}
+
+// Type returns the AlgebraicType schema for ScheduleAt: a sum of
+// Interval (microseconds, as I64) and Time (microseconds since the Unix
+// epoch, as I64) — the same on-wire representation spacetimedb_lib's
+// TimeDuration and Timestamp newtypes use.
+func Type() bsatn.AlgebraicType {
+	intervalName, timeName := "Interval", "Time"
+	return bsatn.SumOf(
+		bsatn.SumVariant{Name: &intervalName, Type: bsatn.I64()},
+		bsatn.SumVariant{Name: &timeName, Type: bsatn.I64()},
+	)
+}
+
+// MarshalBSATN encodes s per Type(), for embedding in a scheduled
+// table's row bytes.
+func (s ScheduleAt) MarshalBSATN() ([]byte, error) {
+	v, err := s.schemaValue()
+	if err != nil {
+		return nil, err
+	}
+	return bsatn.MarshalJSON(v, Type())
+}
+
+// UnmarshalScheduleAtBSATN decodes data per Type().
+func UnmarshalScheduleAtBSATN(data []byte) (ScheduleAt, error) {
+	v, err := bsatn.UnmarshalJSON(data, Type())
+	if err != nil {
+		return ScheduleAt{}, err
+	}
+	return scheduleAtFromSchemaValue(v)
+}
+
+func (s ScheduleAt) schemaValue() (any, error) {
+	switch s.Kind {
+	case KindInterval:
+		return map[string]any{"tag": "Interval", "value": float64(s.Interval.Microseconds())}, nil
+	case KindTime:
+		return map[string]any{"tag": "Time", "value": float64(s.Time.UnixMicro())}, nil
+	default:
+		return nil, fmt.Errorf("scheduler: cannot encode ScheduleAt with unknown kind %d", s.Kind)
+	}
+}
+
+func scheduleAtFromSchemaValue(v any) (ScheduleAt, error) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return ScheduleAt{}, fmt.Errorf("scheduler: expected sum object, got %T", v)
+	}
+	tag, _ := obj["tag"].(string)
+	us, ok := obj["value"].(float64)
+	if !ok {
+		return ScheduleAt{}, fmt.Errorf("scheduler: expected numeric value for variant %q, got %T", tag, obj["value"])
+	}
+	switch tag {
+	case "Interval":
+		return Interval(time.Duration(int64(us)) * time.Microsecond), nil
+	case "Time":
+		return At(time.UnixMicro(int64(us))), nil
+	default:
+		return ScheduleAt{}, fmt.Errorf("scheduler: unknown ScheduleAt variant %q", tag)
+	}
+}
+
+// scheduleAtJSON is the encoding/json wire shape for ScheduleAt: exactly
+// one of the two fields is set, mirroring the BSATN sum's exclusivity.
+type scheduleAtJSON struct {
+	IntervalMicros *int64 `json:"interval_micros,omitempty"`
+	TimeUnixMicros *int64 `json:"time_unix_micros,omitempty"`
+}
+
+// MarshalJSON implements encoding/json.Marshaler.
+func (s ScheduleAt) MarshalJSON() ([]byte, error) {
+	switch s.Kind {
+	case KindInterval:
+		us := s.Interval.Microseconds()
+		return json.Marshal(scheduleAtJSON{IntervalMicros: &us})
+	case KindTime:
+		us := s.Time.UnixMicro()
+		return json.Marshal(scheduleAtJSON{TimeUnixMicros: &us})
+	default:
+		return nil, fmt.Errorf("scheduler: cannot marshal ScheduleAt with unknown kind %d", s.Kind)
+	}
+}
+
+// UnmarshalJSON implements encoding/json.Unmarshaler.
+func (s *ScheduleAt) UnmarshalJSON(data []byte) error {
+	var v scheduleAtJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch {
+	case v.IntervalMicros != nil && v.TimeUnixMicros != nil:
+		return fmt.Errorf("scheduler: ScheduleAt JSON must set exactly one of interval_micros/time_unix_micros, got both")
+	case v.IntervalMicros != nil:
+		*s = Interval(time.Duration(*v.IntervalMicros) * time.Microsecond)
+	case v.TimeUnixMicros != nil:
+		*s = At(time.UnixMicro(*v.TimeUnixMicros))
+	default:
+		return fmt.Errorf("scheduler: ScheduleAt JSON must set interval_micros or time_unix_micros")
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestIntervalNextAddsToPrev(t *testing.T) {
+	s := Interval(5 * time.Second)
+	prev := time.Unix(1000, 0)
+	if got := s.Next(prev); !got.Equal(prev.Add(5 * time.Second)) {
+		t.Fatalf("Next = %v, want %v", got, prev.Add(5*time.Second))
+	}
+}
+
+func TestAtNextIgnoresPrev(t *testing.T) {
+	fireAt := time.Unix(5000, 0)
+	s := At(fireAt)
+	if got := s.Next(time.Unix(1, 0)); !got.Equal(fireAt) {
+		t.Fatalf("Next = %v, want %v", got, fireAt)
+	}
+}
+
+func TestScheduleAtBSATNRoundTripsInterval(t *testing.T) {
+	want := Interval(90 * time.Second)
+	data, err := want.MarshalBSATN()
+	if err != nil {
+		t.Fatalf("MarshalBSATN: %v", err)
+	}
+	got, err := UnmarshalScheduleAtBSATN(data)
+	if err != nil {
+		t.Fatalf("UnmarshalScheduleAtBSATN: %v", err)
+	}
+	if !got.IsInterval() || got.Interval != want.Interval {
+		t.Fatalf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestScheduleAtBSATNRoundTripsTime(t *testing.T) {
+	want := At(time.UnixMicro(1_700_000_000_000_000))
+	data, err := want.MarshalBSATN()
+	if err != nil {
+		t.Fatalf("MarshalBSATN: %v", err)
+	}
+	got, err := UnmarshalScheduleAtBSATN(data)
+	if err != nil {
+		t.Fatalf("UnmarshalScheduleAtBSATN: %v", err)
+	}
+	if !got.IsTime() || !got.Time.Equal(want.Time) {
+		t.Fatalf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestScheduleAtJSONRoundTripsInterval(t *testing.T) {
+	want := Interval(30 * time.Second)
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	var got ScheduleAt
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if !got.IsInterval() || got.Interval != want.Interval {
+		t.Fatalf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestScheduleAtJSONRoundTripsTime(t *testing.T) {
+	want := At(time.UnixMicro(1_700_000_000_000_000))
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	var got ScheduleAt
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if !got.IsTime() || !got.Time.Equal(want.Time) {
+		t.Fatalf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestScheduleAtUnmarshalJSONRejectsBothFieldsSet(t *testing.T) {
+	var s ScheduleAt
+	err := json.Unmarshal([]byte(`{"interval_micros":1,"time_unix_micros":2}`), &s)
+	if err == nil {
+		t.Fatal("expected an error when both fields are set")
+	}
+}
+
+func TestScheduleAtUnmarshalJSONRejectsNeitherFieldSet(t *testing.T) {
+	var s ScheduleAt
+	err := json.Unmarshal([]byte(`{}`), &s)
+	if err == nil {
+		t.Fatal("expected an error when neither field is set")
+	}
+}
@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// SimClock is a controllable virtual clock for testing scheduled-reducer
+// timing against clock skew, jitter, and periods where the host missed
+// ticks entirely (e.g. it was paused or overloaded) — failure modes a
+// real wall clock can't be coaxed into on demand. It holds no goroutines
+// or timers of its own; a test advances it explicitly with Advance and
+// reads it with Now.
+type SimClock struct {
+	now    time.Time
+	skew   time.Duration
+	jitter time.Duration
+	rng    *rand.Rand
+}
+
+// NewSimClock returns a SimClock starting at start, with no skew or
+// jitter.
+func NewSimClock(start time.Time) *SimClock {
+	return &SimClock{now: start, rng: rand.New(rand.NewSource(1))}
+}
+
+// SetSkew offsets every Now() reading by d, simulating a host clock that
+// has drifted from the reference time schedules are computed against. d
+// may be negative.
+func (c *SimClock) SetSkew(d time.Duration) {
+	c.skew = d
+}
+
+// SetJitter bounds the random per-call noise Now() adds on top of skew,
+// uniformly distributed in [-max, max]. Pass 0 to disable jitter.
+func (c *SimClock) SetJitter(max time.Duration) {
+	c.jitter = max
+}
+
+// Seed replaces the jitter random source's seed, for a reproducible test
+// run.
+func (c *SimClock) Seed(seed int64) {
+	c.rng = rand.New(rand.NewSource(seed))
+}
+
+// Now returns the clock's current simulated time: its advanced base time
+// plus skew plus one fresh jitter sample.
+func (c *SimClock) Now() time.Time {
+	t := c.now.Add(c.skew)
+	if c.jitter > 0 {
+		offset := time.Duration(c.rng.Int63n(int64(2*c.jitter))) - c.jitter
+		t = t.Add(offset)
+	}
+	return t
+}
+
+// Advance moves the clock's base time forward by d without firing
+// anything, simulating a period where the host was not ticking at all
+// (e.g. a GC pause, a hibernating process, or a stalled scheduler
+// goroutine) — the gap Simulate's catch-up policies are meant to be
+// tested against.
+func (c *SimClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// CatchUpPolicy decides what Simulate does when a scheduled Interval has
+// missed one or more ticks, e.g. because the clock was Advanced past
+// several fire times at once.
+type CatchUpPolicy int
+
+const (
+	// CatchUpSkip drops every missed tick but the last: Simulate returns
+	// at most one fire time, catching the schedule up to "now" without
+	// replaying the ticks it slept through.
+	CatchUpSkip CatchUpPolicy = iota
+	// CatchUpBurst fires once for every missed tick, back to back, so no
+	// scheduled invocation is ever silently dropped.
+	CatchUpBurst
+)
+
+func (p CatchUpPolicy) String() string {
+	switch p {
+	case CatchUpSkip:
+		return "skip"
+	case CatchUpBurst:
+		return "burst"
+	default:
+		return fmt.Sprintf("CatchUpPolicy(%d)", int(p))
+	}
+}
+
+// Simulate returns every fire time a scheduled reducer with schedule s
+// should be considered to have fired at, catching up from prev (its last
+// fire time, or creation time) to now, according to policy. A KindTime
+// schedule fires at most once regardless of policy, since it only ever
+// has one fire time.
+//
+// now is normally read from a SimClock's Now() after the clock has been
+// Advanced past one or more of the schedule's fire times, so a test can
+// assert exactly how many reducer calls a given catch-up policy produces
+// for a given gap.
+func Simulate(s ScheduleAt, prev, now time.Time, policy CatchUpPolicy) []time.Time {
+	if s.Kind == KindTime {
+		if !s.Time.After(now) {
+			return []time.Time{s.Time}
+		}
+		return nil
+	}
+	if s.Interval <= 0 {
+		return nil
+	}
+
+	var fires []time.Time
+	for next := s.Next(prev); !next.After(now); next = next.Add(s.Interval) {
+		fires = append(fires, next)
+		prev = next
+	}
+	if len(fires) == 0 {
+		return nil
+	}
+	if policy == CatchUpSkip {
+		return fires[len(fires)-1:]
+	}
+	return fires
+}
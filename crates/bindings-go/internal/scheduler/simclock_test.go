@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimClockSkew(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewSimClock(start)
+	c.SetSkew(5 * time.Second)
+	if got := c.Now(); !got.Equal(start.Add(5 * time.Second)) {
+		t.Fatalf("Now() = %v, want %v", got, start.Add(5*time.Second))
+	}
+}
+
+func TestSimClockJitterBounded(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewSimClock(start)
+	c.SetJitter(2 * time.Second)
+	for i := 0; i < 100; i++ {
+		got := c.Now()
+		diff := got.Sub(start)
+		if diff < -2*time.Second || diff > 2*time.Second {
+			t.Fatalf("Now() drifted %v outside +/-2s bound", diff)
+		}
+	}
+}
+
+func TestSimClockAdvance(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewSimClock(start)
+	c.Advance(time.Hour)
+	if got := c.Now(); !got.Equal(start.Add(time.Hour)) {
+		t.Fatalf("Now() = %v, want %v", got, start.Add(time.Hour))
+	}
+}
+
+func TestSimulateSkipCatchUpDropsMissedTicks(t *testing.T) {
+	start := time.Unix(0, 0)
+	s := Interval(time.Minute)
+	// 5 missed ticks between prev and now.
+	now := start.Add(5*time.Minute + 30*time.Second)
+	fires := Simulate(s, start, now, CatchUpSkip)
+	if len(fires) != 1 {
+		t.Fatalf("fires = %v, want exactly one (skip policy)", fires)
+	}
+	want := start.Add(5 * time.Minute)
+	if !fires[0].Equal(want) {
+		t.Fatalf("fires[0] = %v, want %v", fires[0], want)
+	}
+}
+
+func TestSimulateBurstCatchUpFiresEveryMissedTick(t *testing.T) {
+	start := time.Unix(0, 0)
+	s := Interval(time.Minute)
+	now := start.Add(5*time.Minute + 30*time.Second)
+	fires := Simulate(s, start, now, CatchUpBurst)
+	if len(fires) != 5 {
+		t.Fatalf("fires = %v, want 5 (burst policy)", fires)
+	}
+	for i, f := range fires {
+		want := start.Add(time.Duration(i+1) * time.Minute)
+		if !f.Equal(want) {
+			t.Fatalf("fires[%d] = %v, want %v", i, f, want)
+		}
+	}
+}
+
+func TestSimulateNoMissedTicksReturnsNil(t *testing.T) {
+	start := time.Unix(0, 0)
+	s := Interval(time.Minute)
+	now := start.Add(30 * time.Second)
+	if fires := Simulate(s, start, now, CatchUpBurst); fires != nil {
+		t.Fatalf("fires = %v, want nil", fires)
+	}
+}
+
+func TestSimulateKindTimeFiresOnceWhenDue(t *testing.T) {
+	fireAt := time.Unix(100, 0)
+	s := At(fireAt)
+	fires := Simulate(s, time.Unix(0, 0), time.Unix(200, 0), CatchUpBurst)
+	if len(fires) != 1 || !fires[0].Equal(fireAt) {
+		t.Fatalf("fires = %v, want [%v]", fires, fireAt)
+	}
+}
+
+func TestSimulateKindTimeNotYetDueReturnsNil(t *testing.T) {
+	fireAt := time.Unix(100, 0)
+	s := At(fireAt)
+	fires := Simulate(s, time.Unix(0, 0), time.Unix(50, 0), CatchUpSkip)
+	if fires != nil {
+		t.Fatalf("fires = %v, want nil", fires)
+	}
+}
@@ -0,0 +1,167 @@
+// Package trace turns a recorded log of host-call table accesses into a
+// reducer dependency graph, so module authors can see which reducers
+// touch which tables before scaling out concurrent execution.
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Access records a single table access made by a reducer during a host
+// call. Recorder accumulates these; Build turns them into a Graph.
+//
+// Tracking is table-granularity, not per-index-range: two reducers that
+// touch the same table are flagged as a potential conflict even if they
+// in fact address disjoint index ranges, which is a conservative
+// over-approximation appropriate for a "should I look closer" signal.
+type Access struct {
+	Reducer string
+	Table   string
+	Write   bool
+}
+
+// Recorder collects Accesses made across many reducer calls.
+type Recorder struct {
+	mu       sync.Mutex
+	accesses []Access
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder { return &Recorder{} }
+
+// Record appends one table access.
+func (r *Recorder) Record(reducer, table string, write bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.accesses = append(r.accesses, Access{Reducer: reducer, Table: table, Write: write})
+}
+
+// Accesses returns a copy of every access recorded so far.
+func (r *Recorder) Accesses() []Access {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Access(nil), r.accesses...)
+}
+
+// Graph is a reducer/table dependency graph built from a set of Accesses.
+type Graph struct {
+	Reducers []string            `json:"reducers"`
+	Tables   []string            `json:"tables"`
+	Reads    map[string][]string `json:"reads"`  // reducer -> tables it reads
+	Writes   map[string][]string `json:"writes"` // reducer -> tables it writes
+}
+
+// Conflict flags two reducers that both write the same table, a
+// prerequisite for a write-write race under concurrent execution.
+type Conflict struct {
+	Table    string `json:"table"`
+	ReducerA string `json:"reducer_a"`
+	ReducerB string `json:"reducer_b"`
+}
+
+// Build aggregates accesses into a Graph.
+func Build(accesses []Access) *Graph {
+	reads := map[string]map[string]bool{}
+	writes := map[string]map[string]bool{}
+	reducerSet := map[string]bool{}
+	tableSet := map[string]bool{}
+
+	for _, a := range accesses {
+		reducerSet[a.Reducer] = true
+		tableSet[a.Table] = true
+		set := reads
+		if a.Write {
+			set = writes
+		}
+		if set[a.Reducer] == nil {
+			set[a.Reducer] = map[string]bool{}
+		}
+		set[a.Reducer][a.Table] = true
+	}
+
+	g := &Graph{
+		Reducers: sortedKeys(reducerSet),
+		Tables:   sortedKeys(tableSet),
+		Reads:    flatten(reads),
+		Writes:   flatten(writes),
+	}
+	return g
+}
+
+// Conflicts reports every pair of distinct reducers that write the same
+// table, one entry per (table, unordered pair).
+func (g *Graph) Conflicts() []Conflict {
+	var out []Conflict
+	for _, table := range g.Tables {
+		var writers []string
+		for reducer, tables := range g.Writes {
+			if contains(tables, table) {
+				writers = append(writers, reducer)
+			}
+		}
+		sort.Strings(writers)
+		for i := 0; i < len(writers); i++ {
+			for j := i + 1; j < len(writers); j++ {
+				out = append(out, Conflict{Table: table, ReducerA: writers[i], ReducerB: writers[j]})
+			}
+		}
+	}
+	return out
+}
+
+// JSON renders the graph as indented JSON.
+func (g *Graph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// DOT renders the graph in Graphviz DOT format: reducers and tables as
+// nodes, read/write accesses as edges.
+func (g *Graph) DOT() string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph reducers {\n")
+	for _, reducer := range g.Reducers {
+		fmt.Fprintf(&buf, "  %q [shape=box];\n", reducer)
+	}
+	for reducer, tables := range g.Reads {
+		for _, table := range tables {
+			fmt.Fprintf(&buf, "  %q -> %q [label=read];\n", reducer, table)
+		}
+	}
+	for reducer, tables := range g.Writes {
+		for _, table := range tables {
+			fmt.Fprintf(&buf, "  %q -> %q [label=write,color=red];\n", reducer, table)
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+func flatten(m map[string]map[string]bool) map[string][]string {
+	out := make(map[string][]string, len(m))
+	for k, set := range m {
+		out[k] = sortedKeys(set)
+	}
+	return out
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
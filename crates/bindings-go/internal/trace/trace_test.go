@@ -0,0 +1,31 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildFlagsWriteWriteConflict(t *testing.T) {
+	r := NewRecorder()
+	r.Record("deposit", "accounts", true)
+	r.Record("withdraw", "accounts", true)
+	r.Record("deposit", "ledger", false)
+
+	g := Build(r.Accesses())
+	conflicts := g.Conflicts()
+	if len(conflicts) != 1 {
+		t.Fatalf("Conflicts = %v, want 1 entry", conflicts)
+	}
+	if conflicts[0].Table != "accounts" {
+		t.Fatalf("conflict table = %q, want accounts", conflicts[0].Table)
+	}
+}
+
+func TestDOTIncludesWriteEdge(t *testing.T) {
+	g := Build([]Access{{Reducer: "deposit", Table: "accounts", Write: true}})
+	dot := g.DOT()
+	want := `"deposit" -> "accounts" [label=write,color=red];`
+	if !strings.Contains(dot, want) {
+		t.Fatalf("DOT output missing write edge: %s", dot)
+	}
+}
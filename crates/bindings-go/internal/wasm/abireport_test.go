@@ -0,0 +1,60 @@
+package wasm
+
+import "testing"
+
+func TestABIReportHasNoDuplicateNames(t *testing.T) {
+	rt := &Runtime{}
+	seen := map[string]bool{}
+	for _, fn := range rt.ABIReport() {
+		if seen[fn.Name] {
+			t.Fatalf("duplicate ABIFunction name %q", fn.Name)
+		}
+		seen[fn.Name] = true
+	}
+}
+
+func TestABIReportCoversEveryRegisteredHostFunction(t *testing.T) {
+	rt := &Runtime{}
+	want := []string{
+		"_console_log",
+		"datastore_table_scan_bsatn",
+		"datastore_table_row_count",
+		"datastore_update_bsatn",
+		"datastore_delete_range_bsatn",
+		"datastore_table_truncate",
+		"bytes_source_read",
+		"txn_metadata_bsatn",
+	}
+	got := map[string]bool{}
+	for _, fn := range rt.ABIReport() {
+		got[fn.Name] = true
+	}
+	for _, name := range want {
+		if !got[name] {
+			t.Errorf("ABIReport is missing entry for %q", name)
+		}
+	}
+}
+
+func TestABIReportReturnsDefensiveCopy(t *testing.T) {
+	rt := &Runtime{}
+	report := rt.ABIReport()
+	report[0].Name = "tampered"
+
+	if rt.ABIReport()[0].Name == "tampered" {
+		t.Fatal("mutating a returned report should not affect the manifest")
+	}
+}
+
+func TestABIStatusString(t *testing.T) {
+	cases := map[ABIStatus]string{
+		StatusStub:    "stub",
+		StatusPartial: "partial",
+		StatusFull:    "full",
+	}
+	for status, want := range cases {
+		if got := status.String(); got != want {
+			t.Errorf("ABIStatus(%d).String() = %q, want %q", status, got, want)
+		}
+	}
+}
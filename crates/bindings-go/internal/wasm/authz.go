@@ -0,0 +1,131 @@
+package wasm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+)
+
+// TableOp distinguishes a read from a write for AuthzHooks.
+type TableOp int
+
+const (
+	OpRead TableOp = iota
+	OpWrite
+)
+
+// AuthzHooks lets an embedder gate table access before a datastore host
+// call is allowed to complete. AuthorizeRead/AuthorizeWrite receive the
+// identity that made the current reducer call (see identity.WithCaller,
+// which Host.CallReducer sets) and the table name; returning an error
+// denies the access and the host call reports failure to the module
+// instead of touching the datastore. A nil hook allows every access.
+//
+// This exists to let embedders prototype permission schemes without
+// modifying the module, and to let a test harness assert that a module
+// under test never touches a table it shouldn't.
+type AuthzHooks struct {
+	AuthorizeRead  func(id identity.Identity, table string) error
+	AuthorizeWrite func(id identity.Identity, table string) error
+}
+
+// SetAuthzHooks installs hooks that every subsequent datastore host call
+// on rt checks before proceeding.
+func (rt *Runtime) SetAuthzHooks(hooks AuthzHooks) {
+	rt.authz = hooks
+}
+
+// RegisterTable records name for tableID, so authorization hooks (and
+// error messages) can refer to tables by name instead of the host ABI's
+// numeric ID. Real table ID resolution arrives with __describe_module__
+// parsing; until then, callers that want named authorization checks
+// must register the mapping themselves.
+//
+// It returns a *LimitExceededError, without registering tableID, if
+// doing so would put rt over its Config.Limits.MaxTables — re-registering
+// an already-known tableID never counts against the limit.
+func (rt *Runtime) RegisterTable(tableID uint32, name string) error {
+	if rt.tableNames == nil {
+		rt.tableNames = map[uint32]string{}
+	}
+	if _, exists := rt.tableNames[tableID]; !exists {
+		if err := checkLimit("tables", len(rt.tableNames)+1, rt.cfg.Limits.MaxTables); err != nil {
+			return err
+		}
+	}
+	rt.tableNames[tableID] = name
+	return nil
+}
+
+// RegisterIndex records name for indexID, the index-side counterpart to
+// RegisterTable: it exists so embedders can refer to indexes by name in
+// error messages, and so Config.Limits.MaxIndexes has something to
+// enforce against (see RegisterTable's doc comment on why this host
+// checks incrementally rather than from a parsed module manifest).
+func (rt *Runtime) RegisterIndex(indexID uint32, name string) error {
+	if rt.indexNames == nil {
+		rt.indexNames = map[uint32]string{}
+	}
+	if _, exists := rt.indexNames[indexID]; !exists {
+		if err := checkLimit("indexes", len(rt.indexNames)+1, rt.cfg.Limits.MaxIndexes); err != nil {
+			return err
+		}
+	}
+	rt.indexNames[indexID] = name
+	return nil
+}
+
+// RegisterIndexKey installs key as the column-extraction function
+// datastore_update_bsatn uses to derive the real key a row is stored
+// under for indexID (see Runtime.rowKey and db.IndexKeyFunc). It exists
+// because Engine and Database treat row bytes as opaque — same as
+// db.RegisterIndex, whose Key field this mirrors — so something that
+// understands the table's schema has to supply the extraction itself. A
+// caller with a parsed module definition should build its Key funcs with
+// moduledef.ModuleDef.TableSchemas and install them via SetTables
+// instead of calling this directly: together they derive every table's
+// index key funcs automatically from TableDef.ProductRef and
+// IndexDef.Columns. An indexID with no key registered still falls back
+// to keying by the whole row.
+func (rt *Runtime) RegisterIndexKey(indexID uint32, key db.IndexKeyFunc) {
+	if rt.indexKeys == nil {
+		rt.indexKeys = map[uint32]db.IndexKeyFunc{}
+	}
+	rt.indexKeys[indexID] = key
+}
+
+// tableName returns the name registered for tableID, or a placeholder
+// if none was registered.
+func (rt *Runtime) tableName(tableID uint32) string {
+	if name, ok := rt.tableNames[tableID]; ok {
+		return name
+	}
+	return fmt.Sprintf("table#%d", tableID)
+}
+
+// authorize runs the configured hook for op against tableID, using the
+// identity propagated on ctx (see identity.WithCaller). It is a no-op
+// unless FeatureRLS is enabled on rt's Config, so installing AuthzHooks
+// has no effect until an embedder opts in; even then it returns nil if
+// no hook is configured for op, or no identity was propagated with the
+// call (e.g. a reducer invoked directly in tests without going through
+// Host).
+func (rt *Runtime) authorize(ctx context.Context, tableID uint32, op TableOp) error {
+	if !rt.featureEnabled(FeatureRLS) {
+		return nil
+	}
+	var hook func(identity.Identity, string) error
+	switch op {
+	case OpRead:
+		hook = rt.authz.AuthorizeRead
+	case OpWrite:
+		hook = rt.authz.AuthorizeWrite
+	}
+	if hook == nil {
+		return nil
+	}
+	caller, _ := identity.FromContext(ctx)
+	return hook(caller, rt.tableName(tableID))
+}
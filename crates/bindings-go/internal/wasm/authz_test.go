@@ -0,0 +1,61 @@
+package wasm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+)
+
+func TestAuthorizeAllowsWhenNoHookConfigured(t *testing.T) {
+	rt := &Runtime{}
+	if err := rt.authorize(context.Background(), 1, OpRead); err != nil {
+		t.Fatalf("authorize with no hooks: %v", err)
+	}
+}
+
+func TestAuthorizeDeniesAndNamesTable(t *testing.T) {
+	rt := &Runtime{cfg: Config{Features: map[string]bool{FeatureRLS: true}}}
+	if err := rt.RegisterTable(1, "accounts"); err != nil {
+		t.Fatalf("RegisterTable: %v", err)
+	}
+
+	var gotID identity.Identity
+	var gotTable string
+	rt.SetAuthzHooks(AuthzHooks{
+		AuthorizeRead: func(id identity.Identity, table string) error {
+			gotID, gotTable = id, table
+			return fmt.Errorf("denied")
+		},
+	})
+
+	caller := identity.Identity{1, 2, 3, 4}
+	ctx := identity.WithCaller(context.Background(), caller)
+	if err := rt.authorize(ctx, 1, OpRead); err == nil {
+		t.Fatal("expected denial")
+	}
+	if gotID != caller {
+		t.Fatalf("hook saw identity %v, want %v", gotID, caller)
+	}
+	if gotTable != "accounts" {
+		t.Fatalf("hook saw table %q, want accounts", gotTable)
+	}
+}
+
+func TestAuthorizeUnregisteredTableUsesPlaceholderName(t *testing.T) {
+	rt := &Runtime{cfg: Config{Features: map[string]bool{FeatureRLS: true}}}
+	var gotTable string
+	rt.SetAuthzHooks(AuthzHooks{
+		AuthorizeWrite: func(id identity.Identity, table string) error {
+			gotTable = table
+			return nil
+		},
+	})
+	if err := rt.authorize(context.Background(), 7, OpWrite); err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if gotTable != "table#7" {
+		t.Fatalf("gotTable = %q, want table#7", gotTable)
+	}
+}
@@ -0,0 +1,90 @@
+package wasm
+
+import "sync"
+
+// byteSource holds data the host is streaming to the guest in chunks
+// across repeated bytes_source_read calls, instead of writing it into
+// guest memory in one shot. Modules use this ABI pattern for anything
+// that can exceed a single buffer: __describe_module__ output, and (once
+// wired to the real datastore) oversized rows read back from a table.
+type byteSource struct {
+	data []byte
+	pos  int
+}
+
+// byteSourcePool reuses byteSource structs across open/read-to-exhaustion
+// cycles, since a hot table scan opens (and quickly exhausts) one per row
+// read back to the guest. Reused structs are reset in putByteSource
+// before returning to the pool.
+var byteSourcePool = sync.Pool{New: func() any { return &byteSource{} }}
+
+func getByteSource(data []byte) *byteSource {
+	src := byteSourcePool.Get().(*byteSource)
+	src.data = data
+	src.pos = 0
+	return src
+}
+
+func putByteSource(src *byteSource) {
+	src.data = nil
+	src.pos = 0
+	byteSourcePool.Put(src)
+}
+
+// byteSources tracks one Runtime's in-flight sources, keyed by an
+// opaque ID handed to the guest when the source is opened.
+type byteSources struct {
+	mu      sync.Mutex
+	next    uint32
+	sources map[uint32]*byteSource
+}
+
+func newByteSources() *byteSources {
+	return &byteSources{sources: map[uint32]*byteSource{}}
+}
+
+// open registers data as a new source ready to stream and returns the ID
+// the guest passes to bytes_source_read to pull it.
+func (s *byteSources) open(data []byte) uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	id := s.next
+	s.sources[id] = getByteSource(data)
+	return id
+}
+
+// remaining reports how many unread bytes are left in source id without
+// consuming them, and whether id refers to a still-open source. It backs
+// the deprecated byte_buffer_source_get_len shim (see deprecated.go);
+// bytes_source_read's read-until-empty protocol never needed to ask this.
+func (s *byteSources) remaining(id uint32) (uint32, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src, ok := s.sources[id]
+	if !ok {
+		return 0, false
+	}
+	return uint32(len(src.data) - src.pos), true
+}
+
+// read copies up to len(buf) unread bytes from source id into buf,
+// returning the number copied. Once a source's data is fully consumed
+// it is dropped (and its struct returned to byteSourcePool); reading an
+// unknown or already-exhausted id returns 0, matching the
+// read-until-nothing-left protocol the guest loops on.
+func (s *byteSources) read(id uint32, buf []byte) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src, ok := s.sources[id]
+	if !ok {
+		return 0
+	}
+	n := copy(buf, src.data[src.pos:])
+	src.pos += n
+	if src.pos >= len(src.data) {
+		delete(s.sources, id)
+		putByteSource(src)
+	}
+	return n
+}
@@ -0,0 +1,56 @@
+package wasm
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestByteSourcesConcurrentOpenReadRace exercises open/read from many
+// goroutines at once; run with -race to catch any pool-reuse-related
+// data races (e.g. a struct handed back out before a prior reader is
+// done with it).
+func TestByteSourcesConcurrentOpenReadRace(t *testing.T) {
+	s := newByteSources()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := []byte(fmt.Sprintf("payload-%d", i))
+			id := s.open(data)
+			buf := make([]byte, len(data))
+			n := s.read(id, buf)
+			if n != len(data) || string(buf[:n]) != string(data) {
+				t.Errorf("goroutine %d: read %q, want %q", i, buf[:n], data)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestByteSourcePoolResetsBetweenUses(t *testing.T) {
+	s := newByteSources()
+	id1 := s.open([]byte("first"))
+	buf := make([]byte, 5)
+	s.read(id1, buf) // exhausts and returns the struct to the pool
+
+	id2 := s.open([]byte("second-value"))
+	buf2 := make([]byte, 12)
+	n := s.read(id2, buf2)
+	if string(buf2[:n]) != "second-value" {
+		t.Fatalf("reused byteSource carried stale state: got %q", buf2[:n])
+	}
+}
+
+func BenchmarkByteSourcesOpenReadCycle(b *testing.B) {
+	s := newByteSources()
+	data := make([]byte, 256)
+	buf := make([]byte, 256)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		id := s.open(data)
+		s.read(id, buf)
+	}
+}
@@ -0,0 +1,62 @@
+package wasm
+
+import "testing"
+
+func TestByteSourcesStreamsInChunks(t *testing.T) {
+	s := newByteSources()
+	id := s.open([]byte("hello world"))
+
+	buf := make([]byte, 4)
+	n := s.read(id, buf)
+	if n != 4 || string(buf[:n]) != "hell" {
+		t.Fatalf("first read = %q, want %q", buf[:n], "hell")
+	}
+	n = s.read(id, buf)
+	if n != 4 || string(buf[:n]) != "o wo" {
+		t.Fatalf("second read = %q, want %q", buf[:n], "o wo")
+	}
+	n = s.read(id, buf)
+	if n != 3 || string(buf[:n]) != "rld" {
+		t.Fatalf("third read = %q, want %q", buf[:n], "rld")
+	}
+	if n := s.read(id, buf); n != 0 {
+		t.Fatalf("read after exhaustion = %d, want 0", n)
+	}
+}
+
+func TestByteSourcesReadUnknownIDReturnsZero(t *testing.T) {
+	s := newByteSources()
+	if n := s.read(42, make([]byte, 4)); n != 0 {
+		t.Fatalf("read on unknown id = %d, want 0", n)
+	}
+}
+
+func TestByteSourcesRemainingReflectsUnreadBytes(t *testing.T) {
+	s := newByteSources()
+	id := s.open([]byte("hello world"))
+
+	if n, ok := s.remaining(id); !ok || n != 11 {
+		t.Fatalf("remaining before any read = %d, %v, want 11, true", n, ok)
+	}
+	s.read(id, make([]byte, 4))
+	if n, ok := s.remaining(id); !ok || n != 7 {
+		t.Fatalf("remaining after reading 4 bytes = %d, %v, want 7, true", n, ok)
+	}
+}
+
+func TestByteSourcesRemainingUnknownIDReportsFalse(t *testing.T) {
+	s := newByteSources()
+	if n, ok := s.remaining(42); ok || n != 0 {
+		t.Fatalf("remaining on unknown id = %d, %v, want 0, false", n, ok)
+	}
+}
+
+func TestCheckRowSize(t *testing.T) {
+	if err := checkRowSize(make([]byte, MaxRowSize)); err != nil {
+		t.Fatalf("row at exactly MaxRowSize should be accepted: %v", err)
+	}
+	err := checkRowSize(make([]byte, MaxRowSize+1))
+	if _, ok := err.(*RowTooLargeError); !ok {
+		t.Fatalf("err = %v, want *RowTooLargeError", err)
+	}
+}
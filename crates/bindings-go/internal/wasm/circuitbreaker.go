@@ -0,0 +1,154 @@
+package wasm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitBreakerConfig configures a CircuitBreaker's trip threshold.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is how many times a reducer must fail within
+	// Window before CircuitBreaker quarantines it. Zero (or negative)
+	// disables enforcement: RecordFailure still tracks history, but
+	// Allow never rejects a call.
+	FailureThreshold int
+	// Window bounds how far back RecordFailure looks when counting
+	// toward FailureThreshold; failures older than Window are forgotten,
+	// so a reducer that fails occasionally over a long period never
+	// trips, only one failing repeatedly in a short burst.
+	Window time.Duration
+}
+
+// CircuitBreaker quarantines a reducer ID that has failed
+// FailureThreshold times within Window, rejecting further calls to it
+// with a *QuarantinedReducerError until Reset is called. It exists so a
+// single reducer that has started trapping on every call (a bad
+// deploy, a data-dependent bug hit by a scheduled reducer's retry loop,
+// or a client hammering a broken endpoint) can't spend an embedded
+// host's CPU and log volume forever; everything else keeps running.
+//
+// A CircuitBreaker is safe for concurrent use by multiple goroutines.
+type CircuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu          sync.Mutex
+	failures    map[uint32][]time.Time
+	quarantined map[uint32]struct{}
+}
+
+// NewCircuitBreaker returns a CircuitBreaker enforcing cfg.
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		cfg:         cfg,
+		failures:    make(map[uint32][]time.Time),
+		quarantined: make(map[uint32]struct{}),
+	}
+}
+
+// Allow reports whether reducerID may be called right now, returning a
+// *QuarantinedReducerError if it is currently quarantined.
+func (b *CircuitBreaker) Allow(reducerID uint32) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.quarantined[reducerID]; ok {
+		return &QuarantinedReducerError{ID: reducerID}
+	}
+	return nil
+}
+
+// RecordFailure records a failed call to reducerID at now, quarantining
+// it once its failure count within Window of now reaches
+// FailureThreshold.
+func (b *CircuitBreaker) RecordFailure(reducerID uint32, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cfg.FailureThreshold <= 0 {
+		return
+	}
+	cutoff := now.Add(-b.cfg.Window)
+	kept := b.failures[reducerID][:0]
+	for _, t := range b.failures[reducerID] {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	kept = append(kept, now)
+	b.failures[reducerID] = kept
+	if len(kept) >= b.cfg.FailureThreshold {
+		b.quarantined[reducerID] = struct{}{}
+	}
+}
+
+// RecordSuccess clears reducerID's failure history, so an intermittent
+// failure doesn't keep counting toward quarantine once calls start
+// succeeding again. It does not itself un-quarantine a reducer that has
+// already tripped — only Reset does that — since a success racing an
+// ongoing failure burst should not silently reopen a circuit the
+// operator has not yet confirmed is fixed.
+func (b *CircuitBreaker) RecordSuccess(reducerID uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.failures, reducerID)
+}
+
+// Reset un-quarantines reducerID and clears its failure history,
+// letting calls through again — the "reset via API" an operator calls
+// once they've confirmed the underlying bug is fixed or decided to
+// accept the failure rate.
+func (b *CircuitBreaker) Reset(reducerID uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.quarantined, reducerID)
+	delete(b.failures, reducerID)
+}
+
+// Quarantined reports whether reducerID is currently quarantined.
+func (b *CircuitBreaker) Quarantined(reducerID uint32) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.quarantined[reducerID]
+	return ok
+}
+
+// QuarantinedReducerError is returned by CallReducer when reducerID is
+// currently quarantined by rt's CircuitBreaker.
+type QuarantinedReducerError struct {
+	ID uint32
+}
+
+func (e *QuarantinedReducerError) Error() string {
+	return fmt.Sprintf("wasm: reducer %d is quarantined after repeated failures", e.ID)
+}
+
+// SetCircuitBreaker installs breaker as the quarantine policy
+// CallReducer checks before every call, gated by FeatureCircuitBreaker
+// the same way Limiter is gated by FeatureRateLimit: installing a
+// breaker has no effect until an embedder opts in.
+func (rt *Runtime) SetCircuitBreaker(breaker *CircuitBreaker) {
+	rt.breaker = breaker
+}
+
+// checkCircuitBreaker consults rt's installed CircuitBreaker, if any. It
+// returns nil unless FeatureCircuitBreaker is enabled and a breaker is
+// installed.
+func (rt *Runtime) checkCircuitBreaker(reducerID uint32) error {
+	if !rt.featureEnabled(FeatureCircuitBreaker) || rt.breaker == nil {
+		return nil
+	}
+	return rt.breaker.Allow(reducerID)
+}
+
+// recordCircuitBreakerOutcome tells rt's installed CircuitBreaker, if
+// any, whether reducerID's call just succeeded or failed, under the same
+// gating as checkCircuitBreaker.
+func (rt *Runtime) recordCircuitBreakerOutcome(reducerID uint32, callErr error) {
+	if !rt.featureEnabled(FeatureCircuitBreaker) || rt.breaker == nil {
+		return
+	}
+	if callErr != nil {
+		rt.breaker.RecordFailure(reducerID, rt.now())
+		return
+	}
+	rt.breaker.RecordSuccess(reducerID)
+}
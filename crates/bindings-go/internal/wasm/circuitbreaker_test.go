@@ -0,0 +1,122 @@
+package wasm
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTripsAfterThresholdWithinWindow(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 3, Window: time.Minute})
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		b.RecordFailure(7, now)
+		if err := b.Allow(7); err != nil {
+			t.Fatalf("Allow after %d failures: %v, want nil (threshold not reached)", i+1, err)
+		}
+	}
+	b.RecordFailure(7, now)
+
+	err := b.Allow(7)
+	if err == nil {
+		t.Fatal("Allow after reaching threshold: want a *QuarantinedReducerError")
+	}
+	var qerr *QuarantinedReducerError
+	if q, ok := err.(*QuarantinedReducerError); !ok {
+		t.Fatalf("err = %T, want *QuarantinedReducerError", err)
+	} else {
+		qerr = q
+	}
+	if qerr.ID != 7 {
+		t.Fatalf("QuarantinedReducerError.ID = %d, want 7", qerr.ID)
+	}
+}
+
+func TestCircuitBreakerForgetsFailuresOutsideWindow(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, Window: time.Minute})
+	base := time.Now()
+
+	b.RecordFailure(1, base)
+	b.RecordFailure(1, base.Add(2*time.Minute)) // outside the window relative to the first failure
+
+	if err := b.Allow(1); err != nil {
+		t.Fatalf("Allow: %v, want nil (first failure should have aged out)", err)
+	}
+}
+
+func TestCircuitBreakerResetClearsQuarantine(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, Window: time.Minute})
+	now := time.Now()
+
+	b.RecordFailure(1, now)
+	if !b.Quarantined(1) {
+		t.Fatal("expected reducer 1 to be quarantined")
+	}
+
+	b.Reset(1)
+	if b.Quarantined(1) {
+		t.Fatal("expected Reset to clear quarantine")
+	}
+	if err := b.Allow(1); err != nil {
+		t.Fatalf("Allow after Reset: %v", err)
+	}
+}
+
+func TestCircuitBreakerRecordSuccessDoesNotUnquarantine(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, Window: time.Minute})
+	now := time.Now()
+
+	b.RecordFailure(1, now)
+	b.RecordSuccess(1)
+
+	if !b.Quarantined(1) {
+		t.Fatal("RecordSuccess should not clear an already-tripped quarantine; only Reset does")
+	}
+}
+
+func TestCircuitBreakerZeroThresholdNeverTrips(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{})
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		b.RecordFailure(1, now)
+	}
+	if err := b.Allow(1); err != nil {
+		t.Fatalf("Allow with zero FailureThreshold: %v, want nil", err)
+	}
+}
+
+func TestCheckCircuitBreakerNoOpUnlessFeatureEnabled(t *testing.T) {
+	rt := &Runtime{}
+	rt.SetCircuitBreaker(NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, Window: time.Minute}))
+	rt.breaker.RecordFailure(1, time.Now())
+
+	if err := rt.checkCircuitBreaker(1); err != nil {
+		t.Fatalf("checkCircuitBreaker without FeatureCircuitBreaker: %v, want nil", err)
+	}
+}
+
+func TestCheckCircuitBreakerRejectsQuarantinedReducer(t *testing.T) {
+	rt := &Runtime{cfg: Config{Features: map[string]bool{FeatureCircuitBreaker: true}}}
+	rt.SetCircuitBreaker(NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, Window: time.Minute}))
+
+	rt.recordCircuitBreakerOutcome(1, errTrap)
+	if err := rt.checkCircuitBreaker(1); err == nil {
+		t.Fatal("checkCircuitBreaker: want an error after a recorded failure reached the threshold")
+	}
+}
+
+func TestRecordCircuitBreakerOutcomeClearsHistoryOnSuccess(t *testing.T) {
+	rt := &Runtime{cfg: Config{Features: map[string]bool{FeatureCircuitBreaker: true}}}
+	rt.SetCircuitBreaker(NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, Window: time.Minute}))
+
+	rt.recordCircuitBreakerOutcome(1, errTrap)
+	rt.recordCircuitBreakerOutcome(1, nil)
+	rt.recordCircuitBreakerOutcome(1, errTrap)
+
+	if err := rt.checkCircuitBreaker(1); err != nil {
+		t.Fatalf("checkCircuitBreaker: %v, want nil (success should have reset the failure streak)", err)
+	}
+}
+
+var errTrap = errors.New("simulated reducer trap")
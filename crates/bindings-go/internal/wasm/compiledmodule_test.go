@@ -0,0 +1,88 @@
+package wasm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCompileInstantiateGivesEachInstanceItsOwnModule(t *testing.T) {
+	ctx := context.Background()
+	cm, err := Compile(ctx, emptyModule, Config{})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	a, err := cm.Instantiate(ctx, "a")
+	if err != nil {
+		t.Fatalf("Instantiate a: %v", err)
+	}
+	defer a.Close(ctx)
+
+	b, err := cm.Instantiate(ctx, "b")
+	if err != nil {
+		t.Fatalf("Instantiate b: %v", err)
+	}
+	defer b.Close(ctx)
+
+	if a.mod == b.mod {
+		t.Fatal("two instances of the same CompiledModule share one guest module instance")
+	}
+}
+
+func TestInstantiateRejectsDuplicateName(t *testing.T) {
+	ctx := context.Background()
+	cm, err := Compile(ctx, emptyModule, Config{})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	a, err := cm.Instantiate(ctx, "dup")
+	if err != nil {
+		t.Fatalf("Instantiate: %v", err)
+	}
+	defer a.Close(ctx)
+
+	if _, err := cm.Instantiate(ctx, "dup"); err == nil {
+		t.Fatal("expected error instantiating a second guest module under the same name")
+	}
+}
+
+func TestPooledInstanceCloseDoesNotTearDownSharedEngine(t *testing.T) {
+	ctx := context.Background()
+	cm, err := Compile(ctx, emptyModule, Config{})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	a, err := cm.Instantiate(ctx, "a")
+	if err != nil {
+		t.Fatalf("Instantiate a: %v", err)
+	}
+	if err := a.Close(ctx); err != nil {
+		t.Fatalf("a.Close: %v", err)
+	}
+
+	// The shared engine must still be usable after one instance closes.
+	b, err := cm.Instantiate(ctx, "b")
+	if err != nil {
+		t.Fatalf("Instantiate b after a closed: %v", err)
+	}
+	defer b.Close(ctx)
+}
+
+func TestRuntimeCloseOwnsEngineForNewRuntime(t *testing.T) {
+	ctx := context.Background()
+	rt, err := NewRuntime(ctx, emptyModule, Config{})
+	if err != nil {
+		t.Fatalf("NewRuntime: %v", err)
+	}
+	if !rt.ownsEngine {
+		t.Fatal("NewRuntime's Runtime should own its engine")
+	}
+	if err := rt.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+// Package wasm hosts SpacetimeDB wasm modules directly from Go, without a
+// dependency on the Rust standalone server: it exposes a Runtime that
+// compiles a module, wires up the spacetime host ABI, and drives reducer
+// calls against a datastore.
+package wasm
+
+// Config controls how a Runtime instantiates and runs modules. The zero
+// value is a usable default (no experimental features enabled).
+type Config struct {
+	// Features gates experimental host functions/behaviors by name (see
+	// Runtime.Features). Unrecognized names are ignored rather than
+	// rejected, so configs stay forward compatible.
+	Features map[string]bool
+
+	// MaxArgsSize bounds how large a reducer's BSATN-encoded argument
+	// buffer may be. Zero means DefaultMaxArgsSize.
+	MaxArgsSize int
+	// MaxErrorSize bounds how large a reducer error message may be. Zero
+	// means DefaultMaxErrorSize. See DefaultMaxErrorSize's doc comment
+	// for why nothing enforces this yet.
+	MaxErrorSize int
+
+	// Middleware wraps every registered host ABI function, in the order
+	// given (the first entry runs outermost). It must be set here rather
+	// than via Runtime.Use after construction, since NewRuntime builds
+	// and instantiates the host module immediately.
+	Middleware []HostMiddleware
+
+	// Hooks attaches callbacks to Runtime lifecycle events (instantiate,
+	// reducer start/end, hotswap). It must be set here for the same
+	// reason as Middleware: NewRuntime fires OnInstantiate before
+	// returning.
+	Hooks Hooks
+
+	// Limits caps how many tables, indexes, and reducers a module may
+	// register with this Runtime (see RegisterTable, RegisterIndex,
+	// SetReducers), so a placement environment can reject a module
+	// whose resource manifest exceeds what it budgets for that module
+	// before the module ever does real work. The zero value imposes no
+	// limit on any of the three.
+	Limits ResourceLimits
+
+	// Engine selects which wazero execution strategy NewRuntime and
+	// Compile use. The zero value, EngineAuto, is correct for
+	// production; forcing EngineInterpreter or EngineCompiler is for
+	// test suites that want to run the same module against both (see
+	// pkg/harness.RunOnEachEngine).
+	Engine Engine
+}
+
+// NewConfig returns a Config with an initialized, empty Features map.
+func NewConfig() Config {
+	return Config{Features: make(map[string]bool)}
+}
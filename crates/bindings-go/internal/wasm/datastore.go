@@ -0,0 +1,177 @@
+package wasm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+// SetDatastore installs database as the real storage backing rt's
+// datastore host calls and module variable storage. Until this is
+// called, rt's datastore_* host functions behave as the stubs documented
+// in hostfuncs.go, and GetVar/SetVar/DeleteVar all fail with
+// errNoDatastore.
+func (rt *Runtime) SetDatastore(database *db.Database) {
+	rt.datastore = database
+}
+
+// errNoDatastore is returned by GetVar/SetVar/DeleteVar when no Database
+// has been installed via SetDatastore.
+var errNoDatastore = errors.New("wasm: no datastore installed; call SetDatastore first")
+
+// GetVar returns the module-level variable named name, emulating a read
+// of SpacetimeDB's st_var system table — how a module run under this
+// host finds configuration a test (or an earlier reducer call) preseeded
+// before it runs, without going through a full table scan host call.
+// ok is false if name has never been set.
+func (rt *Runtime) GetVar(name string) (value string, ok bool, err error) {
+	if rt.datastore == nil {
+		return "", false, errNoDatastore
+	}
+	return rt.datastore.GetVar(name)
+}
+
+// SetVar stores value under name, emulating a write to st_var.
+func (rt *Runtime) SetVar(name, value string) error {
+	if rt.datastore == nil {
+		return errNoDatastore
+	}
+	return rt.datastore.SetVar(name, value)
+}
+
+// DeleteVar removes name from module variable storage, emulating a
+// delete from st_var. It is not an error to delete a name that was
+// never set.
+func (rt *Runtime) DeleteVar(name string) error {
+	if rt.datastore == nil {
+		return errNoDatastore
+	}
+	return rt.datastore.DeleteVar(name)
+}
+
+// scanTableRows reads every row currently stored for the table registered
+// as tableID (see RegisterTable) and frames them for the
+// bytes_source_read ABI: each row is prefixed with its own little-endian
+// uint32 length, since internal/db treats row values as opaque blobs with
+// no self-describing length once several are concatenated back to back.
+func (rt *Runtime) scanTableRows(tableID uint32) ([]byte, error) {
+	cur, err := rt.datastore.Engine.Scan(rt.tableName(tableID))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close()
+
+	var buf bytes.Buffer
+	var lenPrefix [4]byte
+	for cur.Next() {
+		row := cur.Value()
+		binary.LittleEndian.PutUint32(lenPrefix[:], uint32(len(row)))
+		buf.Write(lenPrefix[:])
+		buf.Write(row)
+	}
+	if err := cur.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// rowCount reports how many rows are currently stored for the table
+// registered as tableID.
+func (rt *Runtime) rowCount(tableID uint32) (uint64, error) {
+	cur, err := rt.datastore.Engine.Scan(rt.tableName(tableID))
+	if err != nil {
+		return 0, err
+	}
+	defer cur.Close()
+
+	var count uint64
+	for cur.Next() {
+		count++
+	}
+	return count, cur.Close()
+}
+
+// rowKey derives the key row should be stored under for indexID, via the
+// IndexKeyFunc an embedder installed with RegisterIndexKey — which a
+// caller with a parsed module definition should populate automatically
+// via SetTables(def.TableSchemas()) rather than calling by hand. With
+// no key func registered for indexID (e.g. no ModuleDef was ever
+// registered), it falls back to keying by the row's own bytes — a
+// placeholder that at least lets two distinct rows coexist and an
+// identical re-insert be idempotent, but silently corrupts a
+// multi-column table's updates since two rows differing only outside
+// the index's columns are treated as different keys instead of the same
+// one.
+func (rt *Runtime) rowKey(indexID uint32, row []byte) ([]byte, error) {
+	if key, ok := rt.indexKeys[indexID]; ok {
+		return key(row)
+	}
+	return row, nil
+}
+
+// putRow writes row into the table registered as tableID, keyed by
+// rowKey(indexID, row).
+//
+// Called during a reducer call (rt.txn set, see CallReducer), it writes
+// through rt.txn instead of straight to the Engine, so Rollback can undo
+// it if the reducer goes on to return an error.
+func (rt *Runtime) putRow(tableID, indexID uint32, row []byte) error {
+	table := rt.tableName(tableID)
+	key, err := rt.rowKey(indexID, row)
+	if err != nil {
+		return err
+	}
+	if rt.txn != nil {
+		return rt.txn.Put(table, key, row)
+	}
+	return rt.datastore.Engine.Put(table, key, row)
+}
+
+// deleteRowRange deletes every row in the table registered as tableID
+// whose whole-row key falls in [lower, upper) — the same placeholder
+// keying putRow uses. Called during a reducer call (rt.txn set), it
+// deletes through rt.txn one key at a time instead of calling
+// Database.DeleteRange directly, so Rollback can undo it; outside one
+// (no reducer call in progress, e.g. a test driving the datastore
+// directly) it falls back to Database.DeleteRange.
+func (rt *Runtime) deleteRowRange(tableID uint32, lower, upper []byte) error {
+	table := rt.tableName(tableID)
+	if rt.txn == nil {
+		_, err := rt.datastore.DeleteRange(table, lower, upper)
+		return err
+	}
+
+	cur, err := rt.datastore.Engine.Scan(table)
+	if err != nil {
+		return err
+	}
+	var keys [][]byte
+	for cur.Next() {
+		key := cur.Key()
+		if lower != nil && bytes.Compare(key, lower) < 0 {
+			continue
+		}
+		if upper != nil && bytes.Compare(key, upper) >= 0 {
+			continue
+		}
+		keys = append(keys, append([]byte(nil), key...))
+	}
+	if err := cur.Close(); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := rt.txn.Delete(table, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// truncateTable deletes every row in the table registered as tableID, the
+// unbounded case of deleteRowRange, so it is undone by Rollback too when
+// called during a reducer call.
+func (rt *Runtime) truncateTable(tableID uint32) error {
+	return rt.deleteRowRange(tableID, nil, nil)
+}
@@ -0,0 +1,162 @@
+package wasm
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func TestGetVarWithoutDatastoreErrors(t *testing.T) {
+	rt := &Runtime{}
+	if _, _, err := rt.GetVar("max_players"); err != errNoDatastore {
+		t.Fatalf("GetVar without a datastore: err = %v, want errNoDatastore", err)
+	}
+	if err := rt.SetVar("max_players", "64"); err != errNoDatastore {
+		t.Fatalf("SetVar without a datastore: err = %v, want errNoDatastore", err)
+	}
+}
+
+func TestSetVarGetVarRoundTripsThroughInstalledDatastore(t *testing.T) {
+	rt := &Runtime{}
+	rt.SetDatastore(db.NewDatabase(db.NewMemEngine()))
+
+	if err := rt.SetVar("max_players", "64"); err != nil {
+		t.Fatalf("SetVar: %v", err)
+	}
+
+	got, ok, err := rt.GetVar("max_players")
+	if err != nil {
+		t.Fatalf("GetVar: %v", err)
+	}
+	if !ok || got != "64" {
+		t.Fatalf("GetVar = (%q, %v), want (64, true)", got, ok)
+	}
+
+	if err := rt.DeleteVar("max_players"); err != nil {
+		t.Fatalf("DeleteVar: %v", err)
+	}
+	if _, ok, err := rt.GetVar("max_players"); err != nil || ok {
+		t.Fatalf("GetVar after DeleteVar = (ok=%v, err=%v), want (false, nil)", ok, err)
+	}
+}
+
+func TestPutRowRowCountScanTableRowsRoundTrip(t *testing.T) {
+	rt := &Runtime{}
+	rt.SetDatastore(db.NewDatabase(db.NewMemEngine()))
+	rt.RegisterTable(1, "player")
+
+	if count, err := rt.rowCount(1); err != nil || count != 0 {
+		t.Fatalf("rowCount before any writes = (%d, %v), want (0, nil)", count, err)
+	}
+
+	rows := [][]byte{[]byte("alice"), []byte("bob")}
+	for _, row := range rows {
+		if err := rt.putRow(1, 0, row); err != nil {
+			t.Fatalf("putRow(%q): %v", row, err)
+		}
+	}
+
+	count, err := rt.rowCount(1)
+	if err != nil {
+		t.Fatalf("rowCount: %v", err)
+	}
+	if count != uint64(len(rows)) {
+		t.Fatalf("rowCount = %d, want %d", count, len(rows))
+	}
+
+	data, err := rt.scanTableRows(1)
+	if err != nil {
+		t.Fatalf("scanTableRows: %v", err)
+	}
+
+	var got [][]byte
+	for off := 0; off < len(data); {
+		n := binary.LittleEndian.Uint32(data[off : off+4])
+		off += 4
+		got = append(got, data[off:off+int(n)])
+		off += int(n)
+	}
+	if len(got) != len(rows) {
+		t.Fatalf("scanTableRows decoded %d rows, want %d", len(got), len(rows))
+	}
+}
+
+func TestPutRowKeepsRowsInSeparateTables(t *testing.T) {
+	rt := &Runtime{}
+	rt.SetDatastore(db.NewDatabase(db.NewMemEngine()))
+	rt.RegisterTable(1, "player")
+	rt.RegisterTable(2, "item")
+
+	if err := rt.putRow(1, 0, []byte("a player row")); err != nil {
+		t.Fatalf("putRow: %v", err)
+	}
+
+	if count, err := rt.rowCount(2); err != nil || count != 0 {
+		t.Fatalf("rowCount(item) = (%d, %v), want (0, nil) since only player was written to", count, err)
+	}
+}
+
+// firstFieldKey extracts a row's first comma-separated field as its key,
+// a stand-in for the real column-decoding a schema-aware IndexKeyFunc
+// would do (see internal/db/index_test.go's byNameKey for the same
+// pattern against internal/db directly).
+func firstFieldKey(row []byte) ([]byte, error) {
+	for i, b := range row {
+		if b == ',' {
+			return row[:i], nil
+		}
+	}
+	return row, nil
+}
+
+func TestPutRowWithRegisteredIndexKeyUpdatesByExtractedKeyNotWholeRow(t *testing.T) {
+	rt := &Runtime{}
+	rt.SetDatastore(db.NewDatabase(db.NewMemEngine()))
+	rt.RegisterTable(1, "player")
+	rt.RegisterIndexKey(1, firstFieldKey)
+
+	if err := rt.putRow(1, 1, []byte("1,alice")); err != nil {
+		t.Fatalf("putRow: %v", err)
+	}
+	// Same key ("1") with a different rest-of-row: without real index
+	// keying this would land under a second, distinct whole-row key
+	// instead of updating the first row.
+	if err := rt.putRow(1, 1, []byte("1,alice-renamed")); err != nil {
+		t.Fatalf("putRow: %v", err)
+	}
+
+	count, err := rt.rowCount(1)
+	if err != nil {
+		t.Fatalf("rowCount: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("rowCount = %d, want 1: the second putRow should have updated the row keyed \"1\", not inserted a second one", count)
+	}
+
+	row, ok, err := rt.datastore.Engine.Get("player", []byte("1"))
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !ok || string(row) != "1,alice-renamed" {
+		t.Fatalf("Get(%q) = (%q, %v), want (\"1,alice-renamed\", true)", "1", row, ok)
+	}
+}
+
+func TestPutRowWithoutRegisteredIndexKeyFallsBackToWholeRow(t *testing.T) {
+	rt := &Runtime{}
+	rt.SetDatastore(db.NewDatabase(db.NewMemEngine()))
+	rt.RegisterTable(1, "player")
+
+	if err := rt.putRow(1, 1, []byte("1,alice")); err != nil {
+		t.Fatalf("putRow: %v", err)
+	}
+
+	row, ok, err := rt.datastore.Engine.Get("player", []byte("1,alice"))
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !ok || string(row) != "1,alice" {
+		t.Fatalf("Get(%q) = (%q, %v), want (\"1,alice\", true) since no IndexKeyFunc was registered for index 1", "1,alice", row, ok)
+	}
+}
@@ -0,0 +1,67 @@
+package wasm
+
+import (
+	"sort"
+	"sync"
+)
+
+// deprecatedReplacedBy maps a deprecated ABI import's name to the
+// current import a module should migrate to instead. It is the source
+// of truth for which imports buildHostModule keeps shimming, deliberately
+// kept separate from abiManifest since these are not part of the
+// current ABI surface a new module should target.
+var deprecatedReplacedBy = map[string]string{
+	"datastore_btree_scan_bsatn": "datastore_table_scan_bsatn",
+	"byte_buffer_source_get_len": "bytes_source_read",
+}
+
+// deprecatedUsage counts how many times each deprecated ABI import has
+// been called by the guest, guarded by its own mutex since it is
+// written from host function calls that may run concurrently.
+type deprecatedUsage struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+// recordDeprecatedUse increments name's call counter. Every deprecated
+// shim in hostfuncs.go calls this once per guest call, before forwarding
+// to its current-ABI implementation.
+func (rt *Runtime) recordDeprecatedUse(name string) {
+	rt.deprecated.mu.Lock()
+	defer rt.deprecated.mu.Unlock()
+	if rt.deprecated.counts == nil {
+		rt.deprecated.counts = make(map[string]uint64)
+	}
+	rt.deprecated.counts[name]++
+}
+
+// DeprecatedCall reports one deprecated ABI import's usage, for
+// Runtime.Analyze.
+type DeprecatedCall struct {
+	Name  string
+	Count uint64
+	// ReplacedBy is the current ABI import a module should migrate to
+	// instead of Name.
+	ReplacedBy string
+}
+
+// Analyze reports how many times rt's module has called each deprecated
+// ABI import, so an embedder (or `spacetimedb check`, see
+// cmd/spacetimedb) can warn a module author about reliance on an import
+// a future host will drop, before that removal actually breaks them.
+// Only imports actually called are included; it is safe to call on a
+// Runtime that has never touched a deprecated import, returning nil.
+func (rt *Runtime) Analyze() []DeprecatedCall {
+	rt.deprecated.mu.Lock()
+	defer rt.deprecated.mu.Unlock()
+
+	if len(rt.deprecated.counts) == 0 {
+		return nil
+	}
+	calls := make([]DeprecatedCall, 0, len(rt.deprecated.counts))
+	for name, count := range rt.deprecated.counts {
+		calls = append(calls, DeprecatedCall{Name: name, Count: count, ReplacedBy: deprecatedReplacedBy[name]})
+	}
+	sort.Slice(calls, func(i, j int) bool { return calls[i].Name < calls[j].Name })
+	return calls
+}
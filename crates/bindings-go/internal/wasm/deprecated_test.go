@@ -0,0 +1,28 @@
+package wasm
+
+import "testing"
+
+func TestAnalyzeReportsNoUsageByDefault(t *testing.T) {
+	rt := &Runtime{}
+	if calls := rt.Analyze(); calls != nil {
+		t.Fatalf("Analyze() = %v, want nil for a Runtime that never called a deprecated import", calls)
+	}
+}
+
+func TestAnalyzeCountsAndSortsDeprecatedCalls(t *testing.T) {
+	rt := &Runtime{}
+	rt.recordDeprecatedUse("byte_buffer_source_get_len")
+	rt.recordDeprecatedUse("datastore_btree_scan_bsatn")
+	rt.recordDeprecatedUse("datastore_btree_scan_bsatn")
+
+	calls := rt.Analyze()
+	if len(calls) != 2 {
+		t.Fatalf("Analyze() = %v, want 2 entries", calls)
+	}
+	if calls[0].Name != "byte_buffer_source_get_len" || calls[0].Count != 1 || calls[0].ReplacedBy != "bytes_source_read" {
+		t.Errorf("calls[0] = %+v", calls[0])
+	}
+	if calls[1].Name != "datastore_btree_scan_bsatn" || calls[1].Count != 2 || calls[1].ReplacedBy != "datastore_table_scan_bsatn" {
+		t.Errorf("calls[1] = %+v", calls[1])
+	}
+}
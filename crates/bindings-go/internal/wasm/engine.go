@@ -0,0 +1,55 @@
+package wasm
+
+import (
+	"context"
+
+	"github.com/tetratelabs/wazero"
+)
+
+// Engine selects which of wazero's two execution strategies a Runtime
+// compiles and runs a module under: the ahead-of-time compiler (fast,
+// but only available on platforms wazero has a compiler backend for) or
+// the pure-Go interpreter (slower, but runs anywhere Go does). It exists
+// so an integration test suite can run the same module and assertions
+// against both (see pkg/harness.RunOnEachEngine) and catch a bug that
+// only one of the two engines' code paths triggers, instead of a
+// production host silently always picking the same one Config's zero
+// value happens to resolve to.
+type Engine int
+
+const (
+	// EngineAuto lets wazero pick: the compiler where it has a backend
+	// for the current GOOS/GOARCH, the interpreter otherwise. This is
+	// wazero.NewRuntime's own default behavior.
+	EngineAuto Engine = iota
+	// EngineInterpreter forces the pure-Go interpreter.
+	EngineInterpreter
+	// EngineCompiler forces the ahead-of-time compiler. Compiling a
+	// module under a Runtime configured this way panics (inside wazero
+	// itself) on a platform with no compiler backend; EngineAuto is the
+	// safe default for code that must run anywhere.
+	EngineCompiler
+)
+
+func (e Engine) String() string {
+	switch e {
+	case EngineInterpreter:
+		return "interpreter"
+	case EngineCompiler:
+		return "compiler"
+	default:
+		return "auto"
+	}
+}
+
+// newWazeroRuntime returns a wazero.Runtime configured per engine.
+func newWazeroRuntime(ctx context.Context, engine Engine) wazero.Runtime {
+	switch engine {
+	case EngineInterpreter:
+		return wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfigInterpreter())
+	case EngineCompiler:
+		return wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfigCompiler())
+	default:
+		return wazero.NewRuntime(ctx)
+	}
+}
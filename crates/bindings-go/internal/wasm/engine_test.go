@@ -0,0 +1,36 @@
+package wasm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEngineString(t *testing.T) {
+	cases := []struct {
+		engine Engine
+		want   string
+	}{
+		{EngineAuto, "auto"},
+		{EngineInterpreter, "interpreter"},
+		{EngineCompiler, "compiler"},
+		{Engine(99), "auto"},
+	}
+	for _, c := range cases {
+		if got := c.engine.String(); got != c.want {
+			t.Errorf("Engine(%d).String() = %q, want %q", c.engine, got, c.want)
+		}
+	}
+}
+
+func TestNewWazeroRuntimeHonorsEngineSelection(t *testing.T) {
+	ctx := context.Background()
+	for _, engine := range []Engine{EngineAuto, EngineInterpreter, EngineCompiler} {
+		rt := newWazeroRuntime(ctx, engine)
+		if rt == nil {
+			t.Fatalf("newWazeroRuntime(%s) returned nil", engine)
+		}
+		if err := rt.Close(ctx); err != nil {
+			t.Errorf("closing %s engine: %v", engine, err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package wasm
+
+import (
+	"reflect"
+	"sync"
+)
+
+// FaultInjector is a HostMiddleware source that fails configured host ABI
+// calls on a schedule, so a module's error-handling paths (retry, abort,
+// log-and-continue) can be exercised without a real underlying failure.
+// The zero value is not usable; construct with NewFaultInjector.
+type FaultInjector struct {
+	mu    sync.Mutex
+	rules map[string]*faultRule
+}
+
+type faultRule struct {
+	every   int
+	count   int
+	results []interface{}
+}
+
+// NewFaultInjector returns an empty FaultInjector; configure it with
+// Inject before wiring Middleware into Config.Middleware.
+func NewFaultInjector() *FaultInjector {
+	return &FaultInjector{rules: map[string]*faultRule{}}
+}
+
+// Inject configures every-th call to the host ABI function named fn (its
+// wazero export name, e.g. "datastore_update_bsatn") to short-circuit and
+// return results instead of running the real host function. results must
+// match the target function's return values positionally, both in count
+// and type (e.g. a single uint32 status code for most datastore_*
+// functions) — a mismatch surfaces as a reflect panic the first time the
+// rule fires, since there is no way to validate it against the target
+// function's signature until wrapHostFunc wraps it.
+//
+// every must be >= 1: every == 1 fails every call, every == 3 fails every
+// third. Calling Inject again for the same fn replaces its rule and
+// resets its call count.
+func (f *FaultInjector) Inject(fn string, every int, results ...interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules[fn] = &faultRule{every: every, results: results}
+}
+
+// Clear removes any configured rule for fn, so subsequent calls run
+// normally.
+func (f *FaultInjector) Clear(fn string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.rules, fn)
+}
+
+// trigger reports whether the call to fn about to happen should be
+// faulted, returning the reflect.Values to substitute if so.
+func (f *FaultInjector) trigger(fn string) ([]reflect.Value, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rule, ok := f.rules[fn]
+	if !ok {
+		return nil, false
+	}
+	rule.count++
+	if rule.count%rule.every != 0 {
+		return nil, false
+	}
+	out := make([]reflect.Value, len(rule.results))
+	for i, r := range rule.results {
+		out[i] = reflect.ValueOf(r)
+	}
+	return out, true
+}
+
+// Middleware returns a HostMiddleware that applies f's configured rules
+// ahead of the real host function, so a faulted call never reaches it.
+// Put it first in Config.Middleware if other middleware (e.g. metrics)
+// should still observe the substituted result as if it were real.
+func (f *FaultInjector) Middleware() HostMiddleware {
+	return func(next HostHandler) HostHandler {
+		return func(name string, in []reflect.Value) []reflect.Value {
+			if out, ok := f.trigger(name); ok {
+				return out
+			}
+			return next(name, in)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package wasm
+
+import "testing"
+
+func TestFaultInjectorFailsEveryNthCall(t *testing.T) {
+	fi := NewFaultInjector()
+	fi.Inject("datastore_update_bsatn", 3, StatusBufferTooSmall)
+
+	rt := &Runtime{middleware: []HostMiddleware{fi.Middleware()}}
+	wrapped := rt.wrapHostFunc("datastore_update_bsatn", func(uint32) uint32 { return 0 }).(func(uint32) uint32)
+
+	var got []uint32
+	for i := 0; i < 6; i++ {
+		got = append(got, wrapped(0))
+	}
+
+	want := []uint32{0, 0, StatusBufferTooSmall, 0, 0, StatusBufferTooSmall}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("call %d = %d, want %d (got %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestFaultInjectorOnlyAffectsConfiguredFunction(t *testing.T) {
+	fi := NewFaultInjector()
+	fi.Inject("datastore_update_bsatn", 1, StatusBufferTooSmall)
+
+	rt := &Runtime{middleware: []HostMiddleware{fi.Middleware()}}
+	scan := rt.wrapHostFunc("datastore_table_scan_bsatn", func(uint32) uint32 { return 42 }).(func(uint32) uint32)
+
+	if got := scan(0); got != 42 {
+		t.Fatalf("unrelated function was faulted: got %d, want 42", got)
+	}
+}
+
+func TestFaultInjectorClearStopsInjecting(t *testing.T) {
+	fi := NewFaultInjector()
+	fi.Inject("datastore_update_bsatn", 1, StatusBufferTooSmall)
+	fi.Clear("datastore_update_bsatn")
+
+	rt := &Runtime{middleware: []HostMiddleware{fi.Middleware()}}
+	wrapped := rt.wrapHostFunc("datastore_update_bsatn", func(uint32) uint32 { return 0 }).(func(uint32) uint32)
+
+	if got := wrapped(0); got != 0 {
+		t.Fatalf("Clear did not stop injection: got %d, want 0", got)
+	}
+}
+
+func TestFaultInjectorReplaceRuleResetsCount(t *testing.T) {
+	fi := NewFaultInjector()
+	fi.Inject("datastore_update_bsatn", 2, StatusBufferTooSmall)
+
+	rt := &Runtime{middleware: []HostMiddleware{fi.Middleware()}}
+	wrapped := rt.wrapHostFunc("datastore_update_bsatn", func(uint32) uint32 { return 0 }).(func(uint32) uint32)
+	wrapped(0) // count = 1, not a multiple of 2
+
+	fi.Inject("datastore_update_bsatn", 2, StatusBufferTooSmall) // resets count to 0
+	if got := wrapped(0); got != 0 {
+		t.Fatalf("re-Inject should reset the call count, got %d", got)
+	}
+}
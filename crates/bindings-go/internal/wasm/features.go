@@ -0,0 +1,51 @@
+package wasm
+
+// Known feature names Config.Features may enable. Setting an
+// unrecognized name is not an error (see Config.Features), so this list
+// is not exhaustive validation, just the names the host currently
+// recognizes or has reserved for upcoming work.
+const (
+	// FeatureRLS gates AuthzHooks enforcement: AuthorizeRead/
+	// AuthorizeWrite (see authz.go) are only consulted when this
+	// feature is enabled, so installing hooks doesn't change behavior
+	// until an embedder opts in.
+	FeatureRLS = "rls"
+	// FeatureV9BSATN is reserved for the next BSATN wire revision; no
+	// host behavior depends on it yet.
+	FeatureV9BSATN = "v9-bsatn"
+	// FeatureConcurrentExec is reserved for running multiple reducer
+	// calls against the same module concurrently instead of today's
+	// one-at-a-time execution; no host behavior depends on it yet.
+	FeatureConcurrentExec = "concurrent-exec"
+	// FeatureRateLimit gates Limiter enforcement installed via
+	// SetRateLimiter (see ratelimit.go): CallReducer only consults it
+	// when this feature is enabled.
+	FeatureRateLimit = "rate-limit"
+	// FeatureTxnMetadata gates the txn_metadata_bsatn host call (see
+	// txnmetadata.go): it is test-only diagnostic surface, so it stays
+	// off by default and a module built against a host with it disabled
+	// must treat a zero source ID as "unavailable", not "empty".
+	FeatureTxnMetadata = "txn-metadata"
+	// FeatureCircuitBreaker gates CircuitBreaker enforcement installed
+	// via SetCircuitBreaker (see circuitbreaker.go): CallReducer only
+	// consults it, and only records outcomes against it, when this
+	// feature is enabled.
+	FeatureCircuitBreaker = "circuit-breaker"
+)
+
+// Features reports the set of feature names enabled on rt's Config.
+func (rt *Runtime) Features() map[string]bool {
+	out := make(map[string]bool, len(rt.cfg.Features))
+	for name, enabled := range rt.cfg.Features {
+		if enabled {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+// featureEnabled reports whether name is enabled on rt's Config. A nil
+// or absent-key Features map behaves as "nothing enabled".
+func (rt *Runtime) featureEnabled(name string) bool {
+	return rt.cfg.Features[name]
+}
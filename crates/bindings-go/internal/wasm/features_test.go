@@ -0,0 +1,34 @@
+package wasm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+)
+
+func TestFeaturesReportsOnlyEnabled(t *testing.T) {
+	rt := &Runtime{cfg: Config{Features: map[string]bool{FeatureRLS: true, "unused": false}}}
+	got := rt.Features()
+	if len(got) != 1 || !got[FeatureRLS] {
+		t.Fatalf("Features() = %v, want only %q enabled", got, FeatureRLS)
+	}
+}
+
+func TestAuthorizeIsNoOpWithoutRLSFeature(t *testing.T) {
+	rt := &Runtime{}
+	called := false
+	rt.SetAuthzHooks(AuthzHooks{
+		AuthorizeRead: func(id identity.Identity, table string) error {
+			called = true
+			return fmt.Errorf("should not be called")
+		},
+	})
+	if err := rt.authorize(context.Background(), 1, OpRead); err != nil {
+		t.Fatalf("authorize without FeatureRLS should be a no-op: %v", err)
+	}
+	if called {
+		t.Fatal("hook should not have been called without FeatureRLS enabled")
+	}
+}
@@ -0,0 +1,29 @@
+package wasm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func TestNewRuntimeDefaultsToCurrentFormat(t *testing.T) {
+	rt, err := NewRuntime(context.Background(), emptyModule, Config{})
+	if err != nil {
+		t.Fatalf("NewRuntime: %v", err)
+	}
+	defer rt.Close(context.Background())
+
+	if got := rt.Format(); got != bsatn.CurrentFormat {
+		t.Fatalf("Format() = %s, want %s", got, bsatn.CurrentFormat)
+	}
+}
+
+func TestSetFormatOverridesRuntimeFormat(t *testing.T) {
+	rt := &Runtime{format: bsatn.CurrentFormat}
+	rt.SetFormat(bsatn.Format(7))
+
+	if got := rt.Format(); got != bsatn.Format(7) {
+		t.Fatalf("Format() = %s, want Format(7)", got)
+	}
+}
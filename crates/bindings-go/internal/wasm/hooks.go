@@ -0,0 +1,107 @@
+package wasm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tetratelabs/wazero"
+)
+
+// Hooks are optional callbacks a Runtime invokes at key lifecycle points,
+// letting an embedder attach custom logic — warming caches on
+// instantiate, flushing metrics on reducer end, invalidating derived
+// state on hotswap — without forking the host code. A nil hook is
+// skipped. Configure these via Config.Hooks; like Middleware, they must
+// be set there rather than through a post-construction setter, since
+// NewRuntime and CompiledModule.Instantiate both fire OnInstantiate
+// before returning.
+type Hooks struct {
+	// OnInstantiate runs once the guest module is ready to call: once
+	// for the Runtime's initial construction (NewRuntime or
+	// CompiledModule.Instantiate), and again after every successful
+	// Hotswap.
+	OnInstantiate func(rt *Runtime)
+
+	// OnReducerStart runs immediately before CallReducer invokes the
+	// guest's __call_reducer__ export, after argument size, rate limit,
+	// and manifest validation have already passed.
+	OnReducerStart func(rt *Runtime, reducerID uint32, args []byte)
+
+	// OnReducerEnd runs after CallReducer finishes, whether it succeeded
+	// or not; err is the same error CallReducer is about to return (nil
+	// on success). It does not run if CallReducer rejected the call
+	// before OnReducerStart (see CallReducer).
+	OnReducerEnd func(rt *Runtime, reducerID uint32, args []byte, err error)
+
+	// OnHotswap runs immediately before Hotswap tears down the running
+	// guest instance to replace it with one compiled from newWasmBytes,
+	// while the outgoing module is still live — the place to flush or
+	// invalidate state derived from it.
+	OnHotswap func(rt *Runtime, newWasmBytes []byte)
+}
+
+func (h Hooks) fireInstantiate(rt *Runtime) {
+	if h.OnInstantiate != nil {
+		h.OnInstantiate(rt)
+	}
+}
+
+func (h Hooks) fireReducerStart(rt *Runtime, reducerID uint32, args []byte) {
+	if h.OnReducerStart != nil {
+		h.OnReducerStart(rt, reducerID, args)
+	}
+}
+
+func (h Hooks) fireReducerEnd(rt *Runtime, reducerID uint32, args []byte, err error) {
+	if h.OnReducerEnd != nil {
+		h.OnReducerEnd(rt, reducerID, args, err)
+	}
+}
+
+func (h Hooks) fireHotswap(rt *Runtime, newWasmBytes []byte) {
+	if h.OnHotswap != nil {
+		h.OnHotswap(rt, newWasmBytes)
+	}
+}
+
+// Hotswap replaces rt's running guest instance in place with one freshly
+// compiled from newWasmBytes, keeping rt's Go-side bookkeeping (authz
+// hooks, table names, rate limiter, middleware, hooks themselves) intact
+// across the swap. It fires OnHotswap before tearing down the outgoing
+// instance and OnInstantiate once the replacement is live.
+//
+// Hotswap only affects rt's own guest instance: for a Runtime returned by
+// CompiledModule.Instantiate, every other instance sharing that
+// CompiledModule's engine keeps running its own (unswapped) module.
+func (rt *Runtime) Hotswap(ctx context.Context, newWasmBytes []byte) error {
+	rt.hooks.fireHotswap(rt, newWasmBytes)
+
+	compiled, err := rt.engine.CompileModule(ctx, newWasmBytes)
+	if err != nil {
+		return fmt.Errorf("wasm: hotswap: compile module: %w", err)
+	}
+
+	name := rt.mod.Name()
+	oldCompiled := rt.compiled
+	if err := rt.mod.Close(ctx); err != nil {
+		return fmt.Errorf("wasm: hotswap: closing outgoing instance: %w", err)
+	}
+
+	mod, err := rt.engine.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().WithName(name))
+	if err != nil {
+		return fmt.Errorf("wasm: hotswap: instantiating replacement: %w", err)
+	}
+	rt.compiled = compiled
+	rt.mod = mod
+
+	if rt.ownsEngine {
+		// rt is the sole consumer of its engine, so nothing else can
+		// still be referencing the code we just replaced.
+		if err := oldCompiled.Close(ctx); err != nil {
+			return fmt.Errorf("wasm: hotswap: closing outgoing compiled module: %w", err)
+		}
+	}
+
+	rt.hooks.fireInstantiate(rt)
+	return nil
+}
@@ -0,0 +1,146 @@
+package wasm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewRuntimeFiresOnInstantiate(t *testing.T) {
+	ctx := context.Background()
+	var got *Runtime
+	rt, err := NewRuntime(ctx, emptyModule, Config{Hooks: Hooks{
+		OnInstantiate: func(rt *Runtime) { got = rt },
+	}})
+	if err != nil {
+		t.Fatalf("NewRuntime: %v", err)
+	}
+	defer rt.Close(ctx)
+
+	if got != rt {
+		t.Fatal("OnInstantiate did not fire with the constructed Runtime")
+	}
+}
+
+func TestCompileInstantiateFiresOnInstantiatePerInstance(t *testing.T) {
+	ctx := context.Background()
+	var names []string
+	cm, err := Compile(ctx, emptyModule, Config{Hooks: Hooks{
+		OnInstantiate: func(rt *Runtime) { names = append(names, rt.mod.Name()) },
+	}})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	a, err := cm.Instantiate(ctx, "a")
+	if err != nil {
+		t.Fatalf("Instantiate a: %v", err)
+	}
+	defer a.Close(ctx)
+	b, err := cm.Instantiate(ctx, "b")
+	if err != nil {
+		t.Fatalf("Instantiate b: %v", err)
+	}
+	defer b.Close(ctx)
+
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Fatalf("OnInstantiate fired for %v, want [a b]", names)
+	}
+}
+
+func TestCallReducerFiresStartAndEndHooksEvenOnFailure(t *testing.T) {
+	ctx := context.Background()
+	var startedID, endedID uint32
+	var endErr error
+	rt, err := NewRuntime(ctx, emptyModule, Config{Hooks: Hooks{
+		OnReducerStart: func(_ *Runtime, reducerID uint32, _ []byte) { startedID = reducerID },
+		OnReducerEnd: func(_ *Runtime, reducerID uint32, _ []byte, err error) {
+			endedID = reducerID
+			endErr = err
+		},
+	}})
+	if err != nil {
+		t.Fatalf("NewRuntime: %v", err)
+	}
+	defer rt.Close(ctx)
+
+	// emptyModule exports nothing, so the reducer call itself fails, but
+	// both hooks must still have fired around it.
+	if err := rt.CallReducer(ctx, 7, nil); err == nil {
+		t.Fatal("expected CallReducer to fail against emptyModule")
+	}
+
+	if startedID != 7 {
+		t.Errorf("OnReducerStart saw reducerID %d, want 7", startedID)
+	}
+	if endedID != 7 {
+		t.Errorf("OnReducerEnd saw reducerID %d, want 7", endedID)
+	}
+	if endErr == nil {
+		t.Error("OnReducerEnd saw a nil error, want the CallReducer failure")
+	}
+}
+
+func TestHotswapReplacesModuleAndFiresHooks(t *testing.T) {
+	ctx := context.Background()
+	var hotswapped, reinstantiated bool
+	rt, err := NewRuntime(ctx, emptyModule, Config{})
+	if err != nil {
+		t.Fatalf("NewRuntime: %v", err)
+	}
+	defer rt.Close(ctx)
+
+	rt.hooks = Hooks{
+		OnHotswap:     func(_ *Runtime, _ []byte) { hotswapped = true },
+		OnInstantiate: func(_ *Runtime) { reinstantiated = true },
+	}
+
+	oldMod := rt.mod
+	if err := rt.Hotswap(ctx, emptyModule); err != nil {
+		t.Fatalf("Hotswap: %v", err)
+	}
+
+	if !hotswapped {
+		t.Error("OnHotswap did not fire")
+	}
+	if !reinstantiated {
+		t.Error("OnInstantiate did not fire after Hotswap")
+	}
+	if rt.mod == oldMod {
+		t.Error("Hotswap did not replace the guest module instance")
+	}
+
+	// The new instance must still be usable for a subsequent Hotswap.
+	if err := rt.Hotswap(ctx, emptyModule); err != nil {
+		t.Fatalf("second Hotswap: %v", err)
+	}
+}
+
+func TestHotswapLeavesOtherPooledInstancesRunning(t *testing.T) {
+	ctx := context.Background()
+	cm, err := Compile(ctx, emptyModule, Config{})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	a, err := cm.Instantiate(ctx, "a")
+	if err != nil {
+		t.Fatalf("Instantiate a: %v", err)
+	}
+	defer a.Close(ctx)
+	b, err := cm.Instantiate(ctx, "b")
+	if err != nil {
+		t.Fatalf("Instantiate b: %v", err)
+	}
+	defer b.Close(ctx)
+
+	if err := a.Hotswap(ctx, emptyModule); err != nil {
+		t.Fatalf("Hotswap a: %v", err)
+	}
+
+	// b must still be live and usable after a's swap.
+	if b.mod.Name() != "b" {
+		t.Fatalf("b.mod.Name() = %q, want %q", b.mod.Name(), "b")
+	}
+}
@@ -0,0 +1,230 @@
+package wasm
+
+import (
+	"context"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// abiModule is the wasm import module name modules built against this host
+// link their host calls under, matching the "spacetime_X.0" versioning
+// convention used by the Rust bindings-sys ABI.
+const abiModule = "spacetime_10.0"
+
+// buildHostModule registers the spacetime_10.0 host import module on r.
+// The datastore_* host functions are real once a caller installs a
+// *db.Database via Runtime.SetDatastore, and fall back to their original
+// stub behavior otherwise (see ABIReport for exactly what each one still
+// leaves out); registering them all up front lets any conforming module
+// link and instantiate regardless of whether a datastore has been
+// installed yet. Every function is wrapped with rt's middleware chain
+// (see Runtime.Use) before being handed to wazero, so it must be called
+// after any Use calls that should apply.
+func (rt *Runtime) buildHostModule(ctx context.Context, r wazero.Runtime) error {
+	b := r.NewHostModuleBuilder(abiModule)
+
+	register := func(name string, fn interface{}) {
+		b.NewFunctionBuilder().
+			WithFunc(rt.wrapHostFunc(name, fn)).
+			Export(name)
+	}
+
+	register("_console_log", rt.hostConsoleLog)
+	register("datastore_table_scan_bsatn", rt.hostDatastoreTableScanBsatn)
+	register("datastore_table_row_count", rt.hostDatastoreTableRowCount)
+	register("datastore_update_bsatn", rt.hostDatastoreUpdateBsatn)
+	register("datastore_delete_range_bsatn", rt.hostDatastoreDeleteRangeBsatn)
+	register("datastore_table_truncate", rt.hostDatastoreTableTruncate)
+	register("bytes_source_read", rt.hostBytesSourceRead)
+	register("txn_metadata_bsatn", rt.hostTxnMetadata)
+
+	// Deprecated ABI shims: kept working for modules compiled against an
+	// older host, but not part of the current ABI surface (see
+	// deprecated.go and abiManifest, which deliberately omits these).
+	register("datastore_btree_scan_bsatn", rt.hostDatastoreBtreeScanBsatnDeprecated)
+	register("byte_buffer_source_get_len", rt.hostByteBufferSourceGetLenDeprecated)
+
+	_, err := b.Instantiate(ctx)
+	return err
+}
+
+// hostConsoleLog is the console_log host call: it filters against the
+// Runtime's configured log level (see Runtime.SetLogLevel and
+// Runtime.SetModuleLogLevel) and, if enabled, formats the message to the
+// Runtime's log output. filenamePtr/filenameLen/lineNumber are accepted
+// to match the ABI but not yet surfaced in the formatted output.
+func (rt *Runtime) hostConsoleLog(ctx context.Context, mod api.Module, level, targetPtr, targetLen, filenamePtr, filenameLen, lineNumber, msgPtr, msgLen uint32) {
+	rt.consoleLog(mod, level, targetPtr, targetLen, msgPtr, msgLen)
+}
+
+// scanDenied is returned in place of an iterator ID when an AuthzHooks
+// check denies the read. It is out of band with every real iterator ID
+// this stub can produce (which are always 0, since it never creates a
+// non-empty iterator); the real datastore-backed implementation must
+// keep reserving it once iterator IDs are no longer always 0.
+const scanDenied uint32 = 0xFFFFFFFF
+
+// hostDatastoreTableScanBsatn opens a byteSource (see bytesource.go)
+// streaming every row currently stored for tableID and returns its source
+// ID, for the guest to pull via repeated bytes_source_read calls; outPtr
+// is accepted to match the ABI but unused, since the source ID is
+// returned directly the same way hostTxnMetadata's is. Absent a denial,
+// it reports an empty iterator (source ID 0, indistinguishable from an
+// exhausted one) if no datastore has been installed via SetDatastore, or
+// if the scan itself fails.
+func (rt *Runtime) hostDatastoreTableScanBsatn(ctx context.Context, mod api.Module, tableID uint32, outPtr uint32) uint32 {
+	if err := rt.authorize(ctx, tableID, OpRead); err != nil {
+		return scanDenied
+	}
+	if rt.datastore == nil {
+		return 0
+	}
+	data, err := rt.scanTableRows(tableID)
+	if err != nil {
+		return 0
+	}
+	return rt.sources.open(data)
+}
+
+// hostDatastoreTableRowCount reports how many rows tableID currently
+// holds, or 0 if no datastore has been installed via SetDatastore.
+func (rt *Runtime) hostDatastoreTableRowCount(ctx context.Context, mod api.Module, tableID uint32) uint64 {
+	if rt.datastore == nil {
+		return 0
+	}
+	count, err := rt.rowCount(tableID)
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// Status codes returned by ABI calls that report success/failure as a
+// single uint32. statusRowTooLarge covers MaxRowSize rejection;
+// statusUnauthorized covers an AuthzHooks denial; StatusBufferTooSmall is
+// exported for fault injection (see FaultInjector) since it's not
+// currently returned by any real host function but is a status real
+// SpacetimeDB modules are expected to handle.
+const (
+	statusRowTooLarge    uint32 = 1
+	statusUnauthorized   uint32 = 2
+	StatusBufferTooSmall uint32 = 3
+)
+
+// hostDatastoreUpdateBsatn enforces MaxRowSize and runs AuthorizeWrite, as
+// it always has, and — once a datastore is installed via SetDatastore —
+// writes the row through to it, undone along with the rest of the current
+// CallReducer's writes if the reducer goes on to return an error (see
+// Runtime.putRow and CallReducer). indexID selects the key the row is
+// stored under via whatever IndexKeyFunc is registered for it with
+// RegisterIndexKey — a caller with a parsed module definition gets this
+// populated automatically via SetTables(def.TableSchemas()), which
+// derives each index's key from the table's schema instead of keying by
+// the whole row; an indexID with no key registered (e.g. no ModuleDef
+// was ever registered) falls back to keying by the row's own bytes,
+// which still corrupts a multi-column table's updates (see
+// Runtime.rowKey).
+func (rt *Runtime) hostDatastoreUpdateBsatn(ctx context.Context, mod api.Module, tableID, indexID, rowPtr, rowLen uint32) uint32 {
+	if err := rt.authorize(ctx, tableID, OpWrite); err != nil {
+		return statusUnauthorized
+	}
+	if rowLen > MaxRowSize {
+		return statusRowTooLarge
+	}
+	if rt.datastore == nil {
+		return 0
+	}
+	row, ok := mod.Memory().Read(rowPtr, rowLen)
+	if !ok {
+		return 0
+	}
+	if err := rt.putRow(tableID, indexID, row); err != nil {
+		return 0
+	}
+	return 0
+}
+
+// hostDatastoreDeleteRangeBsatn enforces AuthorizeWrite, as it always has,
+// and — once a datastore is installed via SetDatastore — deletes every row
+// in [lower, upper) (see Runtime.deleteRowRange), undone along with the
+// rest of the current CallReducer's writes if the reducer goes on to
+// return an error. indexID is accepted to match the ABI but unused, for
+// the same reason hostDatastoreUpdateBsatn's is: the range bounds are
+// compared against whole-row keys rather than a real index's ordered key.
+func (rt *Runtime) hostDatastoreDeleteRangeBsatn(ctx context.Context, mod api.Module, tableID, indexID, lowerPtr, lowerLen, upperPtr, upperLen uint32) uint32 {
+	if err := rt.authorize(ctx, tableID, OpWrite); err != nil {
+		return statusUnauthorized
+	}
+	if rt.datastore == nil {
+		return 0
+	}
+	lower, ok := mod.Memory().Read(lowerPtr, lowerLen)
+	if !ok {
+		return 0
+	}
+	upper, ok := mod.Memory().Read(upperPtr, upperLen)
+	if !ok {
+		return 0
+	}
+	if err := rt.deleteRowRange(tableID, lower, upper); err != nil {
+		return 0
+	}
+	return 0
+}
+
+// hostDatastoreTableTruncate enforces AuthorizeWrite, as it always has,
+// and — once a datastore is installed via SetDatastore — truncates the
+// table (see Runtime.truncateTable), undone along with the rest of the
+// current CallReducer's writes if the reducer goes on to return an
+// error.
+func (rt *Runtime) hostDatastoreTableTruncate(ctx context.Context, mod api.Module, tableID uint32) uint32 {
+	if err := rt.authorize(ctx, tableID, OpWrite); err != nil {
+		return statusUnauthorized
+	}
+	if rt.datastore == nil {
+		return 0
+	}
+	if err := rt.truncateTable(tableID); err != nil {
+		return 0
+	}
+	return 0
+}
+
+// hostBytesSourceRead implements the bytes_source_read half of the
+// chunked-read ABI: the guest passes a source ID (obtained from a prior
+// host call that returned one, e.g. a row scan) and a destination
+// buffer, and gets back however many bytes were available, looping
+// until it reads 0. This lets rows larger than any single guest buffer
+// cross the ABI without either side needing to allocate the whole row
+// at once.
+func (rt *Runtime) hostBytesSourceRead(ctx context.Context, mod api.Module, sourceID, bufPtr, bufLen uint32) uint32 {
+	buf := make([]byte, bufLen)
+	n := rt.sources.read(sourceID, buf)
+	if n > 0 && !mod.Memory().Write(bufPtr, buf[:n]) {
+		return 0
+	}
+	return uint32(n)
+}
+
+// hostDatastoreBtreeScanBsatnDeprecated shims the pre-10.0 ABI's
+// datastore_btree_scan_bsatn import onto the current
+// datastore_table_scan_bsatn implementation, so a module built against
+// the older name still links and runs. See deprecated.go for the usage
+// counter this records into.
+func (rt *Runtime) hostDatastoreBtreeScanBsatnDeprecated(ctx context.Context, mod api.Module, tableID uint32, outPtr uint32) uint32 {
+	rt.recordDeprecatedUse("datastore_btree_scan_bsatn")
+	return rt.hostDatastoreTableScanBsatn(ctx, mod, tableID, outPtr)
+}
+
+// hostByteBufferSourceGetLenDeprecated shims the pre-10.0 ABI's
+// byte_buffer_source_get_len import: it reports how many unread bytes
+// remain in sourceID without consuming them, the query a module used to
+// make before bytes_source_read's read-until-empty protocol replaced it.
+// An unknown or already-exhausted sourceID reports 0, matching how
+// hostBytesSourceRead treats one.
+func (rt *Runtime) hostByteBufferSourceGetLenDeprecated(ctx context.Context, mod api.Module, sourceID uint32) uint32 {
+	rt.recordDeprecatedUse("byte_buffer_source_get_len")
+	n, _ := rt.sources.remaining(sourceID)
+	return n
+}
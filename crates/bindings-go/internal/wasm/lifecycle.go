@@ -0,0 +1,83 @@
+package wasm
+
+import (
+	"context"
+	"fmt"
+)
+
+// LifecycleKind identifies one of the three reducers SpacetimeDB calls
+// automatically rather than in response to a client's explicit request:
+// once when a module is first published, and once per client connection
+// event. A module marks a reducer this way in its own source (e.g. Rust's
+// #[reducer(init)]); __describe_module__ reports which reducer, if any,
+// carries each kind, but this host has no parser for that yet (see
+// ReducerSchema's doc comment), so a caller using CallLifecycleReducer
+// must set ReducerSchema.Lifecycle itself when building the schemas it
+// passes to SetReducers.
+type LifecycleKind int
+
+const (
+	// LifecycleNone marks an ordinary reducer, callable only in response
+	// to an explicit CallReducer. It is ReducerSchema's zero value so an
+	// embedder that never sets Lifecycle gets today's behavior.
+	LifecycleNone LifecycleKind = iota
+	// LifecycleInit marks the reducer, if any, a module wants run
+	// exactly once, when the module is first published.
+	LifecycleInit
+	// LifecycleClientConnected marks the reducer, if any, a module
+	// wants run once per new client connection.
+	LifecycleClientConnected
+	// LifecycleClientDisconnected marks the reducer, if any, a module
+	// wants run once per client disconnection.
+	LifecycleClientDisconnected
+)
+
+// CallLifecycleReducer calls the reducer installed via SetReducers whose
+// Lifecycle equals kind, if any. It is a no-op, returning nil without
+// calling anything, when no installed schema carries that Lifecycle —
+// not every module defines all three — so a host can call e.g.
+// CallLifecycleReducer(ctx, LifecycleInit, nil) unconditionally right
+// after NewRuntime instead of first checking whether the module happens
+// to define an init reducer.
+func (rt *Runtime) CallLifecycleReducer(ctx context.Context, kind LifecycleKind, args []byte) error {
+	if kind == LifecycleNone {
+		return fmt.Errorf("wasm: CallLifecycleReducer: LifecycleNone is not a callable lifecycle")
+	}
+	for id, schema := range rt.reducers {
+		if schema.Lifecycle == kind {
+			return rt.CallReducer(ctx, id, args)
+		}
+	}
+	return nil
+}
+
+// ReducerPanicError is returned by CallReducer when the guest's
+// __call_reducer__ export traps instead of returning normally — a Rust
+// module's reducer body panicking, or any other wasm-level fault (an
+// out-of-bounds memory access, unreachable instruction, and so on).
+type ReducerPanicError struct {
+	ID   uint32
+	Name string
+	// Err is the underlying wazero trap.
+	Err error
+}
+
+func (e *ReducerPanicError) Error() string {
+	return fmt.Sprintf("wasm: reducer %d (%s) trapped: %v", e.ID, e.Name, e.Err)
+}
+
+func (e *ReducerPanicError) Unwrap() error { return e.Err }
+
+// ReducerStatusError is returned by CallReducer when the guest's
+// __call_reducer__ export returns normally but reports a non-zero
+// status, the ABI's way for a reducer to fail without trapping (e.g. a
+// Rust module's reducer returning Err(..) instead of panicking).
+type ReducerStatusError struct {
+	ID     uint32
+	Name   string
+	Status uint32
+}
+
+func (e *ReducerStatusError) Error() string {
+	return fmt.Sprintf("wasm: reducer %d (%s) returned error status %d", e.ID, e.Name, e.Status)
+}
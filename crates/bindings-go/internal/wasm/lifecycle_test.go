@@ -0,0 +1,61 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCallLifecycleReducerIsNoOpWhenModuleDefinesNone(t *testing.T) {
+	rt := &Runtime{}
+	if err := rt.SetReducers([]ReducerSchema{{ID: 1, Name: "score_point"}}); err != nil {
+		t.Fatalf("SetReducers: %v", err)
+	}
+
+	if err := rt.CallLifecycleReducer(context.Background(), LifecycleInit, nil); err != nil {
+		t.Fatalf("CallLifecycleReducer(Init) = %v, want nil (no reducer tagged Init)", err)
+	}
+}
+
+func TestCallLifecycleReducerRejectsLifecycleNone(t *testing.T) {
+	rt := &Runtime{}
+	if err := rt.CallLifecycleReducer(context.Background(), LifecycleNone, nil); err == nil {
+		t.Fatal("CallLifecycleReducer(LifecycleNone): want an error")
+	}
+}
+
+func TestCallLifecycleReducerDispatchesToTaggedReducer(t *testing.T) {
+	rt := &Runtime{}
+	if err := rt.SetReducers([]ReducerSchema{
+		{ID: 1, Name: "score_point"},
+		{ID: 2, Name: "setup", Lifecycle: LifecycleInit, Args: scoreReducerArgs()},
+	}); err != nil {
+		t.Fatalf("SetReducers: %v", err)
+	}
+
+	// CallLifecycleReducer must resolve "setup" (ID 2) and route nil args
+	// through the normal CallReducer validation path, which rejects them
+	// as too small for setup's schema before ever touching guest memory
+	// — proof it dispatched to the tagged reducer, not reducer 1 or
+	// nothing at all.
+	err := rt.CallLifecycleReducer(context.Background(), LifecycleInit, nil)
+	var tooSmall *ReducerArgsTooSmallError
+	if !errors.As(err, &tooSmall) {
+		t.Fatalf("CallLifecycleReducer(Init) error = %v, want *ReducerArgsTooSmallError for setup", err)
+	}
+	if tooSmall.Name != "setup" {
+		t.Fatalf("ReducerArgsTooSmallError.Name = %q, want setup", tooSmall.Name)
+	}
+
+	if err := rt.CallLifecycleReducer(context.Background(), LifecycleClientConnected, nil); err != nil {
+		t.Fatalf("CallLifecycleReducer(ClientConnected) = %v, want nil (no reducer tagged ClientConnected)", err)
+	}
+}
+
+func TestReducerPanicErrorUnwraps(t *testing.T) {
+	inner := errors.New("boom")
+	err := &ReducerPanicError{ID: 1, Name: "r", Err: inner}
+	if !errors.Is(err, inner) {
+		t.Fatalf("errors.Is(err, inner) = false, want true via Unwrap")
+	}
+}
@@ -0,0 +1,154 @@
+package wasm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/logs"
+)
+
+// consoleLogPanicLevel is the raw level bindings-sys reports just before
+// a fatal panic traps the module (see LOG_LEVEL_PANIC in
+// crates/bindings-sys); it is always logged regardless of the configured
+// filter level, the same way a panic can't be silenced by log level in
+// any other logging setup.
+const consoleLogPanicLevel uint32 = 101
+
+// logState holds a Runtime's log-level configuration and the writer
+// enabled messages are formatted to. NewRuntime sets level to
+// logs.LevelInfo; output defaults to os.Stderr when left nil.
+type logState struct {
+	mu        sync.Mutex
+	level     logs.Level
+	overrides map[string]logs.Level
+	output    io.Writer
+}
+
+func (s *logState) effectiveLevel(target string) logs.Level {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Longest matching override prefix wins, so "module::sub" can be
+	// quieted independently of a louder "module" default.
+	best := -1
+	level := s.level
+	for prefix, l := range s.overrides {
+		if strings.HasPrefix(target, prefix) && len(prefix) > best {
+			best = len(prefix)
+			level = l
+		}
+	}
+	return level
+}
+
+func (s *logState) writer() io.Writer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.output == nil {
+		return os.Stderr
+	}
+	return s.output
+}
+
+// SetLogLevel sets the global log level new console_log calls are
+// filtered against, for targets that have no more specific
+// SetModuleLogLevel override. It takes effect immediately, letting an
+// operator quiet a noisy module without restarting the host.
+func (rt *Runtime) SetLogLevel(level logs.Level) {
+	rt.log.mu.Lock()
+	defer rt.log.mu.Unlock()
+	rt.log.level = level
+}
+
+// SetModuleLogLevel overrides the log level for every console_log call
+// whose target starts with prefix (e.g. a Rust module path segment such
+// as "my_module::reducers"), taking priority over the global level set
+// by SetLogLevel for any target it matches. The longest matching prefix
+// wins when overrides overlap. Passing the same prefix again replaces
+// the previous override; there is no way to remove one short of
+// restarting the Runtime, an acceptable limitation for a debugging knob.
+func (rt *Runtime) SetModuleLogLevel(prefix string, level logs.Level) {
+	rt.log.mu.Lock()
+	defer rt.log.mu.Unlock()
+	if rt.log.overrides == nil {
+		rt.log.overrides = map[string]logs.Level{}
+	}
+	rt.log.overrides[prefix] = level
+}
+
+// SetLogOutput redirects where enabled console_log messages are
+// formatted to, in place of the default os.Stderr.
+func (rt *Runtime) SetLogOutput(w io.Writer) {
+	rt.log.mu.Lock()
+	defer rt.log.mu.Unlock()
+	rt.log.output = w
+}
+
+// consoleLog handles the module's console_log host call: it decodes the
+// target and message strings out of guest memory, checks logEnabled
+// against the Runtime's configured level (global default, overridden
+// per module by SetModuleLogLevel), and if enabled formats the message
+// to the configured output.
+func (rt *Runtime) consoleLog(mod api.Module, rawLevel, targetPtr, targetLen, msgPtr, msgLen uint32) {
+	target := readGuestString(mod, targetPtr, targetLen)
+	if !rt.logEnabled(rawLevel, target) {
+		return
+	}
+
+	level, ok := logLevelFromRaw(rawLevel)
+	if !ok {
+		level = logs.LevelError // panic: always logged, and always the most severe.
+	}
+	entry := logs.Entry{Level: level, Message: readGuestString(mod, msgPtr, msgLen)}
+	fmt.Fprintln(rt.log.writer(), entry.Format())
+}
+
+// logEnabled reports whether a console_log call at rawLevel from target
+// should be logged under the Runtime's current configuration. A panic
+// level is always enabled.
+func (rt *Runtime) logEnabled(rawLevel uint32, target string) bool {
+	if rawLevel == consoleLogPanicLevel {
+		return true
+	}
+	level, ok := logLevelFromRaw(rawLevel)
+	if !ok {
+		return false
+	}
+	return level <= rt.log.effectiveLevel(target)
+}
+
+// logLevelFromRaw maps bindings-sys's raw LOG_LEVEL_* constants onto
+// logs.Level. It does not handle consoleLogPanicLevel; callers check
+// that separately since it has no logs.Level of its own.
+func logLevelFromRaw(rawLevel uint32) (logs.Level, bool) {
+	switch rawLevel {
+	case 0:
+		return logs.LevelError, true
+	case 1:
+		return logs.LevelWarn, true
+	case 2:
+		return logs.LevelInfo, true
+	case 3:
+		return logs.LevelDebug, true
+	case 4:
+		return logs.LevelTrace, true
+	default:
+		return 0, false
+	}
+}
+
+// readGuestString reads a UTF-8 string out of the guest's linear memory,
+// returning "" if the range is out of bounds rather than panicking; a
+// malformed pointer/length pair from a misbehaving module shouldn't
+// crash the host's logging path.
+func readGuestString(mod api.Module, ptr, length uint32) string {
+	b, ok := mod.Memory().Read(ptr, length)
+	if !ok {
+		return ""
+	}
+	return string(b)
+}
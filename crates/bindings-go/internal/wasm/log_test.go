@@ -0,0 +1,78 @@
+package wasm
+
+import (
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/logs"
+)
+
+func newTestRuntimeForLogging() *Runtime {
+	rt := &Runtime{}
+	rt.log.level = logs.LevelInfo
+	return rt
+}
+
+func TestLogEnabledFiltersAgainstGlobalLevel(t *testing.T) {
+	rt := newTestRuntimeForLogging()
+
+	if !rt.logEnabled(0, "my_module") { // error
+		t.Fatal("error should be enabled at default info level")
+	}
+	if !rt.logEnabled(2, "my_module") { // info
+		t.Fatal("info should be enabled at default info level")
+	}
+	if rt.logEnabled(3, "my_module") { // debug
+		t.Fatal("debug should not be enabled at default info level")
+	}
+}
+
+func TestSetLogLevelChangesFilterDynamically(t *testing.T) {
+	rt := newTestRuntimeForLogging()
+	rt.SetLogLevel(logs.LevelDebug)
+
+	if !rt.logEnabled(3, "my_module") {
+		t.Fatal("debug should be enabled after SetLogLevel(LevelDebug)")
+	}
+}
+
+func TestSetModuleLogLevelOverridesGlobalForMatchingTarget(t *testing.T) {
+	rt := newTestRuntimeForLogging()
+	rt.SetLogLevel(logs.LevelError)
+	rt.SetModuleLogLevel("noisy_module", logs.LevelTrace)
+
+	if rt.logEnabled(2, "quiet_module") {
+		t.Fatal("quiet_module should stay at the global LevelError filter")
+	}
+	if !rt.logEnabled(2, "noisy_module::reducers") {
+		t.Fatal("noisy_module::reducers should match the noisy_module override")
+	}
+}
+
+func TestSetModuleLogLevelLongestPrefixWins(t *testing.T) {
+	rt := newTestRuntimeForLogging()
+	rt.SetModuleLogLevel("app", logs.LevelError)
+	rt.SetModuleLogLevel("app::verbose", logs.LevelTrace)
+
+	if rt.logEnabled(3, "app::quiet") {
+		t.Fatal("app::quiet should use the shorter 'app' override (LevelError)")
+	}
+	if !rt.logEnabled(3, "app::verbose::inner") {
+		t.Fatal("app::verbose::inner should use the longer, more specific override")
+	}
+}
+
+func TestLogEnabledAlwaysAllowsPanicLevel(t *testing.T) {
+	rt := newTestRuntimeForLogging()
+	rt.SetLogLevel(logs.LevelError)
+
+	if !rt.logEnabled(consoleLogPanicLevel, "any_module") {
+		t.Fatal("panic level should always be enabled")
+	}
+}
+
+func TestLogEnabledRejectsUnknownRawLevel(t *testing.T) {
+	rt := newTestRuntimeForLogging()
+	if rt.logEnabled(999, "any_module") {
+		t.Fatal("unknown raw level should not be enabled")
+	}
+}
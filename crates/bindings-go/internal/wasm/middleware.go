@@ -0,0 +1,44 @@
+package wasm
+
+import "reflect"
+
+// HostHandler is the fully-erased shape every registered host ABI
+// function is reduced to once wrapped: a name (the ABI export name, e.g.
+// "datastore_update_bsatn") and its call's arguments/results as
+// reflect.Values, so middleware doesn't need to know any function's
+// concrete signature.
+type HostHandler func(name string, in []reflect.Value) []reflect.Value
+
+// HostMiddleware wraps a HostHandler with cross-cutting behavior (logging,
+// metrics, auth, fault injection) and returns the wrapped handler.
+// Configure these via Config.Middleware; buildHostModule composes them
+// around every host function it registers so those concerns stop being
+// hand-inlined into each host function body. The first entry in
+// Config.Middleware runs outermost (sees the call before and after every
+// other middleware and the real host function), matching the order
+// net/http middleware stacking reads in.
+type HostMiddleware func(next HostHandler) HostHandler
+
+// wrapHostFunc wraps fn (a Go function matching one of buildHostModule's
+// registrations, e.g. hostConsoleLog) with rt's middleware chain,
+// returning a function of the identical signature so it can still be
+// passed straight to wazero's WithFunc. Middleware only ever sees the
+// erased HostHandler shape; reflect.MakeFunc is what lets a single
+// generic chain wrap host functions with otherwise unrelated arities and
+// types.
+func (rt *Runtime) wrapHostFunc(name string, fn interface{}) interface{} {
+	fnVal := reflect.ValueOf(fn)
+	fnType := fnVal.Type()
+
+	var handler HostHandler = func(_ string, in []reflect.Value) []reflect.Value {
+		return fnVal.Call(in)
+	}
+	for i := len(rt.middleware) - 1; i >= 0; i-- {
+		handler = rt.middleware[i](handler)
+	}
+
+	wrapped := reflect.MakeFunc(fnType, func(in []reflect.Value) []reflect.Value {
+		return handler(name, in)
+	})
+	return wrapped.Interface()
+}
@@ -0,0 +1,86 @@
+package wasm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func addTwo(a, b uint32) uint32 { return a + b }
+
+func TestWrapHostFuncPreservesBehaviorWithNoMiddleware(t *testing.T) {
+	rt := &Runtime{}
+	wrapped := rt.wrapHostFunc("add", addTwo).(func(uint32, uint32) uint32)
+
+	if got := wrapped(2, 3); got != 5 {
+		t.Fatalf("wrapped(2, 3) = %d, want 5", got)
+	}
+}
+
+func TestWrapHostFuncRunsMiddlewareInOrder(t *testing.T) {
+	var calls []string
+	trace := func(label string) HostMiddleware {
+		return func(next HostHandler) HostHandler {
+			return func(name string, in []reflect.Value) []reflect.Value {
+				calls = append(calls, label+":before")
+				out := next(name, in)
+				calls = append(calls, label+":after")
+				return out
+			}
+		}
+	}
+
+	rt := &Runtime{middleware: []HostMiddleware{trace("outer"), trace("inner")}}
+	wrapped := rt.wrapHostFunc("add", addTwo).(func(uint32, uint32) uint32)
+	wrapped(1, 1)
+
+	want := []string{"outer:before", "inner:before", "inner:after", "outer:after"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+}
+
+func TestWrapHostFuncMiddlewareSeesFunctionName(t *testing.T) {
+	var gotName string
+	nameCapture := func(next HostHandler) HostHandler {
+		return func(name string, in []reflect.Value) []reflect.Value {
+			gotName = name
+			return next(name, in)
+		}
+	}
+
+	rt := &Runtime{middleware: []HostMiddleware{nameCapture}}
+	wrapped := rt.wrapHostFunc("datastore_update_bsatn", addTwo).(func(uint32, uint32) uint32)
+	wrapped(1, 1)
+
+	if gotName != "datastore_update_bsatn" {
+		t.Fatalf("middleware saw name %q, want %q", gotName, "datastore_update_bsatn")
+	}
+}
+
+func TestWrapHostFuncMiddlewareCanShortCircuit(t *testing.T) {
+	var calledReal bool
+	realFn := func(a, b uint32) uint32 {
+		calledReal = true
+		return a + b
+	}
+	shortCircuit := func(next HostHandler) HostHandler {
+		return func(name string, in []reflect.Value) []reflect.Value {
+			return []reflect.Value{reflect.ValueOf(uint32(999))}
+		}
+	}
+
+	rt := &Runtime{middleware: []HostMiddleware{shortCircuit}}
+	wrapped := rt.wrapHostFunc("add", realFn).(func(uint32, uint32) uint32)
+
+	if got := wrapped(1, 1); got != 999 {
+		t.Fatalf("wrapped(1, 1) = %d, want 999", got)
+	}
+	if calledReal {
+		t.Fatal("short-circuiting middleware should have prevented the real host function from running")
+	}
+}
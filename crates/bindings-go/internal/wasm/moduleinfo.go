@@ -0,0 +1,85 @@
+package wasm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tetratelabs/wazero"
+)
+
+// SchemaHashSection is the custom section name a module's build process
+// embeds its schema hash under, if it embeds one at all (see
+// codegen.HashModule for how the hash itself is computed, and
+// VerifySchemaHash for checking it). ModuleInfo exposes it under this
+// well-known name so a caller doesn't need its own naming convention.
+const SchemaHashSection = "spacetimedb_schema_hash"
+
+// ModuleInfo reports the custom sections a wasm module's build process
+// embedded in it: free-form build metadata (a git commit, a build
+// timestamp, whatever the toolchain names its section) alongside, if
+// present, the reserved SchemaHashSection.
+type ModuleInfo struct {
+	// CustomSections holds every custom section's raw bytes, keyed by
+	// section name, exactly as the wasm binary encodes them. This host
+	// does not interpret any of them beyond SchemaHashSection.
+	CustomSections map[string][]byte
+}
+
+// AnalyzeModule parses wasmBytes' custom sections without instantiating
+// it, the same "compile, don't run" approach ValidateABI uses — so it
+// can be called on a module the caller has no intention of (or ability
+// to) run, e.g. one being inspected by `spacetimedb check` before publish.
+func AnalyzeModule(ctx context.Context, wasmBytes []byte) (ModuleInfo, error) {
+	engine := wazero.NewRuntime(ctx)
+	defer engine.Close(ctx)
+
+	compiled, err := engine.CompileModule(ctx, wasmBytes)
+	if err != nil {
+		return ModuleInfo{}, fmt.Errorf("wasm: not a valid module: %w", err)
+	}
+	return moduleInfoFrom(compiled), nil
+}
+
+// ModuleInfo returns rt's already-compiled module's custom sections,
+// without recompiling wasmBytes the way the standalone AnalyzeModule
+// must.
+func (rt *Runtime) ModuleInfo() ModuleInfo {
+	return moduleInfoFrom(rt.compiled)
+}
+
+func moduleInfoFrom(compiled wazero.CompiledModule) ModuleInfo {
+	sections := compiled.CustomSections()
+	info := ModuleInfo{CustomSections: make(map[string][]byte, len(sections))}
+	for _, s := range sections {
+		info.CustomSections[s.Name()] = s.Data()
+	}
+	return info
+}
+
+// SchemaHashMismatchError is returned by VerifySchemaHash when a
+// module's embedded SchemaHashSection does not match the expected hash —
+// e.g. because the module was rebuilt without regenerating its embedded
+// hash, or the wasm binary was corrupted or tampered with in transit.
+type SchemaHashMismatchError struct {
+	Embedded string
+	Want     string
+}
+
+func (e *SchemaHashMismatchError) Error() string {
+	return fmt.Sprintf("wasm: embedded schema hash %q does not match expected %q", e.Embedded, e.Want)
+}
+
+// VerifySchemaHash checks info's SchemaHashSection, if any, against
+// want (typically codegen.HashModule(wasmBytes)). A module with no
+// SchemaHashSection at all passes unconditionally: embedding a schema
+// hash is an opt-in build step, not a requirement of this host's ABI.
+func VerifySchemaHash(info ModuleInfo, want string) error {
+	embedded, ok := info.CustomSections[SchemaHashSection]
+	if !ok {
+		return nil
+	}
+	if string(embedded) != want {
+		return &SchemaHashMismatchError{Embedded: string(embedded), Want: want}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package wasm
+
+import (
+	"context"
+	"testing"
+)
+
+// moduleWithSchemaHash is emptyModule plus a custom "spacetimedb_schema_hash"
+// section containing the ASCII bytes "deadbeef", built by hand the same
+// way validate_test.go's emptyModule is: magic + version + one custom
+// section (id 0x00, ULEB128 length, ULEB128 name length, name, data).
+var moduleWithSchemaHash = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x00, 0x20, 0x17, 0x73, 0x70, 0x61, 0x63, 0x65, 0x74, 0x69, 0x6d, 0x65, 0x64, 0x62, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x64, 0x65, 0x61, 0x64, 0x62, 0x65, 0x65, 0x66}
+
+// moduleWithBuildInfo is emptyModule plus an unrelated custom "build_info"
+// section containing "commit=abc123", with no schema hash section at all.
+var moduleWithBuildInfo = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x00, 0x18, 0x0a, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x3d, 0x61, 0x62, 0x63, 0x31, 0x32, 0x33}
+
+func TestAnalyzeModuleReadsCustomSections(t *testing.T) {
+	info, err := AnalyzeModule(context.Background(), moduleWithSchemaHash)
+	if err != nil {
+		t.Fatalf("AnalyzeModule: %v", err)
+	}
+	if got := string(info.CustomSections[SchemaHashSection]); got != "deadbeef" {
+		t.Fatalf("CustomSections[%s] = %q, want %q", SchemaHashSection, got, "deadbeef")
+	}
+}
+
+func TestAnalyzeModuleWithNoSchemaHashSection(t *testing.T) {
+	info, err := AnalyzeModule(context.Background(), moduleWithBuildInfo)
+	if err != nil {
+		t.Fatalf("AnalyzeModule: %v", err)
+	}
+	if got := string(info.CustomSections["build_info"]); got != "commit=abc123" {
+		t.Fatalf("CustomSections[build_info] = %q", got)
+	}
+	if _, ok := info.CustomSections[SchemaHashSection]; ok {
+		t.Fatal("unexpected schema hash section")
+	}
+}
+
+func TestAnalyzeModuleRejectsGarbage(t *testing.T) {
+	if _, err := AnalyzeModule(context.Background(), []byte("not wasm")); err == nil {
+		t.Fatal("expected error for non-wasm bytes")
+	}
+}
+
+func TestVerifySchemaHashMatches(t *testing.T) {
+	info, err := AnalyzeModule(context.Background(), moduleWithSchemaHash)
+	if err != nil {
+		t.Fatalf("AnalyzeModule: %v", err)
+	}
+	if err := VerifySchemaHash(info, "deadbeef"); err != nil {
+		t.Fatalf("VerifySchemaHash: %v", err)
+	}
+}
+
+func TestVerifySchemaHashMismatch(t *testing.T) {
+	info, err := AnalyzeModule(context.Background(), moduleWithSchemaHash)
+	if err != nil {
+		t.Fatalf("AnalyzeModule: %v", err)
+	}
+	err = VerifySchemaHash(info, "somethingelse")
+	if err == nil {
+		t.Fatal("expected mismatch error")
+	}
+	var mismatch *SchemaHashMismatchError
+	if !isSchemaHashMismatch(err, &mismatch) {
+		t.Fatalf("err = %v, want *SchemaHashMismatchError", err)
+	}
+	if mismatch.Embedded != "deadbeef" || mismatch.Want != "somethingelse" {
+		t.Fatalf("mismatch = %+v", mismatch)
+	}
+}
+
+func TestVerifySchemaHashPassesWhenSectionAbsent(t *testing.T) {
+	info, err := AnalyzeModule(context.Background(), moduleWithBuildInfo)
+	if err != nil {
+		t.Fatalf("AnalyzeModule: %v", err)
+	}
+	if err := VerifySchemaHash(info, "anything"); err != nil {
+		t.Fatalf("VerifySchemaHash with no section present: %v", err)
+	}
+}
+
+// runnableModuleWithSchemaHash is validModule from internal/promote's
+// tests (exports empty spacetime_alloc/__call_reducer__ functions, so it
+// can actually be instantiated, unlike emptyModule) plus the same
+// spacetimedb_schema_hash custom section as moduleWithSchemaHash.
+var runnableModuleWithSchemaHash = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x04, 0x01, 0x60, 0x00, 0x00, 0x03, 0x03,
+	0x02, 0x00, 0x00, 0x07, 0x26, 0x02, 0x0f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x00, 0x00, 0x10, 0x5f, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x5f,
+	0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x5f, 0x5f, 0x00, 0x01, 0x0a, 0x07, 0x02, 0x02, 0x00,
+	0x0b, 0x02, 0x00, 0x0b, 0x00, 0x20, 0x17, 0x73, 0x70, 0x61, 0x63, 0x65, 0x74, 0x69, 0x6d, 0x65,
+	0x64, 0x62, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x63, 0x61,
+	0x66, 0x65, 0x66, 0x30, 0x30, 0x64,
+}
+
+func TestRuntimeModuleInfoReadsCustomSections(t *testing.T) {
+	ctx := context.Background()
+	rt, err := NewRuntime(ctx, runnableModuleWithSchemaHash, NewConfig())
+	if err != nil {
+		t.Fatalf("NewRuntime: %v", err)
+	}
+	defer rt.Close(ctx)
+
+	info := rt.ModuleInfo()
+	if got := string(info.CustomSections[SchemaHashSection]); got != "cafef00d" {
+		t.Fatalf("CustomSections[%s] = %q, want %q", SchemaHashSection, got, "cafef00d")
+	}
+}
+
+func isSchemaHashMismatch(err error, out **SchemaHashMismatchError) bool {
+	mismatch, ok := err.(*SchemaHashMismatchError)
+	if ok {
+		*out = mismatch
+	}
+	return ok
+}
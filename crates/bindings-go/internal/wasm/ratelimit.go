@@ -0,0 +1,50 @@
+package wasm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/ratelimit"
+)
+
+// SetRateLimiter installs limiter as the policy CallReducer checks
+// before every call, gated by FeatureRateLimit the same way AuthzHooks
+// is gated by FeatureRLS: installing a limiter has no effect until an
+// embedder opts in. energyCost computes the energy a reducer call costs
+// from its reducerID and argument bytes; a nil energyCost charges 1
+// energy per call regardless of size.
+func (rt *Runtime) SetRateLimiter(limiter *ratelimit.Limiter, energyCost func(reducerID uint32, args []byte) float64) {
+	rt.limiter = limiter
+	rt.energyCost = energyCost
+}
+
+// checkRateLimit consults rt's installed Limiter, if any, using the
+// identity propagated on ctx (see identity.WithCaller). It returns nil
+// unless FeatureRateLimit is enabled, no Limiter is installed, or no
+// identity was propagated with the call (e.g. a reducer invoked
+// directly in tests without going through Host).
+func (rt *Runtime) checkRateLimit(ctx context.Context, reducerID uint32, args []byte) error {
+	if !rt.featureEnabled(FeatureRateLimit) || rt.limiter == nil {
+		return nil
+	}
+	caller, ok := identity.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	cost := 1.0
+	if rt.energyCost != nil {
+		cost = rt.energyCost(reducerID, args)
+	}
+	return rt.limiter.Allow(caller, rt.reducerName(reducerID), cost)
+}
+
+// reducerName returns the name SetReducers registered for reducerID, or
+// a placeholder if none was registered — mirroring tableName's fallback
+// for unregistered table IDs.
+func (rt *Runtime) reducerName(reducerID uint32) string {
+	if schema, ok := rt.reducers[reducerID]; ok {
+		return schema.Name
+	}
+	return fmt.Sprintf("reducer#%d", reducerID)
+}
@@ -0,0 +1,88 @@
+package wasm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/ratelimit"
+)
+
+func TestCheckRateLimitAllowsWhenNoLimiterConfigured(t *testing.T) {
+	rt := &Runtime{cfg: Config{Features: map[string]bool{FeatureRateLimit: true}}}
+	ctx := identity.WithCaller(context.Background(), identity.Identity{1})
+	if err := rt.checkRateLimit(ctx, 1, nil); err != nil {
+		t.Fatalf("checkRateLimit with no limiter: %v", err)
+	}
+}
+
+func TestCheckRateLimitNoOpUnlessFeatureEnabled(t *testing.T) {
+	rt := &Runtime{}
+	rt.SetRateLimiter(ratelimit.NewLimiter(ratelimit.Policy{CallsPerSecond: 1, CallBurst: 0}), nil)
+	ctx := identity.WithCaller(context.Background(), identity.Identity{1})
+	if err := rt.checkRateLimit(ctx, 1, nil); err != nil {
+		t.Fatalf("checkRateLimit without FeatureRateLimit: %v", err)
+	}
+}
+
+func TestCheckRateLimitNoOpWithoutPropagatedIdentity(t *testing.T) {
+	rt := &Runtime{cfg: Config{Features: map[string]bool{FeatureRateLimit: true}}}
+	rt.SetRateLimiter(ratelimit.NewLimiter(ratelimit.Policy{CallsPerSecond: 1, CallBurst: 0}), nil)
+	if err := rt.checkRateLimit(context.Background(), 1, nil); err != nil {
+		t.Fatalf("checkRateLimit with no caller identity: %v", err)
+	}
+}
+
+func TestCheckRateLimitDeniesExhaustedCallBucket(t *testing.T) {
+	rt := &Runtime{cfg: Config{Features: map[string]bool{FeatureRateLimit: true}}}
+	rt.SetRateLimiter(ratelimit.NewLimiter(ratelimit.Policy{CallsPerSecond: 1, CallBurst: 1}), nil)
+
+	ctx := identity.WithCaller(context.Background(), identity.Identity{1, 2, 3, 4})
+	if err := rt.checkRateLimit(ctx, 1, nil); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	err := rt.checkRateLimit(ctx, 1, nil)
+	if err == nil {
+		t.Fatal("second immediate call: want throttled error")
+	}
+	var throttled *ratelimit.ThrottledError
+	if !isThrottled(err, &throttled) {
+		t.Fatalf("err = %v, want *ratelimit.ThrottledError", err)
+	}
+	if throttled.Reason != ratelimit.ReasonCallRate {
+		t.Fatalf("Reason = %q, want %q", throttled.Reason, ratelimit.ReasonCallRate)
+	}
+}
+
+func TestCheckRateLimitUsesRegisteredReducerNameAndEnergyCost(t *testing.T) {
+	rt := &Runtime{cfg: Config{Features: map[string]bool{FeatureRateLimit: true}}}
+	if err := rt.SetReducers([]ReducerSchema{{ID: 5, Name: "send_message"}}); err != nil {
+		t.Fatalf("SetReducers: %v", err)
+	}
+
+	var gotReducer string
+	limiter := ratelimit.NewLimiter(ratelimit.Policy{EnergyPerSecond: 1, EnergyBudget: 10})
+	rt.SetRateLimiter(limiter, func(reducerID uint32, args []byte) float64 { return 20 })
+
+	ctx := identity.WithCaller(context.Background(), identity.Identity{9})
+	err := rt.checkRateLimit(ctx, 5, nil)
+	var throttled *ratelimit.ThrottledError
+	if !isThrottled(err, &throttled) {
+		t.Fatalf("err = %v, want *ratelimit.ThrottledError", err)
+	}
+	gotReducer = throttled.Reducer
+	if gotReducer != "send_message" {
+		t.Fatalf("Reducer = %q, want send_message", gotReducer)
+	}
+	if throttled.Reason != ratelimit.ReasonEnergy {
+		t.Fatalf("Reason = %q, want %q", throttled.Reason, ratelimit.ReasonEnergy)
+	}
+}
+
+func isThrottled(err error, out **ratelimit.ThrottledError) bool {
+	t, ok := err.(*ratelimit.ThrottledError)
+	if ok {
+		*out = t
+	}
+	return ok
+}
@@ -0,0 +1,62 @@
+package wasm
+
+import "fmt"
+
+// DefaultMaxArgsSize bounds how large a BSATN-encoded reducer argument
+// buffer CallReducer will write into a module's guest memory, when
+// Config.MaxArgsSize is left at zero. It exists for the same reason as
+// MaxRowSize: without it, an oversized argument buffer fails deep inside
+// a guest memory write (or a runaway spacetime_alloc call) instead of at
+// a clear, typed validation point.
+const DefaultMaxArgsSize = 8 << 20 // 8 MiB
+
+// ArgsTooLargeError is returned by CallReducer when args exceeds the
+// Runtime's configured MaxArgsSize, before any guest memory is touched.
+type ArgsTooLargeError struct {
+	Size int
+	Max  int
+}
+
+func (e *ArgsTooLargeError) Error() string {
+	return fmt.Sprintf("wasm: reducer args of %d bytes exceed the %d byte maximum", e.Size, e.Max)
+}
+
+// maxArgsSize returns cfg's configured limit, or DefaultMaxArgsSize if
+// unset.
+func (cfg Config) maxArgsSize() int {
+	if cfg.MaxArgsSize > 0 {
+		return cfg.MaxArgsSize
+	}
+	return DefaultMaxArgsSize
+}
+
+// checkArgsSize returns an *ArgsTooLargeError if args exceeds rt's
+// configured MaxArgsSize. CallReducer calls this before touching guest
+// memory at all.
+func (rt *Runtime) checkArgsSize(args []byte) error {
+	if max := rt.cfg.maxArgsSize(); len(args) > max {
+		return &ArgsTooLargeError{Size: len(args), Max: max}
+	}
+	return nil
+}
+
+// maxErrorSize returns cfg's configured limit, or DefaultMaxErrorSize if
+// unset.
+func (cfg Config) maxErrorSize() int {
+	if cfg.MaxErrorSize > 0 {
+		return cfg.MaxErrorSize
+	}
+	return DefaultMaxErrorSize
+}
+
+// DefaultMaxErrorSize bounds how large an error message CallReducer will
+// accept back from a module's reducer error path, when Config.MaxErrorSize
+// is left at zero.
+//
+// The current __call_reducer__ ABI this Runtime targets only reports a
+// status code (see Runtime.CallReducer), not an error message payload, so
+// this limit has nothing to enforce against yet; it is defined now so
+// Config's shape doesn't change again once a message-carrying error path
+// lands (see request tracking the __describe_module__/error-reporting
+// lifecycle work).
+const DefaultMaxErrorSize = 64 << 10 // 64 KiB
@@ -0,0 +1,54 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckArgsSizeRejectsOversizedArgs(t *testing.T) {
+	rt := &Runtime{cfg: Config{MaxArgsSize: 10}}
+
+	err := rt.checkArgsSize(make([]byte, 11))
+
+	var tooLarge *ArgsTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("checkArgsSize() error = %v, want *ArgsTooLargeError", err)
+	}
+	if tooLarge.Size != 11 || tooLarge.Max != 10 {
+		t.Errorf("ArgsTooLargeError = %+v, want Size=11 Max=10", tooLarge)
+	}
+}
+
+func TestCheckArgsSizeAllowsArgsAtExactlyTheLimit(t *testing.T) {
+	rt := &Runtime{cfg: Config{MaxArgsSize: 10}}
+
+	if err := rt.checkArgsSize(make([]byte, 10)); err != nil {
+		t.Fatalf("checkArgsSize() rejected args at exactly the limit: %v", err)
+	}
+}
+
+func TestCallReducerRejectsOversizedArgsBeforeTouchingGuestMemory(t *testing.T) {
+	rt := &Runtime{cfg: Config{MaxArgsSize: 10}}
+
+	err := rt.CallReducer(context.Background(), 1, make([]byte, 11))
+
+	var tooLarge *ArgsTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("CallReducer() error = %v, want *ArgsTooLargeError", err)
+	}
+}
+
+func TestConfigMaxArgsSizeDefaultsWhenUnset(t *testing.T) {
+	cfg := Config{}
+	if got := cfg.maxArgsSize(); got != DefaultMaxArgsSize {
+		t.Errorf("maxArgsSize() = %d, want DefaultMaxArgsSize (%d)", got, DefaultMaxArgsSize)
+	}
+}
+
+func TestConfigMaxErrorSizeDefaultsWhenUnset(t *testing.T) {
+	cfg := Config{}
+	if got := cfg.maxErrorSize(); got != DefaultMaxErrorSize {
+		t.Errorf("maxErrorSize() = %d, want DefaultMaxErrorSize (%d)", got, DefaultMaxErrorSize)
+	}
+}
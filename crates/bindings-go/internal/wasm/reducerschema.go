@@ -0,0 +1,119 @@
+package wasm
+
+import (
+	"fmt"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// ReducerSchema describes one reducer a module's __describe_module__
+// export reports: the numeric reducerID CallReducer dispatches by, its
+// name (for error messages), its argument product's AlgebraicType, and
+// which special lifecycle moment it runs for, if any (see LifecycleKind).
+// Runtime uses it to catch a bad reducerID or an obviously undersized
+// argument buffer before dispatch, instead of letting the module trap
+// with a confusing wasm-level error.
+type ReducerSchema struct {
+	ID        uint32
+	Name      string
+	Args      bsatn.AlgebraicType
+	Lifecycle LifecycleKind
+}
+
+// SetReducers installs schemas as the manifest CallReducer validates
+// every call against. Until this is called, CallReducer performs no
+// reducerID or argument validation at all. A caller with a module's
+// __describe_module__ output in hand can build schemas from it via
+// internal/moduledef.Decode and ModuleDef.ReducerSchemas instead of
+// listing them by hand.
+//
+// It returns a *LimitExceededError, without installing schemas, if
+// len(schemas) exceeds rt's Config.Limits.MaxReducers.
+func (rt *Runtime) SetReducers(schemas []ReducerSchema) error {
+	if err := checkLimit("reducers", len(schemas), rt.cfg.Limits.MaxReducers); err != nil {
+		return err
+	}
+	byID := make(map[uint32]ReducerSchema, len(schemas))
+	for _, s := range schemas {
+		byID[s.ID] = s
+	}
+	rt.reducers = byID
+	return nil
+}
+
+// UnknownReducerError is returned by CallReducer when reducerID does not
+// match any ReducerSchema installed via SetReducers.
+type UnknownReducerError struct {
+	ID uint32
+}
+
+func (e *UnknownReducerError) Error() string {
+	return fmt.Sprintf("wasm: no reducer registered for id %d", e.ID)
+}
+
+// ReducerArgsTooSmallError is returned by CallReducer when args is
+// smaller than the fewest bytes the called reducer's argument product
+// could possibly encode to — a cheap sanity check that catches an
+// obviously wrong call (e.g. args meant for a different reducer) before
+// it ever reaches the guest.
+type ReducerArgsTooSmallError struct {
+	Name    string
+	Size    int
+	MinSize int
+}
+
+func (e *ReducerArgsTooSmallError) Error() string {
+	return fmt.Sprintf("wasm: reducer %q args of %d bytes are smaller than its minimum possible encoding of %d bytes", e.Name, e.Size, e.MinSize)
+}
+
+// validateReducerCall checks reducerID/args against rt's installed
+// reducer schemas, if any. It returns nil immediately if SetReducers was
+// never called, preserving CallReducer's behavior for a Runtime that
+// hasn't opted in.
+func (rt *Runtime) validateReducerCall(reducerID uint32, args []byte) error {
+	if rt.reducers == nil {
+		return nil
+	}
+	schema, ok := rt.reducers[reducerID]
+	if !ok {
+		return &UnknownReducerError{ID: reducerID}
+	}
+	if min := minEncodedSize(schema.Args); len(args) < min {
+		return &ReducerArgsTooSmallError{Name: schema.Name, Size: len(args), MinSize: min}
+	}
+	return nil
+}
+
+// minEncodedSize returns the fewest bytes t could possibly encode to.
+// Variable-length values (strings, arrays, maps) only contribute their
+// fixed-size length prefix, since their content can be empty; a sum only
+// contributes its tag byte, since a variant's own payload can itself be
+// zero-size. This is deliberately a lower bound, not a real decode — it
+// exists to reject obviously-too-small buffers cheaply, not to validate
+// the argument encoding is otherwise well-formed.
+func minEncodedSize(t bsatn.AlgebraicType) int {
+	switch t.Kind {
+	case bsatn.KindBool, bsatn.KindI8, bsatn.KindU8:
+		return 1
+	case bsatn.KindI16, bsatn.KindU16:
+		return 2
+	case bsatn.KindI32, bsatn.KindU32, bsatn.KindF32:
+		return 4
+	case bsatn.KindI64, bsatn.KindU64, bsatn.KindF64:
+		return 8
+	case bsatn.KindI128, bsatn.KindU128:
+		return 16
+	case bsatn.KindString, bsatn.KindArray, bsatn.KindMap:
+		return 4
+	case bsatn.KindSum:
+		return 1
+	case bsatn.KindProduct:
+		total := 0
+		for _, elem := range t.Product {
+			total += minEncodedSize(elem.Type)
+		}
+		return total
+	default:
+		return 0
+	}
+}
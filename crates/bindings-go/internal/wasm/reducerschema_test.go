@@ -0,0 +1,103 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func scoreReducerArgs() bsatn.AlgebraicType {
+	name := "delta"
+	return bsatn.ProductOf(bsatn.ProductElement{Name: &name, Type: bsatn.I32()})
+}
+
+func TestValidateReducerCallSkipsWhenNoSchemasInstalled(t *testing.T) {
+	rt := &Runtime{}
+	if err := rt.validateReducerCall(99, nil); err != nil {
+		t.Fatalf("validateReducerCall() = %v, want nil when SetReducers was never called", err)
+	}
+}
+
+func TestValidateReducerCallRejectsUnknownID(t *testing.T) {
+	rt := &Runtime{}
+	if err := rt.SetReducers([]ReducerSchema{{ID: 1, Name: "score_point", Args: scoreReducerArgs()}}); err != nil {
+		t.Fatalf("SetReducers: %v", err)
+	}
+
+	err := rt.validateReducerCall(2, make([]byte, 4))
+
+	var unknown *UnknownReducerError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("validateReducerCall() error = %v, want *UnknownReducerError", err)
+	}
+	if unknown.ID != 2 {
+		t.Errorf("UnknownReducerError.ID = %d, want 2", unknown.ID)
+	}
+}
+
+func TestValidateReducerCallRejectsUndersizedArgs(t *testing.T) {
+	rt := &Runtime{}
+	if err := rt.SetReducers([]ReducerSchema{{ID: 1, Name: "score_point", Args: scoreReducerArgs()}}); err != nil {
+		t.Fatalf("SetReducers: %v", err)
+	}
+
+	err := rt.validateReducerCall(1, make([]byte, 2))
+
+	var tooSmall *ReducerArgsTooSmallError
+	if !errors.As(err, &tooSmall) {
+		t.Fatalf("validateReducerCall() error = %v, want *ReducerArgsTooSmallError", err)
+	}
+	if tooSmall.Name != "score_point" || tooSmall.Size != 2 || tooSmall.MinSize != 4 {
+		t.Errorf("ReducerArgsTooSmallError = %+v, want Name=score_point Size=2 MinSize=4", tooSmall)
+	}
+}
+
+func TestValidateReducerCallAcceptsWellFormedCall(t *testing.T) {
+	rt := &Runtime{}
+	if err := rt.SetReducers([]ReducerSchema{{ID: 1, Name: "score_point", Args: scoreReducerArgs()}}); err != nil {
+		t.Fatalf("SetReducers: %v", err)
+	}
+
+	if err := rt.validateReducerCall(1, make([]byte, 4)); err != nil {
+		t.Fatalf("validateReducerCall() = %v, want nil", err)
+	}
+}
+
+func TestCallReducerRejectsUnknownReducerIDBeforeTouchingGuestMemory(t *testing.T) {
+	rt := &Runtime{}
+	if err := rt.SetReducers([]ReducerSchema{{ID: 1, Name: "score_point", Args: scoreReducerArgs()}}); err != nil {
+		t.Fatalf("SetReducers: %v", err)
+	}
+
+	err := rt.CallReducer(context.Background(), 42, make([]byte, 4))
+
+	var unknown *UnknownReducerError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("CallReducer() error = %v, want *UnknownReducerError", err)
+	}
+}
+
+func TestMinEncodedSizeForVariousKinds(t *testing.T) {
+	name := "n"
+	cases := []struct {
+		t    bsatn.AlgebraicType
+		want int
+	}{
+		{bsatn.Bool(), 1},
+		{bsatn.U8(), 1},
+		{bsatn.I16(), 2},
+		{bsatn.I32(), 4},
+		{bsatn.I64(), 8},
+		{bsatn.String(), 4},
+		{bsatn.ArrayOf(bsatn.U8()), 4},
+		{bsatn.OptionOf(bsatn.I64()), 1},
+		{bsatn.ProductOf(bsatn.ProductElement{Name: &name, Type: bsatn.I32()}, bsatn.ProductElement{Name: &name, Type: bsatn.String()}), 8},
+	}
+	for _, c := range cases {
+		if got := minEncodedSize(c.t); got != c.want {
+			t.Errorf("minEncodedSize(%s) = %d, want %d", c.t.Kind, got, c.want)
+		}
+	}
+}
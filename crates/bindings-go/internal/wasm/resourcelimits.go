@@ -0,0 +1,46 @@
+package wasm
+
+import "fmt"
+
+// ResourceLimits caps how many tables, indexes, and reducers a module
+// may register with a Runtime (see Config.Limits). A zero field means
+// that resource is unlimited; this matches Config's other limit fields
+// (MaxArgsSize, MaxErrorSize), where zero also means "no limit" rather
+// than "zero allowed".
+//
+// This host has no __describe_module__ parser yet (see
+// internal/codegen.ModuleDef), so it cannot read a module's full table
+// and index manifest up front at Compile/Instantiate time the way a
+// real resource-manifest check eventually should. Until that parser
+// lands, these limits are enforced incrementally, against whatever a
+// caller has registered with RegisterTable/RegisterIndex/SetReducers so
+// far — which is enough to reject an over-budget module the moment its
+// setup code (run right after Instantiate, before any reducer call) has
+// registered one resource too many.
+type ResourceLimits struct {
+	MaxTables   int
+	MaxIndexes  int
+	MaxReducers int
+}
+
+// LimitExceededError is returned by RegisterTable, RegisterIndex, and
+// SetReducers when registering would put a Runtime over its configured
+// ResourceLimits for that resource.
+type LimitExceededError struct {
+	Resource string
+	Count    int
+	Limit    int
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("wasm: module exceeds resource manifest: %d %s registered, limit is %d", e.Count, e.Resource, e.Limit)
+}
+
+// checkLimit returns a *LimitExceededError naming resource if count
+// would exceed limit; limit of zero means unlimited.
+func checkLimit(resource string, count, limit int) error {
+	if limit > 0 && count > limit {
+		return &LimitExceededError{Resource: resource, Count: count, Limit: limit}
+	}
+	return nil
+}
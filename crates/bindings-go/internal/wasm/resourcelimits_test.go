@@ -0,0 +1,77 @@
+package wasm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegisterTableRejectsOverLimit(t *testing.T) {
+	rt := &Runtime{cfg: Config{Limits: ResourceLimits{MaxTables: 1}}}
+	if err := rt.RegisterTable(1, "accounts"); err != nil {
+		t.Fatalf("RegisterTable(1): %v", err)
+	}
+
+	err := rt.RegisterTable(2, "sessions")
+	var limitErr *LimitExceededError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("RegisterTable(2) error = %v, want *LimitExceededError", err)
+	}
+	if limitErr.Resource != "tables" || limitErr.Count != 2 || limitErr.Limit != 1 {
+		t.Errorf("LimitExceededError = %+v, want Resource=tables Count=2 Limit=1", limitErr)
+	}
+	if _, ok := rt.tableNames[2]; ok {
+		t.Error("sessions should not be registered after exceeding the limit")
+	}
+}
+
+func TestRegisterTableReRegisteringSameIDDoesNotCountTwice(t *testing.T) {
+	rt := &Runtime{cfg: Config{Limits: ResourceLimits{MaxTables: 1}}}
+	if err := rt.RegisterTable(1, "accounts"); err != nil {
+		t.Fatalf("RegisterTable: %v", err)
+	}
+	if err := rt.RegisterTable(1, "accounts_v2"); err != nil {
+		t.Fatalf("re-registering tableID 1 should not exceed the limit: %v", err)
+	}
+	if rt.tableNames[1] != "accounts_v2" {
+		t.Errorf("tableNames[1] = %q, want accounts_v2", rt.tableNames[1])
+	}
+}
+
+func TestRegisterIndexRejectsOverLimit(t *testing.T) {
+	rt := &Runtime{cfg: Config{Limits: ResourceLimits{MaxIndexes: 1}}}
+	if err := rt.RegisterIndex(1, "by_name"); err != nil {
+		t.Fatalf("RegisterIndex(1): %v", err)
+	}
+
+	err := rt.RegisterIndex(2, "by_score")
+	var limitErr *LimitExceededError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("RegisterIndex(2) error = %v, want *LimitExceededError", err)
+	}
+	if limitErr.Resource != "indexes" {
+		t.Errorf("Resource = %q, want indexes", limitErr.Resource)
+	}
+}
+
+func TestSetReducersRejectsOverLimit(t *testing.T) {
+	rt := &Runtime{cfg: Config{Limits: ResourceLimits{MaxReducers: 1}}}
+
+	err := rt.SetReducers([]ReducerSchema{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}})
+	var limitErr *LimitExceededError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("SetReducers error = %v, want *LimitExceededError", err)
+	}
+	if limitErr.Resource != "reducers" || limitErr.Count != 2 || limitErr.Limit != 1 {
+		t.Errorf("LimitExceededError = %+v, want Resource=reducers Count=2 Limit=1", limitErr)
+	}
+	if rt.reducers != nil {
+		t.Error("reducers should not be installed after exceeding the limit")
+	}
+}
+
+func TestSetReducersUnlimitedByDefault(t *testing.T) {
+	rt := &Runtime{}
+	if err := rt.SetReducers([]ReducerSchema{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}); err != nil {
+		t.Fatalf("SetReducers: %v", err)
+	}
+}
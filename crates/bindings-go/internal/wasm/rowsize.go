@@ -0,0 +1,30 @@
+package wasm
+
+import "fmt"
+
+// MaxRowSize bounds how large a single BSATN-encoded row this host will
+// accept from, or hand back to, a module. Rows above the memory pool's
+// normal allocation size used to silently fall back to a one-off large
+// allocation that could push a module past its page limit; MaxRowSize
+// turns that into an explicit, typed rejection instead, and rows that
+// legitimately need to move more data than this do so through
+// byteSource's chunked reads rather than one oversized buffer.
+const MaxRowSize = 1 << 20 // 1 MiB
+
+// RowTooLargeError is returned when a row exceeds MaxRowSize.
+type RowTooLargeError struct {
+	Size int
+	Max  int
+}
+
+func (e *RowTooLargeError) Error() string {
+	return fmt.Sprintf("wasm: row of %d bytes exceeds the %d byte maximum", e.Size, e.Max)
+}
+
+// checkRowSize returns a *RowTooLargeError if len(row) exceeds MaxRowSize.
+func checkRowSize(row []byte) error {
+	if len(row) > MaxRowSize {
+		return &RowTooLargeError{Size: len(row), Max: MaxRowSize}
+	}
+	return nil
+}
@@ -0,0 +1,386 @@
+package wasm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/logs"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/ratelimit"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// Runtime hosts a single compiled SpacetimeDB wasm module: it owns the
+// wazero engine instance, the module's linear memory, and the host ABI
+// implementation the module calls into.
+type Runtime struct {
+	cfg      Config
+	engine   wazero.Runtime
+	compiled wazero.CompiledModule
+	mod      api.Module
+	sources  *byteSources
+
+	authz      AuthzHooks
+	tableNames map[uint32]string
+	indexNames map[uint32]string
+
+	// indexKeys holds, for an indexID an embedder has described via
+	// RegisterIndexKey, the function that derives a row's real index key
+	// from its raw bytes. datastore_update_bsatn consults it (see
+	// Runtime.rowKey) so a write is keyed by the columns the index
+	// actually names instead of the row's own bytes; an indexID with no
+	// entry here still falls back to that whole-row placeholder.
+	indexKeys map[uint32]db.IndexKeyFunc
+
+	limiter    *ratelimit.Limiter
+	energyCost func(reducerID uint32, args []byte) float64
+
+	breaker *CircuitBreaker
+
+	// datastore is the real storage backing rt's datastore host calls,
+	// once SetDatastore installs one (see datastore.go); nil until then,
+	// in which case those host calls behave as the stubs documented in
+	// hostfuncs.go.
+	datastore *db.Database
+
+	// txn is the in-progress transaction datastore host calls write
+	// through while a reducer call is running (see CallReducer), so an
+	// erroring reducer's writes are undone instead of left live in
+	// datastore. nil outside a CallReducer, and always nil if datastore
+	// itself is nil.
+	txn *db.Txn
+
+	// format is the BSATN format rt assumes a guest module's reducer
+	// arguments and return values are encoded in. It starts at
+	// bsatn.CurrentFormat; this host has no __describe_module__ parser
+	// yet (see ReducerSchema's doc comment) and a module's own
+	// __describe_module__ output has no field reporting which BSATN
+	// format revision it was built against even once that parser
+	// exists, so SetFormat must be called by hand today, the same way
+	// SetReducers is. It exists now so a future per-module format
+	// revision has a seam to plug into without another Runtime field.
+	format bsatn.Format
+
+	log logState
+
+	middleware []HostMiddleware
+
+	hooks Hooks
+
+	deprecated deprecatedUsage
+
+	// reducers is nil until SetReducers is called, in which case
+	// CallReducer skips reducerID/argument validation entirely (see
+	// validateReducerCall).
+	reducers map[uint32]ReducerSchema
+
+	// clock overrides now() for tests that want deterministic virtual
+	// time reported by txn_metadata_bsatn (see SetClock); left nil in
+	// production.
+	clock func() time.Time
+	// txnSeq counts CallReducer invocations made against this Runtime,
+	// surfaced to the guest as TxnMetadata.SequenceNumber.
+	txnSeq uint64
+
+	// ownsEngine is true for a Runtime built by NewRuntime, which owns
+	// its engine outright and must close it to release anything; it is
+	// false for a Runtime returned by CompiledModule.Instantiate, which
+	// shares its engine with every other instance of the same
+	// CompiledModule and so must close only its own guest instance (see
+	// Close).
+	ownsEngine bool
+}
+
+// SetClock overrides the virtual time txn_metadata_bsatn reports to the
+// guest (see TxnMetadata.VirtualTimeMicros) with clock instead of the
+// wall clock, so a test can wire in a scheduler.SimClock's Now method
+// and assert a module under test observes exactly the virtual time the
+// test controls.
+func (rt *Runtime) SetClock(clock func() time.Time) {
+	rt.clock = clock
+}
+
+// Format returns the BSATN format rt assumes this module's reducer
+// arguments and return values are encoded in — bsatn.CurrentFormat until
+// SetFormat changes it.
+func (rt *Runtime) Format() bsatn.Format {
+	return rt.format
+}
+
+// SetFormat overrides the BSATN format rt assumes this module uses, for
+// an embedder that has determined by some out-of-band means (today,
+// nothing in this package does) that a particular module was built
+// against an older format than bsatn.CurrentFormat.
+func (rt *Runtime) SetFormat(format bsatn.Format) {
+	rt.format = format
+}
+
+// now is a seam for tests to control the virtual time txn_metadata_bsatn
+// reports without sleeping; production callers always get the real
+// clock.
+func (rt *Runtime) now() time.Time {
+	if rt.clock != nil {
+		return rt.clock()
+	}
+	return time.Now()
+}
+
+// NewRuntime compiles wasmBytes and instantiates it against the
+// spacetime_10.0 host ABI, ready for CallReducer. The returned Runtime
+// owns its own wazero engine; see Compile and CompiledModule.Instantiate
+// to reuse one compiled module across many instances instead.
+func NewRuntime(ctx context.Context, wasmBytes []byte, cfg Config) (*Runtime, error) {
+	engine := newWazeroRuntime(ctx, cfg.Engine)
+
+	rt := &Runtime{cfg: cfg, engine: engine, sources: newByteSources(), middleware: cfg.Middleware, hooks: cfg.Hooks, ownsEngine: true, format: bsatn.CurrentFormat}
+	rt.log.level = logs.LevelInfo
+	if err := rt.buildHostModule(ctx, engine); err != nil {
+		engine.Close(ctx)
+		return nil, fmt.Errorf("wasm: registering host module: %w", err)
+	}
+
+	compiled, err := engine.CompileModule(ctx, wasmBytes)
+	if err != nil {
+		engine.Close(ctx)
+		return nil, fmt.Errorf("wasm: compile module: %w", err)
+	}
+	rt.compiled = compiled
+
+	mod, err := engine.InstantiateModule(ctx, compiled, wazero.NewModuleConfig())
+	if err != nil {
+		engine.Close(ctx)
+		return nil, fmt.Errorf("wasm: instantiate module: %w", err)
+	}
+	rt.mod = mod
+
+	rt.hooks.fireInstantiate(rt)
+	return rt, nil
+}
+
+// Compile builds a CompiledModule from wasmBytes, registering the
+// spacetime_10.0 host ABI against cfg once. Call Instantiate on the
+// result as many times as needed instead of calling NewRuntime
+// repeatedly with the same bytes, to pay wasm validation and compilation
+// cost once; see CompiledModule's doc comment for what is and is not
+// isolated between the instances this produces.
+func Compile(ctx context.Context, wasmBytes []byte, cfg Config) (*CompiledModule, error) {
+	engine := newWazeroRuntime(ctx, cfg.Engine)
+
+	rt := &Runtime{cfg: cfg, engine: engine, sources: newByteSources(), middleware: cfg.Middleware, hooks: cfg.Hooks, format: bsatn.CurrentFormat}
+	rt.log.level = logs.LevelInfo
+	if err := rt.buildHostModule(ctx, engine); err != nil {
+		engine.Close(ctx)
+		return nil, fmt.Errorf("wasm: registering host module: %w", err)
+	}
+
+	compiled, err := engine.CompileModule(ctx, wasmBytes)
+	if err != nil {
+		engine.Close(ctx)
+		return nil, fmt.Errorf("wasm: compile module: %w", err)
+	}
+	rt.compiled = compiled
+
+	return &CompiledModule{engine: engine, compiled: compiled, hostRuntime: rt}, nil
+}
+
+// CompiledModule is a spacetime_10.0 wasm module compiled and linked
+// against its host ABI once, ready to be instantiated many times. Each
+// Instantiate call gets its own wazero guest instance — its own linear
+// memory and globals, so CallReducer, MemorySize, and guest memory
+// reads/writes on one instance never observe another's — which is what
+// lets e.g. pkg/harness.ModulePool hand every parallel test case its
+// own Runtime without recompiling the module per test.
+//
+// What is NOT isolated between instances: wazero registers a host
+// module's functions once per engine, under a fixed import name
+// ("spacetime_10.0") that every instance's guest code resolves against,
+// so every instance calls into the same Go closures — those closures
+// close over the single Runtime Compile built internally (hostRuntime).
+// Anything that Runtime tracks outside of wasm guest state itself
+// (SetClock, SetLogLevel/SetModuleLogLevel, feature flags, the table
+// name registry, and the bytes_source_read ID counter) is therefore
+// shared across every instance of a CompiledModule, not per-instance.
+// A caller that needs isolation of that bookkeeping too should give
+// each such test case its own CompiledModule instead of sharing one.
+type CompiledModule struct {
+	engine      wazero.Runtime
+	compiled    wazero.CompiledModule
+	hostRuntime *Runtime
+}
+
+// Instantiate creates a new guest instance of cm's compiled module,
+// under the given name (which must be unique among every instance still
+// live in cm's engine — see wazero.NewModuleConfig's WithName). The
+// returned Runtime's Close releases only this instance, not cm itself;
+// call CompiledModule.Close once every instance is done with it.
+func (cm *CompiledModule) Instantiate(ctx context.Context, name string) (*Runtime, error) {
+	mod, err := cm.engine.InstantiateModule(ctx, cm.compiled, wazero.NewModuleConfig().WithName(name))
+	if err != nil {
+		return nil, fmt.Errorf("wasm: instantiate module %q: %w", name, err)
+	}
+	inst := &Runtime{
+		cfg:        cm.hostRuntime.cfg,
+		engine:     cm.engine,
+		compiled:   cm.compiled,
+		mod:        mod,
+		sources:    cm.hostRuntime.sources,
+		authz:      cm.hostRuntime.authz,
+		tableNames: cm.hostRuntime.tableNames,
+		indexKeys:  cm.hostRuntime.indexKeys,
+		limiter:    cm.hostRuntime.limiter,
+		energyCost: cm.hostRuntime.energyCost,
+		breaker:    cm.hostRuntime.breaker,
+		datastore:  cm.hostRuntime.datastore,
+		format:     cm.hostRuntime.format,
+		middleware: cm.hostRuntime.middleware,
+		hooks:      cm.hostRuntime.hooks,
+	}
+	inst.log.level = logs.LevelInfo
+	inst.hooks.fireInstantiate(inst)
+	return inst, nil
+}
+
+// Close releases cm's engine and every instance instantiated from it.
+func (cm *CompiledModule) Close(ctx context.Context) error {
+	return cm.engine.Close(ctx)
+}
+
+// Close releases rt's resources: for a Runtime returned by NewRuntime,
+// its whole engine (compiled code and running instance together); for a
+// Runtime returned by CompiledModule.Instantiate, just its own guest
+// instance, leaving the shared CompiledModule usable by every other
+// instance taken from it.
+func (rt *Runtime) Close(ctx context.Context) error {
+	if rt.ownsEngine {
+		return rt.engine.Close(ctx)
+	}
+	return rt.mod.Close(ctx)
+}
+
+// MemorySize returns the module's current linear memory size in bytes.
+// It grows monotonically within a single Runtime's lifetime (wasm
+// memory only ever grows), so comparing it across successive
+// CallReducer calls is a cheap way to notice a module leaking memory
+// long before it exhausts its guest address space — see
+// pkg/harness.MemoryTracker.
+func (rt *Runtime) MemorySize() uint32 {
+	return rt.mod.Memory().Size()
+}
+
+// writeToGuestChunkSize bounds each individual Memory().Write call
+// writeToGuest issues. Writing a legitimately large argument buffer in
+// chunks, rather than as one wazero call sized to the whole buffer,
+// keeps a single oversized write from being the first place a bad
+// length surfaces; CallReducer's MaxArgsSize check runs before this is
+// ever reached, so this is purely about not needing one huge internal
+// copy on the happy path.
+const writeToGuestChunkSize = 64 << 10 // 64 KiB
+
+// writeToGuest copies data into the module's linear memory using its
+// exported allocator, returning the guest pointer it was written at.
+func (rt *Runtime) writeToGuest(ctx context.Context, data []byte) (uint32, error) {
+	alloc := rt.mod.ExportedFunction("spacetime_alloc")
+	if alloc == nil {
+		return 0, fmt.Errorf("wasm: module does not export spacetime_alloc")
+	}
+	results, err := alloc.Call(ctx, uint64(len(data)))
+	if err != nil {
+		return 0, fmt.Errorf("wasm: spacetime_alloc: %w", err)
+	}
+	ptr := uint32(results[0])
+	for off := 0; off < len(data); off += writeToGuestChunkSize {
+		end := off + writeToGuestChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if !rt.mod.Memory().Write(ptr+uint32(off), data[off:end]) {
+			return 0, fmt.Errorf("wasm: guest memory write out of bounds")
+		}
+	}
+	return ptr, nil
+}
+
+// CallReducer invokes the module's generic reducer entry point with the
+// given (already-resolved) reducer ID and BSATN-encoded argument bytes.
+//
+// This is an early, minimal form: it guesses that the module exports
+// "__call_reducer__(id, args_ptr, args_len) -> u32" and does not yet parse
+// __describe_module__ to validate the ID or map reducer names to it —
+// unless a caller has installed a manifest via SetReducers, in which
+// case reducerID and a size heuristic on args are validated against it
+// before dispatch (see validateReducerCall).
+//
+// If a datastore has been installed via SetDatastore, the call runs
+// inside a db.Txn: every datastore_update_bsatn, datastore_delete_range_bsatn,
+// and datastore_table_truncate host call the reducer makes writes
+// through it instead of straight to the Engine, and that Txn is
+// committed if the reducer returns nil or rolled back — undoing every
+// one of those writes — if it returns an error, matching SpacetimeDB's
+// atomic reducer semantics. A reducer that panics (see ReducerPanicError)
+// counts as erroring for this purpose. If the rollback itself fails
+// (e.g. the Engine rejects an undo write), that failure is wrapped onto
+// the reducer's own error rather than silently discarded, since it means
+// the reducer's writes were not actually all undone.
+func (rt *Runtime) CallReducer(ctx context.Context, reducerID uint32, args []byte) error {
+	if err := rt.checkArgsSize(args); err != nil {
+		return err
+	}
+	if err := rt.checkRateLimit(ctx, reducerID, args); err != nil {
+		return err
+	}
+	if err := rt.validateReducerCall(reducerID, args); err != nil {
+		return err
+	}
+	if err := rt.checkCircuitBreaker(reducerID); err != nil {
+		return err
+	}
+	rt.txnSeq++
+
+	if rt.datastore != nil {
+		rt.txn = rt.datastore.Begin()
+	}
+
+	rt.hooks.fireReducerStart(rt, reducerID, args)
+	err := rt.callReducer(ctx, reducerID, args)
+	rt.recordCircuitBreakerOutcome(reducerID, err)
+	rt.hooks.fireReducerEnd(rt, reducerID, args, err)
+
+	if rt.txn != nil {
+		txn := rt.txn
+		rt.txn = nil
+		if err != nil {
+			if rbErr := txn.Rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (rollback also failed: %v)", err, rbErr)
+			}
+		} else if commitErr := txn.Commit(); commitErr != nil {
+			err = commitErr
+		}
+	}
+	return err
+}
+
+// callReducer does the actual guest call; split out of CallReducer so
+// OnReducerEnd always fires exactly once, from a single return point,
+// regardless of which of the checks below fails.
+func (rt *Runtime) callReducer(ctx context.Context, reducerID uint32, args []byte) error {
+	fn := rt.mod.ExportedFunction("__call_reducer__")
+	if fn == nil {
+		return fmt.Errorf("wasm: module does not export __call_reducer__")
+	}
+	argsPtr, err := rt.writeToGuest(ctx, args)
+	if err != nil {
+		return err
+	}
+	results, err := fn.Call(ctx, uint64(reducerID), uint64(argsPtr), uint64(len(args)))
+	if err != nil {
+		return &ReducerPanicError{ID: reducerID, Name: rt.reducerName(reducerID), Err: err}
+	}
+	if status := uint32(results[0]); status != 0 {
+		return &ReducerStatusError{ID: reducerID, Name: rt.reducerName(reducerID), Status: status}
+	}
+	return nil
+}
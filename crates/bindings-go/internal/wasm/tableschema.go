@@ -0,0 +1,53 @@
+package wasm
+
+import "github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+
+// IndexSchema describes one secondary index a module's __describe_module__
+// export reports: the numeric indexID datastore_update_bsatn selects it
+// by, its name (for error messages), and a Key func that derives the
+// ordered key a row is stored under for it. Key is nil for an index
+// SetTables should register by name only, leaving rowKey's whole-row
+// fallback in place for it.
+type IndexSchema struct {
+	ID   uint32
+	Name string
+	Key  db.IndexKeyFunc
+}
+
+// TableSchema describes one table a module's __describe_module__ export
+// reports: the numeric tableID datastore host calls select it by, its
+// name (for error messages), and its indexes.
+type TableSchema struct {
+	ID      uint32
+	Name    string
+	Indexes []IndexSchema
+}
+
+// SetTables registers every table and index in tables on rt via
+// RegisterTable, RegisterIndex, and — for an IndexSchema with a non-nil
+// Key — RegisterIndexKey, so datastore_update_bsatn keys a row by the
+// columns an index actually names instead of Runtime.rowKey's whole-row
+// fallback. A caller with a module's __describe_module__ output in hand
+// can build tables from it via internal/moduledef.Decode and
+// ModuleDef.TableSchemas instead of listing them, and deriving each
+// index's Key, by hand.
+//
+// It returns an error, without registering anything, if any call it
+// makes to RegisterTable or RegisterIndex does (e.g. tables exceeds
+// Config.Limits.MaxTables or MaxIndexes).
+func (rt *Runtime) SetTables(tables []TableSchema) error {
+	for _, table := range tables {
+		if err := rt.RegisterTable(table.ID, table.Name); err != nil {
+			return err
+		}
+		for _, idx := range table.Indexes {
+			if err := rt.RegisterIndex(idx.ID, idx.Name); err != nil {
+				return err
+			}
+			if idx.Key != nil {
+				rt.RegisterIndexKey(idx.ID, idx.Key)
+			}
+		}
+	}
+	return nil
+}
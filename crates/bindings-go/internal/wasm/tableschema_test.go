@@ -0,0 +1,54 @@
+package wasm
+
+import (
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func TestSetTablesRegistersIndexKeyUsedByPutRow(t *testing.T) {
+	rt := &Runtime{}
+	rt.SetDatastore(db.NewDatabase(db.NewMemEngine()))
+
+	err := rt.SetTables([]TableSchema{
+		{
+			ID:   1,
+			Name: "player",
+			Indexes: []IndexSchema{
+				{ID: 1, Name: "player_id_idx", Key: firstFieldKey},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SetTables: %v", err)
+	}
+
+	if err := rt.putRow(1, 1, []byte("1,alice")); err != nil {
+		t.Fatalf("putRow: %v", err)
+	}
+	// Same key ("1") with a different rest-of-row: without SetTables
+	// having installed the IndexKeyFunc this would land under a second,
+	// distinct whole-row key instead of updating the first row.
+	if err := rt.putRow(1, 1, []byte("1,alice-renamed")); err != nil {
+		t.Fatalf("putRow: %v", err)
+	}
+
+	count, err := rt.rowCount(1)
+	if err != nil {
+		t.Fatalf("rowCount: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("rowCount = %d, want 1", count)
+	}
+}
+
+func TestSetTablesPropagatesRegisterTableLimitError(t *testing.T) {
+	rt := &Runtime{cfg: Config{Limits: ResourceLimits{MaxTables: 1}}}
+	err := rt.SetTables([]TableSchema{
+		{ID: 1, Name: "a"},
+		{ID: 2, Name: "b"},
+	})
+	if _, ok := err.(*LimitExceededError); !ok {
+		t.Fatalf("err = %v, want *LimitExceededError", err)
+	}
+}
@@ -0,0 +1,114 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+// failingPutEngine wraps a db.Engine and fails every Put whose key
+// matches failKey, used below to force Txn.Rollback's undo loop to hit
+// a genuine Engine error.
+type failingPutEngine struct {
+	db.Engine
+	failKey []byte
+}
+
+func (e *failingPutEngine) Put(table string, key, value []byte) error {
+	if string(key) == string(e.failKey) {
+		return errors.New("wasm: simulated engine fault")
+	}
+	return e.Engine.Put(table, key, value)
+}
+
+func TestCallReducerRollsBackDatastoreWritesOnFailure(t *testing.T) {
+	ctx := context.Background()
+	rt, err := NewRuntime(ctx, emptyModule, Config{})
+	if err != nil {
+		t.Fatalf("NewRuntime: %v", err)
+	}
+	defer rt.Close(ctx)
+
+	rt.SetDatastore(db.NewDatabase(db.NewMemEngine()))
+	rt.RegisterTable(1, "widgets")
+
+	var sawTxnDuringCall bool
+	rt.hooks.OnReducerStart = func(rt *Runtime, _ uint32, _ []byte) {
+		sawTxnDuringCall = rt.txn != nil
+		rt.putRow(1, 0, []byte("row-a"))
+	}
+
+	// emptyModule exports no __call_reducer__, so the call always fails —
+	// the same failure reducerlimits_test.go and hooks_test.go already
+	// rely on to exercise error paths without a real reducer.
+	if err := rt.CallReducer(ctx, 7, nil); err == nil {
+		t.Fatal("expected CallReducer to fail against emptyModule")
+	}
+
+	if !sawTxnDuringCall {
+		t.Fatal("rt.txn was nil inside OnReducerStart, want a Txn begun for the call")
+	}
+	if rt.txn != nil {
+		t.Fatal("rt.txn still set after CallReducer returned, want it cleared")
+	}
+
+	count, err := rt.rowCount(1)
+	if err != nil {
+		t.Fatalf("rowCount: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("rowCount = %d, want 0: the write made during the failed call should have been rolled back", count)
+	}
+}
+
+func TestCallReducerWrapsRollbackFailureIntoReturnedError(t *testing.T) {
+	ctx := context.Background()
+	rt, err := NewRuntime(ctx, emptyModule, Config{})
+	if err != nil {
+		t.Fatalf("NewRuntime: %v", err)
+	}
+	defer rt.Close(ctx)
+
+	engine := &failingPutEngine{Engine: db.NewMemEngine()}
+	database := db.NewDatabase(engine)
+	// Pre-populate so the write below is an update (undo = Put, which
+	// failingPutEngine can intercept) rather than an insert (undo =
+	// Delete).
+	database.Engine.Put("widgets", []byte("row-a"), []byte("row-a"))
+	rt.SetDatastore(database)
+	rt.RegisterTable(1, "widgets")
+
+	rt.hooks.OnReducerStart = func(rt *Runtime, _ uint32, _ []byte) {
+		rt.putRow(1, 0, []byte("row-a"))
+		engine.failKey = []byte("row-a")
+	}
+
+	err = rt.CallReducer(ctx, 7, nil)
+	if err == nil {
+		t.Fatal("expected CallReducer to fail against emptyModule")
+	}
+	if !strings.Contains(err.Error(), "rollback also failed") {
+		t.Fatalf("err = %q, want it to mention the rollback failure instead of discarding it", err.Error())
+	}
+}
+
+func TestCallReducerLeavesDatastoreWritesUntouchedWithoutNewDatastoreInstalled(t *testing.T) {
+	ctx := context.Background()
+	rt, err := NewRuntime(ctx, emptyModule, Config{})
+	if err != nil {
+		t.Fatalf("NewRuntime: %v", err)
+	}
+	defer rt.Close(ctx)
+
+	// No SetDatastore call at all: CallReducer must not panic trying to
+	// begin a transaction against a nil datastore.
+	if err := rt.CallReducer(ctx, 7, nil); err == nil {
+		t.Fatal("expected CallReducer to fail against emptyModule")
+	}
+	if rt.txn != nil {
+		t.Fatal("rt.txn should stay nil when no datastore is installed")
+	}
+}
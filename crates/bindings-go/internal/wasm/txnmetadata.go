@@ -0,0 +1,62 @@
+package wasm
+
+import (
+	"context"
+
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// TxnMetadata is the BSATN-encoded payload the txn_metadata_bsatn host
+// call delivers to a guest: host-provided context a diagnostic reducer
+// under test can assert on, since none of it is otherwise observable
+// from inside a reducer.
+type TxnMetadata struct {
+	// VirtualTimeMicros is the Runtime's clock reading (see SetClock)
+	// at the moment txn_metadata_bsatn was called, as microseconds
+	// since the Unix epoch.
+	VirtualTimeMicros int64
+	// CallerIdentity is the identity Host.CallReducer propagated for
+	// the in-progress call (see identity.WithCaller), or identity.Zero
+	// if the reducer was invoked without one (e.g. directly against
+	// Runtime.CallReducer in a test), as its four u64 limbs: bsatn's
+	// reflect codec does not decode fixed-size arrays, so
+	// identity.Identity's [4]uint64 is flattened to a slice here rather
+	// than embedded directly.
+	CallerIdentity []uint64
+	// SequenceNumber is the Runtime's call counter: 1 for the first
+	// CallReducer invocation made against it, incrementing by one per
+	// call thereafter.
+	SequenceNumber uint64
+}
+
+// hostTxnMetadata implements the txn_metadata_bsatn host call: a
+// test-only, FeatureTxnMetadata-gated way for a module under test to
+// read back the host's view of the current transaction (see
+// TxnMetadata). Disabled, it returns 0, indistinguishable from every
+// other "no source" result bytes_source_read already treats as empty;
+// a module relying on this call must only be run against a host it
+// knows has the feature enabled.
+//
+// The encoded TxnMetadata is handed to the guest the same way an
+// oversized table row would be: opened as a byteSource and read back via
+// repeated bytes_source_read calls, rather than written into guest
+// memory directly, so this call needs no output buffer size negotiated
+// with the guest up front.
+func (rt *Runtime) hostTxnMetadata(ctx context.Context, mod api.Module) uint32 {
+	if !rt.featureEnabled(FeatureTxnMetadata) {
+		return 0
+	}
+	caller, _ := identity.FromContext(ctx)
+	data, err := bsatn.Marshal(TxnMetadata{
+		VirtualTimeMicros: rt.now().UnixMicro(),
+		CallerIdentity:    caller[:],
+		SequenceNumber:    rt.txnSeq,
+	})
+	if err != nil {
+		return 0
+	}
+	return rt.sources.open(data)
+}
@@ -0,0 +1,63 @@
+package wasm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func TestHostTxnMetadataDisabledByDefault(t *testing.T) {
+	rt := &Runtime{sources: newByteSources()}
+	if id := rt.hostTxnMetadata(context.Background(), nil); id != 0 {
+		t.Fatalf("hostTxnMetadata with FeatureTxnMetadata disabled = %d, want 0", id)
+	}
+}
+
+func TestHostTxnMetadataReportsClockIdentityAndSequence(t *testing.T) {
+	rt := &Runtime{
+		sources: newByteSources(),
+		cfg:     Config{Features: map[string]bool{FeatureTxnMetadata: true}},
+	}
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	rt.SetClock(func() time.Time { return fixed })
+	rt.txnSeq = 3
+
+	caller := identity.Identity{1, 2, 3, 4}
+	ctx := identity.WithCaller(context.Background(), caller)
+
+	id := rt.hostTxnMetadata(ctx, nil)
+	if id == 0 {
+		t.Fatal("hostTxnMetadata with FeatureTxnMetadata enabled returned 0")
+	}
+
+	buf := make([]byte, 256)
+	n := rt.sources.read(id, buf)
+	var got TxnMetadata
+	if err := bsatn.Unmarshal(buf[:n], &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.VirtualTimeMicros != fixed.UnixMicro() {
+		t.Errorf("VirtualTimeMicros = %d, want %d", got.VirtualTimeMicros, fixed.UnixMicro())
+	}
+	var gotIdentity identity.Identity
+	copy(gotIdentity[:], got.CallerIdentity)
+	if gotIdentity != caller {
+		t.Errorf("CallerIdentity = %v, want %v", gotIdentity, caller)
+	}
+	if got.SequenceNumber != 3 {
+		t.Errorf("SequenceNumber = %d, want 3", got.SequenceNumber)
+	}
+}
+
+func TestRuntimeNowFallsBackToWallClockWithoutSetClock(t *testing.T) {
+	rt := &Runtime{}
+	before := time.Now()
+	got := rt.now()
+	after := time.Now()
+	if got.Before(before) || got.After(after) {
+		t.Fatalf("now() = %v, want between %v and %v", got, before, after)
+	}
+}
@@ -0,0 +1,40 @@
+package wasm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tetratelabs/wazero"
+)
+
+// requiredExports lists the guest exports a module must provide to be
+// runnable under this host. It is deliberately short: enough to catch
+// "this is not a SpacetimeDB module at all" before spending an
+// instantiation and a failed CallReducer on it.
+var requiredExports = []string{"spacetime_alloc", "__call_reducer__"}
+
+// ValidateABI compiles wasmBytes (without instantiating it, so it does
+// not need a live host module to link against) and checks that it
+// exports the functions this host requires. It returns a descriptive
+// error naming every missing export rather than stopping at the first.
+func ValidateABI(ctx context.Context, wasmBytes []byte) error {
+	engine := wazero.NewRuntime(ctx)
+	defer engine.Close(ctx)
+
+	compiled, err := engine.CompileModule(ctx, wasmBytes)
+	if err != nil {
+		return fmt.Errorf("wasm: not a valid module: %w", err)
+	}
+
+	exports := compiled.ExportedFunctions()
+	var missing []string
+	for _, name := range requiredExports {
+		if _, ok := exports[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("wasm: module missing required exports: %v", missing)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package wasm
+
+import (
+	"context"
+	"testing"
+)
+
+// emptyModule is the minimal valid wasm binary: just the magic number and
+// version, with no sections at all.
+var emptyModule = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+func TestValidateABIRejectsModuleMissingExports(t *testing.T) {
+	err := ValidateABI(context.Background(), emptyModule)
+	if err == nil {
+		t.Fatal("expected error for module with no exports")
+	}
+}
+
+func TestValidateABIRejectsGarbage(t *testing.T) {
+	if err := ValidateABI(context.Background(), []byte("not wasm")); err == nil {
+		t.Fatal("expected error for non-wasm bytes")
+	}
+}
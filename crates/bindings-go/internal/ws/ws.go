@@ -0,0 +1,363 @@
+// Package ws implements the minimal slice of RFC 6455 (WebSocket) this
+// tree needs to speak the SpacetimeDB client protocol: client-side
+// dialing and handshake, and frame reading/writing for both text and
+// binary messages. There is no dependency on a third-party websocket
+// library (this module takes on no dependencies beyond wazero), so this
+// package exists in their place, scoped to exactly what
+// pkg/spacetimedb's client needs rather than the full RFC (no
+// extensions, no per-message compression, no fragmented-frame
+// reassembly beyond what a single logical message requires).
+package ws
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Message types, matching RFC 6455 section 5.2's opcode values for the
+// two data frame kinds callers observe through ReadMessage.
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// websocketGUID is the fixed GUID RFC 6455 section 1.3 has the server
+// concatenate with the client's Sec-WebSocket-Key before hashing, to
+// prove the response came from a server that understood the request as
+// a WebSocket upgrade rather than echoing the header back unexamined.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Conn is a dialed WebSocket connection. It is not safe for concurrent
+// use by multiple goroutines calling ReadMessage or WriteMessage at the
+// same time, matching the underlying net.Conn's own concurrency
+// contract for a single logical stream.
+type Conn struct {
+	nc     net.Conn
+	br     *bufio.Reader
+	client bool // true if this Conn must mask outgoing frames (RFC 6455 section 5.1)
+}
+
+// Dial opens a TCP (or TLS, for wss://) connection to a ws:// or wss://
+// URL and performs the WebSocket opening handshake. header carries any
+// additional request headers the caller wants sent with the upgrade
+// request (e.g. Authorization).
+func Dial(urlStr string, header http.Header) (*Conn, *http.Response, error) {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ws: parse url: %w", err)
+	}
+
+	var nc net.Conn
+	switch u.Scheme {
+	case "ws":
+		nc, err = net.Dial("tcp", hostPort(u, "80"))
+	case "wss":
+		nc, err = tls.Dial("tcp", hostPort(u, "443"), nil)
+	default:
+		return nil, nil, fmt.Errorf("ws: unsupported scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("ws: dial: %w", err)
+	}
+
+	key, err := randomKey()
+	if err != nil {
+		nc.Close()
+		return nil, nil, fmt.Errorf("ws: generate key: %w", err)
+	}
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Opaque: u.RequestURI()},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header.Clone(),
+		Host:       u.Host,
+	}
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if err := req.Write(nc); err != nil {
+		nc.Close()
+		return nil, nil, fmt.Errorf("ws: write handshake request: %w", err)
+	}
+
+	br := bufio.NewReader(nc)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		nc.Close()
+		return nil, nil, fmt.Errorf("ws: read handshake response: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		nc.Close()
+		return nil, resp, fmt.Errorf("ws: server did not switch protocols: %s", resp.Status)
+	}
+	if got, want := resp.Header.Get("Sec-WebSocket-Accept"), acceptKey(key); got != want {
+		nc.Close()
+		return nil, resp, fmt.Errorf("ws: Sec-WebSocket-Accept mismatch: got %q, want %q", got, want)
+	}
+
+	return &Conn{nc: nc, br: br, client: true}, resp, nil
+}
+
+func hostPort(u *url.URL, defaultPort string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), defaultPort)
+}
+
+func randomKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// WriteMessage sends data as a single unfragmented frame of messageType
+// (TextMessage or BinaryMessage), masked per RFC 6455 section 5.1 since
+// every frame this package writes originates from a client.
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	op := byte(opBinary)
+	if messageType == TextMessage {
+		op = opText
+	}
+	frame, err := encodeFrame(op, data, c.client)
+	if err != nil {
+		return err
+	}
+	_, err = c.nc.Write(frame)
+	return err
+}
+
+// ReadMessage reads the next complete message, reassembling it from
+// continuation frames if the sender fragmented it, and transparently
+// answering any ping with a pong before returning the next data
+// message. It returns the message's type (TextMessage or
+// BinaryMessage) and payload.
+func (c *Conn) ReadMessage() (messageType int, data []byte, err error) {
+	for {
+		op, fin, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch op {
+		case opPing:
+			if err := c.writeControl(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			return 0, nil, io.EOF
+		case opText, opBinary:
+			if fin {
+				if op == opText {
+					return TextMessage, payload, nil
+				}
+				return BinaryMessage, payload, nil
+			}
+			return c.readContinuation(op, payload)
+		default:
+			return 0, nil, fmt.Errorf("ws: unexpected opcode %d", op)
+		}
+	}
+}
+
+func (c *Conn) readContinuation(op byte, first []byte) (int, []byte, error) {
+	buf := bytes.NewBuffer(first)
+	for {
+		contOp, fin, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		if contOp != opContinuation {
+			return 0, nil, fmt.Errorf("ws: expected continuation frame, got opcode %d", contOp)
+		}
+		buf.Write(payload)
+		if fin {
+			if op == opText {
+				return TextMessage, buf.Bytes(), nil
+			}
+			return BinaryMessage, buf.Bytes(), nil
+		}
+	}
+}
+
+func (c *Conn) writeControl(op byte, payload []byte) error {
+	frame, err := encodeFrame(op, payload, c.client)
+	if err != nil {
+		return err
+	}
+	_, err = c.nc.Write(frame)
+	return err
+}
+
+// readFrame reads and unmasks (if masked) one raw frame off the wire.
+func (c *Conn) readFrame() (op byte, fin bool, payload []byte, err error) {
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(c.br, head); err != nil {
+		return 0, false, nil, err
+	}
+	fin = head[0]&0x80 != 0
+	op = head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(c.br, ext); err != nil {
+			return 0, false, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(c.br, ext); err != nil {
+			return 0, false, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, maskKey[:]); err != nil {
+			return 0, false, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return 0, false, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+	return op, fin, payload, nil
+}
+
+// encodeFrame builds a single unfragmented frame with the given opcode
+// and payload, masking it if mask is true (required for every frame a
+// client sends, forbidden for every frame a server sends).
+func encodeFrame(op byte, payload []byte, mask bool) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(0x80 | op) // FIN=1, no extensions, opcode
+
+	maskBit := byte(0)
+	if mask {
+		maskBit = 0x80
+	}
+
+	n := len(payload)
+	switch {
+	case n <= 125:
+		buf.WriteByte(maskBit | byte(n))
+	case n <= 0xFFFF:
+		buf.WriteByte(maskBit | 126)
+		var ext [2]byte
+		binary.BigEndian.PutUint16(ext[:], uint16(n))
+		buf.Write(ext[:])
+	default:
+		buf.WriteByte(maskBit | 127)
+		var ext [8]byte
+		binary.BigEndian.PutUint64(ext[:], uint64(n))
+		buf.Write(ext[:])
+	}
+
+	if !mask {
+		buf.Write(payload)
+		return buf.Bytes(), nil
+	}
+
+	var maskKey [4]byte
+	if _, err := rand.Read(maskKey[:]); err != nil {
+		return nil, fmt.Errorf("ws: generate mask key: %w", err)
+	}
+	buf.Write(maskKey[:])
+	masked := make([]byte, n)
+	for i, b := range payload {
+		masked[i] = b ^ maskKey[i%4]
+	}
+	buf.Write(masked)
+	return buf.Bytes(), nil
+}
+
+// Close sends a close frame and closes the underlying connection. It
+// does not wait for the peer's close frame in return.
+func (c *Conn) Close() error {
+	_ = c.writeControl(opClose, nil)
+	return c.nc.Close()
+}
+
+// Upgrade performs the server side of the WebSocket opening handshake
+// on an incoming HTTP request, hijacking its connection. It exists for
+// this package's own tests, standing in for a real SpacetimeDB server.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return nil, fmt.Errorf("ws: not a websocket upgrade request")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, fmt.Errorf("ws: missing Sec-WebSocket-Key")
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("ws: ResponseWriter does not support hijacking")
+	}
+	nc, brw, err := hj.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("ws: hijack: %w", err)
+	}
+
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := brw.WriteString(resp); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("ws: write handshake response: %w", err)
+	}
+	if err := brw.Flush(); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("ws: flush handshake response: %w", err)
+	}
+
+	return &Conn{nc: nc, br: brw.Reader, client: false}, nil
+}
@@ -0,0 +1,99 @@
+package ws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDialUpgradeRoundTripsTextAndBinaryMessages(t *testing.T) {
+	serverDone := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(serverDone)
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			t.Errorf("server Upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		typ, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Errorf("server ReadMessage: %v", err)
+			return
+		}
+		if typ != TextMessage || string(data) != "hello" {
+			t.Errorf("server read (%d, %q), want (%d, %q)", typ, data, TextMessage, "hello")
+		}
+
+		if err := conn.WriteMessage(BinaryMessage, []byte{1, 2, 3, 4}); err != nil {
+			t.Errorf("server WriteMessage: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	wsURL := "ws://" + strings.TrimPrefix(srv.URL, "http://")
+	conn, resp, err := Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status = %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+
+	if err := conn.WriteMessage(TextMessage, []byte("hello")); err != nil {
+		t.Fatalf("client WriteMessage: %v", err)
+	}
+
+	typ, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("client ReadMessage: %v", err)
+	}
+	if typ != BinaryMessage || len(data) != 4 || data[3] != 4 {
+		t.Fatalf("client read (%d, %v), want (%d, [1 2 3 4])", typ, data, BinaryMessage)
+	}
+
+	<-serverDone
+}
+
+func TestReadMessageHandlesLargePayload(t *testing.T) {
+	large := make([]byte, 70000)
+	for i := range large {
+		large[i] = byte(i)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			t.Errorf("server Upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+		if err := conn.WriteMessage(BinaryMessage, large); err != nil {
+			t.Errorf("server WriteMessage: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	wsURL := "ws://" + strings.TrimPrefix(srv.URL, "http://")
+	conn, _, err := Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if len(data) != len(large) {
+		t.Fatalf("len(data) = %d, want %d", len(data), len(large))
+	}
+	for i := range large {
+		if data[i] != large[i] {
+			t.Fatalf("data[%d] = %d, want %d", i, data[i], large[i])
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package bsatn implements SpacetimeDB's Binary SATN (Spacetime Algebraic
+// Type Notation) wire format: fixed-width little-endian integers and floats,
+// u32-length-prefixed strings/bytes/arrays/maps, and u8-tagged sum values.
+// The layout matches the Rust `spacetimedb_sats::bsatn` encoder/decoder so
+// that Go hosts and clients can interoperate with the rest of SpacetimeDB.
+//
+// This package is a stable, standalone API: Marshal/Unmarshal (and the
+// UnmarshalZeroCopy/UnmarshalArena variants for high-throughput
+// decoding) work against any Go value via reflection, MarshalJSON/
+// UnmarshalJSON bridge to encoding/json for tooling that would rather
+// speak JSON, AlgebraicType and its constructors (Bool, I32, ProductOf,
+// SumOf, ...) describe a value's schema, and Writer/Reader give lower-
+// level access to the wire format for a caller hand-rolling its own
+// codec. It has no dependency on the rest of this module, so an
+// external Go service can import it on its own to encode reducer
+// arguments or decode row data without vendoring anything internal.
+package bsatn
+
+import "fmt"
+
+// AlgebraicType is the Go mirror of SpacetimeDB's AlgebraicType: a
+// structural type description used to encode/decode BSATN values and to
+// describe module schemas (tables, reducer arguments, etc).
+type AlgebraicType struct {
+	// Kind selects which of the fields below is meaningful.
+	Kind AlgebraicTypeKind
+
+	// Product holds the element types, in order, when Kind == KindProduct.
+	Product []ProductElement
+	// Sum holds the variant types, in order, when Kind == KindSum.
+	Sum []SumVariant
+	// Array holds the element type when Kind == KindArray.
+	Array *AlgebraicType
+	// MapKey/MapValue hold the key/value types when Kind == KindMap.
+	MapKey   *AlgebraicType
+	MapValue *AlgebraicType
+}
+
+// AlgebraicTypeKind discriminates the shape of an AlgebraicType.
+type AlgebraicTypeKind uint8
+
+const (
+	KindProduct AlgebraicTypeKind = iota
+	KindSum
+	KindArray
+	KindMap
+	KindBool
+	KindI8
+	KindU8
+	KindI16
+	KindU16
+	KindI32
+	KindU32
+	KindI64
+	KindU64
+	KindI128
+	KindU128
+	KindF32
+	KindF64
+	KindString
+)
+
+// ProductElement is a single named field of a product (struct-like) type.
+type ProductElement struct {
+	Name *string
+	Type AlgebraicType
+}
+
+// SumVariant is a single named variant of a sum (enum-like) type.
+type SumVariant struct {
+	Name *string
+	Type AlgebraicType
+}
+
+// Primitive constructors mirroring spacetimedb_sats::AlgebraicType's
+// associated constants.
+func Bool() AlgebraicType   { return AlgebraicType{Kind: KindBool} }
+func I8() AlgebraicType     { return AlgebraicType{Kind: KindI8} }
+func U8() AlgebraicType     { return AlgebraicType{Kind: KindU8} }
+func I16() AlgebraicType    { return AlgebraicType{Kind: KindI16} }
+func U16() AlgebraicType    { return AlgebraicType{Kind: KindU16} }
+func I32() AlgebraicType    { return AlgebraicType{Kind: KindI32} }
+func U32() AlgebraicType    { return AlgebraicType{Kind: KindU32} }
+func I64() AlgebraicType    { return AlgebraicType{Kind: KindI64} }
+func U64() AlgebraicType    { return AlgebraicType{Kind: KindU64} }
+func I128() AlgebraicType   { return AlgebraicType{Kind: KindI128} }
+func U128() AlgebraicType   { return AlgebraicType{Kind: KindU128} }
+func F32() AlgebraicType    { return AlgebraicType{Kind: KindF32} }
+func F64() AlgebraicType    { return AlgebraicType{Kind: KindF64} }
+func String() AlgebraicType { return AlgebraicType{Kind: KindString} }
+
+// Bytes returns the canonical `[]u8` representation used for byte strings.
+func Bytes() AlgebraicType {
+	return AlgebraicType{Kind: KindArray, Array: &AlgebraicType{Kind: KindU8}}
+}
+
+// ArrayOf builds an array (Go slice) type with the given element type.
+func ArrayOf(elem AlgebraicType) AlgebraicType {
+	return AlgebraicType{Kind: KindArray, Array: &elem}
+}
+
+// MapOf builds a map type with the given key/value types.
+func MapOf(key, value AlgebraicType) AlgebraicType {
+	return AlgebraicType{Kind: KindMap, MapKey: &key, MapValue: &value}
+}
+
+// ProductOf builds a product type from the given elements.
+func ProductOf(elems ...ProductElement) AlgebraicType {
+	return AlgebraicType{Kind: KindProduct, Product: elems}
+}
+
+// SumOf builds a sum type from the given variants.
+func SumOf(variants ...SumVariant) AlgebraicType {
+	return AlgebraicType{Kind: KindSum, Sum: variants}
+}
+
+// OptionOf builds the standard SpacetimeDB option encoding: a sum type with
+// a "some" variant (tag 0) carrying inner and a "none" variant (tag 1)
+// carrying the unit product.
+func OptionOf(inner AlgebraicType) AlgebraicType {
+	some, none := "some", "none"
+	return SumOf(
+		SumVariant{Name: &some, Type: inner},
+		SumVariant{Name: &none, Type: ProductOf()},
+	)
+}
+
+func (t AlgebraicTypeKind) String() string {
+	switch t {
+	case KindProduct:
+		return "Product"
+	case KindSum:
+		return "Sum"
+	case KindArray:
+		return "Array"
+	case KindMap:
+		return "Map"
+	case KindBool:
+		return "Bool"
+	case KindI8:
+		return "I8"
+	case KindU8:
+		return "U8"
+	case KindI16:
+		return "I16"
+	case KindU16:
+		return "U16"
+	case KindI32:
+		return "I32"
+	case KindU32:
+		return "U32"
+	case KindI64:
+		return "I64"
+	case KindU64:
+		return "U64"
+	case KindI128:
+		return "I128"
+	case KindU128:
+		return "U128"
+	case KindF32:
+		return "F32"
+	case KindF64:
+		return "F64"
+	case KindString:
+		return "String"
+	default:
+		return fmt.Sprintf("AlgebraicTypeKind(%d)", uint8(t))
+	}
+}
@@ -0,0 +1,49 @@
+package bsatn
+
+// Arena is a reusable buffer that UnmarshalArena copies a decoded
+// value's strings and []byte fields into, instead of giving each field
+// its own heap allocation. Grouping a batch of rows' intermediate
+// allocations into one buffer, released all at once with Reset, cuts
+// the GC pressure a high-churn subscription would otherwise put on the
+// allocator: one growth-amortized buffer instead of one allocation per
+// decoded string/[]byte field per row.
+//
+// An Arena is not safe for concurrent use; each goroutine decoding a
+// batch should use its own.
+type Arena struct {
+	buf []byte
+}
+
+// NewArena returns an Arena with size bytes pre-allocated. size need
+// not be exact — the buffer grows like a slice if a batch needs more —
+// but sizing it close to a typical batch's real footprint avoids paying
+// for that growth on the hot path.
+func NewArena(size int) *Arena {
+	return &Arena{buf: make([]byte, 0, size)}
+}
+
+// Reset discards every allocation Arena has made so far, reusing its
+// backing array for the next batch. Every value previously decoded with
+// UnmarshalArena(data, v, a) becomes invalid the instant Reset is
+// called — a caller must finish using a batch's decoded values (e.g.
+// return from every row's callback) before calling Reset.
+func (a *Arena) Reset() {
+	a.buf = a.buf[:0]
+}
+
+// Len reports how many bytes the arena currently holds, for tests that
+// want to assert a batch reused one growing buffer instead of
+// allocating fresh ones per row.
+func (a *Arena) Len() int {
+	return len(a.buf)
+}
+
+// alloc copies src into a's buffer, growing it like append would if
+// necessary, and returns the copy. The three-index slice expression
+// caps the result at exactly len(src) so a later alloc's append cannot
+// silently overwrite it in place before it grows the backing array.
+func (a *Arena) alloc(src []byte) []byte {
+	start := len(a.buf)
+	a.buf = append(a.buf, src...)
+	return a.buf[start:len(a.buf):len(a.buf)]
+}
@@ -0,0 +1,102 @@
+package bsatn
+
+import "testing"
+
+type arenaRow struct {
+	Name string
+	Data []byte
+}
+
+func TestUnmarshalArenaCopiesOutOfInputBuffer(t *testing.T) {
+	arena := NewArena(64)
+	buf, err := Marshal(arenaRow{Name: "alice", Data: []byte{1, 2, 3}})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var dst arenaRow
+	if err := UnmarshalArena(buf, &dst, arena); err != nil {
+		t.Fatalf("UnmarshalArena: %v", err)
+	}
+	if dst.Name != "alice" {
+		t.Fatalf("Name = %q, want alice", dst.Name)
+	}
+
+	// Mutating the input buffer must not affect the decoded value: unlike
+	// UnmarshalZeroCopy, an arena-decoded value does not alias data.
+	for i := range buf {
+		buf[i] = 0xff
+	}
+	if dst.Name != "alice" {
+		t.Fatalf("Name changed after mutating input buffer: %q", dst.Name)
+	}
+	if string(dst.Data) != "\x01\x02\x03" {
+		t.Fatalf("Data changed after mutating input buffer: %v", dst.Data)
+	}
+}
+
+func TestUnmarshalArenaReusesBufferAcrossRows(t *testing.T) {
+	arena := NewArena(256)
+	rows := []arenaRow{{Name: "one"}, {Name: "two"}, {Name: "three"}}
+
+	var decoded []arenaRow
+	for _, row := range rows {
+		buf, err := Marshal(row)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		var dst arenaRow
+		if err := UnmarshalArena(buf, &dst, arena); err != nil {
+			t.Fatalf("UnmarshalArena: %v", err)
+		}
+		decoded = append(decoded, dst)
+	}
+
+	for i, row := range rows {
+		if decoded[i].Name != row.Name {
+			t.Fatalf("decoded[%d].Name = %q, want %q", i, decoded[i].Name, row.Name)
+		}
+	}
+	if arena.Len() == 0 {
+		t.Fatal("arena.Len() = 0, want the batch's strings to have been copied into it")
+	}
+}
+
+func TestArenaResetInvalidatesLengthNotOldSlices(t *testing.T) {
+	arena := NewArena(16)
+	buf, err := Marshal(arenaRow{Name: "alice"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var dst arenaRow
+	if err := UnmarshalArena(buf, &dst, arena); err != nil {
+		t.Fatalf("UnmarshalArena: %v", err)
+	}
+	if arena.Len() == 0 {
+		t.Fatal("expected arena to have grown")
+	}
+	arena.Reset()
+	if arena.Len() != 0 {
+		t.Fatalf("Len() after Reset = %d, want 0", arena.Len())
+	}
+}
+
+func TestUnmarshalArenaMatchesUnmarshalForNonStringFields(t *testing.T) {
+	arena := NewArena(64)
+	type numeric struct {
+		A int32
+		B uint64
+		C bool
+	}
+	buf, err := Marshal(numeric{A: -5, B: 42, C: true})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var dst numeric
+	if err := UnmarshalArena(buf, &dst, arena); err != nil {
+		t.Fatalf("UnmarshalArena: %v", err)
+	}
+	if dst != (numeric{A: -5, B: 42, C: true}) {
+		t.Fatalf("dst = %+v, want {A:-5 B:42 C:true}", dst)
+	}
+}
@@ -0,0 +1,438 @@
+package bsatn
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"unsafe"
+)
+
+// tagFieldName is the struct tag key used to control BSATN encoding of Go
+// struct fields, mirroring the `#[sats(...)]` attribute on the Rust side.
+const tagFieldName = "bsatn"
+
+// MarshalOption customizes Marshal's and MarshalJSON's encoding behavior.
+type MarshalOption func(*marshalOptions)
+
+type marshalOptions struct {
+	sortMapKeys bool
+}
+
+func newMarshalOptions(opts []MarshalOption) marshalOptions {
+	o := marshalOptions{sortMapKeys: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithSortMapKeys controls whether Marshal/MarshalJSON sort a map's keys
+// into canonical order before encoding (default true). Go's map
+// iteration order is randomized per process, so two calls encoding the
+// same logical map value produce different bytes with this off — which
+// breaks anything that compares or hashes the encoded bytes (row
+// hashing, golden tests). The canonical order is: strings and byte
+// keys compare lexicographically by their raw bytes; integers compare
+// numerically; bools sort false before true. Turn this off only when
+// encoding throughput on very large maps matters more than determinism.
+func WithSortMapKeys(enabled bool) MarshalOption {
+	return func(o *marshalOptions) { o.sortMapKeys = enabled }
+}
+
+// Marshal encodes v into its BSATN representation.
+//
+// Supported Go types: bool, all fixed-width int/uint kinds, float32/64,
+// string, []byte, slices, maps (sorted into canonical key order by
+// default, see WithSortMapKeys), pointers (encoded as an option), and
+// structs (encoded as a product, one field per exported field in
+// declaration order, honoring `bsatn:"-"` to skip a field).
+func Marshal(v any, opts ...MarshalOption) ([]byte, error) {
+	o := newMarshalOptions(opts)
+	w := &writer{buf: make([]byte, 0, 64), sortMapKeys: o.sortMapKeys}
+	if err := w.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+// Unmarshal decodes BSATN-encoded data into v, which must be a non-nil
+// pointer. Every string and []byte in the result is a fresh copy,
+// independent of data.
+func Unmarshal(data []byte, v any) error {
+	return unmarshal(data, v, false, nil)
+}
+
+// UnmarshalZeroCopy decodes BSATN-encoded data into v like Unmarshal,
+// except every string and []byte in the result aliases data instead of
+// being copied. This avoids an allocation per string/byte field, which
+// matters when scanning large string-heavy tables, but it means data
+// must outlive v and must not be mutated while v is in use. Call Clone
+// on v before retaining it past data's lifetime (e.g. past the end of a
+// single table scan callback).
+func UnmarshalZeroCopy(data []byte, v any) error {
+	return unmarshal(data, v, true, nil)
+}
+
+// UnmarshalArena decodes BSATN-encoded data into v like Unmarshal,
+// except every string and []byte in the result is copied into a
+// instead of getting its own heap allocation. v remains valid until a
+// is Reset, at which point it must not be read again. This is meant for
+// decoding a batch of rows sharing one Arena (see Arena's doc comment),
+// not as a replacement for Unmarshal on a single value.
+func UnmarshalArena(data []byte, v any, a *Arena) error {
+	return unmarshal(data, v, false, a)
+}
+
+func unmarshal(data []byte, v any, zeroCopy bool, arena *Arena) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("bsatn: Unmarshal requires a non-nil pointer, got %T", v)
+	}
+	r := &reader{buf: data, zeroCopy: zeroCopy, arena: arena}
+	if err := r.decode(rv.Elem()); err != nil {
+		return err
+	}
+	if r.pos != len(r.buf) {
+		return fmt.Errorf("bsatn: %d trailing bytes after decoding %T", len(r.buf)-r.pos, v)
+	}
+	return nil
+}
+
+type writer struct {
+	buf         []byte
+	sortMapKeys bool
+}
+
+func (w *writer) putU8(x uint8)   { w.buf = append(w.buf, x) }
+func (w *writer) putU16(x uint16) { w.buf = binary.LittleEndian.AppendUint16(w.buf, x) }
+func (w *writer) putU32(x uint32) { w.buf = binary.LittleEndian.AppendUint32(w.buf, x) }
+func (w *writer) putU64(x uint64) { w.buf = binary.LittleEndian.AppendUint64(w.buf, x) }
+
+func (w *writer) putBytes(b []byte) {
+	w.putU32(uint32(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *writer) encode(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			w.putU8(1)
+		} else {
+			w.putU8(0)
+		}
+	case reflect.Int8:
+		w.putU8(uint8(v.Int()))
+	case reflect.Uint8:
+		w.putU8(uint8(v.Uint()))
+	case reflect.Int16:
+		w.putU16(uint16(v.Int()))
+	case reflect.Uint16:
+		w.putU16(uint16(v.Uint()))
+	case reflect.Int32:
+		w.putU32(uint32(v.Int()))
+	case reflect.Uint32:
+		w.putU32(uint32(v.Uint()))
+	case reflect.Int, reflect.Int64:
+		w.putU64(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint64:
+		w.putU64(v.Uint())
+	case reflect.Float32:
+		w.putU32(math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		w.putU64(math.Float64bits(v.Float()))
+	case reflect.String:
+		w.putBytes([]byte(v.String()))
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			w.putBytes(v.Bytes())
+			return nil
+		}
+		w.putU32(uint32(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := w.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		if w.sortMapKeys {
+			sortMapKeysCanonical(keys)
+		}
+		w.putU32(uint32(len(keys)))
+		for _, k := range keys {
+			if err := w.encode(k); err != nil {
+				return err
+			}
+			if err := w.encode(v.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr:
+		// Options are encoded as a sum: tag 0 = some, tag 1 = none.
+		if v.IsNil() {
+			w.putU8(1)
+			return nil
+		}
+		w.putU8(0)
+		return w.encode(v.Elem())
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" || f.Tag.Get(tagFieldName) == "-" {
+				continue
+			}
+			if err := w.encode(v.Field(i)); err != nil {
+				return fmt.Errorf("field %s: %w", f.Name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("bsatn: unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+type reader struct {
+	buf      []byte
+	pos      int
+	zeroCopy bool
+	// arena, if set, holds the copies made of decoded strings/[]byte
+	// instead of giving each one its own heap allocation. Never set
+	// together with zeroCopy, which aliases buf directly and so needs no
+	// copy at all.
+	arena *Arena
+}
+
+func (r *reader) getU8() (uint8, error) {
+	if r.pos+1 > len(r.buf) {
+		return 0, ErrBufferTooSmall
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) getU16() (uint16, error) {
+	if r.pos+2 > len(r.buf) {
+		return 0, ErrBufferTooSmall
+	}
+	x := binary.LittleEndian.Uint16(r.buf[r.pos:])
+	r.pos += 2
+	return x, nil
+}
+
+func (r *reader) getU32() (uint32, error) {
+	if r.pos+4 > len(r.buf) {
+		return 0, ErrBufferTooSmall
+	}
+	x := binary.LittleEndian.Uint32(r.buf[r.pos:])
+	r.pos += 4
+	return x, nil
+}
+
+func (r *reader) getU64() (uint64, error) {
+	if r.pos+8 > len(r.buf) {
+		return 0, ErrBufferTooSmall
+	}
+	x := binary.LittleEndian.Uint64(r.buf[r.pos:])
+	r.pos += 8
+	return x, nil
+}
+
+// sortMapKeysCanonical sorts keys (all of the same reflect.Type, since
+// they come from a single Go map) into the canonical order documented on
+// WithSortMapKeys. Key kinds without a defined canonical order (structs,
+// interfaces) are left in Go's randomized order rather than rejected,
+// since Marshal already supports them as map keys.
+func sortMapKeysCanonical(keys []reflect.Value) {
+	if len(keys) < 2 {
+		return
+	}
+	switch keys[0].Kind() {
+	case reflect.String:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	case reflect.Bool:
+		sort.Slice(keys, func(i, j int) bool { return !keys[i].Bool() && keys[j].Bool() })
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Int() < keys[j].Int() })
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Uint() < keys[j].Uint() })
+	case reflect.Float32, reflect.Float64:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Float() < keys[j].Float() })
+	}
+}
+
+func (r *reader) getBytes() ([]byte, error) {
+	n, err := r.getU32()
+	if err != nil {
+		return nil, err
+	}
+	if r.pos+int(n) > len(r.buf) {
+		return nil, ErrBufferTooSmall
+	}
+	b := r.buf[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+func (r *reader) decode(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := r.getU8()
+		if err != nil {
+			return newDecodeError(r, "Bool", err)
+		}
+		v.SetBool(b != 0)
+	case reflect.Int8:
+		b, err := r.getU8()
+		if err != nil {
+			return newDecodeError(r, "Int8", err)
+		}
+		v.SetInt(int64(int8(b)))
+	case reflect.Uint8:
+		b, err := r.getU8()
+		if err != nil {
+			return newDecodeError(r, "Uint8", err)
+		}
+		v.SetUint(uint64(b))
+	case reflect.Int16:
+		x, err := r.getU16()
+		if err != nil {
+			return newDecodeError(r, "Int16", err)
+		}
+		v.SetInt(int64(int16(x)))
+	case reflect.Uint16:
+		x, err := r.getU16()
+		if err != nil {
+			return newDecodeError(r, "Uint16", err)
+		}
+		v.SetUint(uint64(x))
+	case reflect.Int32:
+		x, err := r.getU32()
+		if err != nil {
+			return newDecodeError(r, "Int32", err)
+		}
+		v.SetInt(int64(int32(x)))
+	case reflect.Uint32:
+		x, err := r.getU32()
+		if err != nil {
+			return newDecodeError(r, "Uint32", err)
+		}
+		v.SetUint(uint64(x))
+	case reflect.Int, reflect.Int64:
+		x, err := r.getU64()
+		if err != nil {
+			return newDecodeError(r, "Int64", err)
+		}
+		v.SetInt(int64(x))
+	case reflect.Uint, reflect.Uint64:
+		x, err := r.getU64()
+		if err != nil {
+			return newDecodeError(r, "Uint64", err)
+		}
+		v.SetUint(x)
+	case reflect.Float32:
+		x, err := r.getU32()
+		if err != nil {
+			return newDecodeError(r, "Float32", err)
+		}
+		v.SetFloat(float64(math.Float32frombits(x)))
+	case reflect.Float64:
+		x, err := r.getU64()
+		if err != nil {
+			return newDecodeError(r, "Float64", err)
+		}
+		v.SetFloat(math.Float64frombits(x))
+	case reflect.String:
+		b, err := r.getBytes()
+		if err != nil {
+			return newDecodeError(r, "String", err)
+		}
+		switch {
+		case r.zeroCopy:
+			v.SetString(unsafe.String(unsafe.SliceData(b), len(b)))
+		case r.arena != nil:
+			copied := r.arena.alloc(b)
+			v.SetString(unsafe.String(unsafe.SliceData(copied), len(copied)))
+		default:
+			v.SetString(string(b))
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := r.getBytes()
+			if err != nil {
+				return newDecodeError(r, "Bytes", err)
+			}
+			switch {
+			case r.zeroCopy:
+				v.SetBytes(b)
+			case r.arena != nil:
+				v.SetBytes(r.arena.alloc(b))
+			default:
+				v.SetBytes(append([]byte(nil), b...))
+			}
+			return nil
+		}
+		n, err := r.getU32()
+		if err != nil {
+			return newDecodeError(r, "slice length", err)
+		}
+		out := reflect.MakeSlice(v.Type(), int(n), int(n))
+		for i := 0; i < int(n); i++ {
+			if err := r.decode(out.Index(i)); err != nil {
+				return prependIndex(err, i)
+			}
+		}
+		v.Set(out)
+	case reflect.Map:
+		n, err := r.getU32()
+		if err != nil {
+			return newDecodeError(r, "map length", err)
+		}
+		out := reflect.MakeMapWithSize(v.Type(), int(n))
+		kt, vt := v.Type().Key(), v.Type().Elem()
+		for i := 0; i < int(n); i++ {
+			kv := reflect.New(kt).Elem()
+			if err := r.decode(kv); err != nil {
+				return prependField(err, fmt.Sprintf("<key %d>", i))
+			}
+			vv := reflect.New(vt).Elem()
+			if err := r.decode(vv); err != nil {
+				return prependField(err, fmt.Sprintf("<value %d>", i))
+			}
+			out.SetMapIndex(kv, vv)
+		}
+		v.Set(out)
+	case reflect.Ptr:
+		tag, err := r.getU8()
+		if err != nil {
+			return newDecodeError(r, "option tag", err)
+		}
+		if tag == 1 {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := r.decode(elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" || f.Tag.Get(tagFieldName) == "-" {
+				continue
+			}
+			if err := r.decode(v.Field(i)); err != nil {
+				return prependField(err, f.Name)
+			}
+		}
+	default:
+		return fmt.Errorf("bsatn: unsupported type %s", v.Type())
+	}
+	return nil
+}
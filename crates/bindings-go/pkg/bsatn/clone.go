@@ -0,0 +1,69 @@
+package bsatn
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Clone detaches every string and []byte reachable from v (a pointer to
+// a value decoded by UnmarshalZeroCopy) from the input buffer they
+// alias, replacing each with a fresh copy. Call it before retaining a
+// zero-copy-decoded value past the lifetime of the buffer passed to
+// UnmarshalZeroCopy, e.g. before storing a scanned row outside the
+// callback that decoded it. Clone is unnecessary, but harmless, for
+// values decoded by the ordinary Unmarshal.
+func Clone(v any) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return
+	}
+	cloneValue(rv.Elem())
+}
+
+func cloneValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(strings.Clone(v.String()))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.Len() > 0 {
+				v.SetBytes(append([]byte(nil), v.Bytes()...))
+			}
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			cloneValue(v.Index(i))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			cloneValue(v.Index(i))
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			val := reflect.New(v.Type().Elem()).Elem()
+			val.Set(v.MapIndex(k))
+			cloneValue(val)
+
+			newKey := k
+			if k.Kind() == reflect.String {
+				newKey = reflect.ValueOf(strings.Clone(k.String())).Convert(k.Type())
+			}
+			if newKey != k {
+				v.SetMapIndex(k, reflect.Value{})
+			}
+			v.SetMapIndex(newKey, val)
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			cloneValue(v.Elem())
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			}
+			cloneValue(v.Field(i))
+		}
+	}
+}
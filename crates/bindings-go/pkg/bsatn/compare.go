@@ -0,0 +1,327 @@
+package bsatn
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Compare orders two BSATN-encoded values of the same keyType the way
+// the server's BTree index does: numerically for every integer and
+// float kind (not as a byte-wise compare of the little-endian wire
+// encoding, which would misorder multi-byte integers), with IEEE-754
+// total order for floats (so -0 sorts before +0 and NaNs sort outside
+// the rest of the range, rather than every NaN comparing unordered),
+// byte-wise for strings and byte arrays, and lexicographically
+// element-by-element for arrays and product (composite) keys. It
+// returns a negative number if a sorts before b, zero if they are
+// equal, and a positive number if a sorts after b — the same contract
+// as bytes.Compare and sort.Slice's less function's sign, so callers
+// comparing composite keys can fall through field by field exactly like
+// a generated Ord impl would.
+//
+// Compare panics if a or b is not validly encoded as keyType; both are
+// expected to already have round-tripped through this package's own
+// Marshal (or the server's matching Rust encoder), so a decode failure
+// here means a caller is comparing keys of the wrong type or handling
+// corrupted storage, not a condition the BTree index, range scans, or a
+// KV backend should try to recover from in-band.
+func Compare(a, b []byte, keyType AlgebraicType) int {
+	ra, rb := &reader{buf: a}, &reader{buf: b}
+	cmp, err := compareValue(ra, rb, keyType)
+	if err != nil {
+		panic(fmt.Sprintf("bsatn: Compare: %v", err))
+	}
+	return cmp
+}
+
+func compareValue(ra, rb *reader, t AlgebraicType) (int, error) {
+	switch t.Kind {
+	case KindBool, KindU8:
+		x, err := ra.getU8()
+		if err != nil {
+			return 0, newDecodeError(ra, t.Kind.String(), err)
+		}
+		y, err := rb.getU8()
+		if err != nil {
+			return 0, newDecodeError(rb, t.Kind.String(), err)
+		}
+		return compareUint64(uint64(x), uint64(y)), nil
+	case KindI8:
+		x, err := ra.getU8()
+		if err != nil {
+			return 0, newDecodeError(ra, "Int8", err)
+		}
+		y, err := rb.getU8()
+		if err != nil {
+			return 0, newDecodeError(rb, "Int8", err)
+		}
+		return compareInt64(int64(int8(x)), int64(int8(y))), nil
+	case KindU16:
+		x, err := ra.getU16()
+		if err != nil {
+			return 0, newDecodeError(ra, "Uint16", err)
+		}
+		y, err := rb.getU16()
+		if err != nil {
+			return 0, newDecodeError(rb, "Uint16", err)
+		}
+		return compareUint64(uint64(x), uint64(y)), nil
+	case KindI16:
+		x, err := ra.getU16()
+		if err != nil {
+			return 0, newDecodeError(ra, "Int16", err)
+		}
+		y, err := rb.getU16()
+		if err != nil {
+			return 0, newDecodeError(rb, "Int16", err)
+		}
+		return compareInt64(int64(int16(x)), int64(int16(y))), nil
+	case KindU32:
+		x, err := ra.getU32()
+		if err != nil {
+			return 0, newDecodeError(ra, "Uint32", err)
+		}
+		y, err := rb.getU32()
+		if err != nil {
+			return 0, newDecodeError(rb, "Uint32", err)
+		}
+		return compareUint64(uint64(x), uint64(y)), nil
+	case KindI32:
+		x, err := ra.getU32()
+		if err != nil {
+			return 0, newDecodeError(ra, "Int32", err)
+		}
+		y, err := rb.getU32()
+		if err != nil {
+			return 0, newDecodeError(rb, "Int32", err)
+		}
+		return compareInt64(int64(int32(x)), int64(int32(y))), nil
+	case KindU64:
+		x, err := ra.getU64()
+		if err != nil {
+			return 0, newDecodeError(ra, "Uint64", err)
+		}
+		y, err := rb.getU64()
+		if err != nil {
+			return 0, newDecodeError(rb, "Uint64", err)
+		}
+		return compareUint64(x, y), nil
+	case KindI64:
+		x, err := ra.getU64()
+		if err != nil {
+			return 0, newDecodeError(ra, "Int64", err)
+		}
+		y, err := rb.getU64()
+		if err != nil {
+			return 0, newDecodeError(rb, "Int64", err)
+		}
+		return compareInt64(int64(x), int64(y)), nil
+	case KindU128:
+		return compare128(ra, rb, false)
+	case KindI128:
+		return compare128(ra, rb, true)
+	case KindF32:
+		x, err := ra.getU32()
+		if err != nil {
+			return 0, newDecodeError(ra, "Float32", err)
+		}
+		y, err := rb.getU32()
+		if err != nil {
+			return 0, newDecodeError(rb, "Float32", err)
+		}
+		return compareUint64(uint64(totalOrderBits32(x)), uint64(totalOrderBits32(y))), nil
+	case KindF64:
+		x, err := ra.getU64()
+		if err != nil {
+			return 0, newDecodeError(ra, "Float64", err)
+		}
+		y, err := rb.getU64()
+		if err != nil {
+			return 0, newDecodeError(rb, "Float64", err)
+		}
+		return compareUint64(totalOrderBits64(x), totalOrderBits64(y)), nil
+	case KindString:
+		x, err := ra.getBytes()
+		if err != nil {
+			return 0, newDecodeError(ra, "String", err)
+		}
+		y, err := rb.getBytes()
+		if err != nil {
+			return 0, newDecodeError(rb, "String", err)
+		}
+		return bytes.Compare(x, y), nil
+	case KindArray:
+		if t.Array.Kind == KindU8 {
+			x, err := ra.getBytes()
+			if err != nil {
+				return 0, newDecodeError(ra, "Bytes", err)
+			}
+			y, err := rb.getBytes()
+			if err != nil {
+				return 0, newDecodeError(rb, "Bytes", err)
+			}
+			return bytes.Compare(x, y), nil
+		}
+		na, err := ra.getU32()
+		if err != nil {
+			return 0, newDecodeError(ra, "array length", err)
+		}
+		nb, err := rb.getU32()
+		if err != nil {
+			return 0, newDecodeError(rb, "array length", err)
+		}
+		n := na
+		if nb < n {
+			n = nb
+		}
+		for i := uint32(0); i < n; i++ {
+			cmp, err := compareValue(ra, rb, *t.Array)
+			if err != nil {
+				return 0, prependIndex(err, int(i))
+			}
+			if cmp != 0 {
+				return cmp, nil
+			}
+		}
+		return compareUint64(uint64(na), uint64(nb)), nil
+	case KindMap:
+		na, err := ra.getU32()
+		if err != nil {
+			return 0, newDecodeError(ra, "map length", err)
+		}
+		nb, err := rb.getU32()
+		if err != nil {
+			return 0, newDecodeError(rb, "map length", err)
+		}
+		n := na
+		if nb < n {
+			n = nb
+		}
+		for i := uint32(0); i < n; i++ {
+			cmp, err := compareValue(ra, rb, *t.MapKey)
+			if err != nil {
+				return 0, prependField(err, fmt.Sprintf("<key %d>", i))
+			}
+			if cmp != 0 {
+				return cmp, nil
+			}
+			cmp, err = compareValue(ra, rb, *t.MapValue)
+			if err != nil {
+				return 0, prependField(err, fmt.Sprintf("<value %d>", i))
+			}
+			if cmp != 0 {
+				return cmp, nil
+			}
+		}
+		return compareUint64(uint64(na), uint64(nb)), nil
+	case KindProduct:
+		for _, elem := range t.Product {
+			name := ""
+			if elem.Name != nil {
+				name = *elem.Name
+			}
+			cmp, err := compareValue(ra, rb, elem.Type)
+			if err != nil {
+				return 0, prependField(err, name)
+			}
+			if cmp != 0 {
+				return cmp, nil
+			}
+		}
+		return 0, nil
+	case KindSum:
+		tagA, err := ra.getU8()
+		if err != nil {
+			return 0, newDecodeError(ra, "sum tag", err)
+		}
+		tagB, err := rb.getU8()
+		if err != nil {
+			return 0, newDecodeError(rb, "sum tag", err)
+		}
+		if tagA != tagB {
+			return compareUint64(uint64(tagA), uint64(tagB)), nil
+		}
+		if int(tagA) >= len(t.Sum) {
+			return 0, newDecodeError(ra, "sum tag", ErrInvalidTag)
+		}
+		return compareValue(ra, rb, t.Sum[tagA].Type)
+	default:
+		return 0, fmt.Errorf("bsatn: unsupported schema kind %s for Compare", t.Kind)
+	}
+}
+
+func compareUint64(x, y uint64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareInt64(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compare128 reads a little-endian 128-bit integer (low u64 word, then
+// high u64 word — the same layout skip() consumes) from each reader and
+// compares them as signed values when signed is true, unsigned
+// otherwise.
+func compare128(ra, rb *reader, signed bool) (int, error) {
+	loA, err := ra.getU64()
+	if err != nil {
+		return 0, newDecodeError(ra, "Int128 low word", err)
+	}
+	hiA, err := ra.getU64()
+	if err != nil {
+		return 0, newDecodeError(ra, "Int128 high word", err)
+	}
+	loB, err := rb.getU64()
+	if err != nil {
+		return 0, newDecodeError(rb, "Int128 low word", err)
+	}
+	hiB, err := rb.getU64()
+	if err != nil {
+		return 0, newDecodeError(rb, "Int128 high word", err)
+	}
+
+	if signed {
+		if cmp := compareInt64(int64(hiA), int64(hiB)); cmp != 0 {
+			return cmp, nil
+		}
+	} else if cmp := compareUint64(hiA, hiB); cmp != 0 {
+		return cmp, nil
+	}
+	return compareUint64(loA, loB), nil
+}
+
+// totalOrderBits64/32 map a float's bit pattern to an unsigned integer
+// whose natural order matches IEEE-754's totalOrder predicate: negative
+// values (including -NaN) sort before positive ones, -0 sorts before
+// +0, and NaNs sort outside the rest of the range instead of comparing
+// unordered the way a direct float comparison would. Flipping every bit
+// of a negative value's representation (rather than just its sign bit)
+// reverses the order of its now-unsigned magnitude bits, since a more
+// negative float has a larger magnitude.
+func totalOrderBits64(bits uint64) uint64 {
+	if bits&(1<<63) != 0 {
+		return ^bits
+	}
+	return bits | (1 << 63)
+}
+
+func totalOrderBits32(bits uint32) uint32 {
+	if bits&(1<<31) != 0 {
+		return ^bits
+	}
+	return bits | (1 << 31)
+}
@@ -0,0 +1,119 @@
+package bsatn
+
+import (
+	"math"
+	"testing"
+)
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal(%v): %v", v, err)
+	}
+	return data
+}
+
+func TestCompareOrdersSignedIntegersNumerically(t *testing.T) {
+	neg := mustMarshal(t, int32(-1))
+	pos := mustMarshal(t, int32(1))
+	if Compare(neg, pos, I32()) >= 0 {
+		t.Error("expected -1 to sort before 1")
+	}
+	if Compare(pos, neg, I32()) <= 0 {
+		t.Error("expected 1 to sort after -1")
+	}
+	if Compare(neg, neg, I32()) != 0 {
+		t.Error("expected a value to compare equal to itself")
+	}
+
+	// A byte-wise comparison of the little-endian encoding would get
+	// this backwards: -1 encodes as 0xFFFFFFFF, which is numerically
+	// larger than 1's 0x01000000 as raw bytes.
+	if Compare(neg, pos, I32()) != -1 {
+		t.Errorf("Compare(-1, 1) = %d, want -1", Compare(neg, pos, I32()))
+	}
+}
+
+func TestCompareOrdersUnsignedIntegersNumerically(t *testing.T) {
+	small := mustMarshal(t, uint64(1))
+	big := mustMarshal(t, uint64(math.MaxUint64))
+	if Compare(small, big, U64()) >= 0 {
+		t.Error("expected 1 to sort before MaxUint64")
+	}
+}
+
+func TestCompareFloatsUseTotalOrder(t *testing.T) {
+	negZero := mustMarshal(t, math.Copysign(0, -1))
+	posZero := mustMarshal(t, float64(0))
+	negOne := mustMarshal(t, float64(-1))
+	posInf := mustMarshal(t, math.Inf(1))
+	negInf := mustMarshal(t, math.Inf(-1))
+	nan := mustMarshal(t, math.NaN())
+
+	if Compare(negZero, posZero, F64()) >= 0 {
+		t.Error("expected -0 to sort before +0 under total order")
+	}
+	if Compare(negInf, negOne, F64()) >= 0 {
+		t.Error("expected -Inf to sort before -1")
+	}
+	if Compare(posInf, nan, F64()) >= 0 {
+		t.Error("expected +Inf to sort before NaN under total order")
+	}
+	if Compare(nan, nan, F64()) != 0 {
+		t.Error("expected NaN to compare equal to itself under total order")
+	}
+}
+
+func TestCompareStringsAreByteWise(t *testing.T) {
+	a := mustMarshal(t, "apple")
+	b := mustMarshal(t, "banana")
+	if Compare(a, b, String()) >= 0 {
+		t.Error("expected \"apple\" to sort before \"banana\"")
+	}
+}
+
+func TestCompareCompositeKeysFallThroughFields(t *testing.T) {
+	name, score := "Name", "Score"
+	schema := ProductOf(
+		ProductElement{Name: &name, Type: String()},
+		ProductElement{Name: &score, Type: I32()},
+	)
+
+	type key struct {
+		Name  string
+		Score int32
+	}
+	aliceLow := mustMarshal(t, key{Name: "alice", Score: 1})
+	aliceHigh := mustMarshal(t, key{Name: "alice", Score: 2})
+	bobLow := mustMarshal(t, key{Name: "bob", Score: 0})
+
+	if Compare(aliceLow, aliceHigh, schema) >= 0 {
+		t.Error("expected equal first field to fall through to the second field")
+	}
+	if Compare(aliceHigh, bobLow, schema) >= 0 {
+		t.Error("expected \"alice\" to sort before \"bob\" regardless of Score")
+	}
+}
+
+func TestCompareArraysAreLexicographicThenByLength(t *testing.T) {
+	short := mustMarshal(t, []int32{1, 2})
+	long := mustMarshal(t, []int32{1, 2, 3})
+	differsEarly := mustMarshal(t, []int32{1, 3})
+
+	if Compare(short, long, ArrayOf(I32())) >= 0 {
+		t.Error("expected a prefix to sort before the longer array it's a prefix of")
+	}
+	if Compare(long, differsEarly, ArrayOf(I32())) >= 0 {
+		t.Error("expected the earliest differing element to decide the order")
+	}
+}
+
+func TestComparePanicsOnMalformedInput(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Compare to panic on truncated input")
+		}
+	}()
+	Compare([]byte{1, 2}, mustMarshal(t, int64(1)), I64())
+}
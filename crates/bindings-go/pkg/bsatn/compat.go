@@ -0,0 +1,129 @@
+package bsatn
+
+import "fmt"
+
+// IncompatibleTypeError explains why TypeCompatible rejected a schema
+// change, in terms of the path (dotted product field names, "[]" for
+// array/map elements, or a sum variant name) at which the incompatible
+// change was found.
+type IncompatibleTypeError struct {
+	Path   string
+	Reason string
+}
+
+func (e *IncompatibleTypeError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("bsatn: incompatible schema change: %s", e.Reason)
+	}
+	return fmt.Sprintf("bsatn: incompatible schema change at %s: %s", e.Path, e.Reason)
+}
+
+// TypeCompatible reports whether new can safely replace old as the type
+// of an already-stored column, reducer argument, or client-generated
+// binding — i.e. whether every value BSATN-encodable under old still
+// decodes correctly (with the same meaning) under new. It returns nil
+// when compatible, or an *IncompatibleTypeError identifying the first
+// incompatible change found otherwise.
+//
+// TypeCompatible allows the changes SpacetimeDB treats as non-breaking
+// schema evolution:
+//   - appending new variants to a sum type (existing tags keep their
+//     meaning; a reader built against old just never sees the new ones)
+//   - appending new fields to a product type
+//   - widening T to OptionOf(T) (every old value is still a "some")
+//
+// It rejects everything else — removing or reordering product fields or
+// sum variants, changing a field's or variant's type, changing Kind, or
+// narrowing an option back to its inner type — because any of those can
+// make an old value decode incorrectly or not at all under new.
+func TypeCompatible(old, new AlgebraicType) error {
+	return typeCompatible("", old, new)
+}
+
+func typeCompatible(path string, old, new AlgebraicType) error {
+	if inner, ok := asOptionOf(new); ok && old.Kind != KindSum {
+		return typeCompatible(path, old, inner)
+	}
+
+	if old.Kind != new.Kind {
+		return &IncompatibleTypeError{Path: path, Reason: fmt.Sprintf("kind changed from %s to %s", old.Kind, new.Kind)}
+	}
+
+	switch old.Kind {
+	case KindProduct:
+		if len(new.Product) < len(old.Product) {
+			return &IncompatibleTypeError{Path: path, Reason: "product has fewer fields than before"}
+		}
+		for i, oldElem := range old.Product {
+			newElem := new.Product[i]
+			name := elementPath(path, oldElem.Name, i)
+			if !sameName(oldElem.Name, newElem.Name) {
+				return &IncompatibleTypeError{Path: name, Reason: "field renamed or reordered"}
+			}
+			if err := typeCompatible(name, oldElem.Type, newElem.Type); err != nil {
+				return err
+			}
+		}
+	case KindSum:
+		if len(new.Sum) < len(old.Sum) {
+			return &IncompatibleTypeError{Path: path, Reason: "sum has fewer variants than before"}
+		}
+		for i, oldVariant := range old.Sum {
+			newVariant := new.Sum[i]
+			name := elementPath(path, oldVariant.Name, i)
+			if !sameName(oldVariant.Name, newVariant.Name) {
+				return &IncompatibleTypeError{Path: name, Reason: "variant renamed or reordered"}
+			}
+			if err := typeCompatible(name, oldVariant.Type, newVariant.Type); err != nil {
+				return err
+			}
+		}
+	case KindArray:
+		if err := typeCompatible(path+"[]", *old.Array, *new.Array); err != nil {
+			return err
+		}
+	case KindMap:
+		if err := typeCompatible(path+"[key]", *old.MapKey, *new.MapKey); err != nil {
+			return err
+		}
+		if err := typeCompatible(path+"[value]", *old.MapValue, *new.MapValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// asOptionOf reports whether t has the shape OptionOf builds — a sum of
+// exactly a "some" variant and a nullary "none" variant — and returns
+// the "some" variant's inner type.
+func asOptionOf(t AlgebraicType) (AlgebraicType, bool) {
+	if t.Kind != KindSum || len(t.Sum) != 2 {
+		return AlgebraicType{}, false
+	}
+	some, none := t.Sum[0], t.Sum[1]
+	if some.Name == nil || *some.Name != "some" {
+		return AlgebraicType{}, false
+	}
+	if none.Name == nil || *none.Name != "none" || none.Type.Kind != KindProduct || len(none.Type.Product) != 0 {
+		return AlgebraicType{}, false
+	}
+	return some.Type, true
+}
+
+func sameName(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func elementPath(path string, name *string, index int) string {
+	label := fmt.Sprintf("[%d]", index)
+	if name != nil {
+		label = *name
+	}
+	if path == "" {
+		return label
+	}
+	return path + "." + label
+}
@@ -0,0 +1,106 @@
+package bsatn
+
+import "testing"
+
+func mustIncompatible(t *testing.T, err error, wantPath string) {
+	t.Helper()
+	if err == nil {
+		t.Fatal("TypeCompatible: want error, got nil")
+	}
+	ite, ok := err.(*IncompatibleTypeError)
+	if !ok {
+		t.Fatalf("err = %T(%v), want *IncompatibleTypeError", err, err)
+	}
+	if wantPath != "" && ite.Path != wantPath {
+		t.Fatalf("Path = %q, want %q", ite.Path, wantPath)
+	}
+}
+
+func TestTypeCompatibleIdenticalTypes(t *testing.T) {
+	name := "id"
+	p := ProductOf(ProductElement{Name: &name, Type: U32()})
+	if err := TypeCompatible(p, p); err != nil {
+		t.Fatalf("TypeCompatible: %v", err)
+	}
+}
+
+func TestTypeCompatibleAppendingProductField(t *testing.T) {
+	id, added := "id", "nickname"
+	old := ProductOf(ProductElement{Name: &id, Type: U32()})
+	new := ProductOf(ProductElement{Name: &id, Type: U32()}, ProductElement{Name: &added, Type: String()})
+	if err := TypeCompatible(old, new); err != nil {
+		t.Fatalf("TypeCompatible: %v", err)
+	}
+}
+
+func TestTypeCompatibleRemovingProductFieldRejected(t *testing.T) {
+	id, name := "id", "name"
+	old := ProductOf(ProductElement{Name: &id, Type: U32()}, ProductElement{Name: &name, Type: String()})
+	new := ProductOf(ProductElement{Name: &id, Type: U32()})
+	mustIncompatible(t, TypeCompatible(old, new), "")
+}
+
+func TestTypeCompatibleReorderingProductFieldsRejected(t *testing.T) {
+	id, name := "id", "name"
+	old := ProductOf(ProductElement{Name: &id, Type: U32()}, ProductElement{Name: &name, Type: String()})
+	new := ProductOf(ProductElement{Name: &name, Type: String()}, ProductElement{Name: &id, Type: U32()})
+	mustIncompatible(t, TypeCompatible(old, new), "id")
+}
+
+func TestTypeCompatibleAppendingSumVariant(t *testing.T) {
+	active, banned := "active", "banned"
+	old := SumOf(SumVariant{Name: &active, Type: ProductOf()})
+	new := SumOf(SumVariant{Name: &active, Type: ProductOf()}, SumVariant{Name: &banned, Type: ProductOf()})
+	if err := TypeCompatible(old, new); err != nil {
+		t.Fatalf("TypeCompatible: %v", err)
+	}
+}
+
+func TestTypeCompatibleRemovingSumVariantRejected(t *testing.T) {
+	active, banned := "active", "banned"
+	old := SumOf(SumVariant{Name: &active, Type: ProductOf()}, SumVariant{Name: &banned, Type: ProductOf()})
+	new := SumOf(SumVariant{Name: &active, Type: ProductOf()})
+	mustIncompatible(t, TypeCompatible(old, new), "")
+}
+
+func TestTypeCompatibleWideningToOption(t *testing.T) {
+	old := String()
+	new := OptionOf(String())
+	if err := TypeCompatible(old, new); err != nil {
+		t.Fatalf("TypeCompatible: %v", err)
+	}
+}
+
+func TestTypeCompatibleNarrowingFromOptionRejected(t *testing.T) {
+	old := OptionOf(String())
+	new := String()
+	mustIncompatible(t, TypeCompatible(old, new), "")
+}
+
+func TestTypeCompatibleChangingFieldTypeRejected(t *testing.T) {
+	id := "id"
+	old := ProductOf(ProductElement{Name: &id, Type: U32()})
+	new := ProductOf(ProductElement{Name: &id, Type: String()})
+	mustIncompatible(t, TypeCompatible(old, new), "id")
+}
+
+func TestTypeCompatibleArrayElementTypeChangeRejected(t *testing.T) {
+	old := ArrayOf(U32())
+	new := ArrayOf(String())
+	mustIncompatible(t, TypeCompatible(old, new), "[]")
+}
+
+func TestTypeCompatibleNestedProductWideningToOption(t *testing.T) {
+	id, addr := "id", "address"
+	old := ProductOf(ProductElement{Name: &id, Type: U32()}, ProductElement{Name: &addr, Type: String()})
+	new := ProductOf(ProductElement{Name: &id, Type: U32()}, ProductElement{Name: &addr, Type: OptionOf(String())})
+	if err := TypeCompatible(old, new); err != nil {
+		t.Fatalf("TypeCompatible: %v", err)
+	}
+}
+
+func TestTypeCompatibleMapValueTypeChangeRejected(t *testing.T) {
+	old := MapOf(String(), U32())
+	new := MapOf(String(), String())
+	mustIncompatible(t, TypeCompatible(old, new), "[value]")
+}
@@ -0,0 +1,126 @@
+package bsatn
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// enumRegistry maps a Go type registered via RegisterEnum to its
+// variant names, in tag order. DeriveAlgebraicType consults it before
+// falling back to reflect.Kind, since a registered enum's underlying
+// integer kind would otherwise derive as a plain IntN/UintN instead of
+// the sum the module schema actually declares.
+var (
+	enumRegistryMu sync.RWMutex
+	enumRegistry   = map[reflect.Type][]string{}
+)
+
+// RegisterEnum tells DeriveAlgebraicType to render t (an integer-kinded
+// Go type used like an enum) as a SATS sum with one unit-payload variant
+// per name in variants, in tag order. Call it once per enum type, e.g.
+// in an init() alongside the type's declaration.
+func RegisterEnum(t reflect.Type, variants []string) {
+	enumRegistryMu.Lock()
+	defer enumRegistryMu.Unlock()
+	enumRegistry[t] = append([]string(nil), variants...)
+}
+
+// DeriveAlgebraicType computes the canonical AlgebraicType for a Go
+// type, so callers don't have to hand-build a schema that must be kept
+// in sync with the Go struct by hand:
+//
+//   - a type registered with RegisterEnum derives as a sum with one
+//     unit-payload variant per registered name
+//   - structs derive as products, one element per exported field (in
+//     declaration order), honoring `bsatn:"-"` the same way Marshal does
+//   - pointers derive as an option over the pointee type
+//   - []byte derives as Bytes(); other slices/arrays derive as an array
+//     of the element type
+//   - maps derive as MapOf(keyType, valueType)
+//   - remaining kinds map onto the matching AlgebraicType primitive
+//
+// It returns an error for kinds with no BSATN representation (chan,
+// func, unsafe.Pointer, interfaces, and complex numbers).
+func DeriveAlgebraicType(t reflect.Type) (AlgebraicType, error) {
+	enumRegistryMu.RLock()
+	variants, isEnum := enumRegistry[t]
+	enumRegistryMu.RUnlock()
+	if isEnum {
+		sum := make([]SumVariant, len(variants))
+		for i, name := range variants {
+			name := name
+			sum[i] = SumVariant{Name: &name, Type: ProductOf()}
+		}
+		return SumOf(sum...), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Bool(), nil
+	case reflect.Int8:
+		return I8(), nil
+	case reflect.Uint8:
+		return U8(), nil
+	case reflect.Int16:
+		return I16(), nil
+	case reflect.Uint16:
+		return U16(), nil
+	case reflect.Int32:
+		return I32(), nil
+	case reflect.Uint32:
+		return U32(), nil
+	case reflect.Int, reflect.Int64:
+		return I64(), nil
+	case reflect.Uint, reflect.Uint64:
+		return U64(), nil
+	case reflect.Float32:
+		return F32(), nil
+	case reflect.Float64:
+		return F64(), nil
+	case reflect.String:
+		return String(), nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Bytes(), nil
+		}
+		elem, err := DeriveAlgebraicType(t.Elem())
+		if err != nil {
+			return AlgebraicType{}, err
+		}
+		return ArrayOf(elem), nil
+	case reflect.Map:
+		key, err := DeriveAlgebraicType(t.Key())
+		if err != nil {
+			return AlgebraicType{}, err
+		}
+		value, err := DeriveAlgebraicType(t.Elem())
+		if err != nil {
+			return AlgebraicType{}, err
+		}
+		return MapOf(key, value), nil
+	case reflect.Ptr:
+		inner, err := DeriveAlgebraicType(t.Elem())
+		if err != nil {
+			return AlgebraicType{}, err
+		}
+		return OptionOf(inner), nil
+	case reflect.Struct:
+		var elems []ProductElement
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" || f.Tag.Get(tagFieldName) == "-" {
+				continue
+			}
+			fieldType, err := DeriveAlgebraicType(f.Type)
+			if err != nil {
+				return AlgebraicType{}, fmt.Errorf("field %s: %w", f.Name, err)
+			}
+			name := f.Name
+			elems = append(elems, ProductElement{Name: &name, Type: fieldType})
+		}
+		return ProductOf(elems...), nil
+	default:
+		return AlgebraicType{}, fmt.Errorf("bsatn: cannot derive AlgebraicType for %s", t)
+	}
+}
@@ -0,0 +1,83 @@
+package bsatn
+
+import (
+	"reflect"
+	"testing"
+)
+
+type point struct {
+	X int32
+	Y int32
+}
+
+type withOptions struct {
+	Name  string
+	Tags  []string
+	Extra *int32
+	Meta  map[string]int32
+	Skip  int32 `bsatn:"-"`
+}
+
+func TestDeriveAlgebraicTypeStruct(t *testing.T) {
+	got, err := DeriveAlgebraicType(reflect.TypeOf(point{}))
+	if err != nil {
+		t.Fatalf("DeriveAlgebraicType: %v", err)
+	}
+	want := ProductOf(
+		ProductElement{Name: strPtr("X"), Type: I32()},
+		ProductElement{Name: strPtr("Y"), Type: I32()},
+	)
+	assertEqualType(t, got, want)
+}
+
+func TestDeriveAlgebraicTypeSkipsIgnoredField(t *testing.T) {
+	got, err := DeriveAlgebraicType(reflect.TypeOf(withOptions{}))
+	if err != nil {
+		t.Fatalf("DeriveAlgebraicType: %v", err)
+	}
+	if len(got.Product) != 4 {
+		t.Fatalf("got %d fields, want 4 (Skip excluded): %+v", len(got.Product), got.Product)
+	}
+	if got.Product[2].Type.Kind != KindSum {
+		t.Fatalf("Extra (*int32) should derive as an option (sum), got %v", got.Product[2].Type.Kind)
+	}
+	if got.Product[3].Type.Kind != KindMap {
+		t.Fatalf("Meta should derive as a map, got %v", got.Product[3].Type.Kind)
+	}
+}
+
+type trafficLight int
+
+const (
+	trafficRed trafficLight = iota
+	trafficYellow
+	trafficGreen
+)
+
+func TestDeriveAlgebraicTypeRegisteredEnum(t *testing.T) {
+	RegisterEnum(reflect.TypeOf(trafficRed), []string{"red", "yellow", "green"})
+	got, err := DeriveAlgebraicType(reflect.TypeOf(trafficGreen))
+	if err != nil {
+		t.Fatalf("DeriveAlgebraicType: %v", err)
+	}
+	if got.Kind != KindSum || len(got.Sum) != 3 {
+		t.Fatalf("got %+v, want a 3-variant sum", got)
+	}
+	if *got.Sum[2].Name != "green" {
+		t.Fatalf("variant 2 name = %q, want green", *got.Sum[2].Name)
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func assertEqualType(t *testing.T, got, want AlgebraicType) {
+	t.Helper()
+	if got.Kind != want.Kind || len(got.Product) != len(want.Product) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range got.Product {
+		if *got.Product[i].Name != *want.Product[i].Name || got.Product[i].Type.Kind != want.Product[i].Type.Kind {
+			t.Fatalf("field %d: got %+v, want %+v", i, got.Product[i], want.Product[i])
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package bsatn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrBufferTooSmall is returned when the input buffer ends before a value
+// has been fully decoded.
+var ErrBufferTooSmall = errors.New("bsatn: buffer too small")
+
+// ErrInvalidTag is returned when a sum type's discriminant byte does not
+// correspond to any known variant.
+var ErrInvalidTag = errors.New("bsatn: invalid sum tag")
+
+// DecodeError wraps a decode failure with enough context to find the
+// exact byte and field that caused it: Offset is the byte position in
+// the input where decoding was attempting to read, Path names the field
+// (and, for slices, index) at that point using dotted/bracketed
+// notation like "Rows[3].Name", Expected describes what was being
+// decoded, and Got describes what was actually found. Err is the
+// underlying sentinel (ErrBufferTooSmall, ErrInvalidTag, ...) or reader
+// error; use errors.Is/errors.As against it, not against DecodeError
+// itself.
+type DecodeError struct {
+	Offset   int
+	Path     string
+	Expected string
+	Got      string
+	Err      error
+}
+
+func (e *DecodeError) Error() string {
+	var path string
+	if e.Path != "" {
+		path = fmt.Sprintf(" at %s", e.Path)
+	}
+	return fmt.Sprintf("bsatn: decode error%s (offset %d): expected %s, got %s: %v", path, e.Offset, e.Expected, e.Got, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// newDecodeError builds a DecodeError anchored at r's current read
+// position, for a leaf decode failure with no path segment yet (callers
+// higher up the call stack attach one via prependField/prependIndex as
+// the error returns through them).
+func newDecodeError(r *reader, expected string, err error) error {
+	got := fmt.Sprintf("%d bytes remaining", len(r.buf)-r.pos)
+	if errors.Is(err, ErrInvalidTag) {
+		got = "an out-of-range tag"
+	}
+	return &DecodeError{Offset: r.pos, Expected: expected, Got: got, Err: err}
+}
+
+// prependField attaches name to the front of err's DecodeError.Path, if
+// err wraps one; otherwise err is returned unchanged. A leading "["
+// (from a slice index closer to the failure) is joined without a dot,
+// e.g. name="Rows" + path="[3]" -> "Rows[3]"; otherwise a dot separates
+// the two names, e.g. name="Meta" + path="Value" -> "Meta.Value".
+func prependField(err error, name string) error {
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		return err
+	}
+	switch {
+	case de.Path == "":
+		de.Path = name
+	case strings.HasPrefix(de.Path, "["):
+		de.Path = name + de.Path
+	default:
+		de.Path = name + "." + de.Path
+	}
+	return de
+}
+
+// prependIndex attaches a "[i]" segment to the front of err's
+// DecodeError.Path, if err wraps one; otherwise err is returned
+// unchanged. A following field name is dot-separated, e.g. index=1 +
+// path="Name" -> "[1].Name"; a following index is joined directly, e.g.
+// index=1 + path="[2]" -> "[1][2]".
+func prependIndex(err error, i int) error {
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		return err
+	}
+	sep := "."
+	if de.Path == "" || strings.HasPrefix(de.Path, "[") {
+		sep = ""
+	}
+	de.Path = fmt.Sprintf("[%d]%s%s", i, sep, de.Path)
+	return de
+}
@@ -0,0 +1,49 @@
+package bsatn
+
+import (
+	"errors"
+	"testing"
+)
+
+type withNested struct {
+	Meta nestedMeta
+}
+
+type nestedMeta struct {
+	Rows []nestedRow
+}
+
+type nestedRow struct {
+	Name string
+}
+
+func TestDecodeErrorReportsPathAndOffset(t *testing.T) {
+	full, err := Marshal(withNested{Meta: nestedMeta{Rows: []nestedRow{{Name: "a"}, {Name: "bb"}}}})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	// Truncate mid-way through the second row's string, so decoding fails
+	// while inside Meta.Rows[1].Name.
+	truncated := full[:len(full)-1]
+
+	var dst withNested
+	err = Unmarshal(truncated, &dst)
+	if err == nil {
+		t.Fatal("expected error decoding truncated input")
+	}
+
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected *DecodeError, got %T: %v", err, err)
+	}
+	if de.Path != "Meta.Rows[1].Name" {
+		t.Fatalf("Path = %q, want %q", de.Path, "Meta.Rows[1].Name")
+	}
+	if de.Offset != len(truncated)-1 {
+		t.Fatalf("Offset = %d, want %d", de.Offset, len(truncated)-1)
+	}
+	if !errors.Is(err, ErrBufferTooSmall) {
+		t.Fatalf("expected errors.Is match against ErrBufferTooSmall, got %v", err)
+	}
+}
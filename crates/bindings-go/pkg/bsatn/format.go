@@ -0,0 +1,122 @@
+package bsatn
+
+import "fmt"
+
+// Format identifies a revision of the BSATN wire encoding Marshal and
+// Unmarshal produce and consume. There is exactly one today — this type,
+// NegotiateFormat, and MarshalVersioned/UnmarshalVersioned exist so a
+// future incompatible revision has somewhere to plug in without changing
+// Marshal's and Unmarshal's signatures, which every existing caller in
+// this module (and every generated binding) depends on staying stable.
+type Format uint8
+
+// FormatV1 is the only format this package has ever produced: the
+// encoding Marshal and Unmarshal implement today, with no leading
+// version tag of its own.
+const FormatV1 Format = 1
+
+func (f Format) String() string {
+	switch f {
+	case FormatV1:
+		return "v1"
+	default:
+		return fmt.Sprintf("Format(%d)", uint8(f))
+	}
+}
+
+// CurrentFormat is the format this package's Marshal produces, and the
+// one a connection or host should advertise as most-preferred when
+// negotiating with NegotiateFormat.
+const CurrentFormat = FormatV1
+
+// supportedFormats lists every format this build's Unmarshal can decode.
+// It has one entry today; a future format revision adds to it rather
+// than replacing it, the same way protocol.supportedKinds grows per
+// server version instead of being replaced wholesale.
+var supportedFormats = map[Format]bool{
+	FormatV1: true,
+}
+
+// Supported reports whether this package can decode f.
+func Supported(f Format) bool {
+	return supportedFormats[f]
+}
+
+// ErrNoCommonFormat is returned by NegotiateFormat when local and remote
+// share no format in common — today that can only happen if a caller
+// passes an empty or entirely-unrecognized list, since FormatV1 is the
+// only format either side of this codebase ever advertises.
+type ErrNoCommonFormat struct {
+	Local  []Format
+	Remote []Format
+}
+
+func (e *ErrNoCommonFormat) Error() string {
+	return fmt.Sprintf("bsatn: no common format between local %v and remote %v", e.Local, e.Remote)
+}
+
+// NegotiateFormat picks the highest-numbered format present in both
+// local and remote — the format a connection or host should use for the
+// rest of its lifetime. Pass every format this build can decode, newest
+// first or in any order, as local; NegotiateFormat always prefers a
+// newer shared format over an older one regardless of either slice's
+// order.
+func NegotiateFormat(local, remote []Format) (Format, error) {
+	remoteSet := make(map[Format]bool, len(remote))
+	for _, f := range remote {
+		remoteSet[f] = true
+	}
+	var best Format
+	for _, f := range local {
+		if remoteSet[f] && f > best {
+			best = f
+		}
+	}
+	if best == 0 {
+		return 0, &ErrNoCommonFormat{Local: local, Remote: remote}
+	}
+	return best, nil
+}
+
+// UnsupportedFormatError is returned by UnmarshalVersioned when data's
+// leading format tag names a format this build of the package does not
+// know how to decode — e.g. bytes written by a newer client after a
+// future format revision ships.
+type UnsupportedFormatError struct {
+	Format Format
+}
+
+func (e *UnsupportedFormatError) Error() string {
+	return fmt.Sprintf("bsatn: unsupported format %s", e.Format)
+}
+
+// MarshalVersioned encodes v the same way Marshal does, prefixed with a
+// one-byte Format tag identifying the encoding that follows. Use this,
+// not Marshal, for bytes that might still be read back after a future
+// format revision ships — e.g. anything persisted to disk, rather than
+// exchanged and discarded within a single negotiated connection.
+func MarshalVersioned(format Format, v any, opts ...MarshalOption) ([]byte, error) {
+	if !Supported(format) {
+		return nil, &UnsupportedFormatError{Format: format}
+	}
+	data, err := Marshal(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(format)}, data...), nil
+}
+
+// UnmarshalVersioned decodes data written by MarshalVersioned into out,
+// rejecting it with an *UnsupportedFormatError if its leading format tag
+// names a format this build does not know how to decode, rather than
+// misinterpreting the bytes that follow as FormatV1.
+func UnmarshalVersioned(data []byte, out any) error {
+	if len(data) < 1 {
+		return fmt.Errorf("bsatn: UnmarshalVersioned: empty input")
+	}
+	format := Format(data[0])
+	if !Supported(format) {
+		return &UnsupportedFormatError{Format: format}
+	}
+	return Unmarshal(data[1:], out)
+}
@@ -0,0 +1,64 @@
+package bsatn
+
+import "testing"
+
+func TestNegotiateFormatPicksHighestShared(t *testing.T) {
+	got, err := NegotiateFormat([]Format{FormatV1}, []Format{FormatV1})
+	if err != nil {
+		t.Fatalf("NegotiateFormat: %v", err)
+	}
+	if got != FormatV1 {
+		t.Fatalf("NegotiateFormat = %s, want %s", got, FormatV1)
+	}
+}
+
+func TestNegotiateFormatErrorsWithNoCommonFormat(t *testing.T) {
+	_, err := NegotiateFormat([]Format{FormatV1}, []Format{Format(99)})
+	if err == nil {
+		t.Fatal("NegotiateFormat: want an error, got nil")
+	}
+	noCommon, ok := err.(*ErrNoCommonFormat)
+	if !ok {
+		t.Fatalf("err = %T, want *ErrNoCommonFormat", err)
+	}
+	if len(noCommon.Remote) != 1 || noCommon.Remote[0] != Format(99) {
+		t.Fatalf("ErrNoCommonFormat.Remote = %v, want [99]", noCommon.Remote)
+	}
+}
+
+func TestMarshalVersionedRoundTrips(t *testing.T) {
+	data, err := MarshalVersioned(CurrentFormat, "hello")
+	if err != nil {
+		t.Fatalf("MarshalVersioned: %v", err)
+	}
+	if data[0] != byte(CurrentFormat) {
+		t.Fatalf("leading byte = %d, want %d", data[0], byte(CurrentFormat))
+	}
+	var out string
+	if err := UnmarshalVersioned(data, &out); err != nil {
+		t.Fatalf("UnmarshalVersioned: %v", err)
+	}
+	if out != "hello" {
+		t.Fatalf("out = %q, want hello", out)
+	}
+}
+
+func TestMarshalVersionedRejectsUnsupportedFormat(t *testing.T) {
+	_, err := MarshalVersioned(Format(99), "hello")
+	unsupported, ok := err.(*UnsupportedFormatError)
+	if !ok {
+		t.Fatalf("err = %T, want *UnsupportedFormatError", err)
+	}
+	if unsupported.Format != Format(99) {
+		t.Fatalf("UnsupportedFormatError.Format = %d, want 99", unsupported.Format)
+	}
+}
+
+func TestUnmarshalVersionedRejectsUnknownFormatTag(t *testing.T) {
+	data := append([]byte{99}, []byte("garbage")...)
+	var out string
+	err := UnmarshalVersioned(data, &out)
+	if _, ok := err.(*UnsupportedFormatError); !ok {
+		t.Fatalf("err = %T, want *UnsupportedFormatError", err)
+	}
+}
@@ -0,0 +1,180 @@
+package bsatn
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// MarshalJSON encodes v — a value produced by encoding/json.Unmarshal
+// into `any` (map[string]any for objects, []any for arrays, float64 for
+// numbers, string, bool, or nil) — into its BSATN representation per
+// schema. It is the JSON-driven counterpart to UnmarshalTyped: since
+// BSATN carries no field names on the wire, a schema is required to
+// know which JSON object key maps to which product element, and in
+// which order to emit them.
+//
+// Sum values are represented in JSON as {"tag": "<variant name>",
+// "value": <variant payload>}. Byte arrays (KindArray of KindU8) accept
+// either a JSON string (encoded as its UTF-8 bytes) or an array of
+// small integers.
+//
+// Map keys (JSON object keys, always strings on the wire) are sorted
+// lexicographically before encoding by default; see WithSortMapKeys.
+func MarshalJSON(v any, schema AlgebraicType, opts ...MarshalOption) ([]byte, error) {
+	o := newMarshalOptions(opts)
+	w := &writer{buf: make([]byte, 0, 64), sortMapKeys: o.sortMapKeys}
+	if err := encodeJSONValue(w, v, schema); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+func encodeJSONValue(w *writer, v any, t AlgebraicType) error {
+	switch t.Kind {
+	case KindBool:
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("bsatn: expected bool, got %T", v)
+		}
+		if b {
+			w.putU8(1)
+		} else {
+			w.putU8(0)
+		}
+	case KindI8, KindU8, KindI16, KindU16, KindI32, KindU32, KindI64, KindU64:
+		n, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("bsatn: expected number for %s, got %T", t.Kind, v)
+		}
+		switch t.Kind {
+		case KindI8:
+			w.putU8(uint8(int8(n)))
+		case KindU8:
+			w.putU8(uint8(n))
+		case KindI16:
+			w.putU16(uint16(int16(n)))
+		case KindU16:
+			w.putU16(uint16(n))
+		case KindI32:
+			w.putU32(uint32(int32(n)))
+		case KindU32:
+			w.putU32(uint32(n))
+		case KindI64:
+			w.putU64(uint64(int64(n)))
+		case KindU64:
+			w.putU64(uint64(n))
+		}
+	case KindF32:
+		n, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("bsatn: expected number for F32, got %T", v)
+		}
+		w.putU32(math.Float32bits(float32(n)))
+	case KindF64:
+		n, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("bsatn: expected number for F64, got %T", v)
+		}
+		w.putU64(math.Float64bits(n))
+	case KindString:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("bsatn: expected string, got %T", v)
+		}
+		w.putBytes([]byte(s))
+	case KindArray:
+		if t.Array.Kind == KindU8 {
+			return encodeJSONBytes(w, v)
+		}
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("bsatn: expected array, got %T", v)
+		}
+		w.putU32(uint32(len(arr)))
+		for i, elem := range arr {
+			if err := encodeJSONValue(w, elem, *t.Array); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+	case KindMap:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("bsatn: expected object for map, got %T", v)
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		if w.sortMapKeys {
+			sort.Strings(keys)
+		}
+		w.putU32(uint32(len(obj)))
+		for _, k := range keys {
+			if err := encodeJSONValue(w, k, *t.MapKey); err != nil {
+				return err
+			}
+			if err := encodeJSONValue(w, obj[k], *t.MapValue); err != nil {
+				return err
+			}
+		}
+	case KindProduct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("bsatn: expected object for product, got %T", v)
+		}
+		for _, elem := range t.Product {
+			name := ""
+			if elem.Name != nil {
+				name = *elem.Name
+			}
+			val, present := obj[name]
+			if !present {
+				return fmt.Errorf("bsatn: missing field %q", name)
+			}
+			if err := encodeJSONValue(w, val, elem.Type); err != nil {
+				return fmt.Errorf("field %s: %w", name, err)
+			}
+		}
+	case KindSum:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("bsatn: expected object with tag/value for sum, got %T", v)
+		}
+		tag, ok := obj["tag"].(string)
+		if !ok {
+			return fmt.Errorf("bsatn: sum object missing string \"tag\"")
+		}
+		for i, variant := range t.Sum {
+			if variant.Name == nil || *variant.Name != tag {
+				continue
+			}
+			w.putU8(uint8(i))
+			return encodeJSONValue(w, obj["value"], variant.Type)
+		}
+		return fmt.Errorf("bsatn: unknown sum variant %q", tag)
+	default:
+		return fmt.Errorf("bsatn: unsupported schema kind %s for JSON encoding", t.Kind)
+	}
+	return nil
+}
+
+func encodeJSONBytes(w *writer, v any) error {
+	switch b := v.(type) {
+	case string:
+		w.putBytes([]byte(b))
+	case []any:
+		out := make([]byte, len(b))
+		for i, e := range b {
+			n, ok := e.(float64)
+			if !ok {
+				return fmt.Errorf("bsatn: expected number at byte array index %d, got %T", i, e)
+			}
+			out[i] = byte(n)
+		}
+		w.putBytes(out)
+	default:
+		return fmt.Errorf("bsatn: expected string or array for byte array, got %T", v)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package bsatn
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalJSONMatchesMarshal(t *testing.T) {
+	type player struct {
+		Name    string
+		Score   int32
+		Tags    []string
+		Country *string
+	}
+	country := "UK"
+	want, err := Marshal(player{Name: "Ada", Score: 10, Tags: []string{"pro"}, Country: &country})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	name, score, tags, ctry := "Name", "Score", "Tags", "Country"
+	schema := ProductOf(
+		ProductElement{Name: &name, Type: String()},
+		ProductElement{Name: &score, Type: I32()},
+		ProductElement{Name: &tags, Type: ArrayOf(String())},
+		ProductElement{Name: &ctry, Type: OptionOf(String())},
+	)
+
+	var v any
+	if err := json.Unmarshal([]byte(`{"Name":"Ada","Score":10,"Tags":["pro"],"Country":{"tag":"some","value":"UK"}}`), &v); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+
+	got, err := MarshalJSON(v, schema)
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("MarshalJSON produced %x, want %x (matching Marshal)", got, want)
+	}
+}
+
+func TestMarshalJSONMissingFieldErrors(t *testing.T) {
+	name := "Name"
+	schema := ProductOf(ProductElement{Name: &name, Type: String()})
+	if _, err := MarshalJSON(map[string]any{}, schema); err == nil {
+		t.Fatal("expected error for missing field")
+	}
+}
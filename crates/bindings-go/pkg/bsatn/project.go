@@ -0,0 +1,151 @@
+package bsatn
+
+import (
+	"fmt"
+	"math"
+)
+
+// ProjectProductColumns decodes only the requested columns (by position)
+// out of a BSATN-encoded product value, skipping every other field
+// without fully decoding it. It exists so something that has a table's
+// schema but not a generated Go struct for its row type — e.g. an index
+// key function driven by a parsed module definition's TableDef.ProductRef
+// and IndexDef.Columns — can still pull a secondary index's key columns
+// out of raw row bytes.
+//
+// schema must be a product type (a table's row type always is). Returned
+// values use the native Go type each column's AlgebraicTypeKind decodes
+// to, in the same order as columns, ready to pass straight to
+// EncodeOrderedKey: bool, int8/16/32/64, uint8/16/32/64, float32/64,
+// string, or []byte. A column whose type isn't one of those (KindProduct,
+// KindSum, KindMap, or a non-byte KindArray) is reported as an error
+// instead of silently producing a key EncodeOrderedKey can't build —
+// composite-column indexes aren't supported yet.
+func ProjectProductColumns(data []byte, schema AlgebraicType, columns []uint32) ([]any, error) {
+	if schema.Kind != KindProduct {
+		return nil, fmt.Errorf("bsatn: project columns: schema is %s, not a product", schema.Kind)
+	}
+
+	want := make(map[uint32]bool, len(columns))
+	for _, c := range columns {
+		want[c] = true
+	}
+
+	r := &reader{buf: data}
+	got := make(map[uint32]any, len(columns))
+	for i, elem := range schema.Product {
+		col := uint32(i)
+		if !want[col] {
+			if err := skip(r, elem.Type); err != nil {
+				return nil, fmt.Errorf("bsatn: project columns: skip field %d: %w", i, err)
+			}
+			continue
+		}
+		v, err := decodeOrderedKeyValue(r, elem.Type)
+		if err != nil {
+			return nil, fmt.Errorf("bsatn: project columns: field %d: %w", i, err)
+		}
+		got[col] = v
+	}
+
+	out := make([]any, len(columns))
+	for i, c := range columns {
+		v, ok := got[c]
+		if !ok {
+			return nil, fmt.Errorf("bsatn: project columns: column %d out of range for a %d-field product", c, len(schema.Product))
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// decodeOrderedKeyValue decodes one product element as whichever native
+// Go type EncodeOrderedKey accepts for it, or reports an error for a
+// type EncodeOrderedKey has no case for.
+func decodeOrderedKeyValue(r *reader, t AlgebraicType) (any, error) {
+	switch t.Kind {
+	case KindBool:
+		b, err := r.getU8()
+		if err != nil {
+			return nil, newDecodeError(r, "Bool", err)
+		}
+		return b != 0, nil
+	case KindI8:
+		b, err := r.getU8()
+		if err != nil {
+			return nil, newDecodeError(r, "Int8", err)
+		}
+		return int8(b), nil
+	case KindU8:
+		b, err := r.getU8()
+		if err != nil {
+			return nil, newDecodeError(r, "Uint8", err)
+		}
+		return b, nil
+	case KindI16:
+		x, err := r.getU16()
+		if err != nil {
+			return nil, newDecodeError(r, "Int16", err)
+		}
+		return int16(x), nil
+	case KindU16:
+		x, err := r.getU16()
+		if err != nil {
+			return nil, newDecodeError(r, "Uint16", err)
+		}
+		return x, nil
+	case KindI32:
+		x, err := r.getU32()
+		if err != nil {
+			return nil, newDecodeError(r, "Int32", err)
+		}
+		return int32(x), nil
+	case KindU32:
+		x, err := r.getU32()
+		if err != nil {
+			return nil, newDecodeError(r, "Uint32", err)
+		}
+		return x, nil
+	case KindI64:
+		x, err := r.getU64()
+		if err != nil {
+			return nil, newDecodeError(r, "Int64", err)
+		}
+		return int64(x), nil
+	case KindU64:
+		x, err := r.getU64()
+		if err != nil {
+			return nil, newDecodeError(r, "Uint64", err)
+		}
+		return x, nil
+	case KindF32:
+		x, err := r.getU32()
+		if err != nil {
+			return nil, newDecodeError(r, "Float32", err)
+		}
+		return math.Float32frombits(x), nil
+	case KindF64:
+		x, err := r.getU64()
+		if err != nil {
+			return nil, newDecodeError(r, "Float64", err)
+		}
+		return math.Float64frombits(x), nil
+	case KindString:
+		b, err := r.getBytes()
+		if err != nil {
+			return nil, newDecodeError(r, "String", err)
+		}
+		return string(b), nil
+	case KindArray:
+		if t.Array.Kind == KindU8 {
+			b, err := r.getBytes()
+			if err != nil {
+				return nil, newDecodeError(r, "Bytes", err)
+			}
+			return append([]byte(nil), b...), nil
+		}
+		return nil, fmt.Errorf("bsatn: index column type %s is not supported as an index key (only EncodeOrderedKey's scalar types and byte arrays are)", t.Kind)
+	default:
+		return nil, fmt.Errorf("bsatn: index column type %s is not supported as an index key (only EncodeOrderedKey's scalar types and byte arrays are)", t.Kind)
+	}
+}
@@ -0,0 +1,101 @@
+package bsatn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// fillChunkSize is how many bytes Decoder reads from its underlying
+// io.Reader at a time when it needs more data to finish decoding a
+// value. It is not a limit on value size: Decoder keeps reading chunks
+// until a full value is available.
+const fillChunkSize = 4096
+
+// Decoder decodes a sequence of BSATN-encoded values read incrementally
+// from an io.Reader, so a large or multi-row scan can be consumed
+// without first buffering it entirely in memory the way Unmarshal
+// requires. It reuses the same reflect-based decode Unmarshal does, so
+// it supports exactly the same Go types.
+//
+// A Decoder is not safe for concurrent use.
+type Decoder struct {
+	r   io.Reader
+	buf []byte
+}
+
+// NewDecoder returns a Decoder reading BSATN-encoded values from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Decode reads the next BSATN-encoded value from the Decoder's
+// underlying io.Reader into v, which must be a non-nil pointer. It
+// returns io.EOF if the underlying reader has no more data and nothing
+// of the next value has been read yet, or io.ErrUnexpectedEOF if the
+// reader runs out partway through a value.
+//
+// Unlike Unmarshal, every string and []byte decoded is a fresh copy:
+// there is no single byte slice for a zero-copy result to alias.
+func (d *Decoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("bsatn: Decode requires a non-nil pointer, got %T", v)
+	}
+	readAny := false
+	for {
+		r := &reader{buf: d.buf}
+		decErr := r.decode(rv.Elem())
+		if decErr == nil {
+			d.buf = d.buf[r.pos:]
+			return nil
+		}
+		if !errors.Is(decErr, ErrBufferTooSmall) {
+			return decErr
+		}
+
+		chunk := make([]byte, fillChunkSize)
+		n, err := d.r.Read(chunk)
+		if n > 0 {
+			readAny = true
+			d.buf = append(d.buf, chunk[:n]...)
+			continue
+		}
+		if err == nil {
+			continue
+		}
+		if err == io.EOF {
+			if !readAny && len(d.buf) == 0 {
+				return io.EOF
+			}
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+}
+
+// Encoder writes a sequence of BSATN-encoded values to an io.Writer,
+// the streaming-friendly symmetric counterpart to Decoder. It offers no
+// buffering of its own beyond what Marshal needs to build one value;
+// use a buffered io.Writer if writing many small values to a syscall-
+// backed destination.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns an Encoder writing BSATN-encoded values to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode marshals v with opts (see Marshal) and writes the result to
+// the Encoder's underlying io.Writer.
+func (e *Encoder) Encode(v any, opts ...MarshalOption) error {
+	data, err := Marshal(v, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
@@ -0,0 +1,106 @@
+package bsatn
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+type streamRow struct {
+	ID   int32
+	Name string
+}
+
+func TestEncoderDecoderRoundTripsMultipleValues(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	want := []streamRow{
+		{ID: 1, Name: "alpha"},
+		{ID: 2, Name: "bravo"},
+		{ID: 3, Name: "charlie"},
+	}
+	for _, row := range want {
+		if err := enc.Encode(row); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+	}
+
+	dec := NewDecoder(&buf)
+	var got []streamRow
+	for {
+		var row streamRow
+		err := dec.Decode(&row)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		got = append(got, row)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("decoded %d rows, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// chunkedReader hands back src one byte at a time, forcing Decoder to
+// refill its buffer repeatedly mid-value instead of getting it all in
+// one Read.
+type chunkedReader struct {
+	src []byte
+}
+
+func (r *chunkedReader) Read(p []byte) (int, error) {
+	if len(r.src) == 0 {
+		return 0, io.EOF
+	}
+	p[0] = r.src[0]
+	r.src = r.src[1:]
+	return 1, nil
+}
+
+func TestDecoderHandlesByteAtATimeReader(t *testing.T) {
+	data, err := Marshal(streamRow{ID: 42, Name: "zebra"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	dec := NewDecoder(&chunkedReader{src: data})
+	var got streamRow
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := streamRow{ID: 42, Name: "zebra"}
+	if got != want {
+		t.Errorf("Decode = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecoderReturnsEOFOnEmptyInput(t *testing.T) {
+	dec := NewDecoder(bytes.NewReader(nil))
+	var row streamRow
+	if err := dec.Decode(&row); err != io.EOF {
+		t.Fatalf("Decode on empty input = %v, want io.EOF", err)
+	}
+}
+
+func TestDecoderReturnsUnexpectedEOFOnTruncatedValue(t *testing.T) {
+	data, err := Marshal(streamRow{ID: 1, Name: "alpha"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data[:len(data)-2]))
+	var row streamRow
+	err = dec.Decode(&row)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Decode on truncated input = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
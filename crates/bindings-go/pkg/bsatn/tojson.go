@@ -0,0 +1,181 @@
+package bsatn
+
+import (
+	"fmt"
+	"math"
+)
+
+// UnmarshalJSON decodes BSATN-encoded data per schema into a JSON-ish
+// value: map[string]any for a product, []any for an array (unless its
+// element type is KindU8, decoded as a []byte), a Go map[string]any for
+// a map (non-string keys are stringified with fmt.Sprint), float64 for
+// every numeric kind, string, bool, or {"tag": "<variant name>",
+// "value": <payload>} for a sum. It is the read-side counterpart to
+// MarshalJSON, used by harness.ExpectTable to compare table contents in
+// a schema-driven, diffable way without generating a Go struct per
+// table.
+func UnmarshalJSON(data []byte, schema AlgebraicType) (any, error) {
+	r := &reader{buf: data}
+	v, err := decodeJSONValue(r, schema)
+	if err != nil {
+		return nil, err
+	}
+	if r.pos != len(r.buf) {
+		return nil, fmt.Errorf("bsatn: %d trailing bytes after decoding", len(r.buf)-r.pos)
+	}
+	return v, nil
+}
+
+func decodeJSONValue(r *reader, t AlgebraicType) (any, error) {
+	switch t.Kind {
+	case KindBool:
+		b, err := r.getU8()
+		if err != nil {
+			return nil, newDecodeError(r, "Bool", err)
+		}
+		return b != 0, nil
+	case KindI8:
+		b, err := r.getU8()
+		if err != nil {
+			return nil, newDecodeError(r, "Int8", err)
+		}
+		return float64(int8(b)), nil
+	case KindU8:
+		b, err := r.getU8()
+		if err != nil {
+			return nil, newDecodeError(r, "Uint8", err)
+		}
+		return float64(b), nil
+	case KindI16:
+		x, err := r.getU16()
+		if err != nil {
+			return nil, newDecodeError(r, "Int16", err)
+		}
+		return float64(int16(x)), nil
+	case KindU16:
+		x, err := r.getU16()
+		if err != nil {
+			return nil, newDecodeError(r, "Uint16", err)
+		}
+		return float64(x), nil
+	case KindI32:
+		x, err := r.getU32()
+		if err != nil {
+			return nil, newDecodeError(r, "Int32", err)
+		}
+		return float64(int32(x)), nil
+	case KindU32:
+		x, err := r.getU32()
+		if err != nil {
+			return nil, newDecodeError(r, "Uint32", err)
+		}
+		return float64(x), nil
+	case KindI64:
+		x, err := r.getU64()
+		if err != nil {
+			return nil, newDecodeError(r, "Int64", err)
+		}
+		return float64(int64(x)), nil
+	case KindU64:
+		x, err := r.getU64()
+		if err != nil {
+			return nil, newDecodeError(r, "Uint64", err)
+		}
+		return float64(x), nil
+	case KindF32:
+		x, err := r.getU32()
+		if err != nil {
+			return nil, newDecodeError(r, "Float32", err)
+		}
+		return float64(math.Float32frombits(x)), nil
+	case KindF64:
+		x, err := r.getU64()
+		if err != nil {
+			return nil, newDecodeError(r, "Float64", err)
+		}
+		return math.Float64frombits(x), nil
+	case KindString:
+		b, err := r.getBytes()
+		if err != nil {
+			return nil, newDecodeError(r, "String", err)
+		}
+		return string(b), nil
+	case KindArray:
+		if t.Array.Kind == KindU8 {
+			b, err := r.getBytes()
+			if err != nil {
+				return nil, newDecodeError(r, "Bytes", err)
+			}
+			return append([]byte(nil), b...), nil
+		}
+		n, err := r.getU32()
+		if err != nil {
+			return nil, newDecodeError(r, "array length", err)
+		}
+		out := make([]any, n)
+		for i := range out {
+			v, err := decodeJSONValue(r, *t.Array)
+			if err != nil {
+				return nil, prependIndex(err, i)
+			}
+			out[i] = v
+		}
+		return out, nil
+	case KindMap:
+		n, err := r.getU32()
+		if err != nil {
+			return nil, newDecodeError(r, "map length", err)
+		}
+		out := make(map[string]any, n)
+		for i := 0; i < int(n); i++ {
+			k, err := decodeJSONValue(r, *t.MapKey)
+			if err != nil {
+				return nil, prependField(err, fmt.Sprintf("<key %d>", i))
+			}
+			v, err := decodeJSONValue(r, *t.MapValue)
+			if err != nil {
+				return nil, prependField(err, fmt.Sprintf("<value %d>", i))
+			}
+			ks, ok := k.(string)
+			if !ok {
+				ks = fmt.Sprint(k)
+			}
+			out[ks] = v
+		}
+		return out, nil
+	case KindProduct:
+		out := make(map[string]any, len(t.Product))
+		for _, elem := range t.Product {
+			name := ""
+			if elem.Name != nil {
+				name = *elem.Name
+			}
+			v, err := decodeJSONValue(r, elem.Type)
+			if err != nil {
+				return nil, prependField(err, name)
+			}
+			out[name] = v
+		}
+		return out, nil
+	case KindSum:
+		tag, err := r.getU8()
+		if err != nil {
+			return nil, newDecodeError(r, "sum tag", err)
+		}
+		if int(tag) >= len(t.Sum) {
+			return nil, newDecodeError(r, "sum tag", ErrInvalidTag)
+		}
+		variant := t.Sum[tag]
+		val, err := decodeJSONValue(r, variant.Type)
+		if err != nil {
+			return nil, err
+		}
+		name := ""
+		if variant.Name != nil {
+			name = *variant.Name
+		}
+		return map[string]any{"tag": name, "value": val}, nil
+	default:
+		return nil, fmt.Errorf("bsatn: unsupported schema kind %s for JSON decoding", t.Kind)
+	}
+}
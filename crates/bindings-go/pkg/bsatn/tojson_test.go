@@ -0,0 +1,42 @@
+package bsatn
+
+import "testing"
+
+func TestUnmarshalJSONRoundTripsMarshalJSON(t *testing.T) {
+	name, score := "Name", "Score"
+	schema := ProductOf(
+		ProductElement{Name: &name, Type: String()},
+		ProductElement{Name: &score, Type: I32()},
+	)
+	data, err := MarshalJSON(map[string]any{"Name": "Ada", "Score": float64(10)}, schema)
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+
+	got, err := UnmarshalJSON(data, schema)
+	if err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	row, ok := got.(map[string]any)
+	if !ok {
+		t.Fatalf("got %T, want map[string]any", got)
+	}
+	if row["Name"] != "Ada" || row["Score"] != float64(10) {
+		t.Fatalf("row = %v, want {Name: Ada, Score: 10}", row)
+	}
+}
+
+func TestUnmarshalJSONDecodesSumAsTagValue(t *testing.T) {
+	inner := OptionOf(String())
+
+	// An option-none value: tag 1 (none), no payload.
+	encoded := []byte{1}
+	got, err := UnmarshalJSON(encoded, inner)
+	if err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	row, ok := got.(map[string]any)
+	if !ok || row["tag"] != "none" {
+		t.Fatalf("got %v, want {tag: none, ...}", got)
+	}
+}
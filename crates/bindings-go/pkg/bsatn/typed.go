@@ -0,0 +1,187 @@
+package bsatn
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// UnknownFieldsMode controls how UnmarshalTyped handles a product
+// element present in schema that has no matching field on the
+// destination struct — the shape a client sees when a module adds a
+// column after the client was built against an older schema.
+type UnknownFieldsMode int
+
+const (
+	// UnknownFieldsError fails decoding as soon as an unmatched element
+	// is seen. This is the strictest and most surprising-free option,
+	// appropriate when the client and module are expected to agree on
+	// schema exactly.
+	UnknownFieldsError UnknownFieldsMode = iota
+	// UnknownFieldsIgnore silently skips unmatched elements.
+	UnknownFieldsIgnore
+	// UnknownFieldsCapture skips unmatched elements but returns their
+	// name and raw encoded bytes so a caller can inspect or re-encode
+	// them, e.g. to round-trip a row it doesn't fully understand.
+	UnknownFieldsCapture
+)
+
+// DecodeOptions configures UnmarshalTyped.
+type DecodeOptions struct {
+	UnknownFields UnknownFieldsMode
+}
+
+// UnknownField is one schema element UnmarshalTyped could not match to
+// a destination struct field.
+type UnknownField struct {
+	Name string
+	Raw  []byte
+}
+
+// UnmarshalTyped decodes data into v (a pointer to struct) using schema,
+// a product AlgebraicType, as the authoritative field layout instead of
+// v's own field list. Unlike Unmarshal, which requires the struct and
+// the encoding to agree field-for-field, UnmarshalTyped matches schema
+// elements to struct fields by name and handles the rest according to
+// opts.UnknownFields — so a Go client built against an older version of
+// a module's schema keeps working when the module adds columns, instead
+// of misdecoding every field after the new one.
+//
+// schema.Kind must be KindProduct. Fields matched by name are decoded
+// with the same rules as Unmarshal; bsatn:"-" struct fields are treated
+// as absent, same as Marshal/Unmarshal.
+func UnmarshalTyped(data []byte, schema AlgebraicType, v any, opts DecodeOptions) ([]UnknownField, error) {
+	if schema.Kind != KindProduct {
+		return nil, fmt.Errorf("bsatn: UnmarshalTyped requires a product schema, got %s", schema.Kind)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("bsatn: UnmarshalTyped requires a non-nil pointer to struct, got %T", v)
+	}
+	structVal := rv.Elem()
+	fields := structFieldsByName(structVal.Type())
+
+	r := &reader{buf: data}
+	var unknown []UnknownField
+	for _, elem := range schema.Product {
+		name := ""
+		if elem.Name != nil {
+			name = *elem.Name
+		}
+		if idx, ok := fields[name]; ok {
+			if err := r.decode(structVal.Field(idx)); err != nil {
+				return unknown, fmt.Errorf("field %s: %w", name, err)
+			}
+			continue
+		}
+
+		switch opts.UnknownFields {
+		case UnknownFieldsError:
+			return unknown, fmt.Errorf("bsatn: unknown field %q in encoded row", name)
+		case UnknownFieldsIgnore:
+			if err := skip(r, elem.Type); err != nil {
+				return unknown, fmt.Errorf("skip field %s: %w", name, err)
+			}
+		case UnknownFieldsCapture:
+			start := r.pos
+			if err := skip(r, elem.Type); err != nil {
+				return unknown, fmt.Errorf("skip field %s: %w", name, err)
+			}
+			unknown = append(unknown, UnknownField{Name: name, Raw: append([]byte(nil), r.buf[start:r.pos]...)})
+		default:
+			return unknown, fmt.Errorf("bsatn: unknown UnknownFieldsMode %d", opts.UnknownFields)
+		}
+	}
+	if r.pos != len(r.buf) {
+		return unknown, fmt.Errorf("bsatn: %d trailing bytes after decoding %T", len(r.buf)-r.pos, v)
+	}
+	return unknown, nil
+}
+
+// structFieldsByName indexes t's exported, non-skipped fields by name.
+func structFieldsByName(t reflect.Type) map[string]int {
+	out := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Tag.Get(tagFieldName) == "-" {
+			continue
+		}
+		out[f.Name] = i
+	}
+	return out
+}
+
+// skip advances r past one encoded value of type t without decoding it
+// into a Go value, so UnmarshalTyped can pass over a schema element it
+// has no destination field for.
+func skip(r *reader, t AlgebraicType) error {
+	switch t.Kind {
+	case KindBool, KindI8, KindU8:
+		_, err := r.getU8()
+		return err
+	case KindI16, KindU16:
+		_, err := r.getU16()
+		return err
+	case KindI32, KindU32, KindF32:
+		_, err := r.getU32()
+		return err
+	case KindI64, KindU64, KindF64:
+		_, err := r.getU64()
+		return err
+	case KindI128, KindU128:
+		if _, err := r.getU64(); err != nil {
+			return err
+		}
+		_, err := r.getU64()
+		return err
+	case KindString:
+		_, err := r.getBytes()
+		return err
+	case KindArray:
+		if t.Array.Kind == KindU8 {
+			_, err := r.getBytes()
+			return err
+		}
+		n, err := r.getU32()
+		if err != nil {
+			return err
+		}
+		for i := uint32(0); i < n; i++ {
+			if err := skip(r, *t.Array); err != nil {
+				return err
+			}
+		}
+		return nil
+	case KindMap:
+		n, err := r.getU32()
+		if err != nil {
+			return err
+		}
+		for i := uint32(0); i < n; i++ {
+			if err := skip(r, *t.MapKey); err != nil {
+				return err
+			}
+			if err := skip(r, *t.MapValue); err != nil {
+				return err
+			}
+		}
+		return nil
+	case KindProduct:
+		for _, elem := range t.Product {
+			if err := skip(r, elem.Type); err != nil {
+				return err
+			}
+		}
+		return nil
+	case KindSum:
+		tag, err := r.getU8()
+		if err != nil {
+			return err
+		}
+		if int(tag) >= len(t.Sum) {
+			return fmt.Errorf("bsatn: sum tag %d out of range (%d variants)", tag, len(t.Sum))
+		}
+		return skip(r, t.Sum[tag].Type)
+	default:
+		return fmt.Errorf("bsatn: cannot skip unknown kind %s", t.Kind)
+	}
+}
@@ -0,0 +1,74 @@
+package bsatn
+
+import "testing"
+
+type playerV1 struct {
+	Name  string
+	Score int32
+}
+
+type playerV2 struct {
+	Name    string
+	Score   int32
+	Country string
+}
+
+func encodePlayerV2(t *testing.T, p playerV2) []byte {
+	t.Helper()
+	data, err := Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return data
+}
+
+func v2Schema() AlgebraicType {
+	name, score, country := "Name", "Score", "Country"
+	return ProductOf(
+		ProductElement{Name: &name, Type: String()},
+		ProductElement{Name: &score, Type: I32()},
+		ProductElement{Name: &country, Type: String()},
+	)
+}
+
+func TestUnmarshalTypedErrorsOnUnknownFieldByDefault(t *testing.T) {
+	data := encodePlayerV2(t, playerV2{Name: "Ada", Score: 10, Country: "UK"})
+	var dst playerV1
+	if _, err := UnmarshalTyped(data, v2Schema(), &dst, DecodeOptions{}); err == nil {
+		t.Fatal("expected error for unmatched Country field in strict mode")
+	}
+}
+
+func TestUnmarshalTypedIgnoresUnknownField(t *testing.T) {
+	data := encodePlayerV2(t, playerV2{Name: "Ada", Score: 10, Country: "UK"})
+	var dst playerV1
+	unknown, err := UnmarshalTyped(data, v2Schema(), &dst, DecodeOptions{UnknownFields: UnknownFieldsIgnore})
+	if err != nil {
+		t.Fatalf("UnmarshalTyped: %v", err)
+	}
+	if len(unknown) != 0 {
+		t.Fatalf("Ignore mode should report no unknown fields, got %v", unknown)
+	}
+	if dst.Name != "Ada" || dst.Score != 10 {
+		t.Fatalf("dst = %+v, want Name=Ada Score=10", dst)
+	}
+}
+
+func TestUnmarshalTypedCapturesUnknownField(t *testing.T) {
+	data := encodePlayerV2(t, playerV2{Name: "Ada", Score: 10, Country: "UK"})
+	var dst playerV1
+	unknown, err := UnmarshalTyped(data, v2Schema(), &dst, DecodeOptions{UnknownFields: UnknownFieldsCapture})
+	if err != nil {
+		t.Fatalf("UnmarshalTyped: %v", err)
+	}
+	if len(unknown) != 1 || unknown[0].Name != "Country" {
+		t.Fatalf("unknown = %+v, want one Country field", unknown)
+	}
+	var country string
+	if err := Unmarshal(unknown[0].Raw, &country); err != nil {
+		t.Fatalf("Unmarshal captured raw: %v", err)
+	}
+	if country != "UK" {
+		t.Fatalf("captured Country = %q, want UK", country)
+	}
+}
@@ -0,0 +1,55 @@
+package bsatn
+
+import "testing"
+
+type zcRow struct {
+	Name string
+	Tags []string
+	Blob []byte
+}
+
+func TestUnmarshalZeroCopyAliasesInputBuffer(t *testing.T) {
+	data, err := Marshal(zcRow{Name: "ada", Tags: []string{"x"}, Blob: []byte("hi")})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	buf := append([]byte(nil), data...)
+
+	var dst zcRow
+	if err := UnmarshalZeroCopy(buf, &dst); err != nil {
+		t.Fatalf("UnmarshalZeroCopy: %v", err)
+	}
+
+	// Mutating the source buffer should be visible through the
+	// zero-copy decoded strings/bytes, proving they alias it.
+	for i := range buf {
+		buf[i] = 0
+	}
+	if dst.Name == "ada" {
+		t.Fatal("dst.Name did not alias the input buffer")
+	}
+	if dst.Blob[0] != 0 {
+		t.Fatal("dst.Blob did not alias the input buffer")
+	}
+}
+
+func TestCloneDetachesFromInputBuffer(t *testing.T) {
+	data, err := Marshal(zcRow{Name: "ada", Tags: []string{"x"}, Blob: []byte("hi")})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	buf := append([]byte(nil), data...)
+
+	var dst zcRow
+	if err := UnmarshalZeroCopy(buf, &dst); err != nil {
+		t.Fatalf("UnmarshalZeroCopy: %v", err)
+	}
+	Clone(&dst)
+
+	for i := range buf {
+		buf[i] = 0
+	}
+	if dst.Name != "ada" || dst.Tags[0] != "x" || string(dst.Blob) != "hi" {
+		t.Fatalf("Clone did not detach dst from the input buffer: %+v", dst)
+	}
+}
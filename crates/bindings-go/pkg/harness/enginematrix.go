@@ -0,0 +1,47 @@
+package harness
+
+import (
+	"context"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/wasm"
+)
+
+// engineMatrix lists the wasm.Engine values RunOnEachEngine exercises,
+// and the subtest name each runs under.
+var engineMatrix = []struct {
+	name   string
+	engine wasm.Engine
+}{
+	{"interpreter", wasm.EngineInterpreter},
+	{"compiler", wasm.EngineCompiler},
+}
+
+// RunOnEachEngine instantiates wasmBytes under cfg once per wazero
+// execution strategy (see wasm.Engine) and runs fn against each as a
+// t.Run subtest, so a single test body catches a bug that only one of
+// the two engines' code paths triggers instead of whichever one Config's
+// zero value happens to resolve to on the machine running the suite.
+// cfg.Engine is overwritten per subtest; the caller does not need to set
+// it. Each subtest gets its own Runtime, closed via t.Cleanup.
+func RunOnEachEngine(t *testing.T, wasmBytes []byte, cfg wasm.Config, fn func(t *testing.T, rt *wasm.Runtime)) {
+	t.Helper()
+	for _, m := range engineMatrix {
+		m := m
+		t.Run(m.name, func(t *testing.T) {
+			engineCfg := cfg
+			engineCfg.Engine = m.engine
+			ctx := context.Background()
+			rt, err := wasm.NewRuntime(ctx, wasmBytes, engineCfg)
+			if err != nil {
+				t.Fatalf("harness: RunOnEachEngine: NewRuntime(%s): %v", m.name, err)
+			}
+			t.Cleanup(func() {
+				if err := rt.Close(ctx); err != nil {
+					t.Errorf("harness: RunOnEachEngine: closing %s runtime: %v", m.name, err)
+				}
+			})
+			fn(t, rt)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package harness
+
+import (
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/wasm"
+)
+
+func TestRunOnEachEngineRunsBothSubtests(t *testing.T) {
+	var ran []string
+	RunOnEachEngine(t, emptyModule, wasm.Config{}, func(t *testing.T, rt *wasm.Runtime) {
+		ran = append(ran, t.Name())
+		if rt == nil {
+			t.Fatal("fn called with a nil Runtime")
+		}
+	})
+
+	want := []string{
+		"TestRunOnEachEngineRunsBothSubtests/interpreter",
+		"TestRunOnEachEngineRunsBothSubtests/compiler",
+	}
+	if len(ran) != len(want) {
+		t.Fatalf("subtests run = %v, want %v", ran, want)
+	}
+	for i, name := range want {
+		if ran[i] != name {
+			t.Errorf("ran[%d] = %q, want %q", i, ran[i], name)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package harness
+
+import (
+	"fmt"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+// TableExpectation is a fluent assertion builder over one table's
+// contents, decoded against schema via bsatn.UnmarshalJSON so rows can
+// be compared as plain maps without a generated Go struct.
+type TableExpectation struct {
+	database *db.Database
+	table    string
+	schema   bsatn.AlgebraicType
+	ignore   map[string]bool
+}
+
+// ExpectTable starts an assertion against table's contents in database,
+// decoding each row per schema.
+func ExpectTable(database *db.Database, table string, schema bsatn.AlgebraicType) *TableExpectation {
+	return &TableExpectation{database: database, table: table, schema: schema, ignore: map[string]bool{}}
+}
+
+// Ignoring excludes the named columns from every comparison this
+// TableExpectation makes, e.g. auto-increment IDs or timestamps whose
+// exact value a test can't predict.
+func (e *TableExpectation) Ignoring(columns ...string) *TableExpectation {
+	for _, c := range columns {
+		e.ignore[c] = true
+	}
+	return e
+}
+
+// ToContainExactly asserts that table holds exactly the rows in
+// expected (each a JSON-ish map, as accepted by bsatn.MarshalJSON), no
+// more and no fewer, ignoring any columns named in Ignoring and
+// ignoring row order. On mismatch it returns a *RowDiff describing
+// which rows were missing or unexpected.
+func (e *TableExpectation) ToContainExactly(expected []map[string]any) error {
+	actual, err := e.scan()
+	if err != nil {
+		return err
+	}
+	if diff := diffRowSets(actual, expected, e.ignore); diff != nil {
+		return diff
+	}
+	return nil
+}
+
+// ToBeEmpty asserts that table holds no rows.
+func (e *TableExpectation) ToBeEmpty() error {
+	return e.ToContainExactly(nil)
+}
+
+func (e *TableExpectation) scan() ([]map[string]any, error) {
+	cur, err := e.database.Engine.Scan(e.table)
+	if err != nil {
+		return nil, fmt.Errorf("harness: ExpectTable(%q): %w", e.table, err)
+	}
+	defer cur.Close()
+
+	var rows []map[string]any
+	for cur.Next() {
+		v, err := bsatn.UnmarshalJSON(cur.Value(), e.schema)
+		if err != nil {
+			return nil, fmt.Errorf("harness: ExpectTable(%q): decoding row: %w", e.table, err)
+		}
+		row, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("harness: ExpectTable(%q): schema is not a product, decoded %T", e.table, v)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,83 @@
+package harness
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func playerSchema() bsatn.AlgebraicType {
+	id, name, score := "id", "name", "score"
+	return bsatn.ProductOf(
+		bsatn.ProductElement{Name: &id, Type: bsatn.F64()},
+		bsatn.ProductElement{Name: &name, Type: bsatn.String()},
+		bsatn.ProductElement{Name: &score, Type: bsatn.I32()},
+	)
+}
+
+func putPlayer(t *testing.T, database *db.Database, key byte, id float64, name string, score int32) {
+	t.Helper()
+	data, err := bsatn.MarshalJSON(map[string]any{"id": id, "name": name, "score": float64(score)}, playerSchema())
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	if err := database.Engine.Put("players", []byte{key}, data); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+}
+
+func TestExpectTableToContainExactlyMatches(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	putPlayer(t, database, 1, 1, "Ada", 10)
+	putPlayer(t, database, 2, 2, "Grace", 20)
+
+	err := ExpectTable(database, "players", playerSchema()).ToContainExactly([]map[string]any{
+		{"id": 2.0, "name": "Grace", "score": float64(20)},
+		{"id": 1.0, "name": "Ada", "score": float64(10)},
+	})
+	if err != nil {
+		t.Fatalf("ToContainExactly: %v", err)
+	}
+}
+
+func TestExpectTableIgnoringColumn(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	putPlayer(t, database, 1, 42, "Ada", 10)
+
+	err := ExpectTable(database, "players", playerSchema()).
+		Ignoring("id").
+		ToContainExactly([]map[string]any{
+			{"id": 999.0, "name": "Ada", "score": float64(10)},
+		})
+	if err != nil {
+		t.Fatalf("ToContainExactly with Ignoring: %v", err)
+	}
+}
+
+func TestExpectTableReportsMissingAndUnexpected(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	putPlayer(t, database, 1, 1, "Ada", 10)
+
+	err := ExpectTable(database, "players", playerSchema()).ToContainExactly([]map[string]any{
+		{"id": 1.0, "name": "Grace", "score": float64(20)},
+	})
+	if err == nil {
+		t.Fatal("expected mismatch error")
+	}
+	if !strings.Contains(err.Error(), "missing") || !strings.Contains(err.Error(), "unexpected") {
+		t.Fatalf("error = %q, want both 'missing' and 'unexpected'", err.Error())
+	}
+}
+
+func TestExpectTableToBeEmpty(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	if err := ExpectTable(database, "players", playerSchema()).ToBeEmpty(); err != nil {
+		t.Fatalf("ToBeEmpty: %v", err)
+	}
+	putPlayer(t, database, 1, 1, "Ada", 10)
+	if err := ExpectTable(database, "players", playerSchema()).ToBeEmpty(); err == nil {
+		t.Fatal("expected ToBeEmpty to fail after inserting a row")
+	}
+}
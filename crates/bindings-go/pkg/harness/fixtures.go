@@ -0,0 +1,116 @@
+package harness
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+// FixtureFile is the on-disk shape LoadFixtures reads: one entry per
+// table, each with the rows to insert, in the order to insert them.
+type FixtureFile struct {
+	Tables []FixtureTable `json:"tables"`
+}
+
+// FixtureTable is one table's worth of seed rows, each a JSON object
+// matched against that table's schema by bsatn.MarshalJSON.
+type FixtureTable struct {
+	Name string           `json:"name"`
+	Rows []map[string]any `json:"rows"`
+}
+
+// LoadFixtures reads the JSON fixture file at path and inserts its rows
+// into database within a single transaction (see db.Database.Begin),
+// encoding each row against schemas[table.Name] via bsatn.MarshalJSON.
+// It exists to remove the reducer-call boilerplate module tests would
+// otherwise need just to get rows into a table.
+//
+// A row field's value may be the JSON object {"$gen": "<label>"} to
+// generate a new ID (via database.NextSequence) and bind it to label,
+// or {"$ref": "<label>"} to substitute a value a previous row generated
+// under that label — this is what lets one fixture row's foreign key
+// point at another row without hardcoding an ID neither the fixture
+// author nor the schema actually assigns.
+//
+// LoadFixtures does not itself choose row keys from field values (the
+// datastore SPI keys rows by an opaque []byte, and a fixture's schema
+// may have no single-field primary key); each row is stored under its
+// own sequence-numbered key, so LoadFixtures is for seeding table scans
+// and reducer logic that reads by value, not for tests that need a
+// specific row key.
+func LoadFixtures(database *db.Database, path string, schemas map[string]bsatn.AlgebraicType) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("harness: LoadFixtures: %w", err)
+	}
+	var file FixtureFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("harness: LoadFixtures: parsing %s: %w", path, err)
+	}
+
+	refs := map[string]any{}
+	txn := database.Begin()
+	for _, table := range file.Tables {
+		schema, ok := schemas[table.Name]
+		if !ok {
+			txn.Rollback()
+			return fmt.Errorf("harness: LoadFixtures: no schema registered for table %q", table.Name)
+		}
+		gen := func() uint64 { return database.NextSequence(table.Name) }
+		for i, row := range table.Rows {
+			resolved, ok := resolveRefs(row, refs, gen).(map[string]any)
+			if !ok {
+				txn.Rollback()
+				return fmt.Errorf("harness: LoadFixtures: table %q row %d: not a JSON object", table.Name, i)
+			}
+			encoded, err := bsatn.MarshalJSON(resolved, schema)
+			if err != nil {
+				txn.Rollback()
+				return fmt.Errorf("harness: LoadFixtures: table %q row %d: %w", table.Name, i, err)
+			}
+			key := make([]byte, 8)
+			binary.BigEndian.PutUint64(key, database.NextSequence(table.Name+"$fixtureRow"))
+			if err := txn.Put(table.Name, key, encoded); err != nil {
+				txn.Rollback()
+				return fmt.Errorf("harness: LoadFixtures: table %q row %d: %w", table.Name, i, err)
+			}
+		}
+	}
+	return txn.Commit()
+}
+
+// resolveRefs walks a decoded JSON value, replacing {"$gen": "label"}
+// with a freshly generated value bound to label and {"$ref": "label"}
+// with the value previously bound to label.
+func resolveRefs(v any, refs map[string]any, gen func() uint64) any {
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) == 1 {
+			if label, ok := val["$gen"].(string); ok {
+				id := float64(gen())
+				refs[label] = id
+				return id
+			}
+			if label, ok := val["$ref"].(string); ok {
+				return refs[label]
+			}
+		}
+		out := make(map[string]any, len(val))
+		for k, e := range val {
+			out[k] = resolveRefs(e, refs, gen)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, e := range val {
+			out[i] = resolveRefs(e, refs, gen)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,118 @@
+package harness
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func writeFixtureFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestLoadFixturesInsertsRows(t *testing.T) {
+	path := writeFixtureFile(t, `{
+		"tables": [
+			{"name": "players", "rows": [
+				{"name": "Ada", "score": 10},
+				{"name": "Grace", "score": 20}
+			]}
+		]
+	}`)
+
+	name, score := "name", "score"
+	schemas := map[string]bsatn.AlgebraicType{
+		"players": bsatn.ProductOf(
+			bsatn.ProductElement{Name: &name, Type: bsatn.String()},
+			bsatn.ProductElement{Name: &score, Type: bsatn.I32()},
+		),
+	}
+
+	database := db.NewDatabase(db.NewMemEngine())
+	if err := LoadFixtures(database, path, schemas); err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+
+	rows := scanAll(t, database, "players")
+	if len(rows) != 2 {
+		t.Fatalf("len(rows) = %d, want 2", len(rows))
+	}
+}
+
+func scanAll(t *testing.T, database *db.Database, table string) [][]byte {
+	t.Helper()
+	cur, err := database.Engine.Scan(table)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	defer cur.Close()
+	var out [][]byte
+	for cur.Next() {
+		out = append(out, append([]byte(nil), cur.Value()...))
+	}
+	return out
+}
+
+func TestLoadFixturesResolvesGeneratedReferences(t *testing.T) {
+	path := writeFixtureFile(t, `{
+		"tables": [
+			{"name": "players", "rows": [
+				{"id": {"$gen": "ada"}, "name": "Ada"}
+			]},
+			{"name": "friendships", "rows": [
+				{"player_id": {"$ref": "ada"}, "friend_id": {"$ref": "ada"}}
+			]}
+		]
+	}`)
+
+	id, name := "id", "name"
+	playerID, friendID := "player_id", "friend_id"
+	schemas := map[string]bsatn.AlgebraicType{
+		"players": bsatn.ProductOf(
+			bsatn.ProductElement{Name: &id, Type: bsatn.F64()},
+			bsatn.ProductElement{Name: &name, Type: bsatn.String()},
+		),
+		"friendships": bsatn.ProductOf(
+			bsatn.ProductElement{Name: &playerID, Type: bsatn.F64()},
+			bsatn.ProductElement{Name: &friendID, Type: bsatn.F64()},
+		),
+	}
+
+	database := db.NewDatabase(db.NewMemEngine())
+	if err := LoadFixtures(database, path, schemas); err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+
+	friendships := scanAll(t, database, "friendships")
+	if len(friendships) != 1 {
+		t.Fatalf("len(friendships) = %d, want 1", len(friendships))
+	}
+
+	type friendship struct {
+		PlayerID float64
+		FriendID float64
+	}
+	var f friendship
+	if err := bsatn.Unmarshal(friendships[0], &f); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if f.PlayerID != f.FriendID {
+		t.Fatalf("PlayerID = %v, FriendID = %v, want equal (both $ref ada)", f.PlayerID, f.FriendID)
+	}
+}
+
+func TestLoadFixturesUnknownTableErrors(t *testing.T) {
+	path := writeFixtureFile(t, `{"tables": [{"name": "ghosts", "rows": [{}]}]}`)
+	database := db.NewDatabase(db.NewMemEngine())
+	if err := LoadFixtures(database, path, map[string]bsatn.AlgebraicType{}); err == nil {
+		t.Fatal("expected error for unregistered table schema")
+	}
+}
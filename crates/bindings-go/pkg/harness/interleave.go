@@ -0,0 +1,44 @@
+// Package harness provides test utilities for exercising a Go-hosted
+// SpacetimeDB module: forcing specific goroutine interleavings, loading
+// fixtures, and asserting on table contents.
+package harness
+
+import "sync"
+
+// Interleaving drives a fixed set of named steps through a barrier so a
+// test can force a specific execution order across goroutines that would
+// otherwise race, e.g. to reproduce a lock-conflict or deadlock scenario
+// deterministically.
+type Interleaving struct {
+	order []string
+	mu    sync.Mutex
+	cond  *sync.Cond
+	next  int
+}
+
+// NewInterleaving returns an Interleaving that releases the named steps in
+// order. Each name must be passed to Step exactly once by the goroutine
+// playing that role.
+func NewInterleaving(order ...string) *Interleaving {
+	in := &Interleaving{order: order}
+	in.cond = sync.NewCond(&in.mu)
+	return in
+}
+
+// Step blocks the calling goroutine until every step before name (per the
+// order passed to NewInterleaving) has completed, runs fn, then unblocks
+// whichever goroutine is waiting on the next step.
+func (in *Interleaving) Step(name string, fn func()) {
+	in.mu.Lock()
+	for in.order[in.next] != name {
+		in.cond.Wait()
+	}
+	in.mu.Unlock()
+
+	fn()
+
+	in.mu.Lock()
+	in.next++
+	in.cond.Broadcast()
+	in.mu.Unlock()
+}
@@ -0,0 +1,119 @@
+package harness
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MemorySampler returns the current guest memory size, in bytes, of the
+// module under test. wasm.Runtime.MemorySize satisfies this signature.
+type MemorySampler func() uint32
+
+// LeakReport summarizes one reducer's memory footprint across every
+// call a MemoryTracker observed for it.
+type LeakReport struct {
+	Reducer string
+	Calls   int
+	First   uint32
+	Last    uint32
+	Peak    uint32
+	// Monotonic is true if this reducer's memory sample never shrank
+	// across its observed calls and grew at least once — the signature
+	// of a steady leak, as opposed to memory use that grows once (e.g.
+	// warming a cache) then holds flat, or fluctuates but stays bounded.
+	Monotonic bool
+}
+
+func (r LeakReport) String() string {
+	if !r.Monotonic {
+		return fmt.Sprintf("%s: %d calls, %d -> %d bytes (peak %d), no leak pattern", r.Reducer, r.Calls, r.First, r.Last, r.Peak)
+	}
+	return fmt.Sprintf("%s: %d calls, %d -> %d bytes (peak %d), monotonically growing", r.Reducer, r.Calls, r.First, r.Last, r.Peak)
+}
+
+// MemoryTracker records a MemorySampler's readings across many
+// CallReducer invocations, grouped by reducer name, and reports which
+// reducers show a monotonic growth pattern — a likely leak that would
+// otherwise only surface after thousands of calls against a
+// long-running host. It has no way to observe a CallReducer on its own
+// (see pkg/spacetimedb.Handle.CallReducer and wasm.Runtime.CallReducer);
+// a caller must call Observe once per call, e.g. right after invoking
+// the reducer, the same way pkg/harness.QueryCache leaves invalidation
+// to its caller rather than trying to intercept writes itself.
+type MemoryTracker struct {
+	sample MemorySampler
+
+	mu      sync.Mutex
+	samples map[string][]uint32
+}
+
+// NewMemoryTracker returns a MemoryTracker reading memory size via sample.
+func NewMemoryTracker(sample MemorySampler) *MemoryTracker {
+	return &MemoryTracker{sample: sample, samples: make(map[string][]uint32)}
+}
+
+// Observe records the module's current memory size as a sample for
+// reducer. Call it once per CallReducer invocation of reducer.
+func (t *MemoryTracker) Observe(reducer string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.samples[reducer] = append(t.samples[reducer], t.sample())
+}
+
+// Report returns a LeakReport for every reducer Observe has been called
+// for at least once, sorted by reducer name.
+func (t *MemoryTracker) Report() []LeakReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	names := make([]string, 0, len(t.samples))
+	for name := range t.samples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reports := make([]LeakReport, 0, len(names))
+	for _, name := range names {
+		reports = append(reports, reportFor(name, t.samples[name]))
+	}
+	return reports
+}
+
+// Leaks returns the subset of Report whose Monotonic is true — the
+// reducers worth investigating for an actual leak.
+func (t *MemoryTracker) Leaks() []LeakReport {
+	var leaks []LeakReport
+	for _, r := range t.Report() {
+		if r.Monotonic {
+			leaks = append(leaks, r)
+		}
+	}
+	return leaks
+}
+
+func reportFor(reducer string, samples []uint32) LeakReport {
+	report := LeakReport{Reducer: reducer, Calls: len(samples)}
+	if len(samples) == 0 {
+		return report
+	}
+
+	report.First = samples[0]
+	report.Last = samples[len(samples)-1]
+	report.Peak = samples[0]
+
+	monotonic, grew := true, false
+	for i := 1; i < len(samples); i++ {
+		if samples[i] > report.Peak {
+			report.Peak = samples[i]
+		}
+		switch {
+		case samples[i] < samples[i-1]:
+			monotonic = false
+		case samples[i] > samples[i-1]:
+			grew = true
+		}
+	}
+	report.Monotonic = monotonic && grew
+	return report
+}
@@ -0,0 +1,60 @@
+package harness
+
+import "testing"
+
+func TestMemoryTrackerFlagsMonotonicGrowth(t *testing.T) {
+	sizes := []uint32{1000, 1200, 1400, 1600}
+	i := 0
+	tracker := NewMemoryTracker(func() uint32 {
+		size := sizes[i]
+		if i < len(sizes)-1 {
+			i++
+		}
+		return size
+	})
+
+	for range sizes {
+		tracker.Observe("leaky_reducer")
+	}
+
+	leaks := tracker.Leaks()
+	if len(leaks) != 1 {
+		t.Fatalf("Leaks = %d, want 1", len(leaks))
+	}
+	if leaks[0].Reducer != "leaky_reducer" || leaks[0].First != 1000 || leaks[0].Last != 1600 || leaks[0].Peak != 1600 {
+		t.Fatalf("unexpected report: %+v", leaks[0])
+	}
+}
+
+func TestMemoryTrackerIgnoresBoundedFluctuation(t *testing.T) {
+	sizes := []uint32{1000, 1100, 1000, 1050}
+	i := 0
+	tracker := NewMemoryTracker(func() uint32 {
+		size := sizes[i%len(sizes)]
+		i++
+		return size
+	})
+
+	for range sizes {
+		tracker.Observe("stable_reducer")
+	}
+
+	if leaks := tracker.Leaks(); len(leaks) != 0 {
+		t.Fatalf("Leaks = %v, want none for a reducer whose memory shrinks back down", leaks)
+	}
+	reports := tracker.Report()
+	if len(reports) != 1 || reports[0].Calls != len(sizes) {
+		t.Fatalf("Report = %+v, want one reducer with %d calls", reports, len(sizes))
+	}
+}
+
+func TestMemoryTrackerReportSortsByReducerName(t *testing.T) {
+	tracker := NewMemoryTracker(func() uint32 { return 100 })
+	tracker.Observe("zeta")
+	tracker.Observe("alpha")
+
+	reports := tracker.Report()
+	if len(reports) != 2 || reports[0].Reducer != "alpha" || reports[1].Reducer != "zeta" {
+		t.Fatalf("Report = %+v, want alpha before zeta", reports)
+	}
+}
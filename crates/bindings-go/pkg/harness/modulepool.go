@@ -0,0 +1,107 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/wasm"
+)
+
+// ModulePool compiles one wasm module once (see wasm.Compile) and hands
+// out isolated (wasm.Runtime, db.Database) pairs to a suite of
+// t.Parallel tests, so the pool pays wasm validation/compilation cost
+// once instead of once per test case. Each Acquire gets its own guest
+// instance — its own linear memory and globals — and its own
+// in-memory Database; see wasm.CompiledModule's doc comment for the
+// Runtime bookkeeping (log level, feature flags, SetClock, and so on)
+// that a CompiledModule's instances still share.
+//
+// A ModulePool is safe for concurrent use by multiple goroutines.
+type ModulePool struct {
+	ctx context.Context
+	cm  *wasm.CompiledModule
+
+	mu  sync.Mutex
+	seq uint64
+}
+
+// NewModulePool compiles wasmBytes against cfg and returns a pool ready
+// for Acquire. Call Close once every acquired instance's test has
+// finished, e.g. from a TestMain after m.Run().
+func NewModulePool(ctx context.Context, wasmBytes []byte, cfg wasm.Config) (*ModulePool, error) {
+	cm, err := wasm.Compile(ctx, wasmBytes, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("harness: NewModulePool: %w", err)
+	}
+	return &ModulePool{ctx: ctx, cm: cm}, nil
+}
+
+// PoolInstance is one test's isolated Runtime and Database, as returned
+// by ModulePool.Acquire.
+type PoolInstance struct {
+	Runtime  *wasm.Runtime
+	Database *db.Database
+
+	owner  string
+	closed atomic.Bool
+}
+
+// Validate reports an error if inst is being used by a test other than
+// the one that acquired it, or after that test's Cleanup has already
+// closed it — the two ways a pooled handle can leak across tests: a
+// reference stashed somewhere that outlives its owning test (e.g. a
+// package-level variable, or a goroutine the owning test didn't wait
+// for), or one test mistakenly reusing a handle obtained by another.
+// Calling it is opt-in, for a test or helper that wants to assert it is
+// not about to act on a leaked handle.
+func (inst *PoolInstance) Validate(t *testing.T) error {
+	if inst.closed.Load() {
+		return fmt.Errorf("harness: instance acquired by test %q used after its cleanup already closed it (called from %q)", inst.owner, t.Name())
+	}
+	if t.Name() != inst.owner {
+		return fmt.Errorf("harness: instance acquired by test %q used by test %q", inst.owner, t.Name())
+	}
+	return nil
+}
+
+// Acquire instantiates a fresh isolated Runtime and Database for t and
+// registers a Cleanup that closes the Runtime's guest instance (not the
+// pool's shared engine) when t ends. It is safe to call concurrently
+// from multiple t.Parallel subtests.
+func (p *ModulePool) Acquire(t *testing.T) *PoolInstance {
+	t.Helper()
+
+	p.mu.Lock()
+	p.seq++
+	name := fmt.Sprintf("%s#%d", t.Name(), p.seq)
+	p.mu.Unlock()
+
+	rt, err := p.cm.Instantiate(p.ctx, name)
+	if err != nil {
+		t.Fatalf("harness: ModulePool.Acquire: %v", err)
+	}
+
+	inst := &PoolInstance{
+		Runtime:  rt,
+		Database: db.NewDatabase(db.NewMemEngine()),
+		owner:    t.Name(),
+	}
+	t.Cleanup(func() {
+		inst.closed.Store(true)
+		if err := rt.Close(p.ctx); err != nil {
+			t.Errorf("harness: ModulePool: closing instance for %s: %v", t.Name(), err)
+		}
+	})
+	return inst
+}
+
+// Close releases the pool's shared compiled module and engine. Every
+// instance Acquire handed out must already be closed (normally via its
+// owning test's Cleanup) before calling this.
+func (p *ModulePool) Close() error {
+	return p.cm.Close(p.ctx)
+}
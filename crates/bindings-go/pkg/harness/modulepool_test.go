@@ -0,0 +1,77 @@
+package harness
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/wasm"
+)
+
+// emptyModule is the minimal valid wasm binary (magic + version, no
+// sections), enough to compile and instantiate without needing a real
+// SpacetimeDB module's exports for these pool-lifecycle tests.
+var emptyModule = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+func TestModulePoolGivesEachTestAnIsolatedInstanceAndDatabase(t *testing.T) {
+	pool, err := NewModulePool(context.Background(), emptyModule, wasm.Config{})
+	if err != nil {
+		t.Fatalf("NewModulePool: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := pool.Close(); err != nil {
+			t.Errorf("pool.Close: %v", err)
+		}
+	})
+
+	var mu sync.Mutex
+	seen := map[*wasm.Runtime]bool{}
+
+	for i := 0; i < 5; i++ {
+		t.Run("sub", func(t *testing.T) {
+			t.Parallel()
+			inst := pool.Acquire(t)
+
+			mu.Lock()
+			if seen[inst.Runtime] {
+				t.Error("two subtests were handed the same Runtime instance")
+			}
+			seen[inst.Runtime] = true
+			mu.Unlock()
+
+			inst.Database.RegisterTable(db.TableInfo{Name: "t"})
+			if got := inst.Database.Tables(); len(got) != 1 {
+				t.Errorf("Tables() = %v, want exactly this test's own table", got)
+			}
+
+			if err := inst.Validate(t); err != nil {
+				t.Errorf("Validate on the owning test: %v", err)
+			}
+		})
+	}
+}
+
+func TestPoolInstanceValidateRejectsCrossTestUse(t *testing.T) {
+	pool, err := NewModulePool(context.Background(), emptyModule, wasm.Config{})
+	if err != nil {
+		t.Fatalf("NewModulePool: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := pool.Close(); err != nil {
+			t.Errorf("pool.Close: %v", err)
+		}
+	})
+
+	var leaked *PoolInstance
+	t.Run("owner", func(t *testing.T) {
+		leaked = pool.Acquire(t)
+	})
+
+	// The owning subtest has already finished and its Cleanup has run,
+	// closing leaked's Runtime; using it from this (different) test
+	// must be reported, not silently allowed.
+	if err := leaked.Validate(t); err == nil {
+		t.Fatal("expected Validate to reject a handle used after its owning test finished")
+	}
+}
@@ -0,0 +1,131 @@
+package harness
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/query"
+)
+
+// QueryCache evaluates subscription query Plans against a db.Database at
+// most once per table per invalidation, fanning the same decoded rows
+// out to every Evaluate call for the same table and query.Plan.SQL —
+// mirroring how a real SpacetimeDB server evaluates a subscription query
+// once and pushes the result to every client subscribed to it, instead
+// of re-running it per client. It exists for harness scenarios that
+// simulate many clients subscribed to identical queries, where
+// evaluating (scan + decode + filter) once per transaction instead of
+// once per simulated client matters for how large a scenario stays fast
+// enough to run in CI.
+//
+// Caching only covers plan.Pushed (the part reduced to Plan.SQL, and
+// therefore guaranteed identical for identical SQL); plan.Residual
+// (WhereFunc closures, which cannot be compared for equality) is
+// re-applied against the cached rows on every Evaluate call, same as a
+// real subscription always re-applies its own residual filter
+// client-side.
+//
+// QueryCache has no way to observe writes to the underlying db.Engine
+// itself, so a caller must call Invalidate(table) after any reducer
+// call or direct engine write that touches table; forgetting to is a
+// staleness bug, not a crash, so tests that use QueryCache should assert
+// on the values it returns rather than only that it returns quickly.
+type QueryCache struct {
+	database *db.Database
+
+	mu         sync.Mutex
+	generation map[string]uint64
+	entries    map[cacheKey]*cacheEntry
+}
+
+type cacheKey struct {
+	table string
+	sql   string
+}
+
+type cacheEntry struct {
+	generation uint64
+	rows       []map[string]any
+}
+
+// NewQueryCache returns a QueryCache evaluating queries against database.
+func NewQueryCache(database *db.Database) *QueryCache {
+	return &QueryCache{
+		database:   database,
+		generation: make(map[string]uint64),
+		entries:    make(map[cacheKey]*cacheEntry),
+	}
+}
+
+// Invalidate discards any cached result for table, forcing the next
+// Evaluate call against it to re-scan. Call this once per write (or
+// batch of writes within one reducer/transaction), not once per
+// subscriber — that's the whole point of caching.
+func (c *QueryCache) Invalidate(table string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation[table]++
+}
+
+// Evaluate returns table's rows (decoded against schema) matching plan,
+// reusing a previous Evaluate call's scan of table under the same
+// plan.SQL if table has not been Invalidate'd since.
+func (c *QueryCache) Evaluate(table string, schema bsatn.AlgebraicType, plan query.Plan) ([]map[string]any, error) {
+	key := cacheKey{table: table, sql: plan.SQL}
+
+	c.mu.Lock()
+	gen := c.generation[table]
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	var rows []map[string]any
+	if ok && entry.generation == gen {
+		rows = entry.rows
+	} else {
+		scanned, err := c.scanPushed(table, schema, plan)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.entries[key] = &cacheEntry{generation: gen, rows: scanned}
+		c.mu.Unlock()
+		rows = scanned
+	}
+
+	matched := make([]map[string]any, 0, len(rows))
+	for _, row := range rows {
+		if plan.Matches(row) {
+			matched = append(matched, row)
+		}
+	}
+	return matched, nil
+}
+
+// scanPushed decodes every row of table and keeps the ones satisfying
+// plan.Pushed, the part of plan that is safe to cache since it depends
+// only on plan.SQL.
+func (c *QueryCache) scanPushed(table string, schema bsatn.AlgebraicType, plan query.Plan) ([]map[string]any, error) {
+	cur, err := c.database.Engine.Scan(table)
+	if err != nil {
+		return nil, fmt.Errorf("harness: QueryCache: scan %q: %w", table, err)
+	}
+	defer cur.Close()
+
+	var rows []map[string]any
+	for cur.Next() {
+		decoded, err := bsatn.UnmarshalJSON(cur.Value(), schema)
+		if err != nil {
+			return nil, fmt.Errorf("harness: QueryCache: decode row in %q: %w", table, err)
+		}
+		row, ok := decoded.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("harness: QueryCache: schema for %q must describe a product, decoded %T", table, decoded)
+		}
+		if plan.MatchesPushed(row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
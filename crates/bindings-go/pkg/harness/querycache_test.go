@@ -0,0 +1,99 @@
+package harness
+
+import (
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/query"
+)
+
+// countingEngine wraps a db.Engine and counts Scan calls, so a test can
+// assert QueryCache actually skips a re-scan instead of just returning
+// the right rows by coincidence.
+type countingEngine struct {
+	db.Engine
+	scans int
+}
+
+func (e *countingEngine) Scan(table string) (db.Cursor, error) {
+	e.scans++
+	return e.Engine.Scan(table)
+}
+
+func newCachedPlayers(t *testing.T) (*countingEngine, *QueryCache) {
+	t.Helper()
+	counting := &countingEngine{Engine: db.NewMemEngine()}
+	database := db.NewDatabase(counting)
+	putPlayer(t, database, 1, 1, "Ada", 10)
+	putPlayer(t, database, 2, 2, "Grace", 20)
+	return counting, NewQueryCache(database)
+}
+
+func TestQueryCacheReusesScanForIdenticalPlan(t *testing.T) {
+	counting, cache := newCachedPlayers(t)
+	plan := query.On("players").Where("score", query.Gte, float64(10)).Compile()
+
+	rows1, err := cache.Evaluate("players", playerSchema(), plan)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	rows2, err := cache.Evaluate("players", playerSchema(), plan)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(rows1) != 2 || len(rows2) != 2 {
+		t.Fatalf("rows1 = %d, rows2 = %d, want 2, 2", len(rows1), len(rows2))
+	}
+	if counting.scans != 1 {
+		t.Fatalf("scans = %d, want 1 (second Evaluate should hit the cache)", counting.scans)
+	}
+}
+
+func TestQueryCacheAppliesResidualPerCallNotCached(t *testing.T) {
+	counting, cache := newCachedPlayers(t)
+	plan := query.On("players").
+		Where("score", query.Gte, float64(10)).
+		WhereFunc(func(row map[string]any) bool { return row["name"] == "Ada" }).
+		Compile()
+
+	rows, err := cache.Evaluate("players", playerSchema(), plan)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(rows) != 1 || rows[0]["name"] != "Ada" {
+		t.Fatalf("rows = %v, want just Ada", rows)
+	}
+	if counting.scans != 1 {
+		t.Fatalf("scans = %d, want 1", counting.scans)
+	}
+}
+
+func TestQueryCacheInvalidateForcesRescan(t *testing.T) {
+	counting, cache := newCachedPlayers(t)
+	plan := query.On("players").Where("score", query.Gte, float64(10)).Compile()
+
+	if _, err := cache.Evaluate("players", playerSchema(), plan); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	putPlayer(t, cacheDatabaseFor(counting), 3, 3, "Bob", 30)
+	cache.Invalidate("players")
+
+	rows, err := cache.Evaluate("players", playerSchema(), plan)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %d, want 3 after invalidation picks up the new row", len(rows))
+	}
+	if counting.scans != 2 {
+		t.Fatalf("scans = %d, want 2 (one before, one after Invalidate)", counting.scans)
+	}
+}
+
+// cacheDatabaseFor is a small helper so
+// TestQueryCacheInvalidateForcesRescan can write directly through the
+// same engine QueryCache is reading, without threading a *db.Database
+// through newCachedPlayers just for one test.
+func cacheDatabaseFor(engine db.Engine) *db.Database {
+	return db.NewDatabase(engine)
+}
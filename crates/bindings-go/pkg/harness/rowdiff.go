@@ -0,0 +1,208 @@
+package harness
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldDiff is one field where a Changed row's actual value didn't match
+// what was expected. Path is the field's BSATN name, as decoded by
+// bsatn.UnmarshalJSON (nested paths aren't produced here since
+// TableExpectation only ever decodes one product level per row).
+type FieldDiff struct {
+	Path     string
+	Expected any
+	Actual   any
+}
+
+// ChangedRow is a pair of expected/actual rows treated as "the same row,
+// but wrong" rather than two unrelated rows — see pairChangedRows —
+// reported as just the fields that differ, so a large row's failure
+// doesn't dump every unchanged column.
+type ChangedRow struct {
+	Fields []FieldDiff
+}
+
+func (c ChangedRow) String() string {
+	parts := make([]string, len(c.Fields))
+	for i, f := range c.Fields {
+		parts[i] = fmt.Sprintf("%s: expected %v, got %v", f.Path, f.Expected, f.Actual)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// RowDiff reports how a table's actual contents differ from what was
+// expected, ignoring any columns the comparison was asked to ignore. A
+// nil *RowDiff means the two row sets matched.
+type RowDiff struct {
+	// Changed holds expected/actual row pairs recognized as the same
+	// logical row with some fields wrong (see pairChangedRows).
+	Changed []ChangedRow
+	// Missing holds expected rows with no matching or close-enough
+	// actual row.
+	Missing []map[string]any
+	// Unexpected holds actual rows with no matching or close-enough
+	// expected row.
+	Unexpected []map[string]any
+}
+
+func (d *RowDiff) Error() string {
+	var b strings.Builder
+	b.WriteString("harness: table contents did not match")
+	for _, row := range d.Changed {
+		fmt.Fprintf(&b, "\n  ~ changed:    %s", row)
+	}
+	for _, row := range d.Missing {
+		fmt.Fprintf(&b, "\n  - missing:    %s", formatRow(row))
+	}
+	for _, row := range d.Unexpected {
+		fmt.Fprintf(&b, "\n  + unexpected: %s", formatRow(row))
+	}
+	return b.String()
+}
+
+func formatRow(row map[string]any) string {
+	keys := make([]string, 0, len(row))
+	for k := range row {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", k, row[k])
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// diffRowSets compares actual against expected as multisets of rows,
+// ignoring any column named in ignore and matching each expected row
+// against at most one actual row, order-independent since a table scan
+// makes no ordering guarantee callers should depend on.
+func diffRowSets(actual, expected []map[string]any, ignore map[string]bool) *RowDiff {
+	remaining := append([]map[string]any(nil), actual...)
+	var missing []map[string]any
+	for _, want := range expected {
+		idx := -1
+		for i, got := range remaining {
+			if rowsEqual(got, want, ignore) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			missing = append(missing, want)
+			continue
+		}
+		remaining = append(remaining[:idx], remaining[idx+1:]...)
+	}
+	if len(missing) == 0 && len(remaining) == 0 {
+		return nil
+	}
+
+	var changed []ChangedRow
+	missing, remaining = pairChangedRows(missing, remaining, ignore, &changed)
+	if len(missing) == 0 && len(remaining) == 0 && len(changed) == 0 {
+		return nil
+	}
+	return &RowDiff{Changed: changed, Missing: missing, Unexpected: remaining}
+}
+
+// pairChangedRows greedily pairs each missing row with the unexpected
+// row it shares the most matching fields with. A pair sharing a strict
+// majority of its fields is treated as the same logical row with a few
+// fields wrong (a ChangedRow) rather than two unrelated rows; anything
+// left unpaired, or whose best match doesn't clear that bar, is reported
+// as a plain Missing/Unexpected row instead.
+func pairChangedRows(missing, unexpected []map[string]any, ignore map[string]bool, changed *[]ChangedRow) (stillMissing, stillUnexpected []map[string]any) {
+	stillUnexpected = unexpected
+	for _, want := range missing {
+		bestIdx, bestScore := -1, 0
+		for i, got := range stillUnexpected {
+			if score := matchingFieldCount(want, got, ignore); score > bestScore {
+				bestIdx, bestScore = i, score
+			}
+		}
+		total := countFields(want, ignore)
+		if bestIdx >= 0 && total > 0 && bestScore*2 > total {
+			*changed = append(*changed, diffFields(want, stillUnexpected[bestIdx], ignore))
+			stillUnexpected = append(stillUnexpected[:bestIdx], stillUnexpected[bestIdx+1:]...)
+			continue
+		}
+		stillMissing = append(stillMissing, want)
+	}
+	return stillMissing, stillUnexpected
+}
+
+func countFields(row map[string]any, ignore map[string]bool) int {
+	n := 0
+	for k := range row {
+		if !ignore[k] {
+			n++
+		}
+	}
+	return n
+}
+
+func matchingFieldCount(a, b map[string]any, ignore map[string]bool) int {
+	n := 0
+	for k, v := range a {
+		if ignore[k] {
+			continue
+		}
+		if bv, ok := b[k]; ok && reflect.DeepEqual(v, bv) {
+			n++
+		}
+	}
+	return n
+}
+
+// diffFields returns the fields (by BSATN name) where want and got
+// differ, eliding every field where they agree.
+func diffFields(want, got map[string]any, ignore map[string]bool) ChangedRow {
+	keys := map[string]bool{}
+	for k := range want {
+		keys[k] = true
+	}
+	for k := range got {
+		keys[k] = true
+	}
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var fields []FieldDiff
+	for _, name := range names {
+		if ignore[name] {
+			continue
+		}
+		if reflect.DeepEqual(want[name], got[name]) {
+			continue
+		}
+		fields = append(fields, FieldDiff{Path: name, Expected: want[name], Actual: got[name]})
+	}
+	return ChangedRow{Fields: fields}
+}
+
+func rowsEqual(a, b map[string]any, ignore map[string]bool) bool {
+	for k, v := range a {
+		if ignore[k] {
+			continue
+		}
+		if !reflect.DeepEqual(v, b[k]) {
+			return false
+		}
+	}
+	for k, v := range b {
+		if ignore[k] {
+			continue
+		}
+		if !reflect.DeepEqual(v, a[k]) {
+			return false
+		}
+	}
+	return true
+}
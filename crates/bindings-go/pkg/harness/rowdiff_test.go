@@ -0,0 +1,66 @@
+package harness
+
+import "testing"
+
+func TestDiffRowSetsPairsMajorityMatchAsChanged(t *testing.T) {
+	expected := []map[string]any{{"id": 1.0, "name": "Ada", "score": float64(10)}}
+	actual := []map[string]any{{"id": 1.0, "name": "Ada", "score": float64(99)}}
+
+	diff := diffRowSets(actual, expected, map[string]bool{})
+	if diff == nil {
+		t.Fatal("expected a diff")
+	}
+	if len(diff.Changed) != 1 {
+		t.Fatalf("Changed = %v, want 1 entry", diff.Changed)
+	}
+	if len(diff.Missing) != 0 || len(diff.Unexpected) != 0 {
+		t.Fatalf("expected the mismatch to be reported only as Changed, got Missing=%v Unexpected=%v", diff.Missing, diff.Unexpected)
+	}
+
+	fields := diff.Changed[0].Fields
+	if len(fields) != 1 {
+		t.Fatalf("Fields = %v, want exactly the one differing field", fields)
+	}
+	if fields[0].Path != "score" || fields[0].Expected != float64(10) || fields[0].Actual != float64(99) {
+		t.Fatalf("FieldDiff = %+v, want score: 10 -> 99", fields[0])
+	}
+}
+
+func TestDiffRowSetsLeavesMinorityMatchAsMissingAndUnexpected(t *testing.T) {
+	expected := []map[string]any{{"id": 1.0, "name": "Ada", "score": float64(10)}}
+	actual := []map[string]any{{"id": 1.0, "name": "Grace", "score": float64(20)}}
+
+	diff := diffRowSets(actual, expected, map[string]bool{})
+	if diff == nil {
+		t.Fatal("expected a diff")
+	}
+	if len(diff.Changed) != 0 {
+		t.Fatalf("Changed = %v, want none (only a minority of fields matched)", diff.Changed)
+	}
+	if len(diff.Missing) != 1 || len(diff.Unexpected) != 1 {
+		t.Fatalf("Missing=%v Unexpected=%v, want one of each", diff.Missing, diff.Unexpected)
+	}
+}
+
+func TestDiffRowSetsIgnoredFieldsDoNotCountTowardMatching(t *testing.T) {
+	expected := []map[string]any{{"id": 1.0, "score": float64(10)}}
+	actual := []map[string]any{{"id": 2.0, "score": float64(99)}}
+
+	diff := diffRowSets(actual, expected, map[string]bool{"id": true})
+	if diff == nil {
+		t.Fatal("expected a diff")
+	}
+	// With "id" ignored, only "score" counts, and it differs entirely —
+	// no fields match, so this should not be paired as Changed.
+	if len(diff.Changed) != 0 {
+		t.Fatalf("Changed = %v, want none", diff.Changed)
+	}
+}
+
+func TestChangedRowStringFormatsFieldDiffs(t *testing.T) {
+	c := ChangedRow{Fields: []FieldDiff{{Path: "score", Expected: 10, Actual: 99}}}
+	want := "score: expected 10, got 99"
+	if got := c.String(); got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package harness
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/backup"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+// Snapshot serializes database as a full backup.Write archive, for use
+// as a fixture's base state: expensive to build once (e.g. by running
+// migrations and seed reducers against a real module), cheap to restore
+// many times across a test suite via RestoreChain.
+func Snapshot(database *db.Database) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := backup.Write(&buf, database); err != nil {
+		return nil, fmt.Errorf("harness: Snapshot: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SnapshotDelta captures how database has changed since base (a
+// Snapshot or a prior SnapshotDelta's result restored via RestoreChain),
+// as a backup.WriteDelta archive. A test that mutates a shared fixture
+// database can save just the delta instead of a whole new Snapshot,
+// keeping repeated CI runs that restore many small variations of the
+// same base fixture fast.
+func SnapshotDelta(database *db.Database, base []byte) ([]byte, error) {
+	baseDB, _, err := backup.Read(bytes.NewReader(base), db.NewMemEngine())
+	if err != nil {
+		return nil, fmt.Errorf("harness: SnapshotDelta: decode base: %w", err)
+	}
+	var buf bytes.Buffer
+	if _, err := backup.WriteDelta(&buf, database, baseDB); err != nil {
+		return nil, fmt.Errorf("harness: SnapshotDelta: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RestoreChain rebuilds a database on engine from a Snapshot's base
+// archive plus zero or more SnapshotDelta archives applied in order,
+// giving a test the base fixture's exact final state without having to
+// replay whatever reducer calls originally produced it.
+func RestoreChain(engine db.Engine, base []byte, deltas ...[]byte) (*db.Database, error) {
+	readers := make([]io.Reader, len(deltas))
+	for i, d := range deltas {
+		readers[i] = bytes.NewReader(d)
+	}
+	database, _, err := backup.ApplyChain(bytes.NewReader(base), readers, engine, backup.ReadOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("harness: RestoreChain: %w", err)
+	}
+	return database, nil
+}
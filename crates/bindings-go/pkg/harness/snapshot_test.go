@@ -0,0 +1,38 @@
+package harness
+
+import (
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func TestSnapshotDeltaRestoreChainRoundTrip(t *testing.T) {
+	base := db.NewDatabase(db.NewMemEngine())
+	base.RegisterTable(db.TableInfo{Name: "players"})
+	putPlayer(t, base, 1, 1, "Ada", 10)
+	baseArchive, err := Snapshot(base)
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	edited := db.NewDatabase(db.NewMemEngine())
+	edited.RegisterTable(db.TableInfo{Name: "players"})
+	putPlayer(t, edited, 1, 1, "Ada", 10)
+	putPlayer(t, edited, 2, 2, "Grace", 20)
+	delta, err := SnapshotDelta(edited, baseArchive)
+	if err != nil {
+		t.Fatalf("SnapshotDelta: %v", err)
+	}
+
+	restored, err := RestoreChain(db.NewMemEngine(), baseArchive, delta)
+	if err != nil {
+		t.Fatalf("RestoreChain: %v", err)
+	}
+	err = ExpectTable(restored, "players", playerSchema()).ToContainExactly([]map[string]any{
+		{"id": 1.0, "name": "Ada", "score": float64(10)},
+		{"id": 2.0, "name": "Grace", "score": float64(20)},
+	})
+	if err != nil {
+		t.Fatalf("ToContainExactly: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package harness
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// Action is one reducer-call-shaped operation a stress Runner can pick
+// from. Weight controls how often it is chosen relative to the other
+// actions in the same run; Run performs the call and returns any error
+// the reducer itself reported.
+type Action struct {
+	Name   string
+	Weight int
+	Run    func() error
+}
+
+// Invariant is a property of the datastore that must hold no matter
+// what interleaving of Actions produced the current state, e.g. "sum of
+// account balances equals the total ever deposited" for a bank example.
+type Invariant struct {
+	Name  string
+	Check func() error
+}
+
+// Config controls one stress run.
+type Config struct {
+	// Workers is the number of goroutines concurrently picking and
+	// running Actions.
+	Workers int
+	// Iterations is the number of actions each worker runs.
+	Iterations int
+	// CheckEvery runs every Invariant after this many total actions
+	// have completed across all workers. Checks race with in-flight
+	// actions from other workers by design — this validates
+	// eventually-consistent invariants, not a stop-the-world snapshot;
+	// checks that need exclusivity should take their own locks inside
+	// Check.
+	CheckEvery int
+}
+
+// Failure describes an Invariant that failed during a Run.
+type Failure struct {
+	Invariant string
+	Err       error
+	// Log is the full, in-order sequence of action names that had
+	// completed by the time the failing check ran.
+	Log []string
+	// Minimized is the shortest prefix of Log that Runner.Minimize
+	// found still reproduces the failure, or nil if Reset/Replay were
+	// not configured.
+	Minimized []string
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("stress: invariant %q failed after %d actions: %v", f.Invariant, len(f.Log), f.Err)
+}
+
+// Runner drives a concurrent mix of Actions against a system under test
+// and periodically validates Invariants.
+type Runner struct {
+	Actions    []Action
+	Invariants []Invariant
+
+	// Reset and Replay are optional; when both are set, a failing Run
+	// minimizes its reproduction by replaying prefixes of the action
+	// log single-threaded against a freshly Reset system. Reset must
+	// return the system to the same starting state Run began from.
+	Reset  func()
+	Replay func(actionName string) error
+}
+
+// Run executes cfg.Workers goroutines, each performing cfg.Iterations
+// weighted-random Actions, checking every Invariant after every
+// cfg.CheckEvery completed actions. It returns the first Failure
+// observed, or nil if every check passed.
+func (r *Runner) Run(cfg Config) (*Failure, error) {
+	if len(r.Actions) == 0 {
+		return nil, fmt.Errorf("stress: no actions configured")
+	}
+	totalWeight := 0
+	for _, a := range r.Actions {
+		totalWeight += a.Weight
+	}
+	if totalWeight <= 0 {
+		return nil, fmt.Errorf("stress: total action weight must be positive")
+	}
+
+	var (
+		mu      sync.Mutex
+		log     []string
+		count   int
+		failure *Failure
+	)
+
+	pick := func(rnd *rand.Rand) Action {
+		n := rnd.Intn(totalWeight)
+		for _, a := range r.Actions {
+			if n < a.Weight {
+				return a
+			}
+			n -= a.Weight
+		}
+		return r.Actions[len(r.Actions)-1]
+	}
+
+	recordAndMaybeCheck := func(name string) {
+		mu.Lock()
+		log = append(log, name)
+		count++
+		due := cfg.CheckEvery > 0 && count%cfg.CheckEvery == 0 && failure == nil
+		var snapshot []string
+		if due {
+			snapshot = append([]string(nil), log...)
+		}
+		mu.Unlock()
+
+		if !due {
+			return
+		}
+		for _, inv := range r.Invariants {
+			if err := inv.Check(); err != nil {
+				mu.Lock()
+				if failure == nil {
+					failure = &Failure{Invariant: inv.Name, Err: err, Log: snapshot}
+				}
+				mu.Unlock()
+				return
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+		rnd := rand.New(rand.NewSource(int64(w) + 1))
+		go func() {
+			defer wg.Done()
+			for i := 0; i < cfg.Iterations; i++ {
+				mu.Lock()
+				stop := failure != nil
+				mu.Unlock()
+				if stop {
+					return
+				}
+				a := pick(rnd)
+				_ = a.Run()
+				recordAndMaybeCheck(a.Name)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failure != nil && r.Reset != nil && r.Replay != nil {
+		failure.Minimized = r.minimize(failure)
+	}
+	return failure, nil
+}
+
+// minimize applies delta-debugging (ddmin) to failure.Log: it repeatedly
+// tries removing chunks of the log and replaying the remainder
+// single-threaded against a freshly Reset system, keeping the removal
+// whenever the same invariant still fails. It returns the smallest
+// subsequence it found, which may still be the full log if no chunk
+// could be safely removed.
+func (r *Runner) minimize(failure *Failure) []string {
+	current := append([]string(nil), failure.Log...)
+	chunkSize := len(current) / 2
+
+	reproduces := func(seq []string) bool {
+		r.Reset()
+		for _, name := range seq {
+			_ = r.Replay(name)
+		}
+		for _, inv := range r.Invariants {
+			if inv.Name != failure.Invariant {
+				continue
+			}
+			return inv.Check() != nil
+		}
+		return false
+	}
+
+	for chunkSize >= 1 {
+		improved := false
+		for start := 0; start < len(current); start += chunkSize {
+			end := start + chunkSize
+			if end > len(current) {
+				end = len(current)
+			}
+			candidate := append(append([]string(nil), current[:start]...), current[end:]...)
+			if len(candidate) == len(current) {
+				continue
+			}
+			if reproduces(candidate) {
+				current = candidate
+				improved = true
+				break
+			}
+		}
+		if !improved {
+			chunkSize /= 2
+		}
+	}
+	return current
+}
@@ -0,0 +1,119 @@
+package harness
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// bank is a minimal shared-state system under test: balances plus a
+// running total, used to exercise the "balance conservation" style
+// invariant the request describes.
+type bank struct {
+	mu       sync.Mutex
+	balances map[string]int
+	total    int
+}
+
+func newBank() *bank {
+	return &bank{balances: map[string]int{"a": 100, "b": 100}, total: 200}
+}
+
+func (b *bank) transfer(from, to string, amount int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.balances[from] < amount {
+		return fmt.Errorf("insufficient funds")
+	}
+	b.balances[from] -= amount
+	b.balances[to] += amount
+	return nil
+}
+
+func (b *bank) sumBalances() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sum := 0
+	for _, v := range b.balances {
+		sum += v
+	}
+	return sum
+}
+
+func TestRunnerPassesWhenInvariantHolds(t *testing.T) {
+	b := newBank()
+	runner := &Runner{
+		Actions: []Action{
+			{Name: "a_to_b", Weight: 1, Run: func() error { return b.transfer("a", "b", 1) }},
+			{Name: "b_to_a", Weight: 1, Run: func() error { return b.transfer("b", "a", 1) }},
+		},
+		Invariants: []Invariant{
+			{Name: "conservation", Check: func() error {
+				if got := b.sumBalances(); got != b.total {
+					return fmt.Errorf("sum = %d, want %d", got, b.total)
+				}
+				return nil
+			}},
+		},
+	}
+	failure, err := runner.Run(Config{Workers: 4, Iterations: 200, CheckEvery: 50})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if failure != nil {
+		t.Fatalf("unexpected failure: %v", failure)
+	}
+}
+
+// TestRunnerDetectsAndMinimizesFailure uses a single worker (so the
+// action log is deterministic) and an action that corrupts the total on
+// its third call, then checks that Run reports it and that Minimize
+// shrinks the reproduction down to just that corrupting call.
+func TestRunnerDetectsAndMinimizesFailure(t *testing.T) {
+	b := newBank()
+	calls := 0
+	corrupt := func() error {
+		calls++
+		if calls == 3 {
+			b.mu.Lock()
+			b.balances["a"] += 1000 // corrupts conservation
+			b.mu.Unlock()
+		}
+		return nil
+	}
+
+	runner := &Runner{
+		Actions: []Action{
+			{Name: "noop", Weight: 1, Run: corrupt},
+		},
+		Invariants: []Invariant{
+			{Name: "conservation", Check: func() error {
+				if got := b.sumBalances(); got != b.total {
+					return fmt.Errorf("sum = %d, want %d", got, b.total)
+				}
+				return nil
+			}},
+		},
+		Reset: func() {
+			b.mu.Lock()
+			b.balances = map[string]int{"a": 100, "b": 100}
+			b.mu.Unlock()
+			calls = 0
+		},
+		Replay: func(name string) error { return corrupt() },
+	}
+
+	failure, err := runner.Run(Config{Workers: 1, Iterations: 5, CheckEvery: 1})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if failure == nil {
+		t.Fatal("expected a failure to be detected")
+	}
+	if failure.Invariant != "conservation" {
+		t.Fatalf("Invariant = %q, want conservation", failure.Invariant)
+	}
+	if len(failure.Minimized) != 3 {
+		t.Fatalf("Minimized = %v, want exactly the 3 calls needed to trigger corruption", failure.Minimized)
+	}
+}
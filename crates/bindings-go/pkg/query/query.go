@@ -0,0 +1,151 @@
+// Package query provides a small typed query builder for subscription
+// predicates. Predicates that the server's SQL subscription language can
+// express are pushed down verbatim; anything it can't (arbitrary Go
+// functions, comparisons across unsupported types) is kept as a residual
+// filter evaluated client-side against decoded rows.
+package query
+
+import "fmt"
+
+// Op is a comparison operator supported by pushdown.
+type Op string
+
+const (
+	Eq  Op = "="
+	Neq Op = "<>"
+	Lt  Op = "<"
+	Lte Op = "<="
+	Gt  Op = ">"
+	Gte Op = ">="
+)
+
+// Predicate is a single `column <op> value` condition.
+type Predicate struct {
+	Column string
+	Op     Op
+	Value  any
+}
+
+// Builder accumulates predicates for one table subscription and reports
+// which of them can be pushed into the generated SQL versus which must be
+// evaluated locally as a residual filter.
+type Builder struct {
+	table      string
+	predicates []Predicate
+	residual   []func(row map[string]any) bool
+}
+
+// On starts a query against table.
+func On(table string) *Builder {
+	return &Builder{table: table}
+}
+
+// Where adds a pushable comparison predicate.
+func (b *Builder) Where(column string, op Op, value any) *Builder {
+	b.predicates = append(b.predicates, Predicate{Column: column, Op: op, Value: value})
+	return b
+}
+
+// WhereFunc adds an arbitrary Go predicate that cannot be pushed down and
+// is always evaluated client-side against the decoded row.
+func (b *Builder) WhereFunc(fn func(row map[string]any) bool) *Builder {
+	b.residual = append(b.residual, fn)
+	return b
+}
+
+// Plan is the result of compiling a Builder: the SQL sent to the server
+// and the residual filter (if any) the client must still apply.
+type Plan struct {
+	SQL      string
+	Pushed   []Predicate
+	Residual []func(row map[string]any) bool
+}
+
+// Compile builds the SUBSCRIBE-compatible SQL for b, pushing every
+// Predicate down (WhereFunc conditions can never be pushed, since the
+// server has no way to evaluate an opaque Go closure).
+func (b *Builder) Compile() Plan {
+	sql := fmt.Sprintf("SELECT * FROM %s", b.table)
+	for i, p := range b.predicates {
+		if i == 0 {
+			sql += " WHERE "
+		} else {
+			sql += " AND "
+		}
+		sql += fmt.Sprintf("%s %s %s", p.Column, p.Op, sqlLiteral(p.Value))
+	}
+	return Plan{SQL: sql, Pushed: b.predicates, Residual: b.residual}
+}
+
+// Matches reports whether row satisfies every residual filter in p. Rows
+// already satisfy every pushed predicate by construction of the SQL, so
+// callers only need to run the residual filters after decoding.
+func (p Plan) Matches(row map[string]any) bool {
+	for _, fn := range p.Residual {
+		if !fn(row) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesPushed reports whether row satisfies every predicate in
+// p.Pushed, evaluated directly against the decoded row instead of a
+// real SQL engine. A client with a live server connection never needs
+// this — the server only sends rows already matching Pushed — but a
+// caller evaluating a Plan locally against an in-process or cached
+// dataset (an embedded module, or a test harness deduplicating
+// subscription evaluation, see pkg/harness.QueryCache) has no server to
+// rely on for that filtering.
+func (p Plan) MatchesPushed(row map[string]any) bool {
+	for _, pred := range p.Pushed {
+		if !predicateMatches(row, pred) {
+			return false
+		}
+	}
+	return true
+}
+
+func predicateMatches(row map[string]any, p Predicate) bool {
+	v, ok := row[p.Column]
+	if !ok {
+		return false
+	}
+	switch p.Op {
+	case Eq:
+		return v == p.Value
+	case Neq:
+		return v != p.Value
+	}
+	lhs, lok := toFloat(v)
+	rhs, rok := toFloat(p.Value)
+	if !lok || !rok {
+		return false
+	}
+	switch p.Op {
+	case Lt:
+		return lhs < rhs
+	case Lte:
+		return lhs <= rhs
+	case Gt:
+		return lhs > rhs
+	case Gte:
+		return lhs >= rhs
+	default:
+		return false
+	}
+}
+
+func toFloat(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func sqlLiteral(v any) string {
+	switch x := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", x)
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
@@ -0,0 +1,51 @@
+package query
+
+import "testing"
+
+func TestCompilePushesTypedPredicates(t *testing.T) {
+	plan := On("players").Where("score", Gt, 10).Where("name", Eq, "bob").Compile()
+	want := `SELECT * FROM players WHERE score > 10 AND name = "bob"`
+	if plan.SQL != want {
+		t.Fatalf("SQL = %q, want %q", plan.SQL, want)
+	}
+	if len(plan.Pushed) != 2 || len(plan.Residual) != 0 {
+		t.Fatalf("Pushed = %d, Residual = %d, want 2, 0", len(plan.Pushed), len(plan.Residual))
+	}
+}
+
+func TestWhereFuncStaysResidual(t *testing.T) {
+	plan := On("players").
+		Where("score", Gt, 10).
+		WhereFunc(func(row map[string]any) bool { return row["name"] != "banned" }).
+		Compile()
+
+	if len(plan.Residual) != 1 {
+		t.Fatalf("Residual = %d, want 1", len(plan.Residual))
+	}
+	if plan.Matches(map[string]any{"name": "banned"}) {
+		t.Fatalf("expected residual filter to reject banned row")
+	}
+	if !plan.Matches(map[string]any{"name": "alice"}) {
+		t.Fatalf("expected residual filter to accept alice row")
+	}
+}
+
+func TestMatchesPushedEvaluatesEveryComparisonOperator(t *testing.T) {
+	plan := On("players").Where("score", Gte, float64(10)).Where("name", Neq, "banned").Compile()
+	if !plan.MatchesPushed(map[string]any{"score": 10.0, "name": "alice"}) {
+		t.Fatal("expected row satisfying both pushed predicates to match")
+	}
+	if plan.MatchesPushed(map[string]any{"score": 9.0, "name": "alice"}) {
+		t.Fatal("expected row failing score >= 10 to not match")
+	}
+	if plan.MatchesPushed(map[string]any{"score": 10.0, "name": "banned"}) {
+		t.Fatal("expected row failing name <> banned to not match")
+	}
+}
+
+func TestMatchesPushedMissingColumnDoesNotMatch(t *testing.T) {
+	plan := On("players").Where("score", Gt, float64(10)).Compile()
+	if plan.MatchesPushed(map[string]any{"name": "alice"}) {
+		t.Fatal("expected row missing the compared column to not match")
+	}
+}
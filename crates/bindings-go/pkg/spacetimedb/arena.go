@@ -0,0 +1,34 @@
+package spacetimedb
+
+import "github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+
+// BatchDecoder decodes a batch of rows (e.g. one db.ChangeSet's worth of
+// inserts, see internal/db.ChangeSet) through a single bsatn.Arena
+// shared across every row in the batch, so a high-churn subscription
+// pays for one growing buffer instead of one heap allocation per
+// decoded string/[]byte field per row. Every value Decode produces
+// stays valid until the next Release.
+type BatchDecoder struct {
+	arena *bsatn.Arena
+}
+
+// NewBatchDecoder returns a BatchDecoder backed by a fresh Arena sized
+// size bytes.
+func NewBatchDecoder(size int) *BatchDecoder {
+	return &BatchDecoder{arena: bsatn.NewArena(size)}
+}
+
+// Decode decodes row into v using the decoder's shared arena, per
+// bsatn.UnmarshalArena.
+func (d *BatchDecoder) Decode(row []byte, v any) error {
+	return bsatn.UnmarshalArena(row, v, d.arena)
+}
+
+// Release discards every value Decode has produced since the last
+// Release (or since NewBatchDecoder), reusing the arena's buffer for
+// the next batch. Call it once a batch's user callbacks have all
+// returned — never while a decoded value from this batch is still in
+// use.
+func (d *BatchDecoder) Release() {
+	d.arena.Reset()
+}
@@ -0,0 +1,54 @@
+package spacetimedb
+
+import (
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+type arenaTestRow struct {
+	Name string
+}
+
+func TestBatchDecoderDecodesEachRow(t *testing.T) {
+	dec := NewBatchDecoder(64)
+	rows := []string{"alice", "bob", "carol"}
+
+	var decoded []arenaTestRow
+	for _, name := range rows {
+		buf, err := bsatn.Marshal(arenaTestRow{Name: name})
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		var row arenaTestRow
+		if err := dec.Decode(buf, &row); err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		decoded = append(decoded, row)
+	}
+
+	for i, name := range rows {
+		if decoded[i].Name != name {
+			t.Fatalf("decoded[%d].Name = %q, want %q", i, decoded[i].Name, name)
+		}
+	}
+}
+
+func TestBatchDecoderReleaseResetsArena(t *testing.T) {
+	dec := NewBatchDecoder(64)
+	buf, err := bsatn.Marshal(arenaTestRow{Name: "alice"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var row arenaTestRow
+	if err := dec.Decode(buf, &row); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if dec.arena.Len() == 0 {
+		t.Fatal("expected the arena to have grown")
+	}
+	dec.Release()
+	if dec.arena.Len() != 0 {
+		t.Fatalf("arena.Len() after Release = %d, want 0", dec.arena.Len())
+	}
+}
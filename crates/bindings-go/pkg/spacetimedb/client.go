@@ -0,0 +1,136 @@
+// Package spacetimedb is the Go client SDK for connecting to a
+// SpacetimeDB module: subscribing to table updates and invoking
+// reducers. It is under active development; today it exposes only the
+// row dispatch surface that generated event subscriber code builds on
+// top of, plus per-table churn and bandwidth accounting (see
+// SubscriptionStats).
+package spacetimedb
+
+import "sync"
+
+// InsertHandler is called with the BSATN-encoded row whenever a
+// subscribed table receives an insert. DeleteHandler and UpdateHandler
+// share its signature; they are distinct names so call sites read
+// clearly.
+type InsertHandler func(row []byte)
+
+// DeleteHandler is called with the BSATN-encoded row removed from a
+// subscribed table.
+type DeleteHandler func(row []byte)
+
+// UpdateHandler is called with the BSATN-encoded new value of a row
+// updated in a subscribed table.
+type UpdateHandler func(row []byte)
+
+// Client is the event-dispatch surface that codegen'd typed subscriber
+// wrappers (see internal/codegen) are built on. The full connected
+// client, which maintains a subscription to a live module and decodes
+// row update notifications off the wire, is added in a later change;
+// for now Conn is the only implementation and simply fans out
+// pre-decoded rows handed to it by the host under test.
+type Client interface {
+	// OnInsert registers fn to be called for every row inserted into
+	// table. Multiple handlers may be registered for the same table.
+	OnInsert(table string, fn InsertHandler)
+	// OnDelete registers fn to be called for every row deleted from
+	// table.
+	OnDelete(table string, fn DeleteHandler)
+	// OnUpdate registers fn to be called for every row updated in
+	// table.
+	OnUpdate(table string, fn UpdateHandler)
+}
+
+// Conn is a minimal in-process Client: it stores registered handlers,
+// dispatches to them when told about a row change, and accounts for the
+// row churn and bytes it dispatches per table (see SubscriptionStats).
+// It exists so generated event subscriber code and its tests have
+// something to register against ahead of the real network-connected
+// client.
+type Conn struct {
+	insertHandlers map[string][]InsertHandler
+	deleteHandlers map[string][]DeleteHandler
+	updateHandlers map[string][]UpdateHandler
+
+	mu    sync.Mutex
+	stats map[string]*subscriptionStats
+
+	db *ClientDB
+}
+
+// NewConn returns an empty Conn.
+func NewConn() *Conn {
+	return &Conn{
+		insertHandlers: map[string][]InsertHandler{},
+		deleteHandlers: map[string][]DeleteHandler{},
+		updateHandlers: map[string][]UpdateHandler{},
+		stats:          map[string]*subscriptionStats{},
+		db:             NewClientDB(),
+	}
+}
+
+// Db returns c's ClientDB: the local mirror of every table
+// RegisterTableCache has wired up for c, giving callers a single place
+// to look up a subscribed table by name (c.Db().Table("players").Iter())
+// instead of keeping track of each table's *TableCache themselves. It is
+// always non-nil, even before any table has been registered.
+func (c *Conn) Db() *ClientDB {
+	return c.db
+}
+
+// OnInsert implements Client.
+func (c *Conn) OnInsert(table string, fn InsertHandler) {
+	c.insertHandlers[table] = append(c.insertHandlers[table], fn)
+	c.statsFor(table)
+}
+
+// OnDelete implements Client.
+func (c *Conn) OnDelete(table string, fn DeleteHandler) {
+	c.deleteHandlers[table] = append(c.deleteHandlers[table], fn)
+	c.statsFor(table)
+}
+
+// OnUpdate implements Client.
+func (c *Conn) OnUpdate(table string, fn UpdateHandler) {
+	c.updateHandlers[table] = append(c.updateHandlers[table], fn)
+	c.statsFor(table)
+}
+
+// Dispatch invokes every insert handler registered for table with row,
+// and records row to table's SubscriptionStats. It is exported for use
+// by codegen'd wrappers and by tests driving a Conn directly; the real
+// client will call it internally as subscription updates arrive.
+func (c *Conn) Dispatch(table string, row []byte) {
+	c.statsFor(table).recordInsert(len(row))
+	for _, fn := range c.insertHandlers[table] {
+		fn(row)
+	}
+}
+
+// DispatchDelete invokes every delete handler registered for table with
+// row, and records row to table's SubscriptionStats.
+func (c *Conn) DispatchDelete(table string, row []byte) {
+	c.statsFor(table).recordDelete(len(row))
+	for _, fn := range c.deleteHandlers[table] {
+		fn(row)
+	}
+}
+
+// DispatchUpdate invokes every update handler registered for table with
+// row, and records row to table's SubscriptionStats.
+func (c *Conn) DispatchUpdate(table string, row []byte) {
+	c.statsFor(table).recordUpdate(len(row))
+	for _, fn := range c.updateHandlers[table] {
+		fn(row)
+	}
+}
+
+func (c *Conn) statsFor(table string) *subscriptionStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.stats[table]
+	if !ok {
+		s = &subscriptionStats{}
+		c.stats[table] = s
+	}
+	return s
+}
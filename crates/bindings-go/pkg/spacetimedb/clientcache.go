@@ -0,0 +1,264 @@
+package spacetimedb
+
+import (
+	"sync"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// KeyFunc extracts a comparable key from a decoded row (see
+// bsatn.UnmarshalJSON's map[string]any shape, or a codegen'd typed row
+// struct). Values it returns are used as Go map keys, so they must be
+// comparable — a field value or a small struct of them, not a slice.
+type KeyFunc func(row any) any
+
+// TableCache holds the current set of rows a client is subscribed to
+// for one table, keyed by primary key, plus any secondary indexes
+// declared on it via Index. It is maintained incrementally by
+// ApplyInsert/ApplyDelete/ApplyUpdate as subscription diffs arrive (see
+// NewTableCacheFromConn, which wires it to a Conn automatically), so a
+// game server's per-frame lookups never need to scan every cached row.
+type TableCache struct {
+	keyOf KeyFunc
+
+	mu      sync.RWMutex
+	rows    map[any]any
+	indexes map[string]*cacheIndex
+}
+
+type cacheIndex struct {
+	keyOf   KeyFunc
+	entries map[any]map[any]struct{} // secondary key -> set of primary keys
+}
+
+// NewTableCache returns an empty TableCache whose primary key for a row
+// is keyOf(row).
+func NewTableCache(keyOf KeyFunc) *TableCache {
+	return &TableCache{
+		keyOf:   keyOf,
+		rows:    make(map[any]any),
+		indexes: make(map[string]*cacheIndex),
+	}
+}
+
+// Index declares a secondary index under name, keyed by keyOf(row) for
+// every row currently cached and every row ApplyInsert/ApplyUpdate adds
+// afterward. keyOf need not be unique: Lookup returns every row sharing
+// a key. Calling Index again under the same name replaces it.
+func (t *TableCache) Index(name string, keyOf KeyFunc) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	idx := &cacheIndex{keyOf: keyOf, entries: make(map[any]map[any]struct{})}
+	for pk, row := range t.rows {
+		addToIndex(idx, pk, row)
+	}
+	t.indexes[name] = idx
+}
+
+// ApplyInsert adds row to the cache (replacing any existing row under
+// the same primary key) and updates every declared index.
+func (t *TableCache) ApplyInsert(row any) {
+	pk := t.keyOf(row)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if old, exists := t.rows[pk]; exists {
+		t.removeFromIndexes(pk, old)
+	}
+	t.rows[pk] = row
+	t.addToIndexes(pk, row)
+}
+
+// ApplyUpdate replaces the cached row under row's primary key, exactly
+// as ApplyInsert would; SpacetimeDB subscription updates report an
+// update as a full new row value, so there is no partial-field merge to
+// do here.
+func (t *TableCache) ApplyUpdate(row any) {
+	t.ApplyInsert(row)
+}
+
+// ApplyDelete removes row's cached entry (by its primary key) and drops
+// it from every declared index. It is a no-op if the key is not
+// currently cached.
+func (t *TableCache) ApplyDelete(row any) {
+	pk := t.keyOf(row)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	old, exists := t.rows[pk]
+	if !exists {
+		return
+	}
+	delete(t.rows, pk)
+	t.removeFromIndexes(pk, old)
+}
+
+// Get returns the cached row under primary key, if any.
+func (t *TableCache) Get(key any) (any, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	row, ok := t.rows[key]
+	return row, ok
+}
+
+// Lookup returns every cached row whose value under the named index's
+// keyOf equals key, in no particular order. It returns nil if name was
+// never declared via Index.
+func (t *TableCache) Lookup(name string, key any) []any {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	idx, ok := t.indexes[name]
+	if !ok {
+		return nil
+	}
+	pks := idx.entries[key]
+	rows := make([]any, 0, len(pks))
+	for pk := range pks {
+		rows = append(rows, t.rows[pk])
+	}
+	return rows
+}
+
+// Len returns the number of rows currently cached.
+func (t *TableCache) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.rows)
+}
+
+// Rows returns a snapshot of every cached row, in no particular order.
+func (t *TableCache) Rows() []any {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	rows := make([]any, 0, len(t.rows))
+	for _, row := range t.rows {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Iter returns a snapshot of every cached row, in no particular order.
+// It is Rows under the name the Rust and C# SDKs' client caches use for
+// the same operation (table.iter()), for a ClientDB.Table(...) call
+// site to read as a literal translation of those SDKs' examples.
+func (t *TableCache) Iter() []any {
+	return t.Rows()
+}
+
+func (t *TableCache) addToIndexes(pk any, row any) {
+	for _, idx := range t.indexes {
+		addToIndex(idx, pk, row)
+	}
+}
+
+func (t *TableCache) removeFromIndexes(pk any, row any) {
+	for _, idx := range t.indexes {
+		key := idx.keyOf(row)
+		set, ok := idx.entries[key]
+		if !ok {
+			continue
+		}
+		delete(set, pk)
+		if len(set) == 0 {
+			delete(idx.entries, key)
+		}
+	}
+}
+
+func addToIndex(idx *cacheIndex, pk any, row any) {
+	key := idx.keyOf(row)
+	set, ok := idx.entries[key]
+	if !ok {
+		set = make(map[any]struct{})
+		idx.entries[key] = set
+	}
+	set[pk] = struct{}{}
+}
+
+// NewTableCacheFromConn returns a TableCache for table that stays in
+// sync with conn's dispatched inserts, deletes, and updates: each raw
+// BSATN row conn dispatches is decoded against schema (see
+// bsatn.UnmarshalJSON) before being applied, so callers work with the
+// same map[string]any shape Handle.Query already returns. A row that
+// fails to decode is dropped rather than applied, since Conn's handler
+// signature has no way to report the error back to the subscription.
+func NewTableCacheFromConn(conn *Conn, table string, schema bsatn.AlgebraicType, keyOf KeyFunc) *TableCache {
+	cache := NewTableCache(keyOf)
+	decode := func(row []byte) (any, bool) {
+		decoded, err := bsatn.UnmarshalJSON(row, schema)
+		if err != nil {
+			return nil, false
+		}
+		return decoded, true
+	}
+	conn.OnInsert(table, func(row []byte) {
+		if v, ok := decode(row); ok {
+			cache.ApplyInsert(v)
+		}
+	})
+	conn.OnDelete(table, func(row []byte) {
+		if v, ok := decode(row); ok {
+			cache.ApplyDelete(v)
+		}
+	})
+	conn.OnUpdate(table, func(row []byte) {
+		if v, ok := decode(row); ok {
+			cache.ApplyUpdate(v)
+		}
+	})
+	return cache
+}
+
+// RegisterTableCache builds a TableCache for table exactly as
+// NewTableCacheFromConn does, registers it on c.Db() under table, and
+// returns it so the caller can declare secondary indexes (see
+// TableCache.Index) before any rows arrive. This is the one-call setup
+// codegen'd table wrappers use instead of making every consumer wire up
+// NewTableCacheFromConn and a ClientDB registration separately.
+func (c *Conn) RegisterTableCache(table string, schema bsatn.AlgebraicType, keyOf KeyFunc) *TableCache {
+	cache := NewTableCacheFromConn(c, table, schema, keyOf)
+	c.db.Register(table, cache)
+	return cache
+}
+
+// ClientDB is a client's local mirror of every table it has a
+// TableCache for, so callers can look up a subscribed table by name —
+// client.Db().Table("players").Iter() — instead of keeping track of
+// each table's *TableCache themselves, the same role the Rust SDK's
+// client cache plays. Obtain one via Conn.Db(), not directly.
+type ClientDB struct {
+	mu     sync.RWMutex
+	tables map[string]*TableCache
+}
+
+// NewClientDB returns an empty ClientDB.
+func NewClientDB() *ClientDB {
+	return &ClientDB{tables: make(map[string]*TableCache)}
+}
+
+// Register adds cache under table, replacing whatever was registered
+// under that name before. Most callers want Conn.RegisterTableCache
+// instead, which also wires cache up to conn's dispatch.
+func (db *ClientDB) Register(table string, cache *TableCache) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.tables[table] = cache
+}
+
+// Table returns the TableCache registered under table, or nil if none
+// has been — e.g. the caller never subscribed to that table.
+func (db *ClientDB) Table(table string) *TableCache {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.tables[table]
+}
+
+// Tables returns the name of every table currently registered, in no
+// particular order.
+func (db *ClientDB) Tables() []string {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	names := make([]string, 0, len(db.tables))
+	for name := range db.tables {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,160 @@
+package spacetimedb
+
+import (
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func idKey(row any) any {
+	return row.(map[string]any)["id"]
+}
+
+func nameKey(row any) any {
+	return row.(map[string]any)["name"]
+}
+
+func TestTableCacheAppliesInsertsAndDeletes(t *testing.T) {
+	cache := NewTableCache(idKey)
+	cache.ApplyInsert(map[string]any{"id": float64(1), "name": "Ada"})
+	cache.ApplyInsert(map[string]any{"id": float64(2), "name": "Grace"})
+
+	if cache.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", cache.Len())
+	}
+	row, ok := cache.Get(float64(1))
+	if !ok || row.(map[string]any)["name"] != "Ada" {
+		t.Fatalf("Get(1) = %v, %v", row, ok)
+	}
+
+	cache.ApplyDelete(map[string]any{"id": float64(1), "name": "Ada"})
+	if cache.Len() != 1 {
+		t.Fatalf("Len() after delete = %d, want 1", cache.Len())
+	}
+	if _, ok := cache.Get(float64(1)); ok {
+		t.Fatal("Get(1) after delete still present")
+	}
+}
+
+func TestTableCacheApplyUpdateReplacesRow(t *testing.T) {
+	cache := NewTableCache(idKey)
+	cache.ApplyInsert(map[string]any{"id": float64(1), "name": "Ada"})
+	cache.ApplyUpdate(map[string]any{"id": float64(1), "name": "Ada Lovelace"})
+
+	row, ok := cache.Get(float64(1))
+	if !ok || row.(map[string]any)["name"] != "Ada Lovelace" {
+		t.Fatalf("Get(1) after update = %v, %v", row, ok)
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1 (update must not duplicate)", cache.Len())
+	}
+}
+
+func TestTableCacheSecondaryIndexLookup(t *testing.T) {
+	cache := NewTableCache(idKey)
+	cache.Index("byName", nameKey)
+
+	cache.ApplyInsert(map[string]any{"id": float64(1), "name": "Ada"})
+	cache.ApplyInsert(map[string]any{"id": float64(2), "name": "Grace"})
+	cache.ApplyInsert(map[string]any{"id": float64(3), "name": "Ada"})
+
+	rows := cache.Lookup("byName", "Ada")
+	if len(rows) != 2 {
+		t.Fatalf("Lookup(byName, Ada) = %v, want 2 rows", rows)
+	}
+
+	cache.ApplyDelete(map[string]any{"id": float64(1), "name": "Ada"})
+	if rows := cache.Lookup("byName", "Ada"); len(rows) != 1 {
+		t.Fatalf("Lookup(byName, Ada) after delete = %v, want 1 row", rows)
+	}
+}
+
+func TestTableCacheIndexBuiltRetroactively(t *testing.T) {
+	cache := NewTableCache(idKey)
+	cache.ApplyInsert(map[string]any{"id": float64(1), "name": "Ada"})
+	cache.ApplyInsert(map[string]any{"id": float64(2), "name": "Ada"})
+
+	cache.Index("byName", nameKey)
+	if rows := cache.Lookup("byName", "Ada"); len(rows) != 2 {
+		t.Fatalf("Lookup after retroactive Index = %v, want 2 rows", rows)
+	}
+}
+
+func TestTableCacheIndexTrackedThroughUpdate(t *testing.T) {
+	cache := NewTableCache(idKey)
+	cache.Index("byName", nameKey)
+	cache.ApplyInsert(map[string]any{"id": float64(1), "name": "Ada"})
+
+	cache.ApplyUpdate(map[string]any{"id": float64(1), "name": "Grace"})
+
+	if rows := cache.Lookup("byName", "Ada"); len(rows) != 0 {
+		t.Fatalf("Lookup(byName, Ada) after rename = %v, want none", rows)
+	}
+	if rows := cache.Lookup("byName", "Grace"); len(rows) != 1 {
+		t.Fatalf("Lookup(byName, Grace) after rename = %v, want 1", rows)
+	}
+}
+
+func TestNewTableCacheFromConnDecodesDispatchedRows(t *testing.T) {
+	conn := NewConn()
+	cache := NewTableCacheFromConn(conn, "person", personSchema(), idKey)
+
+	row, err := bsatn.MarshalJSON(map[string]any{"id": float64(1), "name": "Ada"}, personSchema())
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	conn.Dispatch("person", row)
+
+	got, ok := cache.Get(float64(1))
+	if !ok || got.(map[string]any)["name"] != "Ada" {
+		t.Fatalf("Get(1) = %v, %v", got, ok)
+	}
+
+	conn.DispatchDelete("person", row)
+	if _, ok := cache.Get(float64(1)); ok {
+		t.Fatal("row still cached after DispatchDelete")
+	}
+}
+
+func TestTableCacheIterMatchesRows(t *testing.T) {
+	cache := NewTableCache(idKey)
+	cache.ApplyInsert(map[string]any{"id": float64(1), "name": "Ada"})
+
+	if len(cache.Iter()) != len(cache.Rows()) {
+		t.Fatalf("Iter() = %v, want the same rows as Rows() = %v", cache.Iter(), cache.Rows())
+	}
+}
+
+func TestConnRegisterTableCacheWiresDbLookupByName(t *testing.T) {
+	conn := NewConn()
+	cache := conn.RegisterTableCache("person", personSchema(), idKey)
+
+	if got := conn.Db().Table("person"); got != cache {
+		t.Fatalf("Db().Table(person) = %v, want the registered cache %v", got, cache)
+	}
+	if conn.Db().Table("no_such_table") != nil {
+		t.Fatal("Table() for an unregistered name should be nil")
+	}
+
+	row, err := bsatn.MarshalJSON(map[string]any{"id": float64(1), "name": "Ada"}, personSchema())
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	conn.Dispatch("person", row)
+
+	rows := conn.Db().Table("person").Iter()
+	if len(rows) != 1 {
+		t.Fatalf("Iter() after dispatch = %v, want 1 row", rows)
+	}
+}
+
+func TestClientDBTablesListsRegisteredNames(t *testing.T) {
+	db := NewClientDB()
+	db.Register("players", NewTableCache(idKey))
+	db.Register("matches", NewTableCache(idKey))
+
+	names := db.Tables()
+	if len(names) != 2 {
+		t.Fatalf("Tables() = %v, want 2 entries", names)
+	}
+}
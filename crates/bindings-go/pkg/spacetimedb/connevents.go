@@ -0,0 +1,163 @@
+package spacetimedb
+
+import (
+	"sync"
+	"time"
+)
+
+// ConnEventKind identifies the kind of connection lifecycle event
+// recorded by a ConnEventLog.
+type ConnEventKind int
+
+const (
+	// ConnEventConnected means the client established a connection to
+	// the module.
+	ConnEventConnected ConnEventKind = iota
+	// ConnEventDisconnected means a previously established connection
+	// was closed, whether cleanly or not; Code and Reason describe why.
+	ConnEventDisconnected
+	// ConnEventReconnecting means the client is about to attempt to
+	// re-establish a connection after a disconnect.
+	ConnEventReconnecting
+	// ConnEventAuthFailed means the server rejected the client's
+	// credentials during connect or reconnect.
+	ConnEventAuthFailed
+)
+
+func (k ConnEventKind) String() string {
+	switch k {
+	case ConnEventConnected:
+		return "connected"
+	case ConnEventDisconnected:
+		return "disconnected"
+	case ConnEventReconnecting:
+		return "reconnecting"
+	case ConnEventAuthFailed:
+		return "auth_failed"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnEvent is a single recorded connection lifecycle event. Fields not
+// meaningful for a given Kind are left zero: Code and Reason are only
+// set for ConnEventDisconnected, Attempt only for ConnEventReconnecting.
+type ConnEvent struct {
+	Kind ConnEventKind
+	Time time.Time
+
+	// Code is the close code reported by the server or transport for a
+	// ConnEventDisconnected event (e.g. a WebSocket close code).
+	Code int
+	// Reason is a human-readable explanation of the event: the close
+	// reason string for a disconnect, or the server's rejection message
+	// for an auth failure.
+	Reason string
+	// Attempt is the 1-based reconnect attempt number for a
+	// ConnEventReconnecting event.
+	Attempt int
+}
+
+// ConnEventLog records a bounded history of connection lifecycle events
+// (connect, disconnect, reconnect attempts, auth failures) so a
+// long-running Go service can diagnose flaky connectivity after the
+// fact without wiring up its own logging around every call site. It is
+// the connection-state complement to ReducerQueue: ReducerQueue holds
+// calls made while offline, ConnEventLog explains why the client went
+// offline in the first place.
+//
+// A zero ConnEventLog is not usable; construct one with NewConnEventLog.
+type ConnEventLog struct {
+	mu     sync.Mutex
+	events []ConnEvent
+	cap    int
+	next   int
+	full   bool
+
+	// clock overrides now() for tests; left nil in production.
+	clock func() time.Time
+
+	// OnEvent, if set, is called synchronously every time an event is
+	// recorded, in addition to it being appended to the ring buffer. It
+	// is called with the log's lock held, so it must not call back into
+	// the log.
+	OnEvent func(ConnEvent)
+}
+
+// NewConnEventLog returns a ConnEventLog retaining at most capacity
+// events, discarding the oldest once full. A capacity of zero or less
+// is treated as 1.
+func NewConnEventLog(capacity int) *ConnEventLog {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &ConnEventLog{events: make([]ConnEvent, capacity), cap: capacity}
+}
+
+// Connected records a ConnEventConnected event.
+func (l *ConnEventLog) Connected() {
+	l.record(ConnEvent{Kind: ConnEventConnected})
+}
+
+// Disconnected records a ConnEventDisconnected event with the given
+// close code and reason.
+func (l *ConnEventLog) Disconnected(code int, reason string) {
+	l.record(ConnEvent{Kind: ConnEventDisconnected, Code: code, Reason: reason})
+}
+
+// Reconnecting records a ConnEventReconnecting event for the given
+// 1-based attempt number.
+func (l *ConnEventLog) Reconnecting(attempt int) {
+	l.record(ConnEvent{Kind: ConnEventReconnecting, Attempt: attempt})
+}
+
+// AuthFailed records a ConnEventAuthFailed event with the server's
+// rejection message.
+func (l *ConnEventLog) AuthFailed(reason string) {
+	l.record(ConnEvent{Kind: ConnEventAuthFailed, Reason: reason})
+}
+
+func (l *ConnEventLog) record(ev ConnEvent) {
+	ev.Time = l.now()
+
+	l.mu.Lock()
+	l.events[l.next] = ev
+	l.next++
+	if l.next == l.cap {
+		l.next = 0
+		l.full = true
+	}
+	onEvent := l.OnEvent
+	l.mu.Unlock()
+
+	if onEvent != nil {
+		onEvent(ev)
+	}
+}
+
+// Events returns the recorded events, oldest first, up to the log's
+// capacity.
+func (l *ConnEventLog) Events() []ConnEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.full {
+		out := make([]ConnEvent, l.next)
+		copy(out, l.events[:l.next])
+		return out
+	}
+
+	out := make([]ConnEvent, l.cap)
+	copy(out, l.events[l.next:])
+	copy(out[l.cap-l.next:], l.events[:l.next])
+	return out
+}
+
+// now is a seam for tests to control time without sleeping; production
+// callers always get the real clock.
+func (l *ConnEventLog) now() time.Time {
+	if l.clock != nil {
+		return l.clock()
+	}
+	return time.Now()
+}
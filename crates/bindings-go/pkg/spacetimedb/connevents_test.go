@@ -0,0 +1,72 @@
+package spacetimedb
+
+import "testing"
+
+func TestConnEventLogRecordsInOrder(t *testing.T) {
+	l := NewConnEventLog(10)
+	l.Connected()
+	l.Disconnected(1006, "abnormal closure")
+	l.Reconnecting(1)
+	l.AuthFailed("invalid token")
+
+	events := l.Events()
+	if len(events) != 4 {
+		t.Fatalf("len(Events()) = %d, want 4", len(events))
+	}
+
+	wantKinds := []ConnEventKind{ConnEventConnected, ConnEventDisconnected, ConnEventReconnecting, ConnEventAuthFailed}
+	for i, want := range wantKinds {
+		if events[i].Kind != want {
+			t.Fatalf("events[%d].Kind = %v, want %v", i, events[i].Kind, want)
+		}
+	}
+
+	if events[1].Code != 1006 || events[1].Reason != "abnormal closure" {
+		t.Fatalf("disconnect event = %+v, want Code 1006 Reason %q", events[1], "abnormal closure")
+	}
+	if events[2].Attempt != 1 {
+		t.Fatalf("reconnect event Attempt = %d, want 1", events[2].Attempt)
+	}
+	if events[3].Reason != "invalid token" {
+		t.Fatalf("auth failure event Reason = %q, want %q", events[3].Reason, "invalid token")
+	}
+}
+
+func TestConnEventLogEvictsOldestPastCapacity(t *testing.T) {
+	l := NewConnEventLog(2)
+	l.Connected()
+	l.Disconnected(1000, "first")
+	l.Disconnected(1001, "second")
+
+	events := l.Events()
+	if len(events) != 2 {
+		t.Fatalf("len(Events()) = %d, want 2", len(events))
+	}
+	if events[0].Reason != "first" || events[1].Reason != "second" {
+		t.Fatalf("events = %+v, want [first second] (oldest Connected evicted)", events)
+	}
+}
+
+func TestConnEventLogCallsOnEvent(t *testing.T) {
+	l := NewConnEventLog(4)
+	var seen []ConnEventKind
+	l.OnEvent = func(ev ConnEvent) { seen = append(seen, ev.Kind) }
+
+	l.Connected()
+	l.Disconnected(1006, "bye")
+
+	if len(seen) != 2 || seen[0] != ConnEventConnected || seen[1] != ConnEventDisconnected {
+		t.Fatalf("seen = %v, want [Connected Disconnected]", seen)
+	}
+}
+
+func TestConnEventLogZeroCapacityTreatedAsOne(t *testing.T) {
+	l := NewConnEventLog(0)
+	l.Connected()
+	l.Disconnected(1000, "only room for one")
+
+	events := l.Events()
+	if len(events) != 1 || events[0].Kind != ConnEventDisconnected {
+		t.Fatalf("events = %+v, want single Disconnected event", events)
+	}
+}
@@ -0,0 +1,28 @@
+package spacetimedb
+
+import "github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+
+// BridgeDatabaseChanges registers a db.ChangeListener on database that
+// dispatches every db.RowChange to conn, so an embedded Handle's local
+// writes reach subscribers the same way a real subscription update
+// would — including the changes an application never issues an explicit
+// per-row delete for: Database.DeleteRange, Database.Truncate, and
+// Database.ExpireRows (TTL expiration) all deliver their removals
+// through the same db.ChangeListener mechanism, batched per call, so
+// this bridge fans every one of them out to conn identically to an
+// explicit Txn.Delete. It returns nothing to unregister with, matching
+// db.OnChange's own "listeners live for the Database's lifetime" shape.
+func BridgeDatabaseChanges(database *db.Database, conn *Conn) {
+	database.OnChange(func(changes db.ChangeSet) {
+		for _, change := range changes {
+			switch change.Op {
+			case db.ChangeInsert:
+				conn.Dispatch(change.Table, change.Value)
+			case db.ChangeUpdate:
+				conn.DispatchUpdate(change.Table, change.Value)
+			case db.ChangeDelete:
+				conn.DispatchDelete(change.Table, change.Value)
+			}
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package spacetimedb
+
+import (
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func TestBridgeDatabaseChangesDispatchesTxnWrites(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	conn := NewConn()
+	BridgeDatabaseChanges(database, conn)
+
+	var inserted, updated, deleted [][]byte
+	conn.OnInsert("widgets", func(row []byte) { inserted = append(inserted, row) })
+	conn.OnUpdate("widgets", func(row []byte) { updated = append(updated, row) })
+	conn.OnDelete("widgets", func(row []byte) { deleted = append(deleted, row) })
+
+	txn := database.Begin()
+	txn.Put("widgets", []byte("w1"), []byte("v1"))
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if len(inserted) != 1 || string(inserted[0]) != "v1" {
+		t.Fatalf("inserted = %v, want [v1]", inserted)
+	}
+
+	txn = database.Begin()
+	txn.Put("widgets", []byte("w1"), []byte("v2"))
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if len(updated) != 1 || string(updated[0]) != "v2" {
+		t.Fatalf("updated = %v, want [v2]", updated)
+	}
+
+	txn = database.Begin()
+	txn.Delete("widgets", []byte("w1"))
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if len(deleted) != 1 || string(deleted[0]) != "v2" {
+		t.Fatalf("deleted = %v, want [v2] (the row's value before removal)", deleted)
+	}
+}
+
+func TestBridgeDatabaseChangesDispatchesTruncateAsDeletes(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	database.Engine.Put("widgets", []byte("w1"), []byte("v1"))
+	database.Engine.Put("widgets", []byte("w2"), []byte("v2"))
+
+	conn := NewConn()
+	BridgeDatabaseChanges(database, conn)
+
+	var deleted [][]byte
+	conn.OnDelete("widgets", func(row []byte) { deleted = append(deleted, row) })
+
+	if err := database.Truncate("widgets"); err != nil {
+		t.Fatalf("Truncate: %v", err)
+	}
+	if len(deleted) != 2 {
+		t.Fatalf("deleted %d rows, want 2", len(deleted))
+	}
+}
+
+func TestBridgeDatabaseChangesDispatchesExpireRowsAsDeletes(t *testing.T) {
+	database := db.NewDatabase(db.NewMemEngine())
+	database.Engine.Put("sessions", []byte("s1"), []byte("expired"))
+	database.Engine.Put("sessions", []byte("s2"), []byte("fresh"))
+
+	conn := NewConn()
+	BridgeDatabaseChanges(database, conn)
+
+	var deleted [][]byte
+	conn.OnDelete("sessions", func(row []byte) { deleted = append(deleted, row) })
+
+	n, err := database.ExpireRows("sessions", func(key, value []byte) bool {
+		return string(value) == "expired"
+	})
+	if err != nil {
+		t.Fatalf("ExpireRows: %v", err)
+	}
+	if n != 1 || len(deleted) != 1 || string(deleted[0]) != "expired" {
+		t.Fatalf("n=%d deleted=%v, want 1 deleted row \"expired\"", n, deleted)
+	}
+}
@@ -0,0 +1,271 @@
+package spacetimedb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/codegen"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/host"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/identity"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/logs"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/migrate"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/wasm"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/query"
+)
+
+// EmbedOption configures Embed. The zero value of every field it can set
+// is a sensible default, so most callers need no options at all.
+type EmbedOption func(*embedConfig)
+
+type embedConfig struct {
+	dbName     string
+	engine     db.Engine
+	wasmConfig wasm.Config
+
+	logLevel  *logs.Level
+	logOutput io.Writer
+}
+
+// WithDatabaseName sets the name the embedded module is registered under
+// (see internal/host.Host.Register). It defaults to wasmPath's base name,
+// which only matters if a Handle ever calls into a second embedded
+// module by that name (see internal/host's inter-module call support).
+func WithDatabaseName(name string) EmbedOption {
+	return func(c *embedConfig) { c.dbName = name }
+}
+
+// WithEngine backs the embedded module's local row storage (see
+// Handle.Query) with engine instead of the default in-memory one.
+func WithEngine(engine db.Engine) EmbedOption {
+	return func(c *embedConfig) { c.engine = engine }
+}
+
+// WithFeature gates an experimental wasm host feature by name, forwarded
+// to wasm.Config.Features.
+func WithFeature(name string, enabled bool) EmbedOption {
+	return func(c *embedConfig) {
+		if c.wasmConfig.Features == nil {
+			c.wasmConfig.Features = make(map[string]bool)
+		}
+		c.wasmConfig.Features[name] = enabled
+	}
+}
+
+// WithMiddleware wraps every host ABI function the embedded module calls
+// (see wasm.Config.Middleware), for e.g. attaching a wasm.FaultInjector.
+func WithMiddleware(mw ...wasm.HostMiddleware) EmbedOption {
+	return func(c *embedConfig) { c.wasmConfig.Middleware = append(c.wasmConfig.Middleware, mw...) }
+}
+
+// WithLogLevel overrides the embedded module's console_log filter level,
+// which otherwise defaults to logs.LevelInfo (see wasm.Runtime.SetLogLevel).
+func WithLogLevel(level logs.Level) EmbedOption {
+	return func(c *embedConfig) { c.logLevel = &level }
+}
+
+// WithLogOutput redirects the embedded module's console_log output,
+// which otherwise defaults to os.Stderr (see wasm.Runtime.SetLogOutput).
+func WithLogOutput(w io.Writer) EmbedOption {
+	return func(c *embedConfig) { c.logOutput = w }
+}
+
+// Handle is a batteries-included facade over a single embedded wasm
+// module, returned by Embed. It bundles the pieces a program that just
+// wants to run a module in-process would otherwise have to wire up by
+// hand: a wasm.Runtime, an internal/host.Host to call reducers through,
+// and an internal/db.Database for local row storage.
+type Handle struct {
+	dbName    string
+	host      *host.Host
+	runtime   *wasm.Runtime
+	db        *db.Database
+	cfg       embedConfig
+	wasmBytes []byte
+
+	health healthCounters
+}
+
+// Embed boots the wasm module at wasmPath in-process, applying opts on
+// top of sensible defaults (an in-memory database, info-level logging to
+// stderr, no experimental features), and returns a Handle for calling
+// its reducers, querying its local rows, and shutting it down.
+func Embed(ctx context.Context, wasmPath string, opts ...EmbedOption) (*Handle, error) {
+	cfg := embedConfig{
+		dbName:     filepath.Base(wasmPath),
+		wasmConfig: wasm.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	wasmBytes, err := os.ReadFile(wasmPath)
+	if err != nil {
+		return nil, fmt.Errorf("spacetimedb: embed %q: %w", wasmPath, err)
+	}
+
+	runtime, err := wasm.NewRuntime(ctx, wasmBytes, cfg.wasmConfig)
+	if err != nil {
+		return nil, fmt.Errorf("spacetimedb: embed %q: start runtime: %w", wasmPath, err)
+	}
+	if cfg.logLevel != nil {
+		runtime.SetLogLevel(*cfg.logLevel)
+	}
+	if cfg.logOutput != nil {
+		runtime.SetLogOutput(cfg.logOutput)
+	}
+
+	engine := cfg.engine
+	if engine == nil {
+		engine = db.NewMemEngine()
+	}
+
+	h := host.NewHost()
+	h.Register(cfg.dbName, runtime)
+
+	handle := &Handle{
+		dbName:    cfg.dbName,
+		host:      h,
+		runtime:   runtime,
+		db:        db.NewDatabase(engine),
+		cfg:       cfg,
+		wasmBytes: wasmBytes,
+	}
+	handle.health.setLoaded(true)
+	return handle, nil
+}
+
+// SchemaHash returns codegen.HashModule's hash of the wasm bytes h is
+// currently running, so a caller (see Reload, and the "dev --watch" CLI
+// loop built on it) can tell whether a rebuild actually changed the
+// module before reporting a schema change.
+func (h *Handle) SchemaHash() string {
+	return codegen.HashModule(h.wasmBytes)
+}
+
+// Reload re-reads the wasm file at wasmPath and, if its bytes differ
+// from the module h is currently running, replaces h's runtime with a
+// freshly started one built from the same EmbedOptions Embed was
+// called with, then closes the old runtime. It reports changed == false
+// (and leaves h untouched) when the rebuilt module is byte-identical to
+// what's already running, so a watch loop that rebuilds on every save
+// doesn't hot-swap (and doesn't re-run seed reducers) when the build
+// output didn't actually change.
+//
+// Reload does not migrate any state the old runtime's guest held in
+// linear memory; only the reducer code changes, and h.Database's rows
+// (Reload never touches them) are what survives the swap.
+func (h *Handle) Reload(ctx context.Context, wasmPath string) (changed bool, err error) {
+	wasmBytes, err := os.ReadFile(wasmPath)
+	if err != nil {
+		return false, fmt.Errorf("spacetimedb: reload %q: %w", wasmPath, err)
+	}
+	if bytes.Equal(wasmBytes, h.wasmBytes) {
+		return false, nil
+	}
+
+	runtime, err := wasm.NewRuntime(ctx, wasmBytes, h.cfg.wasmConfig)
+	if err != nil {
+		return false, fmt.Errorf("spacetimedb: reload %q: start runtime: %w", wasmPath, err)
+	}
+	if h.cfg.logLevel != nil {
+		runtime.SetLogLevel(*h.cfg.logLevel)
+	}
+	if h.cfg.logOutput != nil {
+		runtime.SetLogOutput(h.cfg.logOutput)
+	}
+
+	old := h.runtime
+	h.host.Register(h.dbName, runtime)
+	h.runtime = runtime
+	h.wasmBytes = wasmBytes
+
+	if err := old.Close(ctx); err != nil {
+		return true, fmt.Errorf("spacetimedb: reload %q: close previous runtime: %w", wasmPath, err)
+	}
+	return true, nil
+}
+
+// CallReducer invokes reducerID (named reducerName for profiling and
+// logging, see internal/host.Host.CallReducer) on the embedded module as
+// identity.Zero, forwarding flags unchanged.
+func (h *Handle) CallReducer(ctx context.Context, reducerName string, reducerID uint32, args []byte, flags protocol.CallFlags) error {
+	start := time.Now()
+	err := h.host.CallReducer(ctx, h.dbName, reducerName, reducerID, args, identity.Zero, flags)
+	h.health.record(time.Since(start), err != nil)
+	return err
+}
+
+// Database returns the Handle's local row storage, for registering
+// tables and indexes the way a real host process would (see
+// db.NewDatabase). Query is a convenience over the same Database for the
+// common case of reading rows back out.
+//
+// Nothing yet connects this Database to the embedded module's own
+// datastore host calls (those are still stubs — see internal/wasm's
+// "real datastore" work); until that lands, rows only appear here if the
+// embedding program puts them there itself, e.g. from a reducer's
+// return value or a side channel the module writes to.
+func (h *Handle) Database() *db.Database {
+	return h.db
+}
+
+// Migrations returns a migrate.Runner for applying migrations, in the
+// order given, against h's local database. Repeated calls (e.g. across
+// Reload) are safe: applied migrations are recorded in h's Database, not
+// the Runner, so migrations already applied are skipped again even if
+// the caller rebuilds the list or the Runner itself.
+func (h *Handle) Migrations(migrations ...migrate.Migration) *migrate.Runner {
+	return migrate.NewRunner(h.db, migrations...)
+}
+
+// Query scans table in the Handle's local database, decodes each row per
+// schema, and returns the rows matching plan. Handle has no server to
+// push plan's pushed predicates down to, so both Pushed and Residual are
+// evaluated locally here, unlike a real subscription's Plan.Matches
+// (which only re-checks Residual because the server already applied
+// Pushed).
+func (h *Handle) Query(table string, schema bsatn.AlgebraicType, plan query.Plan) ([]map[string]any, error) {
+	cur, err := h.db.Engine.Scan(table)
+	if err != nil {
+		return nil, fmt.Errorf("spacetimedb: query %q: %w", table, err)
+	}
+
+	var rows []map[string]any
+	for cur.Next() {
+		decoded, err := bsatn.UnmarshalJSON(cur.Value(), schema)
+		if err != nil {
+			cur.Close()
+			return nil, fmt.Errorf("spacetimedb: query %q: decode row: %w", table, err)
+		}
+		row, ok := decoded.(map[string]any)
+		if !ok {
+			cur.Close()
+			return nil, fmt.Errorf("spacetimedb: query %q: schema must describe a product, decoded %T", table, decoded)
+		}
+		if plan.MatchesPushed(row) && plan.Matches(row) {
+			rows = append(rows, row)
+		}
+	}
+	if err := cur.Close(); err != nil {
+		return nil, fmt.Errorf("spacetimedb: query %q: %w", table, err)
+	}
+	return rows, nil
+}
+
+// Shutdown releases the resources Embed acquired: the embedded module's
+// wasm runtime and its local database engine.
+func (h *Handle) Shutdown() error {
+	h.health.setLoaded(false)
+	if err := h.db.Engine.Close(); err != nil {
+		return fmt.Errorf("spacetimedb: shutdown: close database: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package spacetimedb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/query"
+)
+
+func TestEmbedReportsMissingWasmFile(t *testing.T) {
+	_, err := Embed(context.Background(), "/nonexistent/module.wasm")
+	if err == nil {
+		t.Fatal("expected an error for a missing wasm file")
+	}
+}
+
+func personSchema() bsatn.AlgebraicType {
+	id, name := "id", "name"
+	return bsatn.ProductOf(
+		bsatn.ProductElement{Name: &id, Type: bsatn.U32()},
+		bsatn.ProductElement{Name: &name, Type: bsatn.String()},
+	)
+}
+
+func putPerson(t *testing.T, engine db.Engine, key []byte, id float64, name string) {
+	t.Helper()
+	row, err := bsatn.MarshalJSON(map[string]any{"id": id, "name": name}, personSchema())
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	if err := engine.Put("person", key, row); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+}
+
+func TestHandleQueryDecodesMatchingRows(t *testing.T) {
+	engine := db.NewMemEngine()
+	putPerson(t, engine, []byte{1}, 1, "Ada")
+	putPerson(t, engine, []byte{2}, 2, "Grace")
+
+	h := &Handle{db: db.NewDatabase(engine)}
+	plan := query.On("person").Where("id", query.Gte, float64(2)).Compile()
+
+	rows, err := h.Query("person", personSchema(), plan)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(rows) != 1 || rows[0]["name"] != "Grace" {
+		t.Fatalf("rows = %v, want just Grace", rows)
+	}
+}
+
+func TestHandleQueryAppliesResidualFilter(t *testing.T) {
+	engine := db.NewMemEngine()
+	putPerson(t, engine, []byte{1}, 1, "Ada")
+	putPerson(t, engine, []byte{2}, 2, "Grace")
+
+	h := &Handle{db: db.NewDatabase(engine)}
+	plan := query.On("person").WhereFunc(func(row map[string]any) bool {
+		return row["name"] == "Ada"
+	}).Compile()
+
+	rows, err := h.Query("person", personSchema(), plan)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(rows) != 1 || rows[0]["name"] != "Ada" {
+		t.Fatalf("rows = %v, want just Ada", rows)
+	}
+}
+
+func TestHandleShutdownClosesEngine(t *testing.T) {
+	h := &Handle{db: db.NewDatabase(db.NewMemEngine())}
+	if err := h.Shutdown(); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
+
+func TestEmbedOptionsConfigureEmbedConfig(t *testing.T) {
+	var cfg embedConfig
+	WithDatabaseName("custom")(&cfg)
+	WithFeature("wide-time-columns", true)(&cfg)
+
+	if cfg.dbName != "custom" {
+		t.Fatalf("dbName = %q, want custom", cfg.dbName)
+	}
+	if !cfg.wasmConfig.Features["wide-time-columns"] {
+		t.Fatal("WithFeature did not set the feature flag")
+	}
+}
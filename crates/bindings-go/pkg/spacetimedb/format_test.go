@@ -0,0 +1,62 @@
+package spacetimedb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/ws"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func dialTestWSClient(t *testing.T) *WSClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.Upgrade(w, r)
+		if err != nil {
+			t.Errorf("server Upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+		conn.ReadMessage()
+	}))
+	t.Cleanup(srv.Close)
+
+	wsURL := "ws://" + strings.TrimPrefix(srv.URL, "http://")
+	client, err := DialWSClient(wsURL, nil, protocol.V1_2, WSClientHandlers{})
+	if err != nil {
+		t.Fatalf("DialWSClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestWSClientDefaultsToCurrentFormat(t *testing.T) {
+	client := dialTestWSClient(t)
+	if got := client.Format(); got != bsatn.CurrentFormat {
+		t.Fatalf("Format() = %s, want %s", got, bsatn.CurrentFormat)
+	}
+}
+
+func TestWSClientNegotiateFormatPicksSharedFormat(t *testing.T) {
+	client := dialTestWSClient(t)
+	if err := client.NegotiateFormat([]bsatn.Format{bsatn.CurrentFormat}); err != nil {
+		t.Fatalf("NegotiateFormat: %v", err)
+	}
+	if got := client.Format(); got != bsatn.CurrentFormat {
+		t.Fatalf("Format() = %s, want %s", got, bsatn.CurrentFormat)
+	}
+}
+
+func TestWSClientNegotiateFormatErrorsWithNoCommonFormat(t *testing.T) {
+	client := dialTestWSClient(t)
+	err := client.NegotiateFormat([]bsatn.Format{bsatn.Format(99)})
+	if err == nil {
+		t.Fatal("NegotiateFormat: want an error, got nil")
+	}
+	if got := client.Format(); got != bsatn.CurrentFormat {
+		t.Fatalf("Format() after failed negotiation = %s, want unchanged %s", got, bsatn.CurrentFormat)
+	}
+}
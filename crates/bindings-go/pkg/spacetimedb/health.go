@@ -0,0 +1,83 @@
+package spacetimedb
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/health"
+)
+
+// healthCounters tracks the live counters behind Handle.Health: whether
+// the module is currently loaded, and CallReducer's latency/error
+// history since the module was embedded (or last Reloaded).
+type healthCounters struct {
+	mu                 sync.Mutex
+	loaded             bool
+	lastReducerLatency time.Duration
+	calls              uint64
+	errors             uint64
+}
+
+func (c *healthCounters) setLoaded(loaded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loaded = loaded
+}
+
+func (c *healthCounters) record(latency time.Duration, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastReducerLatency = latency
+	c.calls++
+	if failed {
+		c.errors++
+	}
+}
+
+func (c *healthCounters) snapshot() (loaded bool, latency time.Duration, calls, errors uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.loaded, c.lastReducerLatency, c.calls, c.errors
+}
+
+// Health returns a health.Status snapshot of h, suitable for a
+// Kubernetes liveness/readiness probe (see HealthHandler): whether the
+// module is loaded, the latency of its most recently completed
+// CallReducer call, the row count across every table in its local
+// database, and the reducer call/error counts since it was embedded (or
+// last Reload'd).
+func (h *Handle) Health() health.Status {
+	loaded, latency, calls, errs := h.health.snapshot()
+	return health.Status{
+		ModuleLoaded:       loaded,
+		LastReducerLatency: latency,
+		DatastoreRows:      h.datastoreRows(),
+		ReducerCalls:       calls,
+		ReducerErrors:      errs,
+	}
+}
+
+// datastoreRows sums the row count across every table registered on h's
+// local database.
+func (h *Handle) datastoreRows() int {
+	total := 0
+	for _, info := range h.db.Tables() {
+		cur, err := h.db.Engine.Scan(info.Name)
+		if err != nil {
+			continue
+		}
+		for cur.Next() {
+			total++
+		}
+		cur.Close()
+	}
+	return total
+}
+
+// HealthHandler returns an http.Handler an embedding program can mount
+// (e.g. at /healthz) for Kubernetes liveness/readiness probes; see
+// health.Handler.
+func (h *Handle) HealthHandler() http.Handler {
+	return health.Handler(h)
+}
@@ -0,0 +1,70 @@
+package spacetimedb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func TestHandleHealthReportsUnloadedByDefault(t *testing.T) {
+	h := &Handle{db: db.NewDatabase(db.NewMemEngine())}
+
+	status := h.Health()
+	if status.ModuleLoaded {
+		t.Fatal("ModuleLoaded = true, want false before setLoaded(true)")
+	}
+	if status.Ready() {
+		t.Fatal("Ready() = true, want false")
+	}
+}
+
+func TestHandleHealthCountsCallsAndErrors(t *testing.T) {
+	h := &Handle{db: db.NewDatabase(db.NewMemEngine())}
+	h.health.setLoaded(true)
+	h.health.record(10*time.Millisecond, false)
+	h.health.record(20*time.Millisecond, true)
+
+	status := h.Health()
+	if !status.Ready() {
+		t.Fatal("Ready() = false, want true")
+	}
+	if status.ReducerCalls != 2 || status.ReducerErrors != 1 {
+		t.Fatalf("status = %+v, want 2 calls, 1 error", status)
+	}
+	if status.LastReducerLatency != 20*time.Millisecond {
+		t.Fatalf("LastReducerLatency = %v, want 20ms", status.LastReducerLatency)
+	}
+}
+
+func TestHandleHealthCountsDatastoreRows(t *testing.T) {
+	engine := db.NewMemEngine()
+	putPerson(t, engine, []byte{1}, 1, "Ada")
+	putPerson(t, engine, []byte{2}, 2, "Bob")
+	database := db.NewDatabase(engine)
+	database.RegisterTable(db.TableInfo{Name: "person"})
+	h := &Handle{db: database}
+
+	if got := h.Health().DatastoreRows; got != 2 {
+		t.Fatalf("DatastoreRows = %d, want 2", got)
+	}
+}
+
+func TestHandleHealthHandlerReflectsReadiness(t *testing.T) {
+	h := &Handle{db: db.NewDatabase(db.NewMemEngine())}
+
+	rec := httptest.NewRecorder()
+	h.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 before load", rec.Code)
+	}
+
+	h.health.setLoaded(true)
+	rec = httptest.NewRecorder()
+	h.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 after load", rec.Code)
+	}
+}
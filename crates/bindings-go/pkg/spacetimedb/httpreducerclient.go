@@ -0,0 +1,125 @@
+package spacetimedb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/httpclient"
+)
+
+// HTTPReducerClient calls reducers over HTTP (POST
+// /v1/database/<name>/call/<reducer>) instead of over a live WSClient
+// connection — the right shape for a batch job that calls reducers one
+// at a time and does not want to hold a WebSocket open between calls.
+//
+// Unlike WSClient.CallReducer, a call made through an at-least-once
+// delivery system (the batch job's own process restarting and re-running
+// a call it is unsure completed, a caller's own retry of a timed-out
+// HTTP request) risks applying a reducer twice. CallReducer accepts an
+// idempotency key for exactly this case: it tags the HTTP request with
+// httpclient.IdempotencyKeyHeader so the underlying httpclient.Client (if
+// configured with MaxRetries) safely retries a transient failure instead
+// of refusing to touch a non-idempotent POST, and it caches the outcome
+// of a previously completed key in memory so calling CallReducer again
+// with the same key — even from a different goroutine — returns the
+// cached outcome instead of calling the reducer a second time. That
+// cache is local to this *HTTPReducerClient and does not survive the
+// process restarting and rebuilding the client; surviving a restart
+// relies on the server's own idempotency-key handling behind
+// httpclient.IdempotencyKeyHeader, not on this cache. The cache also
+// never evicts, so a long-lived client called with many distinct keys
+// grows completed without bound; a caller minting a fresh key per logical
+// operation over a long-lived process should plan for that.
+type HTTPReducerClient struct {
+	doer interface {
+		Do(req *http.Request) (*http.Response, error)
+	}
+	server   string
+	database string
+	token    string
+
+	mu        sync.Mutex
+	completed map[string]error
+}
+
+// NewHTTPReducerClient returns an HTTPReducerClient that calls reducers
+// on database at server, authenticating the same way WSClient and
+// SQLClient do: a bearer token obtained from identity.LoadToken or an
+// anonymous login. cfg controls the underlying transport's retry
+// behavior (see httpclient.Config.MaxRetries); pass httpclient.NewConfig()
+// with MaxRetries set for CallReducer's idempotency keys to have
+// anything to protect.
+func NewHTTPReducerClient(server, database, token string, cfg httpclient.Config) *HTTPReducerClient {
+	return &HTTPReducerClient{
+		doer:      httpclient.New(cfg),
+		server:    server,
+		database:  database,
+		token:     token,
+		completed: make(map[string]error),
+	}
+}
+
+// CallReducer invokes reducer on c's database, BSATN-free over this
+// transport: args is sent as its JSON encoding, matching the HTTP call
+// API's request body (unlike WSClient.CallReducer, which BSATN-encodes
+// each argument for the binary WebSocket protocol). idempotencyKey, if
+// non-empty, is both sent as an httpclient.IdempotencyKeyHeader (letting
+// the transport retry a transient failure) and used to deduplicate on
+// the caller's side: a second CallReducer call with the same key returns
+// the first call's outcome without sending another request at all. Pass
+// an empty idempotencyKey to opt out of both and call exactly once,
+// matching this type's behavior before either existed.
+func (c *HTTPReducerClient) CallReducer(ctx context.Context, reducer, idempotencyKey string, args any) error {
+	if idempotencyKey != "" {
+		c.mu.Lock()
+		cached, ok := c.completed[idempotencyKey]
+		c.mu.Unlock()
+		if ok {
+			return cached
+		}
+	}
+
+	err := c.callReducer(ctx, reducer, idempotencyKey, args)
+
+	if idempotencyKey != "" {
+		c.mu.Lock()
+		c.completed[idempotencyKey] = err
+		c.mu.Unlock()
+	}
+	return err
+}
+
+func (c *HTTPReducerClient) callReducer(ctx context.Context, reducer, idempotencyKey string, args any) error {
+	body, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("spacetimedb: call reducer %s: encode args: %w", reducer, err)
+	}
+
+	url := fmt.Sprintf("%s/v1/database/%s/call/%s", c.server, c.database, reducer)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("spacetimedb: call reducer %s: build request: %w", reducer, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	if idempotencyKey != "" {
+		req.Header.Set(httpclient.IdempotencyKeyHeader, idempotencyKey)
+	}
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return fmt.Errorf("spacetimedb: call reducer %s: %w", reducer, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("spacetimedb: call reducer %s: server returned %s: %s", reducer, resp.Status, respBody)
+	}
+	return nil
+}
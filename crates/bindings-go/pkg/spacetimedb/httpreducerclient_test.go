@@ -0,0 +1,108 @@
+package spacetimedb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/httpclient"
+)
+
+func TestHTTPReducerClientCallReducerSendsJSONArgs(t *testing.T) {
+	var gotArgs []any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &gotArgs)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewHTTPReducerClient(srv.URL, "mydb", "tok", httpclient.NewConfig())
+	if err := client.CallReducer(context.Background(), "add_player", "", []any{"alice", 5}); err != nil {
+		t.Fatalf("CallReducer: %v", err)
+	}
+	if len(gotArgs) != 2 || gotArgs[0] != "alice" {
+		t.Fatalf("gotArgs = %v, want [alice 5]", gotArgs)
+	}
+}
+
+func TestHTTPReducerClientRetriesTransientFailureWithIdempotencyKey(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := httpclient.NewConfig()
+	cfg.MaxRetries = 3
+	client := NewHTTPReducerClient(srv.URL, "mydb", "tok", cfg)
+
+	if err := client.CallReducer(context.Background(), "add_player", "key-1", []any{"alice"}); err != nil {
+		t.Fatalf("CallReducer: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("server saw %d calls, want 2 (one failure, one retry)", calls)
+	}
+}
+
+func TestHTTPReducerClientDeduplicatesRepeatedIdempotencyKey(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewHTTPReducerClient(srv.URL, "mydb", "tok", httpclient.NewConfig())
+
+	if err := client.CallReducer(context.Background(), "add_player", "key-1", []any{"alice"}); err != nil {
+		t.Fatalf("CallReducer (1st): %v", err)
+	}
+	if err := client.CallReducer(context.Background(), "add_player", "key-1", []any{"alice"}); err != nil {
+		t.Fatalf("CallReducer (2nd, same key): %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("server saw %d calls, want exactly 1 (2nd call should have hit the dedup cache)", calls)
+	}
+}
+
+func TestHTTPReducerClientWithoutIdempotencyKeyCallsEveryTime(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewHTTPReducerClient(srv.URL, "mydb", "tok", httpclient.NewConfig())
+
+	client.CallReducer(context.Background(), "add_player", "", []any{"alice"})
+	client.CallReducer(context.Background(), "add_player", "", []any{"alice"})
+
+	if calls != 2 {
+		t.Fatalf("server saw %d calls, want 2 (no idempotency key means no dedup)", calls)
+	}
+}
+
+func TestHTTPReducerClientReturnsServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("bad args"))
+	}))
+	defer srv.Close()
+
+	client := NewHTTPReducerClient(srv.URL, "mydb", "tok", httpclient.NewConfig())
+	err := client.CallReducer(context.Background(), "add_player", "", []any{"alice"})
+	if err == nil {
+		t.Fatal("CallReducer: want an error for a 400 response")
+	}
+}
@@ -0,0 +1,162 @@
+package spacetimedb
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+// subscriptionStats is the mutable counter set Conn keeps per table;
+// SubscriptionStats is the immutable snapshot callers actually see.
+type subscriptionStats struct {
+	mu sync.Mutex
+
+	rowsInserted uint64
+	rowsDeleted  uint64
+	rowsUpdated  uint64
+
+	uncompressedBytes uint64
+	compressedBytes   uint64
+
+	latencySamples uint64
+	totalLatency   time.Duration
+}
+
+func (s *subscriptionStats) recordInsert(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rowsInserted++
+	s.uncompressedBytes += uint64(n)
+}
+
+func (s *subscriptionStats) recordDelete(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rowsDeleted++
+	s.uncompressedBytes += uint64(n)
+}
+
+func (s *subscriptionStats) recordUpdate(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rowsUpdated++
+	s.uncompressedBytes += uint64(n)
+}
+
+func (s *subscriptionStats) recordCompressedBytes(n uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compressedBytes += n
+}
+
+func (s *subscriptionStats) recordLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencySamples++
+	s.totalLatency += d
+}
+
+func (s *subscriptionStats) snapshot() SubscriptionStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SubscriptionStats{
+		RowsInserted:      s.rowsInserted,
+		RowsDeleted:       s.rowsDeleted,
+		RowsUpdated:       s.rowsUpdated,
+		UncompressedBytes: s.uncompressedBytes,
+		CompressedBytes:   s.compressedBytes,
+		latencySamples:    s.latencySamples,
+		totalLatency:      s.totalLatency,
+	}
+}
+
+// SubscriptionStats is a point-in-time snapshot of the row churn and
+// bandwidth counters Conn tracks for one table, i.e. one subscription
+// in the current table-granularity Client model. Unlike the internal
+// counters it is copied from, it holds no lock and is safe to copy,
+// store, and read directly.
+type SubscriptionStats struct {
+	RowsInserted uint64
+	RowsDeleted  uint64
+	RowsUpdated  uint64
+
+	// UncompressedBytes is the total size of every row payload Conn has
+	// dispatched for this table.
+	UncompressedBytes uint64
+	// CompressedBytes is the wire size reported via Conn.RecordCompressedBytes;
+	// it stays zero unless the transport reports it, since an in-process
+	// Conn has no wire encoding of its own.
+	CompressedBytes uint64
+
+	latencySamples uint64
+	totalLatency   time.Duration
+}
+
+// AverageLatency returns the mean of every latency sample recorded via
+// Conn.RecordLatency for this subscription, or zero if none have been
+// recorded.
+func (s SubscriptionStats) AverageLatency() time.Duration {
+	if s.latencySamples == 0 {
+		return 0
+	}
+	return s.totalLatency / time.Duration(s.latencySamples)
+}
+
+// RecordCompressedBytes adds n to table's CompressedBytes counter. The
+// in-process Conn has no wire compression of its own; this exists for a
+// network-connected client to report the compressed frame size it
+// actually received for table's update.
+func (c *Conn) RecordCompressedBytes(table string, n uint64) {
+	c.statsFor(table).recordCompressedBytes(n)
+}
+
+// RecordLatency adds d as a server-to-client latency sample for table,
+// e.g. the delta between a reducer's server-side commit timestamp and
+// the time its resulting row update was dispatched locally.
+func (c *Conn) RecordLatency(table string, d time.Duration) {
+	c.statsFor(table).recordLatency(d)
+}
+
+// Stats returns a snapshot of table's SubscriptionStats. A table with
+// no registered handler and no dispatched rows reports a zero value.
+func (c *Conn) Stats(table string) SubscriptionStats {
+	return c.statsFor(table).snapshot()
+}
+
+// AllStats returns a snapshot of every table Conn has stats for, keyed
+// by table name.
+func (c *Conn) AllStats() map[string]SubscriptionStats {
+	c.mu.Lock()
+	tables := make([]string, 0, len(c.stats))
+	for t := range c.stats {
+		tables = append(tables, t)
+	}
+	c.mu.Unlock()
+
+	out := make(map[string]SubscriptionStats, len(tables))
+	for _, t := range tables {
+		out[t] = c.Stats(t)
+	}
+	return out
+}
+
+// PublishExpvar registers an expvar.Map named name (see expvar.Publish)
+// with one entry per table Conn currently has stats for, each an
+// expvar.Func returning that table's live SubscriptionStats snapshot,
+// so operators can inspect per-subscription row churn and bandwidth via
+// /debug/vars without polling Conn directly. Like expvar.Publish, it
+// panics if name is already registered. Tables Conn first sees after
+// this call (a fresh OnInsert/OnDelete/OnUpdate or Dispatch* for a name
+// not seen before) are not retroactively added; call PublishExpvar again
+// under a different name, or register handlers for every table of
+// interest before calling it, to avoid missing one.
+func (c *Conn) PublishExpvar(name string) *expvar.Map {
+	m := expvar.NewMap(name)
+	for table := range c.AllStats() {
+		table := table
+		m.Set(table, expvar.Func(func() any {
+			return c.Stats(table)
+		}))
+	}
+	return m
+}
@@ -0,0 +1,71 @@
+package spacetimedb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestConnDispatchTracksRowChurnAndBytes(t *testing.T) {
+	c := NewConn()
+	c.OnInsert("players", func(row []byte) {})
+	c.OnDelete("players", func(row []byte) {})
+	c.OnUpdate("players", func(row []byte) {})
+
+	c.Dispatch("players", []byte("abcd"))
+	c.DispatchDelete("players", []byte("ab"))
+	c.DispatchUpdate("players", []byte("abcdef"))
+
+	stats := c.Stats("players")
+	if stats.RowsInserted != 1 || stats.RowsDeleted != 1 || stats.RowsUpdated != 1 {
+		t.Fatalf("stats = %+v, want 1 of each", stats)
+	}
+	if stats.UncompressedBytes != 4+2+6 {
+		t.Fatalf("UncompressedBytes = %d, want 12", stats.UncompressedBytes)
+	}
+}
+
+func TestConnRecordLatencyAverages(t *testing.T) {
+	c := NewConn()
+	c.RecordLatency("players", 10*time.Millisecond)
+	c.RecordLatency("players", 30*time.Millisecond)
+
+	got := c.Stats("players").AverageLatency()
+	if got != 20*time.Millisecond {
+		t.Fatalf("AverageLatency = %v, want 20ms", got)
+	}
+}
+
+func TestConnAllStatsIncludesEveryRegisteredTable(t *testing.T) {
+	c := NewConn()
+	c.OnInsert("players", func(row []byte) {})
+	c.OnInsert("messages", func(row []byte) {})
+
+	all := c.AllStats()
+	if _, ok := all["players"]; !ok {
+		t.Fatal("missing players in AllStats")
+	}
+	if _, ok := all["messages"]; !ok {
+		t.Fatal("missing messages in AllStats")
+	}
+}
+
+func TestConnPublishExpvarReportsLiveStats(t *testing.T) {
+	c := NewConn()
+	c.OnInsert("players", func(row []byte) {})
+	m := c.PublishExpvar("test-conn-stats")
+
+	c.Dispatch("players", []byte("abcd"))
+
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(m.String()), &got); err != nil {
+		t.Fatalf("json.Unmarshal(m.String()): %v", err)
+	}
+	var stats SubscriptionStats
+	if err := json.Unmarshal(got["players"], &stats); err != nil {
+		t.Fatalf("json.Unmarshal(players stats): %v", err)
+	}
+	if stats.RowsInserted != 1 {
+		t.Fatalf("RowsInserted = %d, want 1 (live snapshot after PublishExpvar)", stats.RowsInserted)
+	}
+}
@@ -0,0 +1,101 @@
+package spacetimedb
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/ws"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// CallReducer invokes the reducer named name on the connected module,
+// BSATN-encoding args (see encodeReducerArgs) and sending them as a
+// CallReducerRequest. It blocks until the server reports the call's
+// outcome via the TransactionUpdate carrying this call's RequestID, or
+// ctx is done first, and returns an error if the reducer failed or ran
+// out of energy.
+//
+// Generated typed reducer wrappers should call CallReducer the same way
+// hand-written code does, passing each already-typed parameter as one of
+// args; CallReducer does not need to know a reducer's declared parameter
+// types ahead of time.
+func (c *WSClient) CallReducer(ctx context.Context, name string, args ...any) error {
+	encoded, err := encodeReducerArgs(args...)
+	if err != nil {
+		return fmt.Errorf("spacetimedb: call reducer %s: encode args: %w", name, err)
+	}
+
+	reqID := uint32(atomic.AddUint64(&c.nextRequestID, 1))
+	wait := make(chan protocol.TransactionUpdate, 1)
+	c.pendingMu.Lock()
+	c.pending[reqID] = wait
+	c.pendingMu.Unlock()
+	defer func() {
+		c.pendingMu.Lock()
+		delete(c.pending, reqID)
+		c.pendingMu.Unlock()
+	}()
+
+	data, err := encodeWireMessage(protocol.KindCallReducer, protocol.CallReducerRequest{
+		RequestID:   reqID,
+		ReducerName: name,
+		Args:        encoded,
+	})
+	if err != nil {
+		return fmt.Errorf("spacetimedb: call reducer %s: encode request: %w", name, err)
+	}
+	if err := c.conn.WriteMessage(ws.BinaryMessage, data); err != nil {
+		return fmt.Errorf("spacetimedb: call reducer %s: send request: %w", name, err)
+	}
+
+	select {
+	case tx := <-wait:
+		if tx.Status != protocol.ReducerStatusCommitted {
+			return fmt.Errorf("spacetimedb: reducer %s: %s", name, tx.Status)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliverPending hands tx to the CallReducer call waiting on its
+// RequestID, if any is still waiting; a RequestID of zero, or one no
+// longer in c.pending (already delivered, or its CallReducer call gave
+// up on ctx), is not an error — most TransactionUpdates are broadcasts
+// for calls other connections made.
+func (c *WSClient) deliverPending(tx protocol.TransactionUpdate) {
+	c.pendingMu.Lock()
+	wait, ok := c.pending[tx.RequestID]
+	c.pendingMu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case wait <- tx:
+	default:
+	}
+}
+
+// encodeReducerArgs BSATN-encodes each of args individually, then packs
+// the results into a single buffer with one more bsatn.Marshal call over
+// the resulting [][]byte. This reuses the same nested-slice shape
+// SubscriptionUpdate.Inserts already round-trips rather than inventing a
+// bespoke argument-tuple wire format, at the cost of each argument
+// carrying its own redundant length prefix instead of the server's real
+// reducer-specific tuple encoding; a typed codegen'd wrapper that knows
+// a reducer's declared parameter types ahead of time can do better by
+// marshaling its own concrete args struct directly.
+func encodeReducerArgs(args ...any) ([]byte, error) {
+	parts := make([][]byte, len(args))
+	for i, arg := range args {
+		encoded, err := bsatn.Marshal(arg)
+		if err != nil {
+			return nil, fmt.Errorf("arg %d: %w", i, err)
+		}
+		parts[i] = encoded
+	}
+	return bsatn.Marshal(parts)
+}
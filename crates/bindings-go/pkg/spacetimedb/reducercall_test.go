@@ -0,0 +1,179 @@
+package spacetimedb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/ws"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func TestCallReducerSendsRequestAndWaitsForMatchingTransactionUpdate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.Upgrade(w, r)
+		if err != nil {
+			t.Errorf("server Upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Errorf("server ReadMessage: %v", err)
+			return
+		}
+		msg, err := decodeWireMessage(data)
+		if err != nil {
+			t.Errorf("decodeWireMessage: %v", err)
+			return
+		}
+		var req protocol.CallReducerRequest
+		if err := bsatn.Unmarshal(msg.payload, &req); err != nil {
+			t.Errorf("decode CallReducerRequest: %v", err)
+			return
+		}
+		if req.ReducerName != "score_point" {
+			t.Errorf("ReducerName = %q, want score_point", req.ReducerName)
+		}
+
+		var args [][]byte
+		if err := bsatn.Unmarshal(req.Args, &args); err != nil {
+			t.Errorf("decode args: %v", err)
+			return
+		}
+		if len(args) != 2 {
+			t.Fatalf("got %d args, want 2", len(args))
+		}
+		var player string
+		var points int32
+		if err := bsatn.Unmarshal(args[0], &player); err != nil {
+			t.Errorf("decode arg 0: %v", err)
+		}
+		if err := bsatn.Unmarshal(args[1], &points); err != nil {
+			t.Errorf("decode arg 1: %v", err)
+		}
+		if player != "alice" || points != 5 {
+			t.Errorf("args = (%q, %d), want (alice, 5)", player, points)
+		}
+
+		out, err := encodeWireMessage(protocol.KindTransactionUpdate, protocol.TransactionUpdate{
+			RequestID:   req.RequestID,
+			ReducerName: req.ReducerName,
+			Status:      protocol.ReducerStatusCommitted,
+		})
+		if err != nil {
+			t.Errorf("encodeWireMessage: %v", err)
+			return
+		}
+		if err := conn.WriteMessage(ws.BinaryMessage, out); err != nil {
+			t.Errorf("server WriteMessage: %v", err)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	wsURL := "ws://" + strings.TrimPrefix(srv.URL, "http://")
+	client, err := DialWSClient(wsURL, nil, protocol.V1_2, WSClientHandlers{})
+	if err != nil {
+		t.Fatalf("DialWSClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.CallReducer(ctx, "score_point", "alice", int32(5)); err != nil {
+		t.Fatalf("CallReducer: %v", err)
+	}
+}
+
+func TestCallReducerReturnsErrorOnFailedStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.Upgrade(w, r)
+		if err != nil {
+			t.Errorf("server Upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Errorf("server ReadMessage: %v", err)
+			return
+		}
+		msg, err := decodeWireMessage(data)
+		if err != nil {
+			t.Errorf("decodeWireMessage: %v", err)
+			return
+		}
+		var req protocol.CallReducerRequest
+		if err := bsatn.Unmarshal(msg.payload, &req); err != nil {
+			t.Errorf("decode CallReducerRequest: %v", err)
+			return
+		}
+
+		out, err := encodeWireMessage(protocol.KindTransactionUpdate, protocol.TransactionUpdate{
+			RequestID:   req.RequestID,
+			ReducerName: req.ReducerName,
+			Status:      protocol.ReducerStatusFailed,
+		})
+		if err != nil {
+			t.Errorf("encodeWireMessage: %v", err)
+			return
+		}
+		if err := conn.WriteMessage(ws.BinaryMessage, out); err != nil {
+			t.Errorf("server WriteMessage: %v", err)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	wsURL := "ws://" + strings.TrimPrefix(srv.URL, "http://")
+	client, err := DialWSClient(wsURL, nil, protocol.V1_2, WSClientHandlers{})
+	if err != nil {
+		t.Fatalf("DialWSClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.CallReducer(ctx, "score_point", "alice"); err == nil {
+		t.Fatal("expected CallReducer to return an error for a failed reducer status")
+	}
+}
+
+func TestCallReducerTimesOutIfNoMatchingUpdateArrives(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.Upgrade(w, r)
+		if err != nil {
+			t.Errorf("server Upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+		// Drain the request but never reply.
+		if _, _, err := conn.ReadMessage(); err != nil {
+			t.Errorf("server ReadMessage: %v", err)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	wsURL := "ws://" + strings.TrimPrefix(srv.URL, "http://")
+	client, err := DialWSClient(wsURL, nil, protocol.V1_2, WSClientHandlers{})
+	if err != nil {
+		t.Fatalf("DialWSClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.CallReducer(ctx, "score_point"); err == nil {
+		t.Fatal("expected CallReducer to return an error when ctx is done before a reply arrives")
+	}
+}
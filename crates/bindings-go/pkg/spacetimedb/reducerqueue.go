@@ -0,0 +1,209 @@
+package spacetimedb
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+)
+
+// ErrQueueFull is returned by ReducerQueue.Enqueue when adding a call
+// would exceed the queue's configured bounds and DropOldest is false.
+var ErrQueueFull = errors.New("spacetimedb: reducer queue full")
+
+// DropReason identifies why a queued reducer call was removed from a
+// ReducerQueue without being flushed, passed to OnDrop.
+type DropReason int
+
+const (
+	// DropExpired means the call's TTL elapsed before it could be
+	// flushed.
+	DropExpired DropReason = iota
+	// DropEvicted means the call was evicted to make room under
+	// QueueBounds with DropOldest set.
+	DropEvicted
+)
+
+func (r DropReason) String() string {
+	switch r {
+	case DropExpired:
+		return "expired"
+	case DropEvicted:
+		return "evicted"
+	default:
+		return "unknown"
+	}
+}
+
+// ReducerCall is a single reducer invocation queued while disconnected.
+// Args is the BSATN-encoded argument tuple, opaque to ReducerQueue.
+type ReducerCall struct {
+	Name string
+	Args []byte
+
+	// Flags carries the call's protocol-level options, such as
+	// suppressing the success notification a server would otherwise
+	// broadcast (protocol.CallFlagNoSuccessNotify). It rides along
+	// opaquely, same as Args: ReducerQueue neither reads nor validates
+	// it, only replays it back to send on Flush.
+	Flags protocol.CallFlags
+
+	// TTL is how long the call may sit in the queue before it is
+	// dropped as stale; zero means it never expires on its own.
+	TTL time.Duration
+
+	enqueuedAt time.Time
+}
+
+// QueueBounds caps how much a ReducerQueue will hold before it either
+// rejects new calls or evicts old ones, whichever DropOldest selects.
+// A zero value in either field means that dimension is unbounded.
+type QueueBounds struct {
+	MaxCalls int
+	MaxBytes int
+
+	// DropOldest, when true, makes Enqueue make room for a new call by
+	// evicting the oldest queued calls (reporting each via OnDrop)
+	// instead of returning ErrQueueFull.
+	DropOldest bool
+}
+
+// ReducerQueue buffers reducer calls made while a client is disconnected
+// from a module, so they can be replayed in order once Flush is called
+// on reconnect. It is the offline-mode complement to Conn's row dispatch:
+// Conn delivers table updates the server sends, ReducerQueue holds calls
+// the client wants to send but currently can't.
+type ReducerQueue struct {
+	mu     sync.Mutex
+	bounds QueueBounds
+	calls  []ReducerCall
+	bytes  int
+
+	// clock overrides now() for tests; left nil in production.
+	clock func() time.Time
+
+	// OnDrop, if set, is called for every queued call removed without
+	// being flushed, with the reason it was dropped. It is called with
+	// the queue's lock held, so it must not call back into the queue.
+	OnDrop func(ReducerCall, DropReason)
+}
+
+// NewReducerQueue returns an empty ReducerQueue enforcing bounds.
+func NewReducerQueue(bounds QueueBounds) *ReducerQueue {
+	return &ReducerQueue{bounds: bounds}
+}
+
+// Enqueue appends call to the queue, first purging any calls whose TTL
+// has elapsed. It fails with ErrQueueFull if the queue is at its bounds
+// and bounds.DropOldest is false; otherwise it evicts the oldest queued
+// calls (reporting each via OnDrop with DropEvicted) until call fits.
+func (q *ReducerQueue) Enqueue(call ReducerCall) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	call.enqueuedAt = q.now()
+	q.purgeExpiredLocked()
+
+	for q.wouldExceedLocked(len(call.Args)) {
+		if !q.bounds.DropOldest || len(q.calls) == 0 {
+			return ErrQueueFull
+		}
+		q.evictOldestLocked(DropEvicted)
+	}
+
+	q.calls = append(q.calls, call)
+	q.bytes += len(call.Args)
+	return nil
+}
+
+// Len returns the number of calls currently queued, after purging any
+// that have expired.
+func (q *ReducerQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.purgeExpiredLocked()
+	return len(q.calls)
+}
+
+// Flush replays every queued call, oldest first, by passing it to send.
+// It stops and leaves the remaining calls queued (send's call and
+// everything after it are not removed) the first time send returns an
+// error, so a reconnect that drops mid-flush can retry from where it
+// left off. Expired calls are purged, and reported via OnDrop, before
+// send ever sees them.
+func (q *ReducerQueue) Flush(send func(ReducerCall) error) error {
+	q.mu.Lock()
+	q.purgeExpiredLocked()
+	pending := make([]ReducerCall, len(q.calls))
+	copy(pending, q.calls)
+	q.mu.Unlock()
+
+	for i, call := range pending {
+		if err := send(call); err != nil {
+			q.mu.Lock()
+			q.calls = q.calls[i:]
+			q.recomputeBytesLocked()
+			q.mu.Unlock()
+			return err
+		}
+	}
+
+	q.mu.Lock()
+	q.calls = nil
+	q.bytes = 0
+	q.mu.Unlock()
+	return nil
+}
+
+func (q *ReducerQueue) wouldExceedLocked(addBytes int) bool {
+	if q.bounds.MaxCalls != 0 && len(q.calls) >= q.bounds.MaxCalls {
+		return true
+	}
+	if q.bounds.MaxBytes != 0 && q.bytes+addBytes > q.bounds.MaxBytes {
+		return true
+	}
+	return false
+}
+
+func (q *ReducerQueue) evictOldestLocked(reason DropReason) {
+	dropped := q.calls[0]
+	q.calls = q.calls[1:]
+	q.bytes -= len(dropped.Args)
+	if q.OnDrop != nil {
+		q.OnDrop(dropped, reason)
+	}
+}
+
+func (q *ReducerQueue) purgeExpiredLocked() {
+	now := q.now()
+	live := q.calls[:0]
+	for _, call := range q.calls {
+		if call.TTL != 0 && now.Sub(call.enqueuedAt) >= call.TTL {
+			if q.OnDrop != nil {
+				q.OnDrop(call, DropExpired)
+			}
+			continue
+		}
+		live = append(live, call)
+	}
+	q.calls = live
+	q.recomputeBytesLocked()
+}
+
+func (q *ReducerQueue) recomputeBytesLocked() {
+	bytes := 0
+	for _, call := range q.calls {
+		bytes += len(call.Args)
+	}
+	q.bytes = bytes
+}
+
+// now is a seam for tests to control time without sleeping; production
+// callers always get the real clock.
+func (q *ReducerQueue) now() time.Time {
+	if q.clock != nil {
+		return q.clock()
+	}
+	return time.Now()
+}
@@ -0,0 +1,135 @@
+package spacetimedb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+)
+
+func TestReducerQueueFlushesInOrder(t *testing.T) {
+	q := NewReducerQueue(QueueBounds{})
+	if err := q.Enqueue(ReducerCall{Name: "a"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := q.Enqueue(ReducerCall{Name: "b"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	var sent []string
+	if err := q.Flush(func(c ReducerCall) error {
+		sent = append(sent, c.Name)
+		return nil
+	}); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(sent) != 2 || sent[0] != "a" || sent[1] != "b" {
+		t.Fatalf("sent = %v, want [a b]", sent)
+	}
+	if q.Len() != 0 {
+		t.Fatalf("Len after flush = %d, want 0", q.Len())
+	}
+}
+
+func TestReducerQueueFlushStopsOnErrorAndRetainsRemainder(t *testing.T) {
+	q := NewReducerQueue(QueueBounds{})
+	q.Enqueue(ReducerCall{Name: "a"})
+	q.Enqueue(ReducerCall{Name: "b"})
+
+	boom := errors.New("disconnected mid-flush")
+	var sent []string
+	err := q.Flush(func(c ReducerCall) error {
+		sent = append(sent, c.Name)
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("Flush err = %v, want %v", err, boom)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("sent = %v, want 1 call attempted", sent)
+	}
+	if q.Len() != 2 {
+		t.Fatalf("Len after failed flush = %d, want 2 (nothing consumed)", q.Len())
+	}
+}
+
+func TestReducerQueueRejectsWhenFullWithoutDropOldest(t *testing.T) {
+	q := NewReducerQueue(QueueBounds{MaxCalls: 1})
+	if err := q.Enqueue(ReducerCall{Name: "a"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := q.Enqueue(ReducerCall{Name: "b"}); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Enqueue over bound = %v, want ErrQueueFull", err)
+	}
+}
+
+func TestReducerQueueDropOldestEvicts(t *testing.T) {
+	var dropped []DropReason
+	q := NewReducerQueue(QueueBounds{MaxCalls: 1, DropOldest: true})
+	q.OnDrop = func(c ReducerCall, reason DropReason) { dropped = append(dropped, reason) }
+
+	q.Enqueue(ReducerCall{Name: "a"})
+	if err := q.Enqueue(ReducerCall{Name: "b"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if len(dropped) != 1 || dropped[0] != DropEvicted {
+		t.Fatalf("dropped = %v, want [DropEvicted]", dropped)
+	}
+
+	var sent []string
+	q.Flush(func(c ReducerCall) error {
+		sent = append(sent, c.Name)
+		return nil
+	})
+	if len(sent) != 1 || sent[0] != "b" {
+		t.Fatalf("sent = %v, want [b] (a was evicted)", sent)
+	}
+}
+
+func TestReducerQueueExpiresByTTL(t *testing.T) {
+	now := time.Unix(0, 0)
+	q := NewReducerQueue(QueueBounds{})
+	q.clock = func() time.Time { return now }
+
+	var dropped []DropReason
+	q.OnDrop = func(c ReducerCall, reason DropReason) { dropped = append(dropped, reason) }
+
+	q.Enqueue(ReducerCall{Name: "a", TTL: time.Second})
+	now = now.Add(2 * time.Second)
+
+	if got := q.Len(); got != 0 {
+		t.Fatalf("Len after TTL elapsed = %d, want 0", got)
+	}
+	if len(dropped) != 1 || dropped[0] != DropExpired {
+		t.Fatalf("dropped = %v, want [DropExpired]", dropped)
+	}
+}
+
+func TestReducerQueueMaxBytes(t *testing.T) {
+	q := NewReducerQueue(QueueBounds{MaxBytes: 4})
+	if err := q.Enqueue(ReducerCall{Name: "a", Args: []byte("abcd")}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := q.Enqueue(ReducerCall{Name: "b", Args: []byte("x")}); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Enqueue over byte bound = %v, want ErrQueueFull", err)
+	}
+}
+
+func TestReducerQueueFlushPreservesFlags(t *testing.T) {
+	q := NewReducerQueue(QueueBounds{})
+	if err := q.Enqueue(ReducerCall{Name: "a", Flags: protocol.CallFlagNoSuccessNotify}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	var got protocol.CallFlags
+	if err := q.Flush(func(c ReducerCall) error {
+		got = c.Flags
+		return nil
+	}); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if !got.NoSuccessNotify() {
+		t.Fatalf("Flags = %v, want NoSuccessNotify set", got)
+	}
+}
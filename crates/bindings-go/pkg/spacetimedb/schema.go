@@ -0,0 +1,47 @@
+package spacetimedb
+
+import "fmt"
+
+// SchemaVersion pins the module schema hash a set of generated bindings
+// were produced from (see internal/codegen.GenerateSchemaVersion).
+type SchemaVersion struct {
+	Hash string
+}
+
+// SchemaMismatchError is returned when a Go client's generated bindings
+// were built against a different module schema than the one it
+// connected to. Decoding a row with the wrong AlgebraicType silently
+// misreads its bytes rather than failing, so this is surfaced as an
+// error the caller must act on (or explicitly override) instead of a
+// warning.
+type SchemaMismatchError struct {
+	Expected string
+	Got      string
+}
+
+func (e *SchemaMismatchError) Error() string {
+	return fmt.Sprintf("spacetimedb: schema mismatch: bindings built for %q, server reports %q", e.Expected, e.Got)
+}
+
+// CheckSchema compares got — the schema hash a server reports on
+// connect — against want, the hash generated bindings were built from.
+// It returns a *SchemaMismatchError unless the hashes match or
+// allowMismatch is true; allowMismatch exists for callers that
+// knowingly want to connect against a schema their bindings predate,
+// e.g. while a rolling deploy is in progress.
+func CheckSchema(want SchemaVersion, got string, allowMismatch bool) error {
+	if allowMismatch || want.Hash == got {
+		return nil
+	}
+	return &SchemaMismatchError{Expected: want.Hash, Got: got}
+}
+
+// VerifySchema is CheckSchema against the SchemaVersion the caller's
+// generated bindings were built with, called on Conn so a test or the
+// eventual network-connected client can pin it to a connection's
+// lifetime. It has no effect on Conn's dispatch behavior; it exists to
+// give callers one place to run the check when they establish a
+// connection.
+func (c *Conn) VerifySchema(want SchemaVersion, got string, allowMismatch bool) error {
+	return CheckSchema(want, got, allowMismatch)
+}
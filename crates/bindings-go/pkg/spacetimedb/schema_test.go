@@ -0,0 +1,42 @@
+package spacetimedb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckSchemaMatch(t *testing.T) {
+	if err := CheckSchema(SchemaVersion{Hash: "abc"}, "abc", false); err != nil {
+		t.Fatalf("CheckSchema: %v", err)
+	}
+}
+
+func TestCheckSchemaMismatch(t *testing.T) {
+	err := CheckSchema(SchemaVersion{Hash: "abc"}, "def", false)
+	var mismatch *SchemaMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("error is not *SchemaMismatchError: %v", err)
+	}
+	if mismatch.Expected != "abc" || mismatch.Got != "def" {
+		t.Fatalf("mismatch = %+v, want Expected=abc Got=def", mismatch)
+	}
+	if got := err.Error(); got == "" {
+		t.Fatal("Error() returned empty string")
+	}
+}
+
+func TestCheckSchemaAllowMismatch(t *testing.T) {
+	if err := CheckSchema(SchemaVersion{Hash: "abc"}, "def", true); err != nil {
+		t.Fatalf("CheckSchema with allowMismatch: %v", err)
+	}
+}
+
+func TestConnVerifySchemaDelegates(t *testing.T) {
+	c := NewConn()
+	if err := c.VerifySchema(SchemaVersion{Hash: "abc"}, "abc", false); err != nil {
+		t.Fatalf("VerifySchema: %v", err)
+	}
+	if err := c.VerifySchema(SchemaVersion{Hash: "abc"}, "def", false); err == nil {
+		t.Fatal("expected mismatch error")
+	}
+}
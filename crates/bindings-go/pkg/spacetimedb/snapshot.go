@@ -0,0 +1,126 @@
+package spacetimedb
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultSnapshotBatchSize bounds how many rows SliceSnapshotSource puts
+// in a single SnapshotBatch when the caller doesn't specify one.
+const DefaultSnapshotBatchSize = 256
+
+// SnapshotBatch is one bounded chunk of an initial subscription
+// snapshot: some of Table's rows, still BSATN-encoded, and whether
+// SnapshotSource has more batches (for this table or another) to
+// deliver after this one.
+type SnapshotBatch struct {
+	Table string
+	Rows  [][]byte
+	More  bool
+}
+
+// SnapshotSource produces a subscription's initial snapshot in bounded
+// batches, so StreamSnapshot never has to hold more than one batch in
+// memory at a time. A real network-connected client backs this with
+// however its wire protocol chunks a large initial snapshot; tests and
+// an embedded Handle's local database back it with SliceSnapshotSource.
+type SnapshotSource interface {
+	// Next returns the next batch, blocking if necessary until it is
+	// available (a network-backed source would block on a read here).
+	// It returns ok == false once every batch has been delivered.
+	Next(ctx context.Context) (batch SnapshotBatch, ok bool, err error)
+}
+
+// StreamSnapshotOptions configures StreamSnapshot.
+type StreamSnapshotOptions struct {
+	// OnApplied, if set, is called once after every batch source
+	// produces has been dispatched — mirroring a real SpacetimeDB
+	// client's subscription OnApplied callback, which fires once after
+	// the whole initial snapshot is applied, not once per batch.
+	OnApplied func()
+	// OnBatchAcked, if set, is called after each batch is dispatched
+	// and before the next call to source.Next, so a caller backed by a
+	// network source can send a flow-control acknowledgement pacing how
+	// fast the server sends the next batch, instead of the server
+	// blasting an entire huge snapshot at a client that can't keep up.
+	OnBatchAcked func(batch SnapshotBatch)
+}
+
+// StreamSnapshot pulls batches from source and dispatches each row to
+// conn via Conn.Dispatch, one bounded batch at a time, instead of
+// requiring the whole snapshot decoded and buffered in memory before
+// the first OnInsert callback fires. This matters for a module whose
+// initial subscription snapshot is large enough that materializing it
+// all at once risks exhausting memory on a constrained client, e.g. a
+// dedicated game server; StreamSnapshot's peak memory is bounded by one
+// batch, not the whole snapshot, regardless of how source itself
+// produces those batches.
+//
+// StreamSnapshot returns (without calling OnApplied) as soon as ctx is
+// done or source.Next returns an error, so a caller can distinguish a
+// snapshot that streamed to completion from one that didn't.
+func StreamSnapshot(ctx context.Context, conn *Conn, source SnapshotSource, opts StreamSnapshotOptions) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch, ok, err := source.Next(ctx)
+		if err != nil {
+			return fmt.Errorf("spacetimedb: stream snapshot: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		for _, row := range batch.Rows {
+			conn.Dispatch(batch.Table, row)
+		}
+		if opts.OnBatchAcked != nil {
+			opts.OnBatchAcked(batch)
+		}
+		if !batch.More {
+			break
+		}
+	}
+	if opts.OnApplied != nil {
+		opts.OnApplied()
+	}
+	return nil
+}
+
+// SliceSnapshotSource adapts an in-memory table's rows (already fully
+// available, e.g. from an embedded Handle's local database) to
+// SnapshotSource, splitting them into batches of at most BatchSize
+// rows. Rows are not copied; a caller mutating them concurrently with
+// StreamSnapshot races.
+type SliceSnapshotSource struct {
+	Table     string
+	Rows      [][]byte
+	BatchSize int
+
+	pos int
+}
+
+// NewSliceSnapshotSource returns a SliceSnapshotSource over rows,
+// batched by batchSize (or DefaultSnapshotBatchSize if batchSize <= 0).
+func NewSliceSnapshotSource(table string, rows [][]byte, batchSize int) *SliceSnapshotSource {
+	if batchSize <= 0 {
+		batchSize = DefaultSnapshotBatchSize
+	}
+	return &SliceSnapshotSource{Table: table, Rows: rows, BatchSize: batchSize}
+}
+
+// Next implements SnapshotSource.
+func (s *SliceSnapshotSource) Next(ctx context.Context) (SnapshotBatch, bool, error) {
+	if s.pos >= len(s.Rows) {
+		return SnapshotBatch{}, false, nil
+	}
+	end := s.pos + s.BatchSize
+	if end > len(s.Rows) {
+		end = len(s.Rows)
+	}
+	batch := SnapshotBatch{Table: s.Table, Rows: s.Rows[s.pos:end], More: end < len(s.Rows)}
+	s.pos = end
+	return batch, true, nil
+}
@@ -0,0 +1,88 @@
+package spacetimedb
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStreamSnapshotDispatchesEveryRowInBatches(t *testing.T) {
+	rows := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d"), []byte("e")}
+	source := NewSliceSnapshotSource("widgets", rows, 2)
+
+	conn := NewConn()
+	var received [][]byte
+	conn.OnInsert("widgets", func(row []byte) { received = append(received, row) })
+
+	var acked []int
+	var applied bool
+	err := StreamSnapshot(context.Background(), conn, source, StreamSnapshotOptions{
+		OnBatchAcked: func(batch SnapshotBatch) { acked = append(acked, len(batch.Rows)) },
+		OnApplied:    func() { applied = true },
+	})
+	if err != nil {
+		t.Fatalf("StreamSnapshot: %v", err)
+	}
+	if len(received) != 5 {
+		t.Fatalf("received %d rows, want 5", len(received))
+	}
+	if got := len(acked); got != 3 {
+		t.Fatalf("acked %d batches, want 3 (2+2+1)", got)
+	}
+	if acked[0] != 2 || acked[2] != 1 {
+		t.Fatalf("acked sizes = %v, want [2 2 1]", acked)
+	}
+	if !applied {
+		t.Fatal("OnApplied was not called")
+	}
+}
+
+func TestStreamSnapshotOnAppliedFiresOnceAfterAllBatches(t *testing.T) {
+	rows := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	source := NewSliceSnapshotSource("widgets", rows, 1)
+
+	conn := NewConn()
+	var appliedCount int
+	var receivedAtApplied int
+	conn.OnInsert("widgets", func(row []byte) {})
+
+	err := StreamSnapshot(context.Background(), conn, source, StreamSnapshotOptions{
+		OnApplied: func() {
+			appliedCount++
+			receivedAtApplied = int(conn.statsFor("widgets").rowsInserted)
+		},
+	})
+	if err != nil {
+		t.Fatalf("StreamSnapshot: %v", err)
+	}
+	if appliedCount != 1 {
+		t.Fatalf("OnApplied called %d times, want 1", appliedCount)
+	}
+	if receivedAtApplied != 3 {
+		t.Fatalf("rows dispatched by the time OnApplied fired = %d, want 3", receivedAtApplied)
+	}
+}
+
+func TestStreamSnapshotStopsOnCanceledContext(t *testing.T) {
+	rows := [][]byte{[]byte("a"), []byte("b")}
+	source := NewSliceSnapshotSource("widgets", rows, 1)
+	conn := NewConn()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var applied bool
+	err := StreamSnapshot(ctx, conn, source, StreamSnapshotOptions{OnApplied: func() { applied = true }})
+	if err == nil {
+		t.Fatal("StreamSnapshot: want error for canceled context")
+	}
+	if applied {
+		t.Fatal("OnApplied should not fire when canceled before completion")
+	}
+}
+
+func TestNewSliceSnapshotSourceDefaultsBatchSize(t *testing.T) {
+	source := NewSliceSnapshotSource("widgets", nil, 0)
+	if source.BatchSize != DefaultSnapshotBatchSize {
+		t.Fatalf("BatchSize = %d, want %d", source.BatchSize, DefaultSnapshotBatchSize)
+	}
+}
@@ -0,0 +1,96 @@
+package spacetimedb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/httpclient"
+)
+
+// SQLClient runs one-off SQL queries against a module's SQL API (POST
+// /v1/database/<name>/sql), the same endpoint the `spacetimedb sql` CLI
+// command uses (see cmd/spacetimedb/sql.go's remoteSQLRunner). It is
+// independent of WSClient's live subscription connection: a Query call
+// is a single request/response round trip, not a streaming
+// subscription, so an application does not need an open WebSocket just
+// to run an ad hoc query.
+type SQLClient struct {
+	doer interface {
+		Do(req *http.Request) (*http.Response, error)
+	}
+	server   string
+	database string
+	token    string
+}
+
+// NewSQLClient returns a SQLClient that runs queries against database on
+// server, authenticating the same way WSClient does: a bearer token
+// obtained from identity.LoadToken or an anonymous login.
+func NewSQLClient(server, database, token string) *SQLClient {
+	return &SQLClient{doer: httpclient.New(httpclient.NewConfig()), server: server, database: database, token: token}
+}
+
+// Rows is the decoded result of a Query call: one map[string]any per
+// row, keyed by column name, in the order the server returned them.
+type Rows []map[string]any
+
+// Query runs sql against c's database and returns the decoded rows. Use
+// Rows.Decode to unmarshal them into a caller-supplied struct slice
+// instead of working with the raw maps.
+func (c *SQLClient) Query(ctx context.Context, sql string) (Rows, error) {
+	url := fmt.Sprintf("%s/v1/database/%s/sql", c.server, c.database)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBufferString(sql))
+	if err != nil {
+		return nil, fmt.Errorf("spacetimedb: query: build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "text/plain")
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("spacetimedb: query: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("spacetimedb: query: read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("spacetimedb: query: server returned %s: %s", resp.Status, body)
+	}
+
+	var rows []map[string]any
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("spacetimedb: query: parse response: %w", err)
+	}
+	return Rows(rows), nil
+}
+
+// Decode unmarshals r into the slice out points to (out must be a
+// non-nil pointer to a slice, e.g. *[]Player), matching each row's keys
+// against the destination struct's fields using encoding/json's own
+// struct tag and name-matching rules. It round-trips through JSON
+// rather than decoding BSATN directly, since Query's rows already
+// arrived as JSON off the SQL API's HTTP response; a caller wanting
+// BSATN-typed decoding of subscribed rows should use generated table
+// wrappers instead, the way WSClient's subscriptions do.
+func (r Rows) Decode(out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("spacetimedb: Decode: out must be a non-nil pointer to a slice, got %T", out)
+	}
+	data, err := json.Marshal([]map[string]any(r))
+	if err != nil {
+		return fmt.Errorf("spacetimedb: Decode: marshal rows: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("spacetimedb: Decode: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package spacetimedb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSQLClientQueryDecodesRows(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/database/mydb/sql" {
+			t.Errorf("path = %q, want /v1/database/mydb/sql", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q, want Bearer tok", got)
+		}
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"name": "alice", "score": 42},
+			{"name": "bob", "score": 7},
+		})
+	}))
+	defer srv.Close()
+
+	c := NewSQLClient(srv.URL, "mydb", "tok")
+	rows, err := c.Query(context.Background(), "SELECT * FROM players WHERE score > 0")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("len(rows) = %d, want 2", len(rows))
+	}
+	if rows[0]["name"] != "alice" {
+		t.Errorf("rows[0][name] = %v, want alice", rows[0]["name"])
+	}
+}
+
+func TestSQLClientQueryReturnsServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("sql error: no such table"))
+	}))
+	defer srv.Close()
+
+	c := NewSQLClient(srv.URL, "mydb", "tok")
+	if _, err := c.Query(context.Background(), "SELECT * FROM nope"); err == nil {
+		t.Fatal("expected an error for a non-200 response")
+	}
+}
+
+type player struct {
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+func TestRowsDecodeIntoStructSlice(t *testing.T) {
+	rows := Rows{
+		{"name": "alice", "score": float64(42)},
+		{"name": "bob", "score": float64(7)},
+	}
+
+	var players []player
+	if err := rows.Decode(&players); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := []player{{"alice", 42}, {"bob", 7}}
+	if len(players) != len(want) || players[0] != want[0] || players[1] != want[1] {
+		t.Fatalf("players = %+v, want %+v", players, want)
+	}
+}
+
+func TestRowsDecodeRejectsNonSlicePointer(t *testing.T) {
+	rows := Rows{{"name": "alice"}}
+	var dest player
+	if err := rows.Decode(&dest); err == nil {
+		t.Fatal("expected an error decoding into a non-slice pointer")
+	}
+}
@@ -0,0 +1,203 @@
+package spacetimedb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// DefaultSSEHeartbeat is how often SSEHandler sends a keep-alive comment
+// on an idle connection when its Heartbeat field is left zero.
+const DefaultSSEHeartbeat = 15 * time.Second
+
+// defaultSSEBacklog bounds how many past events SSEHandler retains for
+// replay to a client reconnecting with a Last-Event-ID it missed.
+const defaultSSEBacklog = 256
+
+// sseEvent is one row change turned into a JSON payload, tagged with a
+// monotonically increasing ID for Last-Event-ID resume.
+type sseEvent struct {
+	id   uint64
+	kind string
+	data []byte
+}
+
+// SSEHandler bridges a table subscription on a Conn to Server-Sent
+// Events, for a browser dashboard or other quick internal tool that
+// wants row updates as JSON without standing up a websocket client.
+// Every row it re-emits is decoded against schema (see
+// bsatn.UnmarshalJSON) before being marshaled to JSON.
+//
+// A client that reconnects with a Last-Event-ID header replays whatever
+// of SSEHandler's bounded backlog it missed before resuming live
+// updates; an ID older than the backlog's oldest entry cannot be
+// recovered and the client silently starts from the current position,
+// the same gap-handling tradeoff SSE's own spec leaves to servers.
+type SSEHandler struct {
+	table  string
+	schema bsatn.AlgebraicType
+
+	// Heartbeat is how often an idle connection gets a keep-alive
+	// comment, so intermediate proxies don't time it out. Zero means
+	// DefaultSSEHeartbeat.
+	Heartbeat time.Duration
+
+	mu          sync.Mutex
+	nextID      uint64
+	backlog     []sseEvent
+	backlogCap  int
+	subscribers map[chan sseEvent]struct{}
+}
+
+// NewSSEHandler returns an SSEHandler that re-emits every insert,
+// delete, and update conn dispatches for table, decoded against schema.
+// It registers its own handlers on conn immediately; conn must not
+// already be closed or discarded.
+func NewSSEHandler(conn *Conn, table string, schema bsatn.AlgebraicType) *SSEHandler {
+	h := &SSEHandler{
+		table:       table,
+		schema:      schema,
+		backlogCap:  defaultSSEBacklog,
+		subscribers: map[chan sseEvent]struct{}{},
+	}
+	conn.OnInsert(table, func(row []byte) { h.emit("insert", row) })
+	conn.OnDelete(table, func(row []byte) { h.emit("delete", row) })
+	conn.OnUpdate(table, func(row []byte) { h.emit("update", row) })
+	return h
+}
+
+func (h *SSEHandler) emit(kind string, row []byte) {
+	value, err := bsatn.UnmarshalJSON(row, h.schema)
+	if err != nil {
+		// A row this handler's own Conn just decoded from the wire
+		// failing to re-decode against schema means schema doesn't
+		// match the table's actual type; there is no row-level way to
+		// report that to a browser client, so drop it rather than send
+		// bad JSON.
+		return
+	}
+	data, err := json.Marshal(map[string]any{"kind": kind, "table": h.table, "row": value})
+	if err != nil {
+		return
+	}
+
+	h.mu.Lock()
+	h.nextID++
+	evt := sseEvent{id: h.nextID, kind: kind, data: data}
+	h.backlog = append(h.backlog, evt)
+	if len(h.backlog) > h.backlogCap {
+		h.backlog = h.backlog[len(h.backlog)-h.backlogCap:]
+	}
+	for ch := range h.subscribers {
+		select {
+		case ch <- evt:
+		default:
+			// A slow subscriber that can't keep up loses this event; it
+			// will pick it back up from the backlog on next reconnect,
+			// same as any other gap.
+		}
+	}
+	h.mu.Unlock()
+}
+
+func (h *SSEHandler) subscribe() chan sseEvent {
+	ch := make(chan sseEvent, 64)
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *SSEHandler) unsubscribe(ch chan sseEvent) {
+	h.mu.Lock()
+	delete(h.subscribers, ch)
+	h.mu.Unlock()
+}
+
+// backlogAfter returns every retained event with id > lastID, in order.
+func (h *SSEHandler) backlogAfter(lastID uint64) []sseEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var out []sseEvent
+	for _, evt := range h.backlog {
+		if evt.id > lastID {
+			out = append(out, evt)
+		}
+	}
+	return out
+}
+
+// ServeHTTP streams table's row events to r as an SSE response,
+// replaying any backlogged events after the Last-Event-ID header (if
+// present) before continuing live, and writing a heartbeat comment on
+// idle connections. It runs until the client disconnects or the
+// request's context is cancelled.
+func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if lastID, ok := parseLastEventID(r.Header.Get("Last-Event-ID")); ok {
+		for _, evt := range h.backlogAfter(lastID) {
+			if !writeSSEEvent(w, evt) {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+
+	ch := h.subscribe()
+	defer h.unsubscribe(ch)
+
+	heartbeat := h.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = DefaultSSEHeartbeat
+	}
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case evt := <-ch:
+			if !writeSSEEvent(w, evt) {
+				return
+			}
+			flusher.Flush()
+		case <-ticker.C:
+			if _, err := w.Write([]byte(": heartbeat\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, evt sseEvent) bool {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", evt.id, evt.kind, evt.data)
+	return err == nil
+}
+
+func parseLastEventID(header string) (uint64, bool) {
+	if header == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(header, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
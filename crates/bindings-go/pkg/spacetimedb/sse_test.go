@@ -0,0 +1,123 @@
+package spacetimedb
+
+import (
+	"bufio"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func playerSchema() bsatn.AlgebraicType {
+	name := "Name"
+	return bsatn.ProductOf(bsatn.ProductElement{Name: &name, Type: bsatn.String()})
+}
+
+func encodePlayer(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := bsatn.MarshalJSON(map[string]any{"Name": name}, playerSchema())
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	return data
+}
+
+func TestSSEHandlerStreamsInsertAsEvent(t *testing.T) {
+	conn := NewConn()
+	h := NewSSEHandler(conn, "players", playerSchema())
+	h.Heartbeat = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/subscribe/players", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(rec, req)
+		close(done)
+	}()
+
+	// Give ServeHTTP a moment to subscribe before dispatching.
+	time.Sleep(20 * time.Millisecond)
+	conn.Dispatch("players", encodePlayer(t, "Ada"))
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "event: insert") {
+		t.Fatalf("body missing insert event: %q", body)
+	}
+	if !strings.Contains(body, `"Name":"Ada"`) {
+		t.Fatalf("body missing decoded row: %q", body)
+	}
+	if !strings.Contains(body, "id: 1") {
+		t.Fatalf("body missing event id: %q", body)
+	}
+}
+
+func TestSSEHandlerReplaysBacklogAfterLastEventID(t *testing.T) {
+	conn := NewConn()
+	h := NewSSEHandler(conn, "players", playerSchema())
+	h.Heartbeat = time.Hour
+
+	conn.Dispatch("players", encodePlayer(t, "Ada"))
+	conn.Dispatch("players", encodePlayer(t, "Bob"))
+	conn.Dispatch("players", encodePlayer(t, "Cid"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/subscribe/players", nil).WithContext(ctx)
+	req.Header.Set("Last-Event-ID", "1")
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(rec, req)
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+
+	body := rec.Body.String()
+	if strings.Contains(body, "Ada") {
+		t.Fatalf("body replayed already-seen event: %q", body)
+	}
+	if !strings.Contains(body, "Bob") || !strings.Contains(body, "Cid") {
+		t.Fatalf("body missing backlog replay: %q", body)
+	}
+}
+
+func TestSSEHandlerSendsHeartbeat(t *testing.T) {
+	conn := NewConn()
+	h := NewSSEHandler(conn, "players", playerSchema())
+	h.Heartbeat = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/subscribe/players", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(rec, req)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	scanner := bufio.NewScanner(strings.NewReader(rec.Body.String()))
+	found := false
+	for scanner.Scan() {
+		if scanner.Text() == ": heartbeat" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("body missing heartbeat comment: %q", rec.Body.String())
+	}
+}
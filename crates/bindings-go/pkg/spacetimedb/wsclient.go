@@ -0,0 +1,299 @@
+package spacetimedb
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/ws"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// wireMessage prefixes every message WSClient sends or receives with
+// one byte identifying its protocol.MessageKind, followed by that
+// kind's BSATN-encoded payload. protocol.DecodeEnvelope decodes a
+// payload once its kind is already known; this one-byte tag is how
+// WSClient learns the kind of each message as it arrives on a single
+// shared WebSocket stream.
+type wireMessage struct {
+	kind    protocol.MessageKind
+	payload []byte
+}
+
+func encodeWireMessage(kind protocol.MessageKind, v any) ([]byte, error) {
+	payload, err := bsatn.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("spacetimedb: marshal %s payload: %w", kind, err)
+	}
+	return append([]byte{byte(kind)}, payload...), nil
+}
+
+func decodeWireMessage(data []byte) (wireMessage, error) {
+	if len(data) < 1 {
+		return wireMessage{}, fmt.Errorf("spacetimedb: empty websocket message")
+	}
+	return wireMessage{kind: protocol.MessageKind(data[0]), payload: data[1:]}, nil
+}
+
+// SubscribeRequest is the payload a SubscribeMulti call sends to ask
+// the server to start streaming matching rows for every query in
+// QueryStrings, matching the Rust and C# SDKs' subscribe-multi message.
+type SubscribeRequest struct {
+	QueryStrings []string
+}
+
+// WSClient is a Client that maintains a live WebSocket connection to a
+// SpacetimeDB module: it performs Subscribe/SubscribeMulti, decodes
+// InitialSubscription and incremental TransactionUpdate messages off
+// the wire, and dispatches their row changes to handlers registered the
+// same way as on Conn (which WSClient embeds and dispatches through).
+type WSClient struct {
+	*Conn
+
+	conn    *ws.Conn
+	version protocol.ServerVersion
+
+	// format is the BSATN format this connection encodes and expects to
+	// decode. It starts at bsatn.CurrentFormat, the same way version
+	// starts at whatever the caller passed to DialWSClient; call
+	// NegotiateFormat once a real handshake can tell this client which
+	// formats the server supports, the same way a future handshake
+	// reader would derive version instead of a caller hardcoding it.
+	format bsatn.Format
+
+	// handlers holds the callbacks passed to DialWSClient. They are
+	// fixed at construction, before readLoop starts (see DialWSClient),
+	// so handleMessage can read them without synchronization: nothing
+	// ever writes to this field again after Dial returns.
+	handlers WSClientHandlers
+
+	closeOnce sync.Once
+	closed    atomic.Bool
+
+	// nextRequestID hands out the RequestID every CallReducer call tags
+	// its CallReducerRequest with, starting at 1 so a zero RequestID on
+	// an incoming TransactionUpdate unambiguously means "not correlated
+	// with any call this connection is waiting on" (see CallReducer).
+	nextRequestID uint64
+
+	pendingMu sync.Mutex
+	pending   map[uint32]chan protocol.TransactionUpdate
+
+	// subsMu guards activeQueries, the set of query strings this
+	// connection currently believes it is subscribed to (see
+	// Subscriptions). Subscribe/SubscribeMulti add to it once the
+	// request is sent; Unsubscribe/UnsubscribeMulti remove from it.
+	subsMu        sync.Mutex
+	activeQueries map[string]struct{}
+}
+
+// WSClientHandlers holds the callbacks a WSClient dispatches to as
+// messages arrive. They are passed to DialWSClient and wired up before
+// it starts reading from the connection, so a message that arrives
+// immediately after the handshake — very plausible for IdentityToken,
+// which servers send right on connect — is never raced against a
+// caller assigning handlers after Dial returns. A nil field is simply
+// not called, the same as leaving it unset.
+type WSClientHandlers struct {
+	// OnIdentity, if set, is called once with the identity and auth
+	// token the server assigns this connection.
+	OnIdentity func(protocol.IdentityToken)
+	// OnTransactionUpdate, if set, is called for every reducer call
+	// outcome the server reports over this connection.
+	OnTransactionUpdate func(protocol.TransactionUpdate)
+	// OnError, if set, is called from the read loop's goroutine with
+	// any error reading or decoding a message. The read loop stops
+	// after the first one, since a malformed or out-of-sync stream
+	// cannot be recovered message-by-message.
+	OnError func(error)
+}
+
+// DialWSClient connects to a SpacetimeDB module at urlStr (a ws:// or
+// wss:// URL) and starts reading subscription and transaction update
+// messages in the background. header carries any additional headers
+// the connection should present during the WebSocket handshake (e.g.
+// Authorization). version selects which message kinds
+// protocol.DecodeEnvelope accepts from this server; pass the version
+// negotiated via the server's own handshake response if known, or
+// protocol's newest supported version otherwise. handlers are wired up
+// before the background read loop starts, so they are already in place
+// for even the very first message the server sends.
+func DialWSClient(urlStr string, header http.Header, version protocol.ServerVersion, handlers WSClientHandlers) (*WSClient, error) {
+	conn, _, err := ws.Dial(urlStr, header)
+	if err != nil {
+		return nil, fmt.Errorf("spacetimedb: dial %s: %w", urlStr, err)
+	}
+	c := &WSClient{
+		Conn:          NewConn(),
+		conn:          conn,
+		version:       version,
+		format:        bsatn.CurrentFormat,
+		pending:       map[uint32]chan protocol.TransactionUpdate{},
+		activeQueries: map[string]struct{}{},
+		handlers:      handlers,
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// Subscribe subscribes to a single query's result set. It is shorthand
+// for SubscribeMulti with one query.
+func (c *WSClient) Subscribe(query string) error {
+	return c.SubscribeMulti([]string{query})
+}
+
+// SubscribeMulti subscribes to every query's result set, matching rows
+// for each streamed to this connection as InitialSubscription and then
+// TransactionUpdate messages.
+func (c *WSClient) SubscribeMulti(queries []string) error {
+	data, err := encodeWireMessage(protocol.KindSubscriptionUpdate, SubscribeRequest{QueryStrings: queries})
+	if err != nil {
+		return err
+	}
+	if err := c.conn.WriteMessage(ws.BinaryMessage, data); err != nil {
+		return fmt.Errorf("spacetimedb: send subscribe request: %w", err)
+	}
+	c.subsMu.Lock()
+	for _, q := range queries {
+		c.activeQueries[q] = struct{}{}
+	}
+	c.subsMu.Unlock()
+	return nil
+}
+
+// Unsubscribe stops receiving updates for a single query's result set.
+// It is shorthand for UnsubscribeMulti with one query.
+func (c *WSClient) Unsubscribe(query string) error {
+	return c.UnsubscribeMulti([]string{query})
+}
+
+// UnsubscribeMulti stops receiving updates for every query's result
+// set. Each query must match, exactly, a string previously passed to
+// Subscribe or SubscribeMulti; the server (and Subscriptions) track a
+// subscription by its query text, not a handle this call returns.
+func (c *WSClient) UnsubscribeMulti(queries []string) error {
+	data, err := encodeWireMessage(protocol.KindUnsubscribe, protocol.UnsubscribeRequest{QueryStrings: queries})
+	if err != nil {
+		return err
+	}
+	if err := c.conn.WriteMessage(ws.BinaryMessage, data); err != nil {
+		return fmt.Errorf("spacetimedb: send unsubscribe request: %w", err)
+	}
+	c.subsMu.Lock()
+	for _, q := range queries {
+		delete(c.activeQueries, q)
+	}
+	c.subsMu.Unlock()
+	return nil
+}
+
+// Subscriptions returns every query string this connection currently
+// believes it is subscribed to — every query passed to Subscribe or
+// SubscribeMulti that has not since been passed to Unsubscribe or
+// UnsubscribeMulti — in no particular order.
+func (c *WSClient) Subscriptions() []string {
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+	queries := make([]string, 0, len(c.activeQueries))
+	for q := range c.activeQueries {
+		queries = append(queries, q)
+	}
+	return queries
+}
+
+// Format returns the BSATN format this connection currently encodes and
+// expects to decode — bsatn.CurrentFormat until NegotiateFormat changes
+// it.
+func (c *WSClient) Format() bsatn.Format {
+	return c.format
+}
+
+// NegotiateFormat picks the format this connection will use for the
+// rest of its lifetime from the formats remote advertises, via
+// bsatn.NegotiateFormat against every format this build can decode. Call
+// it once, before Subscribe or CallReducer, with the list a real
+// handshake response reports the server supports; there is no such
+// handshake message yet (see protocol.ServerVersion's doc comment for
+// the same caveat about version), so today every caller either skips
+// this and keeps bsatn.CurrentFormat or passes a list it otherwise knows
+// out of band.
+func (c *WSClient) NegotiateFormat(remoteSupported []bsatn.Format) error {
+	format, err := bsatn.NegotiateFormat([]bsatn.Format{bsatn.CurrentFormat}, remoteSupported)
+	if err != nil {
+		return fmt.Errorf("spacetimedb: negotiate format: %w", err)
+	}
+	c.format = format
+	return nil
+}
+
+// Close closes the underlying WebSocket connection, stopping the read
+// loop.
+func (c *WSClient) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+		err = c.conn.Close()
+	})
+	return err
+}
+
+func (c *WSClient) readLoop() {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if !c.closed.Load() && c.handlers.OnError != nil {
+				c.handlers.OnError(fmt.Errorf("spacetimedb: read message: %w", err))
+			}
+			return
+		}
+		if err := c.handleMessage(data); err != nil {
+			if c.handlers.OnError != nil {
+				c.handlers.OnError(err)
+			}
+			return
+		}
+	}
+}
+
+func (c *WSClient) handleMessage(data []byte) error {
+	msg, err := decodeWireMessage(data)
+	if err != nil {
+		return err
+	}
+
+	switch msg.kind {
+	case protocol.KindSubscriptionUpdate:
+		var upd protocol.SubscriptionUpdate
+		if err := protocol.DecodeEnvelope(c.version, msg.kind, msg.payload, &upd); err != nil {
+			return fmt.Errorf("spacetimedb: decode subscription update: %w", err)
+		}
+		for _, row := range upd.Inserts {
+			c.Dispatch(upd.Table, row)
+		}
+		for _, row := range upd.Deletes {
+			c.DispatchDelete(upd.Table, row)
+		}
+	case protocol.KindIdentityToken:
+		var tok protocol.IdentityToken
+		if err := protocol.DecodeEnvelope(c.version, msg.kind, msg.payload, &tok); err != nil {
+			return fmt.Errorf("spacetimedb: decode identity token: %w", err)
+		}
+		if c.handlers.OnIdentity != nil {
+			c.handlers.OnIdentity(tok)
+		}
+	case protocol.KindTransactionUpdate:
+		var tx protocol.TransactionUpdate
+		if err := protocol.DecodeEnvelope(c.version, msg.kind, msg.payload, &tx); err != nil {
+			return fmt.Errorf("spacetimedb: decode transaction update: %w", err)
+		}
+		c.deliverPending(tx)
+		if c.handlers.OnTransactionUpdate != nil {
+			c.handlers.OnTransactionUpdate(tx)
+		}
+	default:
+		return fmt.Errorf("spacetimedb: unknown message kind %s", msg.kind)
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package spacetimedb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/protocol"
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/ws"
+)
+
+func TestWSClientDecodesSubscriptionUpdateAndDispatchesRows(t *testing.T) {
+	var gotQueries []string
+	serverReady := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.Upgrade(w, r)
+		if err != nil {
+			t.Errorf("server Upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Errorf("server ReadMessage: %v", err)
+			return
+		}
+		msg, err := decodeWireMessage(data)
+		if err != nil {
+			t.Errorf("decodeWireMessage: %v", err)
+			return
+		}
+		var req SubscribeRequest
+		if err := protocol.DecodeEnvelope(protocol.V1_2, msg.kind, msg.payload, &req); err != nil {
+			t.Errorf("decode SubscribeRequest: %v", err)
+			return
+		}
+		gotQueries = req.QueryStrings
+		close(serverReady)
+
+		upd := protocol.SubscriptionUpdate{
+			Table:   "players",
+			Inserts: [][]byte{{1, 2, 3}},
+			Deletes: [][]byte{{4, 5}},
+		}
+		out, err := encodeWireMessage(protocol.KindSubscriptionUpdate, upd)
+		if err != nil {
+			t.Errorf("encodeWireMessage: %v", err)
+			return
+		}
+		if err := conn.WriteMessage(ws.BinaryMessage, out); err != nil {
+			t.Errorf("server WriteMessage: %v", err)
+		}
+
+		// Keep the connection open briefly so the client has time to
+		// read before the handler returns and the server closes it.
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	wsURL := "ws://" + strings.TrimPrefix(srv.URL, "http://")
+	client, err := DialWSClient(wsURL, nil, protocol.V1_2, WSClientHandlers{})
+	if err != nil {
+		t.Fatalf("DialWSClient: %v", err)
+	}
+	defer client.Close()
+
+	var mu sync.Mutex
+	var inserted, deleted [][]byte
+	done := make(chan struct{})
+	client.OnInsert("players", func(row []byte) {
+		mu.Lock()
+		inserted = append(inserted, row)
+		mu.Unlock()
+	})
+	client.OnDelete("players", func(row []byte) {
+		mu.Lock()
+		deleted = append(deleted, row)
+		mu.Unlock()
+		close(done)
+	})
+
+	if err := client.Subscribe("SELECT * FROM players"); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for dispatched rows")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(inserted) != 1 || string(inserted[0]) != "\x01\x02\x03" {
+		t.Errorf("inserted = %v, want one row [1 2 3]", inserted)
+	}
+	if len(deleted) != 1 || string(deleted[0]) != "\x04\x05" {
+		t.Errorf("deleted = %v, want one row [4 5]", deleted)
+	}
+	if len(gotQueries) != 1 || gotQueries[0] != "SELECT * FROM players" {
+		t.Errorf("server saw queries %v, want [SELECT * FROM players]", gotQueries)
+	}
+}
+
+func TestWSClientUnsubscribeSendsRequestAndUpdatesSubscriptions(t *testing.T) {
+	var gotQueries []string
+	serverDone := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.Upgrade(w, r)
+		if err != nil {
+			t.Errorf("server Upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Errorf("server ReadMessage (subscribe): %v", err)
+			return
+		}
+		if _, err := decodeWireMessage(data); err != nil {
+			t.Errorf("decodeWireMessage (subscribe): %v", err)
+			return
+		}
+
+		_, data, err = conn.ReadMessage()
+		if err != nil {
+			t.Errorf("server ReadMessage (unsubscribe): %v", err)
+			return
+		}
+		msg, err := decodeWireMessage(data)
+		if err != nil {
+			t.Errorf("decodeWireMessage (unsubscribe): %v", err)
+			return
+		}
+		if msg.kind != protocol.KindUnsubscribe {
+			t.Errorf("kind = %s, want %s", msg.kind, protocol.KindUnsubscribe)
+		}
+		var req protocol.UnsubscribeRequest
+		if err := protocol.DecodeEnvelope(protocol.V1_2, msg.kind, msg.payload, &req); err != nil {
+			t.Errorf("decode UnsubscribeRequest: %v", err)
+			return
+		}
+		gotQueries = req.QueryStrings
+		close(serverDone)
+
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	wsURL := "ws://" + strings.TrimPrefix(srv.URL, "http://")
+	client, err := DialWSClient(wsURL, nil, protocol.V1_2, WSClientHandlers{})
+	if err != nil {
+		t.Fatalf("DialWSClient: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Subscribe("SELECT * FROM players"); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if subs := client.Subscriptions(); len(subs) != 1 || subs[0] != "SELECT * FROM players" {
+		t.Fatalf("Subscriptions() = %v, want [SELECT * FROM players]", subs)
+	}
+
+	if err := client.Unsubscribe("SELECT * FROM players"); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+	if subs := client.Subscriptions(); len(subs) != 0 {
+		t.Fatalf("Subscriptions() after Unsubscribe = %v, want none", subs)
+	}
+
+	select {
+	case <-serverDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for server to see the unsubscribe request")
+	}
+	if len(gotQueries) != 1 || gotQueries[0] != "SELECT * FROM players" {
+		t.Errorf("server saw queries %v, want [SELECT * FROM players]", gotQueries)
+	}
+}
+
+func TestWSClientDispatchesIdentityAndTransactionUpdates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.Upgrade(w, r)
+		if err != nil {
+			t.Errorf("server Upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		tok, err := encodeWireMessage(protocol.KindIdentityToken, protocol.IdentityToken{
+			Identity: []byte{9, 9},
+			Token:    "tok123",
+		})
+		if err != nil {
+			t.Errorf("encodeWireMessage identity: %v", err)
+			return
+		}
+		if err := conn.WriteMessage(ws.BinaryMessage, tok); err != nil {
+			t.Errorf("write identity: %v", err)
+			return
+		}
+
+		tx, err := encodeWireMessage(protocol.KindTransactionUpdate, protocol.TransactionUpdate{
+			ReducerName: "add_player",
+			Status:      protocol.ReducerStatusCommitted,
+		})
+		if err != nil {
+			t.Errorf("encodeWireMessage transaction: %v", err)
+			return
+		}
+		if err := conn.WriteMessage(ws.BinaryMessage, tx); err != nil {
+			t.Errorf("write transaction: %v", err)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	identityDone := make(chan protocol.IdentityToken, 1)
+	txDone := make(chan protocol.TransactionUpdate, 1)
+
+	wsURL := "ws://" + strings.TrimPrefix(srv.URL, "http://")
+	client, err := DialWSClient(wsURL, nil, protocol.V1_2, WSClientHandlers{
+		OnIdentity:          func(tok protocol.IdentityToken) { identityDone <- tok },
+		OnTransactionUpdate: func(tx protocol.TransactionUpdate) { txDone <- tx },
+	})
+	if err != nil {
+		t.Fatalf("DialWSClient: %v", err)
+	}
+	defer client.Close()
+
+	select {
+	case tok := <-identityDone:
+		if tok.Token != "tok123" {
+			t.Errorf("Token = %q, want %q", tok.Token, "tok123")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for identity token")
+	}
+
+	select {
+	case tx := <-txDone:
+		if tx.ReducerName != "add_player" || tx.Status != protocol.ReducerStatusCommitted {
+			t.Errorf("got %+v", tx)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for transaction update")
+	}
+}
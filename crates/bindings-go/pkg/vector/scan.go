@@ -0,0 +1,81 @@
+package vector
+
+import (
+	"container/heap"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+// ScanResult is one row returned by CosineSimilarityScan, most similar
+// first.
+type ScanResult struct {
+	Key   []byte
+	Row   []byte
+	Score float32
+}
+
+// CosineSimilarityScan scans every row cur produces, extracts an
+// embedding from each with extract, and returns the k rows with the
+// highest cosine similarity to query, ordered most similar first. Rows
+// extract returns an error for are skipped rather than failing the
+// whole scan, since a table's embedding column is expected to be
+// populated incrementally.
+//
+// It runs in O(n log k) using a min-heap of the current top k, rather
+// than sorting every row, since k is expected to be small relative to
+// table size.
+func CosineSimilarityScan(cur db.Cursor, extract func(row []byte) ([]float32, error), query []float32, k int) ([]ScanResult, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	h := &resultHeap{}
+	heap.Init(h)
+
+	for cur.Next() {
+		embedding, err := extract(cur.Value())
+		if err != nil {
+			continue
+		}
+		score, err := CosineSimilarity(embedding, query)
+		if err != nil {
+			continue
+		}
+		result := ScanResult{
+			Key:   append([]byte(nil), cur.Key()...),
+			Row:   append([]byte(nil), cur.Value()...),
+			Score: score,
+		}
+		if h.Len() < k {
+			heap.Push(h, result)
+		} else if h.Len() > 0 && score > (*h)[0].Score {
+			heap.Pop(h)
+			heap.Push(h, result)
+		}
+	}
+	if err := cur.Close(); err != nil {
+		return nil, err
+	}
+
+	out := make([]ScanResult, h.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(h).(ScanResult)
+	}
+	return out, nil
+}
+
+// resultHeap is a min-heap by Score, so the smallest of the current top
+// k sits at the root and is the cheapest one to evict when a better
+// candidate shows up.
+type resultHeap []ScanResult
+
+func (h resultHeap) Len() int            { return len(h) }
+func (h resultHeap) Less(i, j int) bool  { return h[i].Score < h[j].Score }
+func (h resultHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *resultHeap) Push(x interface{}) { *h = append(*h, x.(ScanResult)) }
+func (h *resultHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,47 @@
+package vector
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+func randVector(n int, r *rand.Rand) []float32 {
+	v := make([]float32, n)
+	for i := range v {
+		v[i] = r.Float32()
+	}
+	return v
+}
+
+func BenchmarkEncodeVector(b *testing.B) {
+	v := randVector(256, rand.New(rand.NewSource(1)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = EncodeVector(v)
+	}
+}
+
+// BenchmarkMarshalVectorGeneric measures the reflection-based path
+// EncodeVector exists to avoid, for comparison.
+func BenchmarkMarshalVectorGeneric(b *testing.B) {
+	v := randVector(256, rand.New(rand.NewSource(1)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := bsatn.Marshal(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCosineSimilarity(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	a, v := randVector(256, r), randVector(256, r)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := CosineSimilarity(a, v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
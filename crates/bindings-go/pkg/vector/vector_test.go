@@ -0,0 +1,78 @@
+package vector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/internal/db"
+)
+
+func TestEncodeDecodeVectorRoundTrip(t *testing.T) {
+	want := []float32{1, -2.5, 3.25, 0}
+	got, err := DecodeVector(EncodeVector(want))
+	if err != nil {
+		t.Fatalf("DecodeVector: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestCosineSimilarityIdenticalVectorsIsOne(t *testing.T) {
+	v := []float32{1, 2, 3}
+	got, err := CosineSimilarity(v, v)
+	if err != nil {
+		t.Fatalf("CosineSimilarity: %v", err)
+	}
+	if got < 0.999999 || got > 1.000001 {
+		t.Fatalf("got %v, want ~1", got)
+	}
+}
+
+func TestCosineSimilarityOrthogonalIsZero(t *testing.T) {
+	got, err := CosineSimilarity([]float32{1, 0}, []float32{0, 1})
+	if err != nil {
+		t.Fatalf("CosineSimilarity: %v", err)
+	}
+	if got < -0.000001 || got > 0.000001 {
+		t.Fatalf("got %v, want ~0", got)
+	}
+}
+
+func TestCosineSimilarityRejectsDimensionMismatch(t *testing.T) {
+	if _, err := CosineSimilarity([]float32{1}, []float32{1, 2}); err == nil {
+		t.Fatal("expected dimension mismatch error")
+	}
+}
+
+func TestCosineSimilarityScanReturnsTopK(t *testing.T) {
+	engine := db.NewMemEngine()
+	rows := map[string][]float32{
+		"a": {1, 0},
+		"b": {0.9, 0.1},
+		"c": {0, 1},
+	}
+	for key, embedding := range rows {
+		if err := engine.Put("vectors", []byte(key), EncodeVector(embedding)); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	cur, err := engine.Scan("vectors")
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	results, err := CosineSimilarityScan(cur, DecodeVector, []float32{1, 0}, 2)
+	if err != nil {
+		t.Fatalf("CosineSimilarityScan: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if string(results[0].Key) != "a" {
+		t.Fatalf("closest match = %q, want a", results[0].Key)
+	}
+	if results[0].Score < results[1].Score {
+		t.Fatalf("results not sorted most-similar first: %+v", results)
+	}
+}
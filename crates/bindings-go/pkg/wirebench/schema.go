@@ -0,0 +1,119 @@
+package wirebench
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// schemaJSON is the on-disk shape ParseSchema reads, a human-writable
+// mirror of bsatn.AlgebraicType: {"kind": "<name>", ...} where the
+// remaining fields depend on kind. Product/Sum name their elements the
+// same way bsatn.MarshalJSON expects row values to be keyed.
+type schemaJSON struct {
+	Kind string `json:"kind"`
+
+	Fields   []schemaFieldJSON `json:"fields,omitempty"`   // product
+	Variants []schemaFieldJSON `json:"variants,omitempty"` // sum
+	Elem     *schemaJSON       `json:"elem,omitempty"`     // array
+	Key      *schemaJSON       `json:"key,omitempty"`      // map
+	Value    *schemaJSON       `json:"value,omitempty"`    // map
+}
+
+type schemaFieldJSON struct {
+	Name string     `json:"name"`
+	Type schemaJSON `json:"type"`
+}
+
+// ParseSchema parses data (see schemaJSON) into an AlgebraicType, for
+// loading a benchmark's schema from a file rather than writing Go code
+// against pkg/bsatn's constructors.
+func ParseSchema(data []byte) (bsatn.AlgebraicType, error) {
+	var s schemaJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return bsatn.AlgebraicType{}, fmt.Errorf("wirebench: parse schema: %w", err)
+	}
+	return s.toAlgebraicType()
+}
+
+func (s schemaJSON) toAlgebraicType() (bsatn.AlgebraicType, error) {
+	switch s.Kind {
+	case "product":
+		elems := make([]bsatn.ProductElement, len(s.Fields))
+		for i, f := range s.Fields {
+			t, err := f.Type.toAlgebraicType()
+			if err != nil {
+				return bsatn.AlgebraicType{}, err
+			}
+			name := f.Name
+			elems[i] = bsatn.ProductElement{Name: &name, Type: t}
+		}
+		return bsatn.ProductOf(elems...), nil
+	case "sum":
+		variants := make([]bsatn.SumVariant, len(s.Variants))
+		for i, v := range s.Variants {
+			t, err := v.Type.toAlgebraicType()
+			if err != nil {
+				return bsatn.AlgebraicType{}, err
+			}
+			name := v.Name
+			variants[i] = bsatn.SumVariant{Name: &name, Type: t}
+		}
+		return bsatn.SumOf(variants...), nil
+	case "array":
+		if s.Elem == nil {
+			return bsatn.AlgebraicType{}, fmt.Errorf("wirebench: array schema missing \"elem\"")
+		}
+		elem, err := s.Elem.toAlgebraicType()
+		if err != nil {
+			return bsatn.AlgebraicType{}, err
+		}
+		return bsatn.ArrayOf(elem), nil
+	case "map":
+		if s.Key == nil || s.Value == nil {
+			return bsatn.AlgebraicType{}, fmt.Errorf("wirebench: map schema missing \"key\" or \"value\"")
+		}
+		key, err := s.Key.toAlgebraicType()
+		if err != nil {
+			return bsatn.AlgebraicType{}, err
+		}
+		value, err := s.Value.toAlgebraicType()
+		if err != nil {
+			return bsatn.AlgebraicType{}, err
+		}
+		return bsatn.MapOf(key, value), nil
+	case "bool":
+		return bsatn.Bool(), nil
+	case "i8":
+		return bsatn.I8(), nil
+	case "u8":
+		return bsatn.U8(), nil
+	case "i16":
+		return bsatn.I16(), nil
+	case "u16":
+		return bsatn.U16(), nil
+	case "i32":
+		return bsatn.I32(), nil
+	case "u32":
+		return bsatn.U32(), nil
+	case "i64":
+		return bsatn.I64(), nil
+	case "u64":
+		return bsatn.U64(), nil
+	case "i128":
+		return bsatn.I128(), nil
+	case "u128":
+		return bsatn.U128(), nil
+	case "f32":
+		return bsatn.F32(), nil
+	case "f64":
+		return bsatn.F64(), nil
+	case "string":
+		return bsatn.String(), nil
+	case "bytes":
+		return bsatn.Bytes(), nil
+	default:
+		return bsatn.AlgebraicType{}, fmt.Errorf("wirebench: unknown schema kind %q", s.Kind)
+	}
+}
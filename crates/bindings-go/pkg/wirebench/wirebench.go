@@ -0,0 +1,148 @@
+// Package wirebench measures how BSATN and SATS-JSON compare for a given
+// schema and set of sample rows, so a Go client author can pick a wire
+// format with real numbers instead of guessing: BSATN is compact and
+// decodes without a general-purpose JSON parser, SATS-JSON is human
+// readable and works with off-the-shelf JSON tooling at the cost of
+// size and allocations.
+package wirebench
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/clockworklabs/SpacetimeDB/crates/bindings-go/pkg/bsatn"
+)
+
+// Stats summarizes iterations repetitions of one encode or decode
+// operation across every row.
+type Stats struct {
+	// Iterations is how many times the operation was repeated (once per
+	// row, per repetition), the denominator for Duration and AllocsPerOp.
+	Iterations  int
+	Duration    time.Duration
+	AllocsPerOp float64
+}
+
+// PerOp returns the average wall-clock time of one operation.
+func (s Stats) PerOp() time.Duration {
+	if s.Iterations == 0 {
+		return 0
+	}
+	return s.Duration / time.Duration(s.Iterations)
+}
+
+// Report is the result of comparing BSATN and SATS-JSON encoding of the
+// same rows against the same schema. SATS-JSON here is exactly the
+// encoding/json representation of the `any` values bsatn.MarshalJSON and
+// bsatn.UnmarshalJSON already accept and produce (see pkg/bsatn's
+// JSON codec), so the comparison is apples to apples: both formats round
+// trip the same in-memory rows.
+type Report struct {
+	Rows int
+
+	// BSATNBytes/JSONBytes are the total encoded size, summed over every
+	// row, in each format.
+	BSATNBytes int
+	JSONBytes  int
+
+	BSATNEncode Stats
+	BSATNDecode Stats
+	JSONEncode  Stats
+	JSONDecode  Stats
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf(
+		"%d rows\n"+
+			"  BSATN: %d bytes  encode %v/op (%.1f allocs/op)  decode %v/op (%.1f allocs/op)\n"+
+			"  JSON:  %d bytes  encode %v/op (%.1f allocs/op)  decode %v/op (%.1f allocs/op)",
+		r.Rows,
+		r.BSATNBytes, r.BSATNEncode.PerOp(), r.BSATNEncode.AllocsPerOp, r.BSATNDecode.PerOp(), r.BSATNDecode.AllocsPerOp,
+		r.JSONBytes, r.JSONEncode.PerOp(), r.JSONEncode.AllocsPerOp, r.JSONDecode.PerOp(), r.JSONDecode.AllocsPerOp,
+	)
+}
+
+// Compare encodes and decodes every row in rows against schema,
+// iterations times each, once in BSATN and once in SATS-JSON, and
+// reports sizes plus per-operation timing and allocation counts for
+// both. Each row must already be in the `any` shape bsatn.MarshalJSON
+// accepts (map[string]any for a product, matching numbers as float64,
+// as produced by encoding/json.Unmarshal). iterations must be at least
+// 1; Compare fails fast with a single validation pass over rows before
+// timing anything, so a schema/row mismatch is reported as an error
+// rather than skewing the benchmark.
+func Compare(schema bsatn.AlgebraicType, rows []any, iterations int) (Report, error) {
+	if iterations < 1 {
+		return Report{}, fmt.Errorf("wirebench: iterations must be at least 1, got %d", iterations)
+	}
+
+	bsatnEncoded := make([][]byte, len(rows))
+	jsonEncoded := make([][]byte, len(rows))
+	for i, row := range rows {
+		b, err := bsatn.MarshalJSON(row, schema)
+		if err != nil {
+			return Report{}, fmt.Errorf("wirebench: BSATN-encode row %d: %w", i, err)
+		}
+		bsatnEncoded[i] = b
+
+		j, err := json.Marshal(row)
+		if err != nil {
+			return Report{}, fmt.Errorf("wirebench: JSON-encode row %d: %w", i, err)
+		}
+		jsonEncoded[i] = j
+	}
+
+	report := Report{Rows: len(rows)}
+	for _, b := range bsatnEncoded {
+		report.BSATNBytes += len(b)
+	}
+	for _, j := range jsonEncoded {
+		report.JSONBytes += len(j)
+	}
+
+	report.BSATNEncode = timeOp(len(rows), iterations, func() {
+		for _, row := range rows {
+			_, _ = bsatn.MarshalJSON(row, schema)
+		}
+	})
+	report.BSATNDecode = timeOp(len(rows), iterations, func() {
+		for _, b := range bsatnEncoded {
+			_, _ = bsatn.UnmarshalJSON(b, schema)
+		}
+	})
+	report.JSONEncode = timeOp(len(rows), iterations, func() {
+		for _, row := range rows {
+			_, _ = json.Marshal(row)
+		}
+	})
+	report.JSONDecode = timeOp(len(rows), iterations, func() {
+		var v any
+		for _, j := range jsonEncoded {
+			_ = json.Unmarshal(j, &v)
+		}
+	})
+
+	return report, nil
+}
+
+// timeOp runs fn iterations times, each covering rowsPerIteration
+// operations, and reports the total elapsed time and average
+// allocations per single operation (not per fn call).
+func timeOp(rowsPerIteration, iterations int, fn func()) Stats {
+	allocs := testing.AllocsPerRun(iterations, fn)
+
+	start := time.Now()
+	for i := 0; i < iterations; i++ {
+		fn()
+	}
+	elapsed := time.Since(start)
+
+	ops := iterations * rowsPerIteration
+	perOpAllocs := allocs
+	if rowsPerIteration > 0 {
+		perOpAllocs = allocs / float64(rowsPerIteration)
+	}
+	return Stats{Iterations: ops, Duration: elapsed, AllocsPerOp: perOpAllocs}
+}
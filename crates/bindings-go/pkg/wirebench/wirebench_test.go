@@ -0,0 +1,62 @@
+package wirebench
+
+import "testing"
+
+func TestCompareReportsSizesAndStats(t *testing.T) {
+	s, err := ParseSchema([]byte(`{"kind":"product","fields":[{"name":"id","type":{"kind":"u32"}},{"name":"name","type":{"kind":"string"}}]}`))
+	if err != nil {
+		t.Fatalf("ParseSchema: %v", err)
+	}
+
+	rows := []any{
+		map[string]any{"id": float64(1), "name": "Ada"},
+		map[string]any{"id": float64(2), "name": "Grace"},
+	}
+
+	report, err := Compare(s, rows, 10)
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	if report.Rows != 2 {
+		t.Fatalf("Rows = %d, want 2", report.Rows)
+	}
+	if report.BSATNBytes == 0 || report.JSONBytes == 0 {
+		t.Fatalf("expected nonzero sizes, got BSATN=%d JSON=%d", report.BSATNBytes, report.JSONBytes)
+	}
+	if report.BSATNEncode.Iterations != 20 || report.JSONDecode.Iterations != 20 {
+		t.Fatalf("expected 10 iterations * 2 rows = 20 ops, got BSATNEncode=%d JSONDecode=%d",
+			report.BSATNEncode.Iterations, report.JSONDecode.Iterations)
+	}
+	if report.String() == "" {
+		t.Fatal("String() returned empty report")
+	}
+}
+
+func TestCompareRejectsZeroIterations(t *testing.T) {
+	s, _ := ParseSchema([]byte(`{"kind":"u32"}`))
+	if _, err := Compare(s, []any{float64(1)}, 0); err == nil {
+		t.Fatal("expected an error for zero iterations")
+	}
+}
+
+func TestCompareRejectsRowNotMatchingSchema(t *testing.T) {
+	s, _ := ParseSchema([]byte(`{"kind":"u32"}`))
+	if _, err := Compare(s, []any{"not a number"}, 1); err == nil {
+		t.Fatal("expected an error for a row that doesn't match the schema")
+	}
+}
+
+func TestParseSchemaRejectsUnknownKind(t *testing.T) {
+	if _, err := ParseSchema([]byte(`{"kind":"nonsense"}`)); err == nil {
+		t.Fatal("expected an error for an unknown schema kind")
+	}
+}
+
+func TestParseSchemaArrayAndMap(t *testing.T) {
+	if _, err := ParseSchema([]byte(`{"kind":"array","elem":{"kind":"string"}}`)); err != nil {
+		t.Fatalf("array schema: %v", err)
+	}
+	if _, err := ParseSchema([]byte(`{"kind":"map","key":{"kind":"string"},"value":{"kind":"u32"}}`)); err != nil {
+		t.Fatalf("map schema: %v", err)
+	}
+}